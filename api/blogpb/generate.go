@@ -0,0 +1,5 @@
+// Package blogpb contains the protobuf messages and the gRPC client and server of the blog API,
+// generated from api/proto/blog/v1/blog.proto.
+package blogpb
+
+//go:generate protoc -I ../proto --go_out=../.. --go_opt=module=BlogManagment --go-grpc_out=../.. --go-grpc_opt=module=BlogManagment blog/v1/blog.proto
@@ -0,0 +1,45 @@
+package client
+
+import "net/http"
+
+// Authenticator adds credentials to outgoing requests
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// AuthenticatorFunc adapts a function to the Authenticator interface
+type AuthenticatorFunc func(req *http.Request) error
+
+// Authenticate calls f(req)
+func (f AuthenticatorFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// BearerToken authenticates requests with a bearer token, such as a JWT
+func BearerToken(token string) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// APIKey authenticates requests with an API key, for machine clients such as CI jobs
+func APIKey(key string) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) error {
+		req.Header.Set("Authorization", "ApiKey "+key)
+		return nil
+	})
+}
+
+// TokenSource authenticates requests with a bearer token obtained for every request,
+// for tokens that expire and are refreshed by the caller
+func TokenSource(token func() (string, error)) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) error {
+		value, err := token()
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+value)
+		return nil
+	})
+}
@@ -0,0 +1,279 @@
+// Package client is a typed Go client for the Blog Management REST API.
+//
+//	c, err := client.New("https://blog.example.com", client.Options{Auth: client.BearerToken(token)})
+//	post, err := c.CreatePost(ctx, &client.CreatePostRequest{Title: "Hello", Body: "World"})
+//
+// Failed requests are retried when it is safe to do so. Every POST carries an Idempotency-Key that
+// stays the same across the retries of a call, so the server applies it at most once.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Default retry settings
+const (
+	DefaultMaxRetries = 3
+	DefaultRetryWait  = 250 * time.Millisecond
+	maxRetryWait      = 10 * time.Second
+)
+
+// TenantHeader is the header naming the tenant a request addresses
+const TenantHeader = "X-Tenant-ID"
+
+// Options configures a Client. The zero value is usable.
+type Options struct {
+	// HTTPClient sends the requests; http.DefaultClient when nil
+	HTTPClient *http.Client
+	// Auth authenticates every request; requests are anonymous when nil
+	Auth Authenticator
+	// MaxRetries is how often a failed request is retried; DefaultMaxRetries when zero, none when negative
+	MaxRetries int
+	// RetryWait is the delay before the first retry, doubled for every further one; DefaultRetryWait when zero
+	RetryWait time.Duration
+	// UserAgent is sent in the User-Agent header when set
+	UserAgent string
+	// Tenant is sent in the X-Tenant-ID header when set, addressing the posts of that tenant
+	Tenant string
+}
+
+// Client calls the Blog Management API. It is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	auth       Authenticator
+	maxRetries int
+	retryWait  time.Duration
+	userAgent  string
+	tenant     string
+}
+
+// New creates a client for the API served at baseURL, e.g. "https://blog.example.com"
+func New(baseURL string, options Options) (*Client, error) {
+	parsed, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    parsed,
+		httpClient: options.HTTPClient,
+		auth:       options.Auth,
+		maxRetries: options.MaxRetries,
+		retryWait:  options.RetryWait,
+		userAgent:  options.UserAgent,
+		tenant:     options.Tenant,
+	}
+	if c.httpClient == nil {
+		c.httpClient = http.DefaultClient
+	}
+	if c.maxRetries == 0 {
+		c.maxRetries = DefaultMaxRetries
+	} else if c.maxRetries < 0 {
+		c.maxRetries = 0
+	}
+	if c.retryWait <= 0 {
+		c.retryWait = DefaultRetryWait
+	}
+	return c, nil
+}
+
+// envelope is the JSON body shared by every API response
+type envelope struct {
+	Error      string          `json:"error"`
+	Message    string          `json:"message"`
+	Data       json.RawMessage `json:"data"`
+	Count      *int            `json:"count"`
+	NextCursor string          `json:"next_cursor"`
+}
+
+// request describes a single API call
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	body        []byte
+	contentType string
+	accept      string
+}
+
+// jsonRequest creates a request whose body is v encoded as JSON
+func jsonRequest(method, path string, v interface{}) (*request, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+	return &request{method: method, path: path, body: body, contentType: "application/json"}, nil
+}
+
+// call sends a request and decodes the envelope of a successful response.
+// The data of the envelope is decoded into out when out is not nil.
+func (c *Client) call(ctx context.Context, req *request, out interface{}) (*envelope, error) {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var env envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return nil, fmt.Errorf("failed to decode %s %s response: %w", req.method, req.path, err)
+	}
+	if out != nil && len(env.Data) > 0 {
+		if err := json.Unmarshal(env.Data, out); err != nil {
+			return nil, fmt.Errorf("failed to decode %s %s response: %w", req.method, req.path, err)
+		}
+	}
+	return &env, nil
+}
+
+// send performs a request, retrying failures that are safe to retry, and returns a response
+// with a 2xx status. Any other status is returned as an *Error.
+func (c *Client) send(ctx context.Context, req *request) (*http.Response, error) {
+	idempotencyKey := ""
+	if req.method == http.MethodPost {
+		idempotencyKey = idempotencyKeyFrom(ctx)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.attempt(ctx, req, idempotencyKey)
+		if err == nil && resp.StatusCode < 300 {
+			return resp, nil
+		}
+
+		var apiErr *Error
+		if err == nil {
+			apiErr = newError(resp)
+			err = apiErr
+		}
+		if attempt >= c.maxRetries || !c.retryable(ctx, req, apiErr, err) {
+			return nil, err
+		}
+
+		wait := c.backoff(attempt)
+		if apiErr != nil && apiErr.RetryAfter > wait {
+			wait = min(apiErr.RetryAfter, maxRetryWait)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends a request once
+func (c *Client) attempt(ctx context.Context, req *request, idempotencyKey string) (*http.Response, error) {
+	target := c.baseURL.JoinPath(req.path)
+	target.RawQuery = req.query.Encode()
+
+	var body io.Reader
+	if req.body != nil {
+		body = bytes.NewReader(req.body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, target.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq.Header.Set("Accept", "application/json")
+	if req.accept != "" {
+		httpReq.Header.Set("Accept", req.accept)
+	}
+	if req.contentType != "" {
+		httpReq.Header.Set("Content-Type", req.contentType)
+	}
+	if idempotencyKey != "" {
+		httpReq.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+	if c.userAgent != "" {
+		httpReq.Header.Set("User-Agent", c.userAgent)
+	}
+	if c.tenant != "" {
+		httpReq.Header.Set(TenantHeader, c.tenant)
+	}
+	if c.auth != nil {
+		if err := c.auth.Authenticate(httpReq); err != nil {
+			return nil, fmt.Errorf("failed to authenticate request: %w", err)
+		}
+	}
+
+	return c.httpClient.Do(httpReq)
+}
+
+// backoff returns the delay before retrying a request that failed attempt+1 times.
+// The delay doubles with every attempt and is jittered to spread out the retries of concurrent clients.
+func (c *Client) backoff(attempt int) time.Duration {
+	wait := c.retryWait
+	for i := 0; i < attempt && wait < maxRetryWait; i++ {
+		wait *= 2
+	}
+	wait = min(wait, maxRetryWait)
+	return wait + time.Duration(rand.Int64N(int64(wait)/4+1))
+}
+
+// retryable reports whether a failed attempt may be repeated. Requests that never got a response
+// and responses saying the server did not process the request are retried; POST requests are
+// only retried because they carry an idempotency key.
+func (c *Client) retryable(ctx context.Context, req *request, apiErr *Error, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if apiErr == nil {
+		var netErr net.Error
+		return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+	}
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusConflict:
+		// Another attempt with the same idempotency key is still running
+		return req.method == http.MethodPost
+	}
+	return false
+}
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a context that makes POST requests use key instead of a generated
+// idempotency key, so that a call can be safely repeated after the client itself restarts
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// idempotencyKeyFrom returns the idempotency key set on ctx, or a new random key
+func idempotencyKeyFrom(ctx context.Context) string {
+	if key, ok := ctx.Value(idempotencyKeyContextKey{}).(string); ok && key != "" {
+		return key
+	}
+	return uuid.New().String()
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
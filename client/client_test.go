@@ -0,0 +1,529 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"BlogManagment/internal/auth"
+	"BlogManagment/internal/config"
+	"BlogManagment/internal/controller"
+	"BlogManagment/internal/gql"
+	"BlogManagment/internal/middleware"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/ratelimit"
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/repository"
+	"BlogManagment/internal/routes"
+	"BlogManagment/internal/service"
+	"BlogManagment/internal/stream"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// MockWebhookService is a mock implementation of WebhookService
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) Publish(event *models.BlogEvent) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+// WithTenant returns the mock itself
+func (m *MockWebhookService) WithTenant(tenantID string) service.WebhookService {
+	return m
+}
+
+func (m *MockWebhookService) CreateWebhook(request *models.WebhookCreateRequest) (*models.WebhookResponse, error) {
+	args := m.Called(request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) GetWebhook(id string) (*models.WebhookResponse, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) GetAllWebhooks() ([]models.WebhookResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WebhookResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) UpdateWebhook(id string, request *models.WebhookUpdateRequest) (*models.WebhookResponse, error) {
+	args := m.Called(id, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookResponse), args.Error(1)
+}
+
+func (m *MockWebhookService) DeleteWebhook(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) ListDeliveries(webhookID, status string, limit int) ([]models.WebhookDelivery, error) {
+	args := m.Called(webhookID, status, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookService) RetryDelivery(id string) (*models.WebhookDelivery, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+}
+
+// newTestDB opens an empty in-memory SQLite database with the idempotency schema
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+uuid.New().String()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.IdempotencyRecord{}))
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+// testAPI holds the services behind the routes served to the client under test
+type testAPI struct {
+	blogs    service.BlogService
+	webhooks *MockWebhookService
+	handler  http.Handler
+}
+
+// newTestAPI sets up the real routes on top of services over a memory store, rate limiting the
+// route groups in limits
+func newTestAPI(t *testing.T, limits map[string]config.GroupLimits) *testAPI {
+	blogRepo := repository.NewMemoryBlogRepository()
+	api := &testAPI{blogs: service.NewBlogService(blogRepo), webhooks: &MockWebhookService{}}
+
+	executor, err := gql.NewExecutor(api.blogs, 1000)
+	require.NoError(t, err)
+	rateLimiter := middleware.NewRateLimiter(ratelimit.NewMemoryStore(time.Hour), &config.RateLimitConfig{Enabled: true, Groups: limits})
+	idempotency := middleware.Idempotency(repository.NewIdempotencyRepository(newTestDB(t)), time.Hour)
+
+	// Every request is made by an API key of an admin, as access control is not under test
+	var scopes []string
+	for _, permission := range rbac.PermissionsOf(rbac.RoleAdmin) {
+		scopes = append(scopes, string(permission))
+	}
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		auth.SetPrincipal(c, &auth.Principal{Subject: "client-test", Kind: auth.KindAPIKey, Scopes: scopes})
+		return c.Next()
+	})
+	routes.SetupRoutes(app, routes.Deps{
+		Blog:         controller.NewBlogController(api.blogs),
+		Transfer:     controller.NewTransferController(service.NewBlogTransferService(blogRepo)),
+		Webhook:      controller.NewWebhookController(api.webhooks),
+		Event:        controller.NewEventController(stream.NewBroker(nil)),
+		GraphQL:      controller.NewGraphQLController(executor),
+		Role:         controller.NewRoleController(nil),
+		APIKey:       controller.NewAPIKeyController(nil),
+		Audit:        controller.NewAuditController(nil),
+		RateLimiter:  rateLimiter,
+		Authorizer:   middleware.NewAuthorizer(nil, true),
+		Idempotency:  idempotency,
+		CacheControl: middleware.CacheControl(0),
+	})
+	api.handler = adaptor.FiberApp(app)
+	return api
+}
+
+// newTestClient serves handler over HTTP and returns a client for it that retries without delay
+func newTestClient(t *testing.T, handler http.Handler, options Options) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	options.RetryWait = time.Millisecond
+	c, err := New(server.URL, options)
+	require.NoError(t, err)
+	return c
+}
+
+func TestNew_InvalidBaseURL(t *testing.T) {
+	_, err := New("localhost:8080", Options{})
+	assert.Error(t, err)
+}
+
+func TestClient_Tenant(t *testing.T) {
+	var tenants []string
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenants = append(tenants, r.Header.Get(TenantHeader))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"id":"1"}}`))
+	}), Options{Tenant: "acme"})
+
+	_, err := c.GetPost(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme"}, tenants)
+}
+
+func TestClient_PostLifecycle(t *testing.T) {
+	api := newTestAPI(t, nil)
+	c := newTestClient(t, api.handler, Options{})
+	ctx := context.Background()
+
+	created, err := c.CreatePost(ctx, &CreatePostRequest{Title: "Hello", Body: "World", Tags: []string{"go"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", created.Title)
+	assert.Equal(t, []string{"go"}, created.Tags)
+
+	post, err := c.GetPost(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, post.ID)
+
+	title := "Renamed"
+	updated, err := c.UpdatePost(ctx, created.ID, &UpdatePostRequest{Title: &title})
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", updated.Title)
+
+	posts, err := c.ListPosts(ctx)
+	require.NoError(t, err)
+	assert.Len(t, posts, 1)
+
+	require.NoError(t, c.DeletePost(ctx, created.ID))
+	_, err = c.GetPost(ctx, created.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_TypedErrors(t *testing.T) {
+	api := newTestAPI(t, nil)
+	c := newTestClient(t, api.handler, Options{})
+
+	_, err := c.CreatePost(context.Background(), &CreatePostRequest{Title: "No body"})
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "Failed to create blog post", apiErr.Title)
+	assert.Contains(t, apiErr.Message, "body is required")
+	assert.ErrorIs(t, err, ErrBadRequest)
+	assert.NotErrorIs(t, err, ErrNotFound)
+
+	err = c.DeletePost(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_Posts_IteratesOverPages(t *testing.T) {
+	api := newTestAPI(t, nil)
+	c := newTestClient(t, api.handler, Options{})
+	for i := 0; i < 5; i++ {
+		_, err := c.CreatePost(context.Background(), &CreatePostRequest{Title: fmt.Sprintf("Post %d", i), Body: "Body", Tags: []string{"go"}})
+		require.NoError(t, err)
+	}
+
+	page, err := c.ListPostsPage(context.Background(), ListPostsOptions{Limit: 2, Tag: "go"})
+	require.NoError(t, err)
+	assert.Len(t, page.Posts, 2)
+	assert.NotEmpty(t, page.NextCursor)
+
+	var titles []string
+	for post, err := range c.Posts(context.Background(), ListPostsOptions{Limit: 2, Tag: "go"}) {
+		require.NoError(t, err)
+		titles = append(titles, post.Title)
+	}
+	assert.ElementsMatch(t, []string{"Post 0", "Post 1", "Post 2", "Post 3", "Post 4"}, titles)
+
+	// Breaking out of the loop stops fetching pages
+	count := 0
+	for range c.Posts(context.Background(), ListPostsOptions{Limit: 2}) {
+		count++
+		break
+	}
+	assert.Equal(t, 1, count)
+
+	for _, err := range c.Posts(context.Background(), ListPostsOptions{After: "not-a-cursor"}) {
+		assert.ErrorIs(t, err, ErrBadRequest)
+	}
+}
+
+func TestClient_RetriesPostWithSameIdempotencyKey(t *testing.T) {
+	api := newTestAPI(t, nil)
+
+	// The first attempt reaches the API but its response is lost on the way back
+	var mu sync.Mutex
+	var keys []string
+	flaky := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		first := len(keys) == 1
+		mu.Unlock()
+
+		if first {
+			api.handler.ServeHTTP(httptest.NewRecorder(), r)
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		api.handler.ServeHTTP(w, r)
+	})
+	c := newTestClient(t, flaky, Options{})
+
+	post, err := c.CreatePost(context.Background(), &CreatePostRequest{Title: "Hello", Body: "World"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", post.Title)
+	require.Len(t, keys, 2)
+	assert.NotEmpty(t, keys[0])
+	assert.Equal(t, keys[0], keys[1])
+	posts, err := api.blogs.GetAllBlogs()
+	require.NoError(t, err)
+	assert.Len(t, posts, 1, "the retry must be answered from the stored response")
+}
+
+func TestClient_WithIdempotencyKey(t *testing.T) {
+	api := newTestAPI(t, nil)
+	c := newTestClient(t, api.handler, Options{})
+	ctx := WithIdempotencyKey(context.Background(), "create-hello")
+
+	first, err := c.CreatePost(ctx, &CreatePostRequest{Title: "Hello", Body: "World"})
+	require.NoError(t, err)
+	second, err := c.CreatePost(ctx, &CreatePostRequest{Title: "Hello", Body: "World"})
+	require.NoError(t, err)
+
+	assert.Equal(t, first.ID, second.ID)
+	posts, err := api.blogs.GetAllBlogs()
+	require.NoError(t, err)
+	assert.Len(t, posts, 1)
+
+	_, err = c.CreatePost(ctx, &CreatePostRequest{Title: "Other", Body: "World"})
+	assert.ErrorIs(t, err, ErrUnprocessableEntity)
+}
+
+func TestClient_Retries(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		status     int
+		maxRetries int
+		wantCalls  int
+	}{
+		{name: "unavailable GET is retried", method: http.MethodGet, status: http.StatusServiceUnavailable, wantCalls: 4},
+		{name: "retries can be disabled", method: http.MethodGet, status: http.StatusServiceUnavailable, maxRetries: -1, wantCalls: 1},
+		{name: "retries are bounded", method: http.MethodGet, status: http.StatusTooManyRequests, maxRetries: 1, wantCalls: 2},
+		{name: "client errors are not retried", method: http.MethodGet, status: http.StatusBadRequest, wantCalls: 1},
+		{name: "server errors are not retried", method: http.MethodGet, status: http.StatusInternalServerError, wantCalls: 1},
+		{name: "in progress POST is retried", method: http.MethodPost, status: http.StatusConflict, wantCalls: 4},
+		{name: "conflicting PATCH is not retried", method: http.MethodPatch, status: http.StatusConflict, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, `{"error":"Failed","message":"try again"}`)
+			}), Options{MaxRetries: tt.maxRetries})
+
+			_, err := c.call(context.Background(), &request{method: tt.method, path: "/api/blog-post"}, nil)
+
+			var apiErr *Error
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tt.status, apiErr.StatusCode)
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
+
+func TestClient_RateLimited(t *testing.T) {
+	api := newTestAPI(t, map[string]config.GroupLimits{"webhooks": {Read: ratelimit.Limit{Burst: 1, Period: time.Hour}}})
+	api.webhooks.On("GetAllWebhooks").Return([]models.WebhookResponse{}, nil)
+	c := newTestClient(t, api.handler, Options{MaxRetries: -1})
+
+	_, err := c.ListWebhooks(context.Background())
+	require.NoError(t, err)
+
+	_, err = c.ListWebhooks(context.Background())
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Greater(t, apiErr.RetryAfter, time.Duration(0))
+}
+
+func TestClient_RetryStopsWhenContextIsDone(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}), Options{})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := c.Health(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClient_Auth(t *testing.T) {
+	var header string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{"status":"OK"}`)
+	})
+
+	c := newTestClient(t, handler, Options{Auth: BearerToken("secret-token")})
+	require.NoError(t, c.Health(context.Background()))
+	assert.Equal(t, "Bearer secret-token", header)
+
+	c = newTestClient(t, handler, Options{Auth: APIKey("bk_key")})
+	require.NoError(t, c.Health(context.Background()))
+	assert.Equal(t, "ApiKey bk_key", header)
+
+	c = newTestClient(t, handler, Options{Auth: TokenSource(func() (string, error) { return "", errors.New("expired") })})
+	err := c.Health(context.Background())
+	assert.ErrorContains(t, err, "expired")
+}
+
+func TestClient_BulkPosts_RolledBack(t *testing.T) {
+	api := newTestAPI(t, nil)
+	c := newTestClient(t, api.handler, Options{})
+
+	response, err := c.BulkPosts(context.Background(), &BulkRequest{
+		Operations: []BulkOperation{{Action: BulkActionDelete, ID: "missing"}},
+	})
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	require.NotNil(t, response)
+	assert.False(t, response.Committed)
+	require.Len(t, response.Results, 1)
+	assert.Equal(t, "blog post not found", response.Results[0].Error)
+}
+
+func TestClient_ExportAndImport(t *testing.T) {
+	api := newTestAPI(t, nil)
+	c := newTestClient(t, api.handler, Options{})
+	_, err := c.CreatePost(context.Background(), &CreatePostRequest{Title: "Exported", Body: "World"})
+	require.NoError(t, err)
+
+	export, err := c.Export(context.Background(), FormatCSV)
+	require.NoError(t, err)
+	data, err := io.ReadAll(export)
+	require.NoError(t, export.Close())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Exported")
+
+	report, err := c.Import(context.Background(), strings.NewReader(`{"title":"Hello","body":"World"}`), ImportOptions{Format: FormatJSONL, DryRun: true})
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, FormatJSONL, report.Format)
+	assert.Equal(t, 1, report.Created)
+
+	posts, err := api.blogs.GetAllBlogs()
+	require.NoError(t, err)
+	assert.Len(t, posts, 1, "a dry run creates nothing")
+}
+
+func TestClient_Webhooks(t *testing.T) {
+	api := newTestAPI(t, nil)
+	c := newTestClient(t, api.handler, Options{})
+	ctx := context.Background()
+	api.webhooks.On("CreateWebhook", &models.WebhookCreateRequest{URL: "https://example.com/hook", Events: []string{EventPostCreated}}).
+		Return(&models.WebhookResponse{ID: "hook", URL: "https://example.com/hook", Events: []string{EventPostCreated}, Active: true, Secret: "generated-secret"}, nil)
+	api.webhooks.On("GetWebhook", "hook").
+		Return(&models.WebhookResponse{ID: "hook", URL: "https://example.com/hook", Events: []string{EventPostCreated}, Active: true}, nil)
+	api.webhooks.On("GetWebhook", "missing").Return(nil, repository.ErrWebhookNotFound)
+	api.webhooks.On("ListDeliveries", "hook", DeliveryStatusDead, 5).
+		Return([]models.WebhookDelivery{{ID: "delivery", WebhookID: "hook", Status: DeliveryStatusDead, Attempts: 5}}, nil)
+
+	webhook, err := c.CreateWebhook(ctx, &CreateWebhookRequest{URL: "https://example.com/hook", Events: []string{EventPostCreated}})
+	require.NoError(t, err)
+	assert.Equal(t, "generated-secret", webhook.Secret)
+
+	fetched, err := c.GetWebhook(ctx, webhook.ID)
+	require.NoError(t, err)
+	assert.Empty(t, fetched.Secret)
+
+	_, err = c.GetWebhook(ctx, "missing")
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "Webhook not found", apiErr.Title)
+
+	deliveries, err := c.ListDeliveries(ctx, ListDeliveriesOptions{WebhookID: webhook.ID, Status: DeliveryStatusDead, Limit: 5})
+	require.NoError(t, err)
+	assert.Equal(t, []Delivery{{ID: "delivery", WebhookID: webhook.ID, Status: DeliveryStatusDead, Attempts: 5}}, deliveries)
+	api.webhooks.AssertExpectations(t)
+}
+
+func TestClient_StreamEvents(t *testing.T) {
+	var query string
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "retry: 3000\n\n")
+		fmt.Fprint(w, "id: 7\nevent: post.created\ndata: {\"id\":\"e1\",\"type\":\"post.created\",\"data\":{\"id\":\"1\"}}\n\n")
+		fmt.Fprint(w, ": heartbeat\n\n")
+		fmt.Fprint(w, "id: 8\nevent: post.deleted\ndata: {\"id\":\"e2\",\"type\":\"post.deleted\",\"data\":{\"id\":\"1\"}}\n\n")
+	}), Options{})
+
+	var events []*Event
+	err := c.StreamEvents(context.Background(), StreamEventsOptions{Types: []string{EventPostCreated, EventPostDeleted}, LastEventID: "6"}, func(event *Event) error {
+		events = append(events, event)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "last_event_id=6&types=post.created%2Cpost.deleted", query)
+	require.Len(t, events, 2)
+	assert.Equal(t, "7", events[0].Sequence)
+	assert.Equal(t, EventPostCreated, events[0].Type)
+	assert.Equal(t, "1", events[0].Data.ID)
+	assert.Equal(t, "8", events[1].Sequence)
+
+	stop := errors.New("stop")
+	err = c.StreamEvents(context.Background(), StreamEventsOptions{}, func(*Event) error { return stop })
+	assert.ErrorIs(t, err, stop)
+}
+
+func TestClient_ConcurrentUse(t *testing.T) {
+	api := newTestAPI(t, nil)
+	c := newTestClient(t, api.handler, Options{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := c.CreatePost(context.Background(), &CreatePostRequest{Title: fmt.Sprintf("Post %d", i), Body: "Body"})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	posts, err := c.ListPosts(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, posts, 10)
+}
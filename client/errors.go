@@ -0,0 +1,90 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Errors matched by an *Error of the corresponding status, for use with errors.Is:
+//
+//	if errors.Is(err, client.ErrNotFound) { ... }
+var (
+	ErrBadRequest          = errors.New("bad request")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrForbidden           = errors.New("forbidden")
+	ErrNotFound            = errors.New("not found")
+	ErrConflict            = errors.New("conflict")
+	ErrUnprocessableEntity = errors.New("unprocessable entity")
+	ErrRateLimited         = errors.New("rate limited")
+	ErrServer              = errors.New("server error")
+)
+
+// Error is an error response of the API
+type Error struct {
+	// StatusCode is the HTTP status of the response
+	StatusCode int
+	// Title is the short description in the error field of the response, e.g. "Blog post not found"
+	Title string
+	// Message explains the error
+	Message string
+	// RetryAfter is how long to wait before retrying, as requested by a rate limited response
+	RetryAfter time.Duration
+	// Data holds the data field of responses that carry one, such as rolled back bulk requests
+	Data json.RawMessage
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" || e.Message == e.Title {
+		return fmt.Sprintf("blog api: %d %s", e.StatusCode, e.Title)
+	}
+	return fmt.Sprintf("blog api: %d %s: %s", e.StatusCode, e.Title, e.Message)
+}
+
+// Is reports whether the error matches one of the status errors of this package
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrUnprocessableEntity:
+		return e.StatusCode == http.StatusUnprocessableEntity
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// newError reads an error response and closes its body
+func newError(resp *http.Response) *Error {
+	defer resp.Body.Close()
+
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		Title:      http.StatusText(resp.StatusCode),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+
+	var env envelope
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if json.Unmarshal(body, &env) == nil {
+		if env.Error != "" {
+			apiErr.Title = env.Error
+		}
+		apiErr.Message = env.Message
+		apiErr.Data = env.Data
+	}
+	return apiErr
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// StreamEventsOptions selects the events to stream
+type StreamEventsOptions struct {
+	// Types only streams events of these types when set
+	Types []string
+	// Tag only streams events of posts with this tag when set
+	Tag string
+	// LastEventID resumes the stream after the event with this sequence, replaying missed events
+	LastEventID string
+}
+
+// StreamEvents streams post events to fn until ctx is done, fn returns an error or the server
+// ends the stream, in which case it returns nil. Pass the Sequence of the last received event
+// as LastEventID to resume without missing events. The HTTPClient must not have a timeout.
+func (c *Client) StreamEvents(ctx context.Context, options StreamEventsOptions, fn func(*Event) error) error {
+	query := url.Values{}
+	if len(options.Types) > 0 {
+		query.Set("types", strings.Join(options.Types, ","))
+	}
+	if options.Tag != "" {
+		query.Set("tag", options.Tag)
+	}
+	if options.LastEventID != "" {
+		query.Set("last_event_id", options.LastEventID)
+	}
+
+	resp, err := c.send(ctx, &request{method: http.MethodGet, path: "/api/events/stream", query: query, accept: "text/event-stream"})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	err = readEvents(resp.Body, fn)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// readEvents parses a text/event-stream body and calls fn for every event
+func readEvents(r io.Reader, fn func(*Event) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+
+	var id string
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			// A blank line dispatches the event; retry hints and heartbeats carry no data
+			if data.Len() > 0 {
+				event := &Event{Sequence: id}
+				if err := json.Unmarshal([]byte(data.String()), event); err != nil {
+					return fmt.Errorf("failed to decode event %s: %w", id, err)
+				}
+				if err := fn(event); err != nil {
+					return err
+				}
+			}
+			data.Reset()
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			id = value
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+		}
+	}
+	return scanner.Err()
+}
+
+// Health checks that the API is up
+func (c *Client) Health(ctx context.Context) error {
+	_, err := c.call(ctx, &request{method: http.MethodGet, path: "/health"}, nil)
+	return err
+}
@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ListPostsOptions selects a page of posts, newest first
+type ListPostsOptions struct {
+	// Limit is the page size, at most 100; 20 when zero
+	Limit int
+	// After is the NextCursor of the previous page
+	After string
+	// Status only lists posts in this publication state when set
+	Status string
+	// Tag only lists posts with this tag when set
+	Tag string
+}
+
+// PostPage is a page of posts. NextCursor continues the listing and is empty on the last page.
+type PostPage struct {
+	Posts      []Post
+	NextCursor string
+}
+
+// CreatePost creates a post
+func (c *Client) CreatePost(ctx context.Context, post *CreatePostRequest) (*Post, error) {
+	req, err := jsonRequest(http.MethodPost, "/api/blog-post", post)
+	if err != nil {
+		return nil, err
+	}
+	var created Post
+	if _, err := c.call(ctx, req, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetPost fetches a post by ID
+func (c *Client) GetPost(ctx context.Context, id string) (*Post, error) {
+	var post Post
+	if _, err := c.call(ctx, &request{method: http.MethodGet, path: "/api/blog-post/" + url.PathEscape(id)}, &post); err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+// ListPosts fetches every post at once. Use ListPostsPage or Posts for large blogs.
+func (c *Client) ListPosts(ctx context.Context) ([]Post, error) {
+	var posts []Post
+	if _, err := c.call(ctx, &request{method: http.MethodGet, path: "/api/blog-post"}, &posts); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// ListPostsPage fetches a single page of posts
+func (c *Client) ListPostsPage(ctx context.Context, options ListPostsOptions) (*PostPage, error) {
+	query := url.Values{}
+	// The limit is always sent so that the server answers with a page
+	limit := options.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	query.Set("limit", strconv.Itoa(limit))
+	if options.After != "" {
+		query.Set("after", options.After)
+	}
+	if options.Status != "" {
+		query.Set("status", options.Status)
+	}
+	if options.Tag != "" {
+		query.Set("tag", options.Tag)
+	}
+
+	page := &PostPage{}
+	env, err := c.call(ctx, &request{method: http.MethodGet, path: "/api/blog-post", query: query}, &page.Posts)
+	if err != nil {
+		return nil, err
+	}
+	page.NextCursor = env.NextCursor
+	return page, nil
+}
+
+// Posts iterates over the posts matching options, fetching the pages as they are needed.
+// Iteration stops after the first error.
+//
+//	for post, err := range c.Posts(ctx, client.ListPostsOptions{Tag: "go"}) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(post.Title)
+//	}
+func (c *Client) Posts(ctx context.Context, options ListPostsOptions) iter.Seq2[*Post, error] {
+	return func(yield func(*Post, error) bool) {
+		for {
+			page, err := c.ListPostsPage(ctx, options)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for i := range page.Posts {
+				if !yield(&page.Posts[i], nil) {
+					return
+				}
+			}
+			if page.NextCursor == "" {
+				return
+			}
+			options.After = page.NextCursor
+		}
+	}
+}
+
+// UpdatePost changes the fields set in update
+func (c *Client) UpdatePost(ctx context.Context, id string, update *UpdatePostRequest) (*Post, error) {
+	req, err := jsonRequest(http.MethodPatch, "/api/blog-post/"+url.PathEscape(id), update)
+	if err != nil {
+		return nil, err
+	}
+	var post Post
+	if _, err := c.call(ctx, req, &post); err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+// DeletePost deletes a post
+func (c *Client) DeletePost(ctx context.Context, id string) error {
+	_, err := c.call(ctx, &request{method: http.MethodDelete, path: "/api/blog-post/" + url.PathEscape(id)}, nil)
+	return err
+}
+
+// BulkPosts applies a batch of operations. When an atomic batch is rolled back, the error is
+// returned together with the response reporting which operation failed.
+func (c *Client) BulkPosts(ctx context.Context, bulk *BulkRequest) (*BulkResponse, error) {
+	req, err := jsonRequest(http.MethodPost, "/api/blog-post/bulk", bulk)
+	if err != nil {
+		return nil, err
+	}
+	var response BulkResponse
+	if _, err := c.call(ctx, req, &response); err != nil {
+		var apiErr *Error
+		if errors.As(err, &apiErr) && len(apiErr.Data) > 0 && json.Unmarshal(apiErr.Data, &response) == nil {
+			return &response, err
+		}
+		return nil, err
+	}
+	return &response, nil
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ImportOptions configures an import
+type ImportOptions struct {
+	// Format of the data; FormatJSONL when empty
+	Format string
+	// DryRun reports what the import would do without writing anything
+	DryRun bool
+}
+
+// Export streams every post in format (FormatJSONL or FormatCSV). The caller must close the returned reader.
+func (c *Client) Export(ctx context.Context, format string) (io.ReadCloser, error) {
+	query := url.Values{}
+	if format != "" {
+		query.Set("format", format)
+	}
+	resp, err := c.send(ctx, &request{method: http.MethodGet, path: "/api/export", query: query, accept: "*/*"})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Import creates and updates posts from r. The data is read into memory first so that the
+// request can be retried.
+func (c *Client) Import(ctx context.Context, r io.Reader, options ImportOptions) (*ImportReport, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import data: %w", err)
+	}
+
+	query := url.Values{}
+	if options.Format != "" {
+		query.Set("format", options.Format)
+	}
+	if options.DryRun {
+		query.Set("dry_run", strconv.FormatBool(true))
+	}
+
+	var report ImportReport
+	req := &request{method: http.MethodPost, path: "/api/import", query: query, body: body, contentType: "application/octet-stream"}
+	if _, err := c.call(ctx, req, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
@@ -0,0 +1,195 @@
+package client
+
+import "time"
+
+// Publication states of a post
+const (
+	StatusDraft     = "draft"
+	StatusPublished = "published"
+)
+
+// Post is a blog post
+type Post struct {
+	ID          string     `json:"id"`
+	Slug        string     `json:"slug"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Body        string     `json:"body"`
+	Tags        []string   `json:"tags"`
+	Status      string     `json:"status"`
+	AuthorID    string     `json:"author_id,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// CreatePostRequest holds the fields of a new post. The slug is derived from the title when empty.
+type CreatePostRequest struct {
+	Slug        string   `json:"slug,omitempty"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Body        string   `json:"body"`
+	Tags        []string `json:"tags,omitempty"`
+	Status      string   `json:"status,omitempty"`
+}
+
+// UpdatePostRequest holds the fields to change; nil fields are left as they are
+type UpdatePostRequest struct {
+	Slug        *string   `json:"slug,omitempty"`
+	Title       *string   `json:"title,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Body        *string   `json:"body,omitempty"`
+	Tags        *[]string `json:"tags,omitempty"`
+	Status      *string   `json:"status,omitempty"`
+}
+
+// Bulk operation actions
+const (
+	BulkActionCreate = "create"
+	BulkActionUpdate = "update"
+	BulkActionDelete = "delete"
+)
+
+// Bulk execution modes
+const (
+	BulkModeAtomic  = "atomic"
+	BulkModePartial = "partial"
+)
+
+// BulkRequest is a batch of create, update and delete operations
+type BulkRequest struct {
+	Mode       string          `json:"mode,omitempty"`
+	Operations []BulkOperation `json:"operations"`
+}
+
+// BulkOperation is a single operation of a bulk request. Data is a *CreatePostRequest for
+// creates and an *UpdatePostRequest for updates.
+type BulkOperation struct {
+	Action string      `json:"action"`
+	ID     string      `json:"id,omitempty"`
+	Data   interface{} `json:"data,omitempty"`
+}
+
+// BulkItemResult is the outcome of a single bulk operation
+type BulkItemResult struct {
+	Index  int    `json:"index"`
+	Action string `json:"action"`
+	ID     string `json:"id,omitempty"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Data   *Post  `json:"data,omitempty"`
+}
+
+// BulkResponse reports the outcome of a bulk request
+type BulkResponse struct {
+	Mode      string           `json:"mode"`
+	Committed bool             `json:"committed"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
+}
+
+// Transfer formats. Export supports JSONL and CSV; import also reads WordPress WXR and
+// gzipped tarballs of Markdown files.
+const (
+	FormatJSONL    = "jsonl"
+	FormatCSV      = "csv"
+	FormatWXR      = "wxr"
+	FormatMarkdown = "markdown"
+)
+
+// ImportItemResult is the outcome of importing a single record
+type ImportItemResult struct {
+	Line   int    `json:"line,omitempty"`
+	Source string `json:"source,omitempty"`
+	ID     string `json:"id,omitempty"`
+	Slug   string `json:"slug,omitempty"`
+	Action string `json:"action"`
+	Reason string `json:"reason,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ImportReport summarizes an import run
+type ImportReport struct {
+	Format  string             `json:"format"`
+	DryRun  bool               `json:"dry_run"`
+	Total   int                `json:"total"`
+	Created int                `json:"created"`
+	Updated int                `json:"updated"`
+	Skipped int                `json:"skipped"`
+	Failed  int                `json:"failed"`
+	Items   []ImportItemResult `json:"items"`
+}
+
+// Webhook is a subscription that receives post events over HTTP. Secret is only set
+// when the webhook was just created or its secret rotated.
+type Webhook struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	Description string    `json:"description"`
+	Events      []string  `json:"events"`
+	Active      bool      `json:"active"`
+	Secret      string    `json:"secret,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CreateWebhookRequest holds the fields of a new webhook. A secret is generated when empty.
+type CreateWebhookRequest struct {
+	URL         string   `json:"url"`
+	Description string   `json:"description"`
+	Events      []string `json:"events"`
+	Secret      string   `json:"secret,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+}
+
+// UpdateWebhookRequest holds the webhook fields to change; nil fields are left as they are
+type UpdateWebhookRequest struct {
+	URL         *string   `json:"url,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Events      *[]string `json:"events,omitempty"`
+	Secret      *string   `json:"secret,omitempty"`
+	Active      *bool     `json:"active,omitempty"`
+}
+
+// Webhook delivery states
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSucceeded = "succeeded"
+	DeliveryStatusDead      = "dead"
+)
+
+// Delivery is an event queued for a webhook together with the outcome of its latest attempt
+type Delivery struct {
+	ID             string     `json:"id"`
+	WebhookID      string     `json:"webhook_id"`
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty"`
+	ResponseStatus int        `json:"response_status,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// Post event types
+const (
+	EventPostCreated   = "post.created"
+	EventPostUpdated   = "post.updated"
+	EventPostDeleted   = "post.deleted"
+	EventPostPublished = "post.published"
+)
+
+// Event is a change to a post received from the event stream. Data holds the post after the
+// change; for post.deleted it only carries the post ID.
+type Event struct {
+	// Sequence is the position of the event in the stream, used to resume after it
+	Sequence   string    `json:"-"`
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       *Post     `json:"data"`
+}
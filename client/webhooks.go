@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ListDeliveriesOptions selects webhook deliveries, most recent first
+type ListDeliveriesOptions struct {
+	// WebhookID only lists the deliveries of this webhook when set
+	WebhookID string
+	// Status only lists deliveries in this state when set, e.g. DeliveryStatusDead
+	Status string
+	// Limit caps the number of deliveries; the server default when zero
+	Limit int
+}
+
+// CreateWebhook subscribes a URL to post events. The returned webhook carries its signing secret.
+func (c *Client) CreateWebhook(ctx context.Context, webhook *CreateWebhookRequest) (*Webhook, error) {
+	req, err := jsonRequest(http.MethodPost, "/api/webhooks", webhook)
+	if err != nil {
+		return nil, err
+	}
+	var created Webhook
+	if _, err := c.call(ctx, req, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// ListWebhooks fetches every webhook
+func (c *Client) ListWebhooks(ctx context.Context) ([]Webhook, error) {
+	var webhooks []Webhook
+	if _, err := c.call(ctx, &request{method: http.MethodGet, path: "/api/webhooks"}, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// GetWebhook fetches a webhook by ID
+func (c *Client) GetWebhook(ctx context.Context, id string) (*Webhook, error) {
+	var webhook Webhook
+	if _, err := c.call(ctx, &request{method: http.MethodGet, path: "/api/webhooks/" + url.PathEscape(id)}, &webhook); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// UpdateWebhook changes the fields set in update
+func (c *Client) UpdateWebhook(ctx context.Context, id string, update *UpdateWebhookRequest) (*Webhook, error) {
+	req, err := jsonRequest(http.MethodPatch, "/api/webhooks/"+url.PathEscape(id), update)
+	if err != nil {
+		return nil, err
+	}
+	var webhook Webhook
+	if _, err := c.call(ctx, req, &webhook); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// DeleteWebhook deletes a webhook
+func (c *Client) DeleteWebhook(ctx context.Context, id string) error {
+	_, err := c.call(ctx, &request{method: http.MethodDelete, path: "/api/webhooks/" + url.PathEscape(id)}, nil)
+	return err
+}
+
+// ListDeliveries fetches webhook deliveries
+func (c *Client) ListDeliveries(ctx context.Context, options ListDeliveriesOptions) ([]Delivery, error) {
+	path := "/api/webhooks/deliveries"
+	if options.WebhookID != "" {
+		path = "/api/webhooks/" + url.PathEscape(options.WebhookID) + "/deliveries"
+	}
+	query := url.Values{}
+	if options.Status != "" {
+		query.Set("status", options.Status)
+	}
+	if options.Limit > 0 {
+		query.Set("limit", strconv.Itoa(options.Limit))
+	}
+
+	var deliveries []Delivery
+	if _, err := c.call(ctx, &request{method: http.MethodGet, path: path, query: query}, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// RetryDelivery queues a delivery, typically a dead letter, for another attempt
+func (c *Client) RetryDelivery(ctx context.Context, id string) (*Delivery, error) {
+	var delivery Delivery
+	req := &request{method: http.MethodPost, path: "/api/webhooks/deliveries/" + url.PathEscape(id) + "/retry"}
+	if _, err := c.call(ctx, req, &delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
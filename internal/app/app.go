@@ -0,0 +1,348 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"BlogManagment/api/blogpb"
+	"BlogManagment/internal/auth"
+	"BlogManagment/internal/cache"
+	"BlogManagment/internal/config"
+	"BlogManagment/internal/controller"
+	"BlogManagment/internal/gql"
+	"BlogManagment/internal/grpcapi"
+	"BlogManagment/internal/mail"
+	"BlogManagment/internal/middleware"
+	"BlogManagment/internal/outbox"
+	"BlogManagment/internal/ratelimit"
+	"BlogManagment/internal/repository"
+	"BlogManagment/internal/routes"
+	"BlogManagment/internal/service"
+	"BlogManagment/internal/stream"
+	"BlogManagment/internal/tenant"
+	"BlogManagment/internal/webhook"
+	"BlogManagment/internal/worker"
+
+	_ "BlogManagment/docs"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/gofiber/swagger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	"gorm.io/gorm"
+)
+
+// App holds the repositories and services of the blog platform on one database. The API server
+// and the maintenance commands are both built from it.
+type App struct {
+	db         *gorm.DB
+	config     *config.AppConfig
+	blogCache  *service.BlogCache
+	replicaSet *repository.ReplicaSet
+
+	webhookRepo repository.WebhookRepository
+	tenantRepo  repository.TenantRepository
+	roleRepo    repository.RoleRepository
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+
+	BlogService     service.BlogService
+	TransferService service.BlogTransferService
+	WebhookService  service.WebhookService
+	TenantService   service.TenantService
+	RoleService     service.RoleService
+	APIKeyService   service.APIKeyService
+	AuditService    service.AuditService
+	// AccountService is nil unless JWT_SECRET is set, as accounts sign in with tokens it signs
+	AccountService     service.AccountService
+	MaintenanceService service.MaintenanceService
+}
+
+// New wires the repositories and services of the platform to a connected database
+func New(db *gorm.DB, cfg *config.AppConfig) (*App, error) {
+	dbConfig := cfg.Database
+	a := &App{
+		db:          db,
+		config:      cfg,
+		webhookRepo: repository.NewWebhookRepository(db),
+		tenantRepo:  repository.NewTenantRepository(db),
+		roleRepo:    repository.NewRoleRepository(db),
+		userRepo:    repository.NewUserRepository(db),
+		sessionRepo: repository.NewSessionRepository(db),
+	}
+
+	// Posts kept in memory are lost on restart; their events and audit entries still go to the
+	// database, so webhooks, stream clients and the audit log see them. Maintenance works on
+	// the posts that are served.
+	blogRepo := repository.NewBlogRepository(db)
+	maintenanceRepo := repository.NewMaintenanceRepository(db)
+	switch {
+	case dbConfig.BlogStore == config.BlogStoreMemory:
+		log.Println("BLOG_STORE is memory, blog posts are not persisted")
+		blogRepo = repository.NewMemoryBlogRepositoryWithOutbox(blogRepo)
+		maintenanceRepo = repository.NewMemoryMaintenanceRepository(blogRepo)
+	case len(dbConfig.ReplicaURLs) > 0:
+		// Post reads go to the replicas that keep up with the primary
+		replicas, err := dbConfig.ConnectReplicas()
+		if err != nil {
+			return nil, err
+		}
+		a.replicaSet = repository.NewReplicaSet(db, replicas, dbConfig.ReplicaMaxLag, dbConfig.ReadYourWritesWindow)
+		if err := a.replicaSet.Check(context.Background()); err != nil {
+			return nil, err
+		}
+		blogRepo = repository.NewReplicatedBlogRepository(a.replicaSet)
+	}
+
+	// Serve post reads from memory; changes made through the service drop what they affect
+	a.BlogService = service.NewBlogService(blogRepo)
+	if cfg.Cache.Enabled {
+		a.blogCache = service.NewBlogCache(cache.NewLRU(cfg.Cache.Size), cfg.Cache.TTL)
+		a.BlogService = service.WithCache(a.BlogService, a.blogCache)
+	}
+
+	a.WebhookService = service.NewWebhookService(a.webhookRepo)
+	a.TransferService = service.NewBlogTransferService(blogRepo)
+	a.TenantService = service.NewTenantService(a.tenantRepo)
+	a.RoleService = service.NewRoleService(a.roleRepo)
+	a.AuditService = service.NewAuditService(repository.NewAuditRepository(db))
+	a.APIKeyService = service.NewAPIKeyService(repository.NewAPIKeyRepository(db), cfg.APIKey.DefaultTTL, cfg.APIKey.RotationGrace)
+	a.MaintenanceService = service.NewMaintenanceService(maintenanceRepo, a.BlogService)
+
+	// Sign local accounts in with access tokens signed by the same secret the verifier checks
+	if cfg.Auth.JWTSecret != "" {
+		mailer, err := mail.New(cfg.Mail.Driver, cfg.Mail.Dir, cfg.Mail.From)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up mail: %w", err)
+		}
+		accountConfig := cfg.Account
+		a.AccountService = service.NewAccountService(a.userRepo, a.sessionRepo, auth.NewJWTIssuer(cfg.Auth.JWTSecret, accountConfig.AccessTokenTTL), mailer, service.AccountPolicy{
+			RefreshTokenTTL:  accountConfig.RefreshTokenTTL,
+			MaxFailedLogins:  accountConfig.MaxFailedLogins,
+			LockoutDuration:  accountConfig.LockoutDuration,
+			PasswordResetTTL: accountConfig.PasswordResetTTL,
+			PasswordResetURL: accountConfig.PasswordResetURL,
+			TOTPIssuer:       accountConfig.TOTPIssuer,
+		})
+	}
+	return a, nil
+}
+
+// Server is the API of an App: the HTTP app and the gRPC server, ready to be started on
+// listeners of the caller's choice
+type Server struct {
+	HTTP *fiber.App
+	GRPC *grpc.Server
+}
+
+// NewServer builds the HTTP and gRPC APIs as configured and starts the background workers they
+// rely on: rate limit and idempotency key cleanup, webhook delivery, the outbox relay and the
+// event stream. The workers stop when ctx is cancelled.
+func (a *App) NewServer(ctx context.Context) (*Server, error) {
+	// Initialize rate limiting
+	rateLimitConfig := a.config.RateLimit
+	var rateLimitStore ratelimit.Store
+	if rateLimitConfig.Store == config.RateLimitStorePostgres {
+		rateLimitStore = ratelimit.NewPostgresStore(a.db, rateLimitConfig.IdleTTL)
+	} else {
+		rateLimitStore = ratelimit.NewMemoryStore(rateLimitConfig.IdleTTL)
+	}
+	worker.RunPeriodically(ctx, "Rate limit cleanup", time.Minute, func(ctx context.Context) error {
+		return rateLimitStore.Cleanup(ctx, time.Now())
+	})
+	rateLimiter := middleware.NewRateLimiter(rateLimitStore, rateLimitConfig)
+
+	// Initialize idempotency key storage
+	idempotencyConfig := a.config.Idempotency
+	idempotencyRepo := repository.NewIdempotencyRepository(a.db)
+	worker.RunPeriodically(ctx, "Idempotency key cleanup", time.Hour, func(ctx context.Context) error {
+		_, err := idempotencyRepo.DeleteExpired(time.Now())
+		return err
+	})
+
+	// Deliver queued webhook events in the background
+	webhookConfig := a.config.Webhook
+	dispatcher := webhook.NewDispatcher(a.webhookRepo, webhookConfig)
+	worker.RunPeriodically(ctx, "Webhook delivery", webhookConfig.PollInterval, dispatcher.RunOnce)
+
+	// Relay post events from the outbox; webhooks are queued by one of the sinks
+	outboxConfig := a.config.Outbox
+	sinks := []outbox.Sink{outbox.NewPublisherSink("webhooks", a.WebhookService)}
+	if outboxConfig.LogEvents {
+		sinks = append(sinks, outbox.NewLogSink(nil))
+	}
+	if outboxConfig.HTTPURL != "" {
+		sinks = append(sinks, outbox.NewHTTPSink(outboxConfig.HTTPURL, outboxConfig.HTTPSecret, outboxConfig.HTTPTimeout))
+	}
+	outboxRepo := repository.NewOutboxRepository(a.db)
+	relay := outbox.NewRelay(outboxRepo, outboxConfig.BatchSize, sinks...)
+	worker.RunPeriodically(ctx, "Outbox relay", outboxConfig.PollInterval, relay.RunOnce)
+	worker.RunPeriodically(ctx, "Outbox cleanup", time.Hour, func(ctx context.Context) error {
+		_, err := relay.Prune(outboxConfig.Retention)
+		return err
+	})
+
+	// Push outbox events to stream clients as soon as any replica commits them. SQLite has no
+	// LISTEN/NOTIFY, but only one process can write to it, so polling the outbox is cheap.
+	broker := stream.NewBroker(outboxRepo)
+	if err := broker.Poll(); err != nil {
+		return nil, fmt.Errorf("failed to start event stream: %w", err)
+	}
+	if a.config.Database.Driver == config.DatabaseDriverSQLite {
+		worker.RunPeriodically(ctx, "Event stream poll", time.Second, func(ctx context.Context) error {
+			return broker.Poll()
+		})
+	} else {
+		go broker.Listen(ctx, a.config.Database.DSN())
+	}
+
+	// Measure the lag of the read replicas, taking those that fall behind out of rotation
+	var replicaLag time.Duration
+	if a.replicaSet != nil {
+		worker.RunPeriodically(ctx, "Replica health check", a.config.Database.ReplicaCheckInterval, a.replicaSet.Check)
+		replicaLag = a.config.Database.ReadYourWritesWindow
+	}
+
+	// Posts changed by imports or by other replicas reach the cache through the event stream
+	if a.blogCache != nil {
+		go invalidateOnEvents(ctx, broker, a.blogCache, replicaLag)
+	}
+
+	// Scope every request to the tenant named by its credentials, header or subdomain
+	tenantConfig := a.config.Tenant
+	tenantResolver := tenant.NewResolver(a.tenantRepo, tenantConfig.BaseDomain)
+
+	// Serve the GraphQL API with a limit on the estimated cost of each operation
+	graphqlConfig := a.config.GraphQL
+	graphqlExecutor, err := gql.NewExecutor(a.BlogService, graphqlConfig.MaxCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up GraphQL: %w", err)
+	}
+
+	// Resolve the caller from API keys, and from bearer tokens when JWT authentication is configured
+	authConfig := a.config.Auth
+	var jwtVerifier auth.Verifier
+	if authConfig.JWTSecret != "" {
+		jwtVerifier = auth.NewJWTVerifier(authConfig.JWTSecret)
+	}
+
+	// Restrict callers to the permissions of their roles when role-based access control is enabled,
+	// and otherwise let every authenticated caller change posts; anonymous callers may only read.
+	// Users only hold the roles that need a second factor when they signed in with one.
+	authorizer := middleware.NewAuthorizer(a.roleRepo, authConfig.RBACEnabled, authConfig.TwoFactorRoles...)
+
+	// Serve the gRPC API, authenticating calls and checking their permissions like HTTP requests
+	grpcAuthenticator := grpcapi.NewAuthenticator(jwtVerifier, a.APIKeyService, tenantResolver, !authConfig.RBACEnabled, authorizer)
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(grpcAuthenticator.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(grpcAuthenticator.StreamInterceptor()),
+	)
+	blogpb.RegisterBlogServiceServer(grpcServer, grpcapi.NewServer(a.BlogService, broker))
+	reflection.Register(grpcServer)
+
+	// Serve the account routes when accounts are enabled
+	var authController *controller.AuthController
+	if a.AccountService != nil {
+		authController = controller.NewAuthController(a.AccountService)
+	} else {
+		log.Println("JWT_SECRET is not set, account routes under /api/auth are disabled")
+	}
+
+	// Initialize controller layer
+	blogController := controller.NewBlogController(a.BlogService)
+	transferController := controller.NewTransferController(a.TransferService)
+	webhookController := controller.NewWebhookController(a.WebhookService)
+	eventController := controller.NewEventController(broker)
+	graphqlController := controller.NewGraphQLController(graphqlExecutor)
+	roleController := controller.NewRoleController(a.RoleService)
+	apiKeyController := controller.NewAPIKeyController(a.APIKeyService)
+	auditController := controller.NewAuditController(a.AuditService)
+	var cacheController *controller.CacheController
+	if a.blogCache != nil {
+		cacheController = controller.NewCacheController(a.blogCache)
+	}
+
+	// Create Fiber app
+	app := fiber.New(fiber.Config{
+		ErrorHandler: middleware.ErrorHandler(),
+		AppName:      "Blog Management API",
+	})
+
+	// Add global middleware
+	app.Use(recover.New())
+	app.Use(requestid.New())
+	app.Use(cors.New(cors.Config{
+		AllowOrigins:  "*",
+		AllowHeaders:  "Origin, Content-Type, Accept, Authorization, Idempotency-Key, Last-Event-ID, X-Request-ID, " + tenantConfig.Header,
+		AllowMethods:  "GET, POST, PUT, PATCH, DELETE",
+		ExposeHeaders: "RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, RateLimit-Policy, Retry-After, Idempotent-Replayed, X-Request-ID",
+	}))
+
+	// Resolve the caller from API keys, and from bearer tokens when JWT authentication is configured
+	app.Use(middleware.Authenticate(jwtVerifier, a.APIKeyService))
+	app.Use(middleware.IdentifyRequester())
+	// Without role-based access control nothing restricts callers to their tenants but the claim of their credentials
+	app.Use(middleware.ResolveTenant(tenantResolver, tenantConfig.Header, !authConfig.RBACEnabled))
+
+	// Swagger documentation
+	app.Get("/swagger/*", swagger.HandlerDefault)
+
+	// Setup routes
+	routes.SetupRoutes(app, routes.Deps{
+		Blog:         blogController,
+		Transfer:     transferController,
+		Webhook:      webhookController,
+		Event:        eventController,
+		GraphQL:      graphqlController,
+		Role:         roleController,
+		APIKey:       apiKeyController,
+		Audit:        auditController,
+		Auth:         authController,
+		Cache:        cacheController,
+		RateLimiter:  rateLimiter,
+		Authorizer:   authorizer,
+		Idempotency:  middleware.Idempotency(idempotencyRepo, idempotencyConfig.TTL),
+		CacheControl: middleware.CacheControl(a.config.Cache.MaxAge),
+
+		// Without RBAC every signed in user may change every post, so only admins create accounts;
+		// with it, accounts that register themselves hold no role until an admin assigns one
+		OpenRegistration: authConfig.RBACEnabled,
+	})
+
+	return &Server{HTTP: app, GRPC: grpcServer}, nil
+}
+
+// invalidateOnEvents drops the cached copies of the posts named by every event of the stream
+// until ctx is cancelled. A subscription that falls behind may have missed changes, so the
+// whole cache is dropped before subscribing again. With read replicas, a post read from a
+// replica that had not caught up yet may have been cached in the meantime, so the copies are
+// dropped once more after replicaLag.
+func invalidateOnEvents(ctx context.Context, broker *stream.Broker, blogCache *service.BlogCache, replicaLag time.Duration) {
+	for {
+		subscription := broker.Subscribe(stream.Filter{})
+		for open := true; open; {
+			select {
+			case <-ctx.Done():
+				broker.Unsubscribe(subscription)
+				return
+			case message, ok := <-subscription.C:
+				if !ok {
+					open = false
+				} else if message.Event.Data != nil {
+					tenantID, id := message.Event.Tenant(), message.Event.Data.ID
+					blogCache.Invalidate(tenantID, id)
+					if replicaLag > 0 {
+						time.AfterFunc(replicaLag, func() { blogCache.Invalidate(tenantID, id) })
+					}
+				}
+			}
+		}
+		log.Println("Blog cache fell behind the event stream, dropping every cached post")
+		blogCache.Clear()
+	}
+}
@@ -0,0 +1,854 @@
+package app_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"BlogManagment/internal/app"
+	"BlogManagment/internal/auth"
+	"BlogManagment/internal/config"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testServer is the full application served over HTTP on an SQLite database of its own
+type testServer struct {
+	app     *app.App
+	url     string
+	mailDir string
+	// authorization is the Authorization header of the requests, by default that of an admin API key
+	authorization string
+}
+
+// newTestServer starts the application configured from the environment, with fast background
+// workers, accounts enabled and env applied on top
+func newTestServer(t *testing.T, env map[string]string) *testServer {
+	t.Helper()
+	server := &testServer{mailDir: t.TempDir()}
+	defaults := map[string]string{
+		"DB_DRIVER":             config.DatabaseDriverSQLite,
+		"DB_PATH":               filepath.Join(t.TempDir(), "blog.db"),
+		"DB_LOG_LEVEL":          "silent",
+		"BLOG_STORE":            config.BlogStoreDatabase,
+		"JWT_SECRET":            "integration-test-secret",
+		"MAIL_DRIVER":           "file",
+		"MAIL_DIR":              server.mailDir,
+		"OUTBOX_POLL_INTERVAL":  "50ms",
+		"WEBHOOK_POLL_INTERVAL": "50ms",
+		"RBAC_ENABLED":          "false",
+	}
+	for key, value := range defaults {
+		t.Setenv(key, value)
+	}
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+
+	cfg, _, err := config.Load("blog-api", nil, io.Discard)
+	require.NoError(t, err)
+	db, err := cfg.Database.Connect()
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	server.app, err = app.New(db, cfg)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	api, err := server.app.NewServer(ctx)
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go api.HTTP.Listener(listener)
+	t.Cleanup(func() { api.HTTP.ShutdownWithTimeout(time.Second) })
+	server.url = "http://" + listener.Addr().String()
+
+	key, err := server.app.APIKeyService.CreateAPIKey(models.DefaultTenantID, rbac.Unrestricted(""), &models.APIKeyCreateRequest{
+		Name: "Integration tests", Scopes: []string{rbac.RoleAdmin},
+	})
+	require.NoError(t, err)
+	server.authorization = "ApiKey " + key.Key
+	return server
+}
+
+// anonymous returns the server for requests without credentials
+func (s *testServer) anonymous() *testServer {
+	anonymous := *s
+	anonymous.authorization = ""
+	return &anonymous
+}
+
+// apiResponse is a response of the API with its JSON envelope decoded
+type apiResponse struct {
+	Status  int             `json:"-"`
+	Header  http.Header     `json:"-"`
+	Body    []byte          `json:"-"`
+	Error   string          `json:"error"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+	Count   int             `json:"count"`
+}
+
+// decode decodes the data of the response
+func (r *apiResponse) decode(t *testing.T, v interface{}) {
+	t.Helper()
+	require.NoError(t, json.Unmarshal(r.Data, v), "data of %s", r.Body)
+}
+
+// request sends a request with a JSON body, unless body is a string, and decodes the response.
+// headers are given as name, value pairs and replace the credentials of the server.
+func (s *testServer) request(t *testing.T, method, path string, body interface{}, headers ...string) *apiResponse {
+	t.Helper()
+	var reader io.Reader
+	contentType := "application/json"
+	switch body := body.(type) {
+	case nil:
+	case string:
+		reader = strings.NewReader(body)
+		contentType = "text/plain"
+	default:
+		encoded, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(encoded)
+	}
+
+	request, err := http.NewRequest(method, s.url+path, reader)
+	require.NoError(t, err)
+	if reader != nil {
+		request.Header.Set("Content-Type", contentType)
+	}
+	if s.authorization != "" {
+		request.Header.Set("Authorization", s.authorization)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		request.Header.Set(headers[i], headers[i+1])
+	}
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	result := &apiResponse{Status: response.StatusCode, Header: response.Header}
+	result.Body, err = io.ReadAll(response.Body)
+	require.NoError(t, err)
+	if strings.HasPrefix(response.Header.Get("Content-Type"), "application/json") {
+		require.NoError(t, json.Unmarshal(result.Body, result), "body %s", result.Body)
+	}
+	return result
+}
+
+// createPost creates a post through the API
+func (s *testServer) createPost(t *testing.T, title string, tags ...string) models.BlogResponse {
+	t.Helper()
+	response := s.request(t, http.MethodPost, "/api/blog-post", models.BlogCreateRequest{Title: title, Body: "Body of " + title, Tags: tags})
+	require.Equal(t, http.StatusCreated, response.Status, "%s", response.Body)
+	var post models.BlogResponse
+	response.decode(t, &post)
+	return post
+}
+
+func TestServer_Health(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	response := server.request(t, http.MethodGet, "/health", nil)
+	assert.Equal(t, http.StatusOK, response.Status)
+	assert.Equal(t, "Blog Management API is running", response.Message)
+	assert.NotEmpty(t, response.Header.Get("X-Request-ID"))
+
+	response = server.request(t, http.MethodGet, "/swagger/doc.json", nil)
+	assert.Equal(t, http.StatusOK, response.Status)
+	assert.Contains(t, string(response.Body), "Blog Management API")
+}
+
+func TestServer_NotFound(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	for _, path := range []string{"/missing", "/api/missing", "/api/blog-post/a/b"} {
+		response := server.request(t, http.MethodGet, path, nil)
+		assert.Equal(t, http.StatusNotFound, response.Status, path)
+		assert.Equal(t, "Not Found", response.Error, path)
+		assert.Equal(t, "The requested endpoint does not exist", response.Message, path)
+	}
+}
+
+func TestServer_CORS(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	t.Run("preflight", func(t *testing.T) {
+		response := server.request(t, http.MethodOptions, "/api/blog-post", nil,
+			"Origin", "https://editor.example.com",
+			"Access-Control-Request-Method", http.MethodPatch,
+			"Access-Control-Request-Headers", "Idempotency-Key, X-Tenant-ID")
+		assert.Equal(t, http.StatusNoContent, response.Status)
+		assert.Equal(t, "*", response.Header.Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, response.Header.Get("Access-Control-Allow-Methods"), http.MethodPatch)
+		assert.Contains(t, response.Header.Get("Access-Control-Allow-Headers"), "Idempotency-Key")
+		assert.Contains(t, response.Header.Get("Access-Control-Allow-Headers"), "X-Tenant-ID")
+	})
+
+	t.Run("simple request", func(t *testing.T) {
+		response := server.request(t, http.MethodGet, "/api/blog-post", nil, "Origin", "https://editor.example.com")
+		assert.Equal(t, http.StatusOK, response.Status)
+		assert.Equal(t, "*", response.Header.Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, response.Header.Get("Access-Control-Expose-Headers"), "RateLimit-Remaining")
+		assert.NotEmpty(t, response.Header.Get("RateLimit-Remaining"))
+	})
+}
+
+func TestServer_ErrorHandler(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	// The body is refused before it is read, so only the headers are sent
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.url, "http://"))
+	require.NoError(t, err)
+	defer conn.Close()
+	fmt.Fprintf(conn, "POST /api/blog-post HTTP/1.1\r\nHost: localhost\r\nContent-Type: application/json\r\nContent-Length: %d\r\n\r\n", 64<<20)
+
+	response, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	var body map[string]string
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, response.StatusCode)
+	assert.Equal(t, map[string]string{"error": "Request failed", "message": "Request Entity Too Large"}, body)
+}
+
+func TestServer_Posts(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	post := server.createPost(t, "Hello world", "go")
+	assert.Equal(t, "hello-world", post.Slug)
+
+	t.Run("read", func(t *testing.T) {
+		response := server.request(t, http.MethodGet, "/api/blog-post/"+post.ID, nil)
+		require.Equal(t, http.StatusOK, response.Status)
+		var stored models.BlogResponse
+		response.decode(t, &stored)
+		assert.Equal(t, post.Title, stored.Title)
+
+		response = server.request(t, http.MethodGet, "/api/blog-post?tag=go", nil)
+		require.Equal(t, http.StatusOK, response.Status)
+		assert.Equal(t, 1, response.Count)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		response := server.request(t, http.MethodPatch, "/api/blog-post/"+post.ID, map[string]interface{}{"title": "Hello again", "tags": []string{"fiber"}})
+		require.Equal(t, http.StatusOK, response.Status, "%s", response.Body)
+		var updated models.BlogResponse
+		response.decode(t, &updated)
+		assert.Equal(t, "Hello again", updated.Title)
+		assert.Equal(t, []string{"fiber"}, updated.Tags)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		response := server.request(t, http.MethodPost, "/api/blog-post", map[string]string{"body": "No title"})
+		assert.Equal(t, http.StatusBadRequest, response.Status)
+	})
+
+	t.Run("idempotent retry", func(t *testing.T) {
+		request := models.BlogCreateRequest{Title: "Once", Body: "Only created once"}
+		first := server.request(t, http.MethodPost, "/api/blog-post", request, "Idempotency-Key", "create-once")
+		require.Equal(t, http.StatusCreated, first.Status)
+		retry := server.request(t, http.MethodPost, "/api/blog-post", request, "Idempotency-Key", "create-once")
+		assert.Equal(t, http.StatusCreated, retry.Status)
+		assert.Equal(t, "true", retry.Header.Get("Idempotent-Replayed"))
+		assert.JSONEq(t, string(first.Data), string(retry.Data))
+	})
+
+	t.Run("bulk", func(t *testing.T) {
+		response := server.request(t, http.MethodPost, "/api/blog-post/bulk", map[string]interface{}{
+			"mode": models.BulkModeAtomic,
+			"operations": []map[string]interface{}{
+				{"action": "create", "data": map[string]string{"title": "Bulk", "body": "Created in bulk"}},
+				{"action": "update", "id": post.ID, "data": map[string]string{"description": "Updated in bulk"}},
+			},
+		})
+		require.Equal(t, http.StatusOK, response.Status, "%s", response.Body)
+		var result models.BlogBulkResponse
+		response.decode(t, &result)
+		assert.True(t, result.Committed)
+		assert.Equal(t, 2, result.Succeeded)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		response := server.request(t, http.MethodDelete, "/api/blog-post/"+post.ID, nil)
+		assert.Equal(t, http.StatusOK, response.Status)
+
+		response = server.request(t, http.MethodGet, "/api/blog-post/"+post.ID, nil)
+		assert.Equal(t, http.StatusNotFound, response.Status)
+		response = server.request(t, http.MethodDelete, "/api/blog-post/"+post.ID, nil)
+		assert.Equal(t, http.StatusNotFound, response.Status)
+	})
+}
+
+func TestServer_ExportAndImport(t *testing.T) {
+	server := newTestServer(t, nil)
+	post := server.createPost(t, "Exported", "go")
+
+	export := server.request(t, http.MethodGet, "/api/export?format=jsonl", nil)
+	require.Equal(t, http.StatusOK, export.Status)
+	assert.Contains(t, export.Header.Get("Content-Disposition"), "blog-export-")
+	assert.Contains(t, string(export.Body), post.ID)
+
+	response := server.request(t, http.MethodPost, "/api/import?format=jsonl&dry_run=true", string(export.Body))
+	require.Equal(t, http.StatusOK, response.Status, "%s", response.Body)
+	var report models.ImportReport
+	response.decode(t, &report)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Total)
+	assert.Zero(t, report.Failed)
+
+	response = server.request(t, http.MethodGet, "/api/export?format=xml", nil)
+	assert.Equal(t, http.StatusBadRequest, response.Status)
+}
+
+func TestServer_Cache(t *testing.T) {
+	server := newTestServer(t, map[string]string{"CACHE_MAX_AGE": "30s"})
+	post := server.createPost(t, "Cached", "go")
+
+	for i := 0; i < 2; i++ {
+		response := server.request(t, http.MethodGet, "/api/blog-post/"+post.ID, nil)
+		require.Equal(t, http.StatusOK, response.Status)
+		assert.Equal(t, "private, max-age=30", response.Header.Get("Cache-Control"))
+		response = server.request(t, http.MethodGet, "/api/blog-post", nil)
+		require.Equal(t, http.StatusOK, response.Status)
+		assert.Equal(t, 1, response.Count)
+	}
+
+	response := server.request(t, http.MethodGet, "/api/blog-post/missing", nil)
+	assert.Equal(t, http.StatusNotFound, response.Status)
+	assert.Equal(t, "no-store", response.Header.Get("Cache-Control"))
+
+	t.Run("changes are visible at once", func(t *testing.T) {
+		response := server.request(t, http.MethodPatch, "/api/blog-post/"+post.ID, map[string]string{"title": "Changed"})
+		require.Equal(t, http.StatusOK, response.Status)
+
+		response = server.request(t, http.MethodGet, "/api/blog-post/"+post.ID, nil)
+		var stored models.BlogResponse
+		response.decode(t, &stored)
+		assert.Equal(t, "Changed", stored.Title)
+	})
+
+	t.Run("imports reach the cache through the event stream", func(t *testing.T) {
+		export := server.request(t, http.MethodGet, "/api/export?format=jsonl", nil)
+		require.Equal(t, http.StatusOK, export.Status)
+		imported := strings.NewReplacer(post.ID, "6ba7b810-9dad-11d1-80b4-00c04fd430c8", post.Slug, "imported").Replace(string(export.Body))
+		response := server.request(t, http.MethodPost, "/api/import?format=jsonl", imported)
+		require.Equal(t, http.StatusOK, response.Status, "%s", response.Body)
+
+		assert.Eventually(t, func() bool {
+			return server.request(t, http.MethodGet, "/api/blog-post", nil).Count == 2
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+
+	t.Run("statistics", func(t *testing.T) {
+		response := server.request(t, http.MethodGet, "/api/admin/cache", nil)
+		require.Equal(t, http.StatusOK, response.Status, "%s", response.Body)
+		var stats struct {
+			Hits   int `json:"hits"`
+			Misses int `json:"misses"`
+			Loads  int `json:"loads"`
+		}
+		response.decode(t, &stats)
+		assert.GreaterOrEqual(t, stats.Hits, 2)
+		assert.GreaterOrEqual(t, stats.Misses, 2)
+		assert.Equal(t, stats.Misses, stats.Loads)
+	})
+}
+
+// webhookReceiver records the deliveries it receives
+type webhookReceiver struct {
+	mu         sync.Mutex
+	signatures []string
+	payloads   [][]byte
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, request *http.Request) {
+	payload, _ := io.ReadAll(request.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.signatures = append(r.signatures, request.Header.Get(webhook.HeaderSignature))
+	r.payloads = append(r.payloads, payload)
+}
+
+func (r *webhookReceiver) received() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.payloads)
+}
+
+func TestServer_Webhooks(t *testing.T) {
+	server := newTestServer(t, nil)
+	receiver := &webhookReceiver{}
+	target := httptest.NewServer(receiver)
+	t.Cleanup(target.Close)
+
+	response := server.request(t, http.MethodPost, "/api/webhooks", models.WebhookCreateRequest{
+		URL: target.URL, Events: []string{models.EventPostCreated}, Secret: "integration-webhook-secret",
+	})
+	require.Equal(t, http.StatusCreated, response.Status, "%s", response.Body)
+	var hook models.WebhookResponse
+	response.decode(t, &hook)
+
+	response = server.request(t, http.MethodGet, "/api/webhooks", nil)
+	assert.Equal(t, http.StatusOK, response.Status)
+	assert.Equal(t, 1, response.Count)
+	response = server.request(t, http.MethodGet, "/api/webhooks/"+hook.ID, nil)
+	assert.Equal(t, http.StatusOK, response.Status)
+	response = server.request(t, http.MethodPatch, "/api/webhooks/"+hook.ID, map[string]string{"description": "Integration test"})
+	assert.Equal(t, http.StatusOK, response.Status, "%s", response.Body)
+
+	// Events travel from the outbox through the relay and the delivery queue to the receiver
+	post := server.createPost(t, "Delivered")
+	require.Eventually(t, func() bool { return receiver.received() > 0 }, 10*time.Second, 20*time.Millisecond)
+	receiver.mu.Lock()
+	assert.NoError(t, webhook.Verify("integration-webhook-secret", receiver.signatures[0], receiver.payloads[0], time.Minute, time.Now()))
+	assert.Contains(t, string(receiver.payloads[0]), post.ID)
+	receiver.mu.Unlock()
+
+	var deliveries []models.WebhookDelivery
+	require.Eventually(t, func() bool {
+		response := server.request(t, http.MethodGet, "/api/webhooks/"+hook.ID+"/deliveries?status=succeeded", nil)
+		response.decode(t, &deliveries)
+		return len(deliveries) == 1
+	}, 10*time.Second, 20*time.Millisecond)
+	response = server.request(t, http.MethodGet, "/api/webhooks/deliveries?status=succeeded", nil)
+	assert.Equal(t, http.StatusOK, response.Status)
+	assert.Equal(t, 1, response.Count)
+
+	response = server.request(t, http.MethodPost, "/api/webhooks/deliveries/"+deliveries[0].ID+"/retry", nil)
+	assert.Equal(t, http.StatusBadRequest, response.Status, "only dead deliveries are retried")
+	response = server.request(t, http.MethodPost, "/api/webhooks/deliveries/missing/retry", nil)
+	assert.Equal(t, http.StatusNotFound, response.Status)
+
+	response = server.request(t, http.MethodDelete, "/api/webhooks/"+hook.ID, nil)
+	assert.Equal(t, http.StatusOK, response.Status)
+	response = server.request(t, http.MethodGet, "/api/webhooks/"+hook.ID, nil)
+	assert.Equal(t, http.StatusNotFound, response.Status)
+}
+
+func TestServer_MemoryStoreEvents(t *testing.T) {
+	server := newTestServer(t, map[string]string{"BLOG_STORE": "memory"})
+	receiver := &webhookReceiver{}
+	target := httptest.NewServer(receiver)
+	t.Cleanup(target.Close)
+
+	response := server.request(t, http.MethodPost, "/api/webhooks", models.WebhookCreateRequest{
+		URL: target.URL, Events: []string{models.EventPostCreated}, Secret: "integration-webhook-secret",
+	})
+	require.Equal(t, http.StatusCreated, response.Status, "%s", response.Body)
+
+	// Posts stay in memory, but their events still go through the outbox of the database
+	post := server.createPost(t, "Remembered")
+	require.Eventually(t, func() bool { return receiver.received() > 0 }, 10*time.Second, 20*time.Millisecond)
+	receiver.mu.Lock()
+	assert.Contains(t, string(receiver.payloads[0]), post.ID)
+	receiver.mu.Unlock()
+}
+
+func TestServer_MemoryStoreAuditAndMaintenance(t *testing.T) {
+	server := newTestServer(t, map[string]string{"BLOG_STORE": "memory"})
+	post := server.createPost(t, "Forgotten")
+	response := server.request(t, http.MethodDelete, "/api/blog-post/"+post.ID, nil)
+	require.Equal(t, http.StatusOK, response.Status, "%s", response.Body)
+
+	// The audit entries of posts kept in memory are still written to the database
+	response = server.request(t, http.MethodGet, "/api/audit?target_id="+post.ID, nil)
+	require.Equal(t, http.StatusOK, response.Status)
+	var entries []models.AuditEntry
+	response.decode(t, &entries)
+	require.Len(t, entries, 2)
+	assert.Equal(t, models.AuditActionPostDelete, entries[0].Action)
+	assert.Equal(t, models.AuditActionPostCreate, entries[1].Action)
+
+	// Maintenance works on the posts in memory rather than on the tables of the database
+	report, err := server.app.MaintenanceService.PurgeTrash("", 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), report.Purged)
+	reindex, err := server.app.MaintenanceService.Reindex(&models.Requester{Kind: models.RequesterKindCLI, Subject: "tests"})
+	require.NoError(t, err)
+	assert.Empty(t, reindex.Tables)
+}
+
+func TestServer_EventStream(t *testing.T) {
+	server := newTestServer(t, nil)
+	post := server.createPost(t, "Streamed")
+
+	response := server.request(t, http.MethodGet, "/api/events/stream?types=bogus", nil)
+	assert.Equal(t, http.StatusBadRequest, response.Status)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.url+"/api/events/stream?types=post.created&last_event_id=0", nil)
+	require.NoError(t, err)
+	stream, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer stream.Body.Close()
+	assert.Equal(t, http.StatusOK, stream.StatusCode)
+	assert.Equal(t, "text/event-stream", stream.Header.Get("Content-Type"))
+
+	// Events committed before the stream was opened are replayed from the outbox
+	scanner := bufio.NewScanner(stream.Body)
+	for scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			var event models.BlogEvent
+			require.NoError(t, json.Unmarshal([]byte(data), &event))
+			assert.Equal(t, models.EventPostCreated, event.Type)
+			assert.Equal(t, post.ID, event.Data.ID)
+			return
+		}
+	}
+	t.Fatalf("stream ended without an event: %v", scanner.Err())
+}
+
+func TestServer_EventStreamHidesDrafts(t *testing.T) {
+	server := newTestServer(t, nil)
+	response := server.request(t, http.MethodPost, "/api/blog-post", models.BlogCreateRequest{Title: "Draft", Body: "Not ready", Status: models.BlogStatusDraft})
+	require.Equal(t, http.StatusCreated, response.Status, "%s", response.Body)
+	var draft models.BlogResponse
+	response.decode(t, &draft)
+	published := server.createPost(t, "Published")
+
+	// Both events are replayed from the outbox, the draft first
+	assert.Equal(t, draft.ID, firstStreamEvent(t, server).Data.ID, "admins see drafts")
+	assert.Equal(t, published.ID, firstStreamEvent(t, server.anonymous()).Data.ID, "anonymous subscribers never see drafts")
+}
+
+// firstStreamEvent opens the event stream as the caller of s, replaying every event, and returns the first event
+func firstStreamEvent(t *testing.T, s *testServer) models.BlogEvent {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url+"/api/events/stream?last_event_id=0", nil)
+	require.NoError(t, err)
+	if s.authorization != "" {
+		request.Header.Set("Authorization", s.authorization)
+	}
+	stream, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer stream.Body.Close()
+	require.Equal(t, http.StatusOK, stream.StatusCode)
+
+	scanner := bufio.NewScanner(stream.Body)
+	for scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			var event models.BlogEvent
+			require.NoError(t, json.Unmarshal([]byte(data), &event))
+			return event
+		}
+	}
+	t.Fatalf("stream ended without an event: %v", scanner.Err())
+	return models.BlogEvent{}
+}
+
+func TestServer_GraphQL(t *testing.T) {
+	server := newTestServer(t, nil)
+	post := server.createPost(t, "Queried", "go")
+
+	response := server.request(t, http.MethodPost, "/graphql", map[string]interface{}{
+		"query":     `query($tag: String) { posts(tag: $tag) { nodes { id title } } }`,
+		"variables": map[string]string{"tag": "go"},
+	})
+	require.Equal(t, http.StatusOK, response.Status, "%s", response.Body)
+	assert.JSONEq(t, fmt.Sprintf(`{"data":{"posts":{"nodes":[{"id":%q,"title":"Queried"}]}}}`, post.ID), string(response.Body))
+}
+
+func TestServer_Roles(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	response := server.request(t, http.MethodGet, "/api/admin/roles", nil)
+	assert.Equal(t, http.StatusOK, response.Status)
+	assert.Equal(t, len(rbac.Roles()), response.Count)
+
+	response = server.request(t, http.MethodPost, "/api/admin/role-assignments", models.RoleAssignmentRequest{Subject: "alice", Role: rbac.RoleEditor})
+	require.Equal(t, http.StatusCreated, response.Status, "%s", response.Body)
+	response = server.request(t, http.MethodGet, "/api/admin/role-assignments?subject=alice", nil)
+	assert.Equal(t, http.StatusOK, response.Status)
+	assert.Equal(t, 1, response.Count)
+	response = server.request(t, http.MethodDelete, "/api/admin/role-assignments/alice/"+rbac.RoleEditor, nil)
+	assert.Equal(t, http.StatusOK, response.Status)
+	response = server.request(t, http.MethodDelete, "/api/admin/role-assignments/alice/"+rbac.RoleEditor, nil)
+	assert.Equal(t, http.StatusNotFound, response.Status)
+
+	response = server.request(t, http.MethodGet, "/api/admin/access-denials", nil)
+	assert.Equal(t, http.StatusOK, response.Status)
+	assert.Zero(t, response.Count)
+}
+
+func TestServer_IdempotencyNeverStoresCredentials(t *testing.T) {
+	server := newTestServer(t, nil)
+	request := models.APIKeyCreateRequest{Name: "CI", Scopes: []string{string(rbac.PostCreate)}}
+
+	var keys [2]models.APIKeyResponse
+	for i := range keys {
+		response := server.request(t, http.MethodPost, "/api/api-keys", request, "Idempotency-Key", "create-key")
+		require.Equal(t, http.StatusCreated, response.Status, "%s", response.Body)
+		assert.Empty(t, response.Header.Get("Idempotent-Replayed"))
+		response.decode(t, &keys[i])
+	}
+	assert.NotEqual(t, keys[0].Key, keys[1].Key, "the key is not kept to be replayed")
+}
+
+func TestServer_APIKeysAndAudit(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	response := server.request(t, http.MethodPost, "/api/api-keys", models.APIKeyCreateRequest{Name: "CI", Scopes: []string{string(rbac.PostCreate)}})
+	require.Equal(t, http.StatusCreated, response.Status, "%s", response.Body)
+	var key models.APIKeyResponse
+	response.decode(t, &key)
+	require.NotEmpty(t, key.Key)
+
+	response = server.request(t, http.MethodGet, "/api/api-keys", nil)
+	assert.Equal(t, http.StatusOK, response.Status)
+	assert.Equal(t, 2, response.Count, "the key of the tests and the new one")
+	response = server.request(t, http.MethodGet, "/api/api-keys/"+key.ID, nil)
+	assert.Equal(t, http.StatusOK, response.Status)
+
+	// Changes made with the key are attributed to it in the audit log
+	response = server.request(t, http.MethodPost, "/api/blog-post", models.BlogCreateRequest{Title: "By key", Body: "Created with an API key"}, "Authorization", "ApiKey "+key.Key)
+	require.Equal(t, http.StatusCreated, response.Status, "%s", response.Body)
+	var post models.BlogResponse
+	response.decode(t, &post)
+
+	response = server.request(t, http.MethodGet, "/api/audit?target_id="+post.ID, nil)
+	require.Equal(t, http.StatusOK, response.Status)
+	var entries []models.AuditEntry
+	response.decode(t, &entries)
+	require.Len(t, entries, 1)
+	assert.Equal(t, models.AuditActionPostCreate, entries[0].Action)
+	assert.Equal(t, auth.KindAPIKey, entries[0].PrincipalKind)
+	export := server.request(t, http.MethodGet, "/api/audit/export?format=csv", nil)
+	assert.Equal(t, http.StatusOK, export.Status)
+	assert.Contains(t, string(export.Body), post.ID)
+
+	response = server.request(t, http.MethodPost, "/api/api-keys/"+key.ID+"/rotate", models.APIKeyRotateRequest{GracePeriod: "0s"})
+	require.Equal(t, http.StatusCreated, response.Status, "%s", response.Body)
+	var rotated models.APIKeyResponse
+	response.decode(t, &rotated)
+	assert.NotEqual(t, key.Key, rotated.Key)
+
+	response = server.request(t, http.MethodDelete, "/api/api-keys/"+rotated.ID, nil)
+	assert.Equal(t, http.StatusOK, response.Status)
+	response = server.request(t, http.MethodPost, "/api/blog-post", models.BlogCreateRequest{Title: "Revoked", Body: "Refused"}, "Authorization", "ApiKey "+rotated.Key)
+	assert.Equal(t, http.StatusUnauthorized, response.Status)
+}
+
+// resetTokenPattern finds the token in a password reset email
+var resetTokenPattern = regexp.MustCompile(`pr_[0-9a-f]+`)
+
+func TestServer_Accounts(t *testing.T) {
+	server := newTestServer(t, nil)
+	credentials := models.RegisterRequest{Email: "alice@example.com", Password: "correct horse battery staple"}
+
+	response := server.request(t, http.MethodPost, "/api/auth/register", credentials)
+	require.Equal(t, http.StatusCreated, response.Status, "%s", response.Body)
+	response = server.request(t, http.MethodPost, "/api/auth/register", credentials)
+	assert.Equal(t, http.StatusConflict, response.Status)
+
+	login := func(t *testing.T, request models.LoginRequest) *apiResponse {
+		return server.request(t, http.MethodPost, "/api/auth/login", request)
+	}
+	response = login(t, models.LoginRequest{Email: credentials.Email, Password: credentials.Password})
+	require.Equal(t, http.StatusOK, response.Status, "%s", response.Body)
+	var tokens models.AuthTokens
+	response.decode(t, &tokens)
+	bearer := "Bearer " + tokens.AccessToken
+
+	t.Run("sessions", func(t *testing.T) {
+		response := server.request(t, http.MethodPost, "/api/auth/refresh", models.RefreshTokenRequest{RefreshToken: tokens.RefreshToken})
+		require.Equal(t, http.StatusOK, response.Status, "%s", response.Body)
+		response.decode(t, &tokens)
+		bearer = "Bearer " + tokens.AccessToken
+
+		response = server.request(t, http.MethodGet, "/api/auth/sessions", nil, "Authorization", bearer)
+		require.Equal(t, http.StatusOK, response.Status)
+		assert.Equal(t, 1, response.Count)
+		response = server.anonymous().request(t, http.MethodGet, "/api/auth/sessions", nil)
+		assert.Equal(t, http.StatusUnauthorized, response.Status)
+
+		other := login(t, models.LoginRequest{Email: credentials.Email, Password: credentials.Password})
+		var otherTokens models.AuthTokens
+		other.decode(t, &otherTokens)
+		response = server.request(t, http.MethodDelete, "/api/auth/sessions/"+otherTokens.SessionID, nil, "Authorization", bearer)
+		assert.Equal(t, http.StatusOK, response.Status)
+		response = server.request(t, http.MethodPost, "/api/auth/logout", models.RefreshTokenRequest{RefreshToken: otherTokens.RefreshToken})
+		assert.Equal(t, http.StatusOK, response.Status)
+	})
+
+	t.Run("two-factor authentication", func(t *testing.T) {
+		response := server.request(t, http.MethodPost, "/api/auth/2fa/enroll", nil, "Authorization", bearer)
+		require.Equal(t, http.StatusOK, response.Status, "%s", response.Body)
+		var enrollment models.TOTPEnrollment
+		response.decode(t, &enrollment)
+
+		code, err := auth.TOTPCode(enrollment.Secret, time.Now())
+		require.NoError(t, err)
+		response = server.request(t, http.MethodPost, "/api/auth/2fa/confirm", models.TwoFactorCodeRequest{Code: code}, "Authorization", bearer)
+		require.Equal(t, http.StatusOK, response.Status, "%s", response.Body)
+		var recovery models.RecoveryCodesResponse
+		response.decode(t, &recovery)
+		require.NotEmpty(t, recovery.RecoveryCodes)
+
+		response = login(t, models.LoginRequest{Email: credentials.Email, Password: credentials.Password})
+		assert.Equal(t, http.StatusUnauthorized, response.Status)
+		assert.Equal(t, "Two-factor authentication required", response.Error)
+		response = login(t, models.LoginRequest{Email: credentials.Email, Password: credentials.Password, RecoveryCode: recovery.RecoveryCodes[0]})
+		require.Equal(t, http.StatusOK, response.Status, "%s", response.Body)
+		response.decode(t, &tokens)
+		bearer = "Bearer " + tokens.AccessToken
+
+		response = server.request(t, http.MethodPost, "/api/auth/2fa/disable", models.TwoFactorCodeRequest{Code: recovery.RecoveryCodes[1]}, "Authorization", bearer)
+		assert.Equal(t, http.StatusOK, response.Status, "%s", response.Body)
+	})
+
+	t.Run("password reset", func(t *testing.T) {
+		response := server.request(t, http.MethodPost, "/api/auth/password-reset", models.PasswordResetRequest{Email: credentials.Email})
+		require.Equal(t, http.StatusAccepted, response.Status)
+
+		messages, err := os.ReadDir(server.mailDir)
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		message, err := os.ReadFile(filepath.Join(server.mailDir, messages[0].Name()))
+		require.NoError(t, err)
+		token := resetTokenPattern.FindString(string(message))
+		require.NotEmpty(t, token, "%s", message)
+
+		response = server.request(t, http.MethodPost, "/api/auth/password-reset/confirm", models.PasswordResetConfirmRequest{Token: token, Password: "another horse battery staple"})
+		require.Equal(t, http.StatusOK, response.Status, "%s", response.Body)
+		response = login(t, models.LoginRequest{Email: credentials.Email, Password: credentials.Password})
+		assert.Equal(t, http.StatusUnauthorized, response.Status)
+		response = login(t, models.LoginRequest{Email: credentials.Email, Password: "another horse battery staple"})
+		assert.Equal(t, http.StatusOK, response.Status)
+	})
+}
+
+func TestServer_AccessControl(t *testing.T) {
+	server := newTestServer(t, map[string]string{"RBAC_ENABLED": "true"})
+	key, err := server.app.APIKeyService.CreateAPIKey(models.DefaultTenantID, rbac.Unrestricted(""), &models.APIKeyCreateRequest{
+		Name: "Author", Scopes: []string{string(rbac.PostCreate)},
+	})
+	require.NoError(t, err)
+	request := models.BlogCreateRequest{Title: "Guarded", Body: "Needs post:create"}
+
+	response := server.anonymous().request(t, http.MethodPost, "/api/blog-post", request)
+	assert.Equal(t, http.StatusUnauthorized, response.Status)
+	response = server.request(t, http.MethodPost, "/api/blog-post", request, "Authorization", "ApiKey "+key.Key)
+	assert.Equal(t, http.StatusCreated, response.Status, "%s", response.Body)
+	response = server.request(t, http.MethodGet, "/api/webhooks", nil, "Authorization", "ApiKey "+key.Key)
+	assert.Equal(t, http.StatusForbidden, response.Status)
+
+	response = server.anonymous().request(t, http.MethodGet, "/api/blog-post", nil)
+	assert.Equal(t, http.StatusOK, response.Status, "reading posts is open to every caller")
+}
+
+func TestServer_DraftsAreHidden(t *testing.T) {
+	server := newTestServer(t, nil)
+	published := server.createPost(t, "Published")
+	response := server.request(t, http.MethodPost, "/api/blog-post", models.BlogCreateRequest{Title: "Draft", Body: "Not ready", Status: models.BlogStatusDraft})
+	require.Equal(t, http.StatusCreated, response.Status, "%s", response.Body)
+	var draft models.BlogResponse
+	response.decode(t, &draft)
+
+	for _, path := range []string{"/api/blog-post", "/api/blog-post?limit=10", "/api/blog-post?status=draft"} {
+		var posts []models.BlogResponse
+		server.request(t, http.MethodGet, path, nil).decode(t, &posts)
+		assert.Contains(t, blogResponseIDs(posts), draft.ID, "admins read drafts from %s", path)
+		server.anonymous().request(t, http.MethodGet, path, nil).decode(t, &posts)
+		assert.NotContains(t, blogResponseIDs(posts), draft.ID, "anonymous callers do not read drafts from %s", path)
+	}
+	response = server.anonymous().request(t, http.MethodGet, "/api/blog-post/"+draft.ID, nil)
+	assert.Equal(t, http.StatusNotFound, response.Status)
+	response = server.anonymous().request(t, http.MethodGet, "/api/blog-post/"+published.ID, nil)
+	assert.Equal(t, http.StatusOK, response.Status)
+
+	response = server.anonymous().request(t, http.MethodPost, "/graphql", map[string]interface{}{
+		"query":     `query($id: ID!) { post(id: $id) { id } posts { nodes { id } } }`,
+		"variables": map[string]string{"id": draft.ID},
+	})
+	require.Equal(t, http.StatusOK, response.Status, "%s", response.Body)
+	assert.JSONEq(t, fmt.Sprintf(`{"data":{"post":null,"posts":{"nodes":[{"id":%q}]}}}`, published.ID), string(response.Body))
+}
+
+func blogResponseIDs(posts []models.BlogResponse) []string {
+	ids := make([]string, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+	return ids
+}
+
+func TestServer_AdministrationNeedsAnAdmin(t *testing.T) {
+	administration := []struct{ method, path string }{
+		{http.MethodGet, "/api/admin/role-assignments"},
+		{http.MethodPost, "/api/admin/role-assignments"},
+		{http.MethodPost, "/api/api-keys"},
+		{http.MethodGet, "/api/api-keys"},
+		{http.MethodGet, "/api/audit"},
+		{http.MethodGet, "/api/audit/export"},
+		{http.MethodGet, "/api/webhooks"},
+		{http.MethodPost, "/api/webhooks"},
+	}
+
+	for _, rbacEnabled := range []string{"false", "true"} {
+		t.Run("RBAC_ENABLED="+rbacEnabled, func(t *testing.T) {
+			server := newTestServer(t, map[string]string{"RBAC_ENABLED": rbacEnabled})
+			credentials := models.RegisterRequest{Email: "mallory@example.com", Password: "correct horse battery staple"}
+			response := server.anonymous().request(t, http.MethodPost, "/api/auth/register", credentials)
+			if rbacEnabled == "true" {
+				require.Equal(t, http.StatusCreated, response.Status, "%s", response.Body)
+			} else {
+				// Without roles every signed in user may change every post, so only admins create accounts
+				require.Equal(t, http.StatusUnauthorized, response.Status, "%s", response.Body)
+				response = server.anonymous().request(t, http.MethodPost, "/api/auth/login", models.LoginRequest{Email: credentials.Email, Password: credentials.Password})
+				require.Equal(t, http.StatusUnauthorized, response.Status, "%s", response.Body)
+
+				credentials.Email = "alice@example.com"
+				response = server.request(t, http.MethodPost, "/api/auth/register", credentials)
+				require.Equal(t, http.StatusCreated, response.Status, "%s", response.Body)
+			}
+			response = server.anonymous().request(t, http.MethodPost, "/api/auth/login", models.LoginRequest{Email: credentials.Email, Password: credentials.Password})
+			require.Equal(t, http.StatusOK, response.Status, "%s", response.Body)
+			var tokens models.AuthTokens
+			response.decode(t, &tokens)
+			user := server.anonymous()
+			user.authorization = "Bearer " + tokens.AccessToken
+
+			for _, route := range administration {
+				response := server.anonymous().request(t, route.method, route.path, map[string]string{})
+				assert.Equal(t, http.StatusUnauthorized, response.Status, "anonymous %s %s", route.method, route.path)
+				response = user.request(t, route.method, route.path, map[string]string{})
+				assert.Equal(t, http.StatusForbidden, response.Status, "user %s %s", route.method, route.path)
+			}
+
+			// Anonymous callers may only read posts, whether or not roles are enforced
+			post := models.BlogCreateRequest{Title: "Anonymous", Body: "Refused"}
+			response = server.anonymous().request(t, http.MethodPost, "/api/blog-post", post)
+			assert.Equal(t, http.StatusUnauthorized, response.Status)
+			response = user.request(t, http.MethodPost, "/api/blog-post", post)
+			if rbacEnabled == "true" {
+				assert.Equal(t, http.StatusForbidden, response.Status, "users who registered themselves hold no role")
+			} else {
+				assert.Equal(t, http.StatusCreated, response.Status, "users created by an admin may write without RBAC")
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+// Package audit carries the requester of a change through the request context, so that the
+// blog service can record it in the audit log.
+package audit
+
+import (
+	"context"
+
+	"BlogManagment/internal/models"
+)
+
+type contextKey struct{}
+
+// NewContext returns a context carrying the requester
+func NewContext(ctx context.Context, requester *models.Requester) context.Context {
+	return context.WithValue(ctx, contextKey{}, requester)
+}
+
+// FromContext returns the requester carried by ctx, or nil when there is none
+func FromContext(ctx context.Context) *models.Requester {
+	requester, _ := ctx.Value(contextKey{}).(*models.Requester)
+	return requester
+}
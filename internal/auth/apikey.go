@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// apiKeyPrefix starts every API key, so that leaked keys are easy to recognise
+const apiKeyPrefix = "bk_"
+
+// ErrMalformedAPIKey is returned for strings that are not API keys
+var ErrMalformedAPIKey = errors.New("malformed API key")
+
+// NewAPIKeySecret creates the random secret of an API key
+func NewAPIKeySecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// FormatAPIKey returns the key handed to clients, made of the key ID and its secret:
+// "bk_<id>_<secret>"
+func FormatAPIKey(id, secret string) string {
+	return apiKeyPrefix + strings.ReplaceAll(id, "-", "") + "_" + secret
+}
+
+// ParseAPIKey splits a key created by FormatAPIKey into the key ID and its secret
+func ParseAPIKey(key string) (id, secret string, err error) {
+	rest, ok := strings.CutPrefix(key, apiKeyPrefix)
+	if !ok {
+		return "", "", ErrMalformedAPIKey
+	}
+	compactID, secret, ok := strings.Cut(rest, "_")
+	if !ok || secret == "" {
+		return "", "", ErrMalformedAPIKey
+	}
+	parsed, err := uuid.Parse(compactID)
+	if err != nil {
+		return "", "", ErrMalformedAPIKey
+	}
+	return parsed.String(), secret, nil
+}
+
+// HashAPIKeySecret returns the hash stored in place of an API key secret. The secrets are
+// random, so a fast hash is enough.
+func HashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeySecretMatches reports, in constant time, whether secret hashes to hash
+func APIKeySecretMatches(secret, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashAPIKeySecret(secret)), []byte(hash)) == 1
+}
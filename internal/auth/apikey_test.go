@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKey_FormatAndParse(t *testing.T) {
+	secret, err := NewAPIKeySecret()
+	require.NoError(t, err)
+
+	key := FormatAPIKey("550e8400-e29b-41d4-a716-446655440000", secret)
+	assert.Equal(t, "bk_550e8400e29b41d4a716446655440000_"+secret, key)
+
+	id, parsedSecret, err := ParseAPIKey(key)
+	require.NoError(t, err)
+	assert.Equal(t, "550e8400-e29b-41d4-a716-446655440000", id)
+	assert.Equal(t, secret, parsedSecret)
+	assert.True(t, APIKeySecretMatches(parsedSecret, HashAPIKeySecret(secret)))
+	assert.False(t, APIKeySecretMatches("guess", HashAPIKeySecret(secret)))
+}
+
+func TestParseAPIKey_Malformed(t *testing.T) {
+	for _, key := range []string{
+		"",
+		"550e8400e29b41d4a716446655440000_secret",
+		"bk_550e8400e29b41d4a716446655440000",
+		"bk_550e8400e29b41d4a716446655440000_",
+		"bk_not-a-uuid_secret",
+	} {
+		_, _, err := ParseAPIKey(key)
+		assert.ErrorIs(t, err, ErrMalformedAPIKey, key)
+	}
+}
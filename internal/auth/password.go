@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// ErrMalformedPasswordHash is returned for stored hashes that were not made by HashPassword
+var ErrMalformedPasswordHash = errors.New("malformed password hash")
+
+// PasswordParams are the argon2id cost parameters of password hashes
+type PasswordParams struct {
+	// Memory is the memory cost in KiB
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultPasswordParams follow the second recommendation of RFC 9106: 64 MiB of memory and
+// three passes
+var DefaultPasswordParams = PasswordParams{Memory: 64 * 1024, Iterations: 3, Parallelism: 4, SaltLength: 16, KeyLength: 32}
+
+// HashPassword hashes a password with argon2id and a random salt. The result is encoded in the
+// PHC string format, "$argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash>", so that it carries the
+// parameters it was made with.
+func HashPassword(password string, params PasswordParams) (string, error) {
+	salt := make([]byte, params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+
+	encoding := base64.RawStdEncoding
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, params.Memory, params.Iterations,
+		params.Parallelism, encoding.EncodeToString(salt), encoding.EncodeToString(key)), nil
+}
+
+// VerifyPassword reports, in constant time, whether password matches a hash made by HashPassword
+func VerifyPassword(password, encoded string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return false, ErrMalformedPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, ErrMalformedPasswordHash
+	}
+	var params PasswordParams
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return false, ErrMalformedPasswordHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, ErrMalformedPasswordHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return false, ErrMalformedPasswordHash
+	}
+
+	candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(candidate, key) == 1, nil
+}
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPasswordParams keep hashing cheap in tests
+var testPasswordParams = PasswordParams{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestHashPassword_Verify(t *testing.T) {
+	encoded, err := HashPassword("correct horse battery staple", testPasswordParams)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=64,t=1,p=1$"), encoded)
+
+	other, err := HashPassword("correct horse battery staple", testPasswordParams)
+	require.NoError(t, err)
+	assert.NotEqual(t, encoded, other, "every hash has its own salt")
+
+	matches, err := VerifyPassword("correct horse battery staple", encoded)
+	require.NoError(t, err)
+	assert.True(t, matches)
+
+	matches, err = VerifyPassword("wrong horse battery staple", encoded)
+	require.NoError(t, err)
+	assert.False(t, matches)
+}
+
+func TestVerifyPassword_Malformed(t *testing.T) {
+	for _, encoded := range []string{
+		"",
+		"plain text",
+		"$argon2i$v=19$m=64,t=1,p=1$c2FsdA$aGFzaA",
+		"$argon2id$v=18$m=64,t=1,p=1$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=64,t=1$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=64,t=1,p=1$not base64!$aGFzaA",
+	} {
+		_, err := VerifyPassword("password", encoded)
+		assert.ErrorIs(t, err, ErrMalformedPasswordHash, encoded)
+	}
+}
+
+func TestJWTIssuer_IssuesTokensTheVerifierAccepts(t *testing.T) {
+	issuer := NewJWTIssuer("secret", 15*time.Minute)
+
+	token, expiresAt, err := issuer.Issue("user-1", "acme", false)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), expiresAt, 2*time.Second)
+
+	principal, err := NewJWTVerifier("secret").Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, &Principal{Subject: "user-1", Kind: KindUser, TenantID: "acme"}, principal)
+
+	token, _, err = issuer.Issue("user-1", "acme", true)
+	require.NoError(t, err)
+	principal, err = NewJWTVerifier("secret").Verify(token)
+	require.NoError(t, err)
+	assert.True(t, principal.TwoFactor)
+
+	_, err = NewJWTVerifier("other secret").Verify(token)
+	assert.Error(t, err)
+
+	issuer.now = func() time.Time { return time.Now().Add(-time.Hour) }
+	expired, _, err := issuer.Issue("user-1", "acme", false)
+	require.NoError(t, err)
+	_, err = NewJWTVerifier("secret").Verify(expired)
+	assert.Error(t, err, "expired tokens are rejected")
+}
+
+func TestToken_Hash(t *testing.T) {
+	token, err := NewToken(RefreshTokenPrefix)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, RefreshTokenPrefix))
+	assert.Len(t, HashToken(token), 64)
+	assert.Equal(t, HashToken(token), HashToken(token))
+
+	other, err := NewToken(RefreshTokenPrefix)
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+}
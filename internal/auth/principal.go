@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Principal kinds
+const (
+	KindUser   = "user"
+	KindAPIKey = "api_key"
+)
+
+// principalKey is the fiber.Ctx locals key holding the authenticated principal
+const principalKey = "auth.principal"
+
+// Principal represents the authenticated caller of a request
+type Principal struct {
+	Subject string
+	Kind    string
+	// TenantID is the tenant the credentials are restricted to; empty when they are not
+	TenantID string
+	// Scopes are the permissions an API key is limited to; nil for users, whose permissions
+	// come from their roles
+	Scopes []string
+	// TwoFactor reports whether a user signed in with a second factor
+	TwoFactor bool
+}
+
+// Verifier validates the credentials of a request and returns the principal they identify
+type Verifier interface {
+	Verify(credentials string) (*Principal, error)
+}
+
+// Authorization schemes
+const (
+	SchemeBearer = "Bearer"
+	SchemeAPIKey = "ApiKey"
+)
+
+// Authenticate verifies the credentials of an authorization value, "Bearer <token>" or
+// "ApiKey <key>", with the verifier of its scheme. A nil verifier leaves its scheme disabled.
+// Values without credentials of an enabled scheme return a nil principal and no error.
+func Authenticate(authorization string, bearer, apiKeys Verifier) (*Principal, error) {
+	scheme, credentials, found := strings.Cut(authorization, " ")
+	if !found {
+		return nil, nil
+	}
+
+	var verifier Verifier
+	switch {
+	case strings.EqualFold(scheme, SchemeBearer):
+		verifier = bearer
+	case strings.EqualFold(scheme, SchemeAPIKey):
+		verifier = apiKeys
+	}
+	if verifier == nil {
+		return nil, nil
+	}
+	return verifier.Verify(strings.TrimSpace(credentials))
+}
+
+// ID returns a stable identifier for the principal that is unique across kinds
+func (p *Principal) ID() string {
+	return p.Kind + ":" + p.Subject
+}
+
+// SetPrincipal stores the authenticated principal on the request context
+func SetPrincipal(c *fiber.Ctx, principal *Principal) {
+	c.Locals(principalKey, principal)
+}
+
+// PrincipalFromCtx returns the authenticated principal, or nil for anonymous requests
+func PrincipalFromCtx(c *fiber.Ctx) *Principal {
+	principal, _ := c.Locals(principalKey).(*Principal)
+	return principal
+}
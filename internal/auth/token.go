@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Prefixes of the opaque tokens handed to clients, so that leaked tokens are easy to recognise
+const (
+	RefreshTokenPrefix       = "rt_"
+	PasswordResetTokenPrefix = "pr_"
+)
+
+// NewToken creates a random opaque token starting with prefix
+func NewToken(prefix string) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return prefix + hex.EncodeToString(buf), nil
+}
+
+// HashToken returns the hash stored in place of an opaque token. The tokens are random, so a
+// fast hash is enough and lets tokens be looked up by their hash.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
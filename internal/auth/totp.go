@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP parameters. They are the defaults of RFC 6238 and the only ones every authenticator app
+// supports: HMAC-SHA1, 6 digits and a 30 second period.
+const (
+	TOTPDigits = 6
+	TOTPPeriod = 30 * time.Second
+	// TOTPSkew is the number of periods a code may be early or late, to allow for clock drift
+	TOTPSkew = 1
+)
+
+// totpEncoding encodes TOTP secrets for otpauth URIs and manual entry
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewTOTPSecret creates a random 160 bit TOTP secret, base32 encoded
+func NewTOTPSecret() (string, error) {
+	buf := make([]byte, 20)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(buf), nil
+}
+
+// TOTPURI returns the otpauth URI that authenticator apps enroll a secret from, usually shown
+// as a QR code. The account is shown in the app under issuer.
+func TOTPURI(issuer, account, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(TOTPDigits))
+	query.Set("period", fmt.Sprint(int(TOTPPeriod.Seconds())))
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// TOTPCounter returns the number of the period at is in
+func TOTPCounter(at time.Time) int64 {
+	return at.Unix() / int64(TOTPPeriod.Seconds())
+}
+
+// TOTPCode returns the code of a secret for the period at is in
+func TOTPCode(secret string, at time.Time) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", fmt.Errorf("invalid TOTP secret: %w", err)
+	}
+	return totpCode(key, TOTPCounter(at)), nil
+}
+
+// totpCode computes the HOTP value of RFC 4226 for a counter
+func totpCode(key []byte, counter int64) string {
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], uint64(counter))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", TOTPDigits, value%1000000)
+}
+
+// VerifyTOTP checks a code against a secret at a time, allowing TOTPSkew periods of drift.
+// Codes of periods up to afterCounter are refused, so that each code works once; on success it
+// returns the period of the code, to be passed as afterCounter next time.
+func VerifyTOTP(secret, code string, at time.Time, afterCounter int64) (int64, bool, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid TOTP secret: %w", err)
+	}
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) != TOTPDigits {
+		return 0, false, nil
+	}
+
+	current := TOTPCounter(at)
+	for counter := current - TOTPSkew; counter <= current+TOTPSkew; counter++ {
+		if counter <= afterCounter {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, counter)), []byte(code)) == 1 {
+			return counter, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// RecoveryCodeCount is the number of recovery codes issued when two-factor authentication is enabled
+const RecoveryCodeCount = 10
+
+// NewRecoveryCode creates a random one-time recovery code of 80 bits, such as
+// "3f9a2-c41e7-08b5d-e6a13", for signing in without the authenticator app
+func NewRecoveryCode() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	code := hex.EncodeToString(buf)
+	return code[:5] + "-" + code[5:10] + "-" + code[10:15] + "-" + code[15:], nil
+}
+
+// HashRecoveryCode returns the hash stored in place of a recovery code. Codes are compared
+// without case, spaces or dashes, since people type them in by hand.
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return HashToken(code)
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6238Secret is the SHA-1 secret of the RFC 6238 test vectors, "12345678901234567890"
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	// The RFC lists 8 digit codes; 6 digit codes are their last 6 digits
+	for unix, want := range map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	} {
+		code, err := TOTPCode(rfc6238Secret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, want, code, unix)
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	secret, err := NewTOTPSecret()
+	require.NoError(t, err)
+	now := time.Date(2024, 1, 1, 12, 0, 10, 0, time.UTC)
+	code, err := TOTPCode(secret, now)
+	require.NoError(t, err)
+
+	counter, ok, err := VerifyTOTP(secret, code, now, 0)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, TOTPCounter(now), counter)
+
+	_, ok, err = VerifyTOTP(secret, code, now, counter)
+	require.NoError(t, err)
+	assert.False(t, ok, "codes work once")
+
+	_, ok, _ = VerifyTOTP(secret, code, now.Add(TOTPPeriod), 0)
+	assert.True(t, ok, "codes of the previous period are accepted")
+	_, ok, _ = VerifyTOTP(secret, code, now.Add(2*TOTPPeriod), 0)
+	assert.False(t, ok)
+
+	_, ok, _ = VerifyTOTP(secret, "12345", now, 0)
+	assert.False(t, ok)
+	_, _, err = VerifyTOTP("not base32!", code, now, 0)
+	assert.Error(t, err)
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri, err := url.Parse(TOTPURI("Blog Management", "alice@example.com", rfc6238Secret))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Blog Management:alice@example.com", uri.Path)
+	assert.Equal(t, rfc6238Secret, uri.Query().Get("secret"))
+	assert.Equal(t, "Blog Management", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+	assert.Equal(t, "30", uri.Query().Get("period"))
+}
+
+func TestRecoveryCode_Hash(t *testing.T) {
+	code, err := NewRecoveryCode()
+	require.NoError(t, err)
+	assert.Len(t, code, 23)
+	assert.Equal(t, HashRecoveryCode(code), HashRecoveryCode(" "+code[:5]+code[6:]+" "), "dashes and spaces are ignored")
+	assert.Equal(t, HashRecoveryCode("3f9a2-c41e7"), HashRecoveryCode("3F9A2C41E7"), "case is ignored")
+}
@@ -0,0 +1,38 @@
+// Package cache keeps recently read values in memory for a limited time.
+package cache
+
+import "time"
+
+// Cache stores values under string keys until they expire or are deleted
+type Cache interface {
+	// Get returns the value stored under key, if it is present and has not expired
+	Get(key string) (interface{}, bool)
+	// Set stores value under key for ttl. A ttl of zero or less keeps the value until it is
+	// deleted or evicted.
+	Set(key string, value interface{}, ttl time.Duration)
+	// Delete removes the values stored under keys
+	Delete(keys ...string)
+	// Clear removes every value
+	Clear()
+	// Stats returns the counters of the cache
+	Stats() Stats
+}
+
+// Stats counts how well a cache is doing
+type Stats struct {
+	Hits        uint64 `json:"hits"`
+	Misses      uint64 `json:"misses"`
+	Evictions   uint64 `json:"evictions"`
+	Expirations uint64 `json:"expirations"`
+	Entries     int    `json:"entries"`
+	Capacity    int    `json:"capacity"`
+}
+
+// HitRatio returns the share of lookups that found a value, or zero before the first lookup
+func (s Stats) HitRatio() float64 {
+	lookups := s.Hits + s.Misses
+	if lookups == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(lookups)
+}
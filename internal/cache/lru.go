@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRU is a Cache that holds a fixed number of values in process memory, evicting the least
+// recently used value to make room for a new one
+type LRU struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+	stats    Stats
+	now      func() time.Time
+}
+
+// entry is a value held by an LRU
+type entry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// expired reports whether the entry has expired at now
+func (e *entry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// NewLRU creates a cache holding at most capacity values
+func NewLRU(capacity int) *LRU {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRU{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+		now:      time.Now,
+	}
+}
+
+// Get returns the value stored under key, marking it as the most recently used
+func (c *LRU) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+	e := element.Value.(*entry)
+	if e.expired(c.now()) {
+		c.remove(element)
+		c.stats.Expirations++
+		c.stats.Misses++
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	c.stats.Hits++
+	return e.value, true
+}
+
+// Set stores value under key for ttl, evicting the least recently used value when the cache is full
+func (c *LRU) Set(key string, value interface{}, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.now().Add(ttl)
+	}
+
+	if element, ok := c.entries[key]; ok {
+		e := element.Value.(*entry)
+		e.value, e.expiresAt = value, expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	for c.order.Len() >= c.capacity {
+		c.remove(c.order.Back())
+		c.stats.Evictions++
+	}
+	c.entries[key] = c.order.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+}
+
+// Delete removes the values stored under keys
+func (c *LRU) Delete(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := c.entries[key]; ok {
+			c.remove(element)
+		}
+	}
+}
+
+// Clear removes every value. The counters are kept.
+func (c *LRU) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+}
+
+// Stats returns the counters of the cache
+func (c *LRU) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = c.order.Len()
+	stats.Capacity = c.capacity
+	return stats
+}
+
+// remove drops an element from the cache. The caller must hold c.mu.
+func (c *LRU) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*entry).key)
+}
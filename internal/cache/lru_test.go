@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU_GetAndSet(t *testing.T) {
+	c := NewLRU(2)
+
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+
+	c.Set("a", 1, 0)
+	value, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	c.Set("a", 2, 0)
+	value, _ = c.Get("a")
+	assert.Equal(t, 2, value)
+
+	assert.Equal(t, Stats{Hits: 2, Misses: 1, Entries: 1, Capacity: 2}, c.Stats())
+	assert.InDelta(t, 2.0/3.0, c.Stats().HitRatio(), 0.001)
+}
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU(2)
+	c.Set("a", 1, 0)
+	c.Set("b", 2, 0)
+
+	// Reading a makes b the least recently used value
+	_, _ = c.Get("a")
+	c.Set("c", 3, 0)
+
+	_, ok := c.Get("b")
+	assert.False(t, ok)
+	_, ok = c.Get("a")
+	assert.True(t, ok)
+	_, ok = c.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), c.Stats().Evictions)
+	assert.Equal(t, 2, c.Stats().Entries)
+}
+
+func TestLRU_Expires(t *testing.T) {
+	now := time.Now()
+	c := NewLRU(2)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1, time.Minute)
+	c.Set("b", 2, 0)
+
+	now = now.Add(time.Minute)
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+	_, ok = c.Get("b")
+	assert.True(t, ok)
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(1), stats.Expirations)
+	assert.Equal(t, 1, stats.Entries)
+}
+
+func TestLRU_DeleteAndClear(t *testing.T) {
+	c := NewLRU(4)
+	c.Set("a", 1, 0)
+	c.Set("b", 2, 0)
+	c.Set("c", 3, 0)
+
+	c.Delete("a", "missing")
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 2, c.Stats().Entries)
+
+	c.Clear()
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Stats().Entries)
+	assert.Equal(t, uint64(0), c.Stats().Evictions)
+}
+
+func TestLRU_Concurrent(t *testing.T) {
+	c := NewLRU(16)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				key := fmt.Sprintf("%d-%d", i, j%32)
+				c.Set(key, j, time.Minute)
+				c.Get(key)
+				if j%10 == 0 {
+					c.Delete(key)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	stats := c.Stats()
+	assert.LessOrEqual(t, stats.Entries, 16)
+	assert.Equal(t, uint64(800), stats.Hits+stats.Misses)
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/service"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// RunAPIKeys implements the api-keys subcommand, which manages API keys without going through
+// the API, e.g. to hand the first key to a CI job:
+//
+//	api-keys create -name NAME -scopes SCOPE,... [-expires DURATION] [-tenant ID]
+//	api-keys list [-tenant ID]
+//	api-keys rotate -id ID [-grace DURATION] [-tenant ID]
+//	api-keys revoke -id ID [-tenant ID]
+func RunAPIKeys(args []string, apiKeyService service.APIKeyService, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("expected an api-keys command: create, list, rotate or revoke")
+	}
+
+	flags := flag.NewFlagSet("api-keys "+args[0], flag.ContinueOnError)
+	tenantID := flags.String("tenant", models.DefaultTenantID, "tenant the key belongs to")
+	id := flags.String("id", "", "ID of the key")
+	name := flags.String("name", "", "name of a new key")
+	scopes := flags.String("scopes", "", "comma-separated permissions or roles a new key is limited to")
+	expires := flags.Duration("expires", 0, "lifetime of a new key; the configured default when zero")
+	grace := flags.String("grace", "", "how long a rotated key keeps working, e.g. 24h; the configured default when empty")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+
+	switch args[0] {
+	case "create":
+		request := &models.APIKeyCreateRequest{Name: *name}
+		for _, scope := range strings.Split(*scopes, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				request.Scopes = append(request.Scopes, scope)
+			}
+		}
+		if *expires > 0 {
+			expiresAt := time.Now().Add(*expires)
+			request.ExpiresAt = &expiresAt
+		}
+		key, err := apiKeyService.CreateAPIKey(*tenantID, rbac.Unrestricted(""), request)
+		if err != nil {
+			return err
+		}
+		return encoder.Encode(key)
+	case "list":
+		keys, err := apiKeyService.ListAPIKeys(*tenantID)
+		if err != nil {
+			return err
+		}
+		return encoder.Encode(keys)
+	case "rotate":
+		key, err := apiKeyService.RotateAPIKey(*tenantID, *id, &models.APIKeyRotateRequest{GracePeriod: *grace})
+		if err != nil {
+			return err
+		}
+		return encoder.Encode(key)
+	case "revoke":
+		return apiKeyService.RevokeAPIKey(*tenantID, *id)
+	default:
+		return fmt.Errorf("unknown api-keys command %q, expected create, list, rotate or revoke", args[0])
+	}
+}
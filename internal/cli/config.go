@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"BlogManagment/internal/config"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// RunConfig implements the config subcommand: "config print" prints the effective value of every
+// setting and the layer it came from, with secrets redacted
+func RunConfig(args []string, cfg *config.AppConfig, stdout io.Writer) error {
+	if len(args) == 0 || args[0] != "print" {
+		return fmt.Errorf("expected a config command: print")
+	}
+
+	flags := flag.NewFlagSet("config print", flag.ContinueOnError)
+	format := flags.String("format", "env", "output format: env or json")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	settings := cfg.Settings()
+	for i := range settings {
+		settings[i].Value = settings[i].Redacted()
+	}
+
+	switch *format {
+	case "env":
+		if cfg.File != "" {
+			fmt.Fprintf(stdout, "# Configuration file: %s\n", cfg.File)
+		}
+		w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+		for _, setting := range settings {
+			fmt.Fprintf(w, "%s=%s\t# %s\n", setting.Key, setting.Value, setting.Source)
+		}
+		return w.Flush()
+	case "json":
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(settings)
+	default:
+		return fmt.Errorf("unknown format %q, expected env or json", *format)
+	}
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/service"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"time"
+)
+
+// RunReindex implements the reindex subcommand: it gives posts without a slug the slug of their
+// title, rebuilds the indexes of the post tables and prints what it did as JSON
+func RunReindex(args []string, maintenanceService service.MaintenanceService, stdout io.Writer) error {
+	flags := flag.NewFlagSet("reindex", flag.ContinueOnError)
+	as := flags.String("as", os.Getenv("USER"), "name the changes are attributed to in the audit log")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	report, err := maintenanceService.Reindex(&models.Requester{Kind: models.RequesterKindCLI, Subject: *as})
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// RunPurgeTrash implements the purge-trash subcommand: it permanently removes posts that were
+// deleted long enough ago and prints how many as JSON
+func RunPurgeTrash(args []string, maintenanceService service.MaintenanceService, stdout io.Writer) error {
+	flags := flag.NewFlagSet("purge-trash", flag.ContinueOnError)
+	olderThan := flags.Duration("older-than", 30*24*time.Hour, "only purge posts deleted longer ago than this")
+	tenantID := flags.String("tenant", "", "tenant whose trash is purged (default: every tenant)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	report, err := maintenanceService.PurgeTrash(*tenantID, *olderThan)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/service"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+)
+
+// RunRoles implements the roles subcommand, which manages role assignments without going
+// through the API, e.g. to appoint the first admin of a tenant:
+//
+//	roles assign -subject SUBJECT -role ROLE [-tenant ID]
+//	roles revoke -subject SUBJECT -role ROLE [-tenant ID]
+//	roles list [-subject SUBJECT] [-tenant ID]
+func RunRoles(args []string, roleService service.RoleService, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("expected a roles command: assign, revoke or list")
+	}
+
+	flags := flag.NewFlagSet("roles "+args[0], flag.ContinueOnError)
+	tenantID := flags.String("tenant", models.DefaultTenantID, "tenant the roles apply to")
+	subject := flags.String("subject", "", "subject (JWT sub claim) of the user")
+	role := flags.String("role", "", "role: admin, editor, author or reviewer")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+
+	switch args[0] {
+	case "assign":
+		assignment, err := roleService.AssignRole(*tenantID, &models.RoleAssignmentRequest{Subject: *subject, Role: *role})
+		if err != nil {
+			return err
+		}
+		return encoder.Encode(assignment)
+	case "revoke":
+		return roleService.RevokeRole(*tenantID, *subject, *role)
+	case "list":
+		assignments, err := roleService.ListRoleAssignments(*tenantID, *subject)
+		if err != nil {
+			return err
+		}
+		return encoder.Encode(assignments)
+	default:
+		return fmt.Errorf("unknown roles command %q, expected assign, revoke or list", args[0])
+	}
+}
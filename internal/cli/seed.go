@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/service"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// seedBatchSize is the number of posts created per bulk request
+const seedBatchSize = 500
+
+// Building blocks of the generated posts
+var (
+	seedTopics = []string{
+		"Go", "PostgreSQL", "Kubernetes", "TypeScript", "GraphQL", "Docker", "Rust", "Redis",
+		"observability", "code review", "technical debt", "remote work", "feature flags", "caching",
+		"CI pipelines", "API design", "database migrations", "incident response", "pair programming",
+	}
+	seedTitleTemplates = []string{
+		"Getting started with %s",
+		"What I learned from a year of %s",
+		"%s in production: lessons from the trenches",
+		"A practical guide to %s",
+		"Why we moved to %s",
+		"Ten mistakes to avoid with %s",
+		"%s for people in a hurry",
+		"Rethinking %s",
+		"The hidden costs of %s",
+		"How %s changed the way our team works",
+	}
+	seedOpenings = []string{
+		"Last quarter our team spent a lot of time on %s.",
+		"Few topics start as many arguments in our office as %s.",
+		"When we first adopted %s, we expected it to be a quick win.",
+		"I have been asked about %s often enough to finally write it down.",
+	}
+	seedSentences = []string{
+		"The first version worked, but it did not survive contact with real traffic.",
+		"Most of the complexity turned out to be in the edge cases nobody wrote down.",
+		"We measured before and after, and the numbers surprised everyone.",
+		"Small, boring changes shipped often beat the big rewrite every time.",
+		"Documentation was the part we underestimated the most.",
+		"It is tempting to optimise early, but the profiler rarely agrees with intuition.",
+		"Onboarding new colleagues became noticeably faster afterwards.",
+		"The tooling has matured a lot over the last couple of years.",
+		"We kept a rollback plan ready, and we needed it exactly once.",
+		"Monitoring told us about problems long before our users did.",
+		"None of this required new infrastructure, only discipline.",
+		"The hardest part was agreeing on what done actually means.",
+	}
+	seedHeadings = []string{"Background", "What we tried", "What worked", "What did not", "Takeaways", "Next steps"}
+	seedTags     = []string{"go", "databases", "devops", "architecture", "testing", "performance", "security", "career", "tooling", "tutorial"}
+)
+
+// RunSeed implements the seed subcommand: it creates fake but realistic looking blog posts,
+// for demos and load tests, and prints how many were created
+func RunSeed(args []string, blogService service.BlogService, stdout io.Writer) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	count := flags.Int("count", 50, "number of posts to create")
+	drafts := flags.Float64("drafts", 0.2, "share of the posts created as drafts, between 0 and 1")
+	seed := flags.Int64("seed", 0, "seed of the generator, for reproducible posts (default: random)")
+	tenantID := flags.String("tenant", models.DefaultTenantID, "tenant the posts are created in")
+	as := flags.String("as", os.Getenv("USER"), "name the changes are attributed to in the audit log")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *count <= 0 {
+		return fmt.Errorf("-count must be positive")
+	}
+	if *drafts < 0 || *drafts > 1 {
+		return fmt.Errorf("-drafts must be between 0 and 1")
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	blogService = blogService.WithTenant(*tenantID).WithRequester(&models.Requester{Kind: models.RequesterKindCLI, Subject: *as})
+
+	random := rand.New(rand.NewSource(*seed))
+	for created := 0; created < *count; {
+		request := &models.BlogBulkRequest{Mode: models.BulkModeAtomic}
+		for i := 0; i < min(seedBatchSize, *count-created); i++ {
+			request.Operations = append(request.Operations, models.BlogBulkOperation{
+				Action: models.BulkActionCreate,
+				Create: fakePost(random, *drafts),
+			})
+		}
+		response, err := blogService.BulkBlogs(request)
+		if err != nil {
+			return fmt.Errorf("seeding failed after %d posts: %w", created, err)
+		}
+		if !response.Committed {
+			// Report the operation that failed rather than those not applied because of it
+			for _, result := range response.Results {
+				if result.Status != http.StatusFailedDependency {
+					return fmt.Errorf("seeding failed after %d posts: %s", created, result.Error)
+				}
+			}
+			return fmt.Errorf("seeding failed after %d posts", created)
+		}
+		created += len(request.Operations)
+	}
+
+	fmt.Fprintf(stdout, "Created %d posts in tenant %s (seed %d)\n", *count, *tenantID, *seed)
+	return nil
+}
+
+// fakePost returns a post with a title, summary, Markdown body and tags made of the building
+// blocks above; drafts is the probability of it being a draft
+func fakePost(random *rand.Rand, drafts float64) *models.BlogCreateRequest {
+	topic := pick(random, seedTopics)
+	opening := fmt.Sprintf(pick(random, seedOpenings), topic)
+
+	var body strings.Builder
+	body.WriteString(opening + " " + sentences(random, 2) + "\n")
+	for _, i := range random.Perm(len(seedHeadings))[:2+random.Intn(3)] {
+		fmt.Fprintf(&body, "\n## %s\n\n%s\n\n%s\n", seedHeadings[i], sentences(random, 3+random.Intn(3)), sentences(random, 2+random.Intn(3)))
+	}
+
+	tags := make([]string, 1+random.Intn(3))
+	for i, j := range random.Perm(len(seedTags))[:len(tags)] {
+		tags[i] = seedTags[j]
+	}
+
+	status := models.BlogStatusPublished
+	if random.Float64() < drafts {
+		status = models.BlogStatusDraft
+	}
+
+	return &models.BlogCreateRequest{
+		Title:       capitalize(fmt.Sprintf(pick(random, seedTitleTemplates), topic)),
+		Description: opening,
+		Body:        body.String(),
+		Tags:        tags,
+		Status:      status,
+	}
+}
+
+// sentences returns n different sentences of the pool, joined into a paragraph
+func sentences(random *rand.Rand, n int) string {
+	paragraph := make([]string, n)
+	for i, j := range random.Perm(len(seedSentences))[:n] {
+		paragraph[i] = seedSentences[j]
+	}
+	return strings.Join(paragraph, " ")
+}
+
+// capitalize upper-cases the first letter of a title starting with a lower-case topic
+func capitalize(title string) string {
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
+// pick returns a random element of values
+func pick(random *rand.Rand, values []string) string {
+	return values[random.Intn(len(values))]
+}
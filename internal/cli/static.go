@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/service"
+	"BlogManagment/internal/staticsite"
+	"encoding/json"
+	"flag"
+	"io"
+)
+
+// RunBuildStatic implements the build-static subcommand: it renders every published post
+// to a directory of HTML pages, feeds and a sitemap and prints a summary as JSON
+func RunBuildStatic(args []string, blogService service.BlogService, stdout io.Writer) error {
+	flags := flag.NewFlagSet("build-static", flag.ContinueOnError)
+	templates := flags.String("templates", "templates/static", "directory of html/template files (post.html, list.html, optional tag.html)")
+	output := flags.String("output", "public", "directory to write the site to")
+	baseURL := flags.String("base-url", "", "absolute URL the site is served from, used in feeds and the sitemap")
+	title := flags.String("title", "Blog", "site title")
+	pageSize := flags.Int("page-size", staticsite.DefaultPageSize, "posts per index, archive and tag page")
+	force := flags.Bool("force", false, "re-render every post, not only the ones that changed")
+	tenantID := flags.String("tenant", models.DefaultTenantID, "tenant whose posts are rendered")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	builder, err := staticsite.NewBuilder(blogService.WithTenant(*tenantID), staticsite.Options{
+		TemplateDir: *templates,
+		OutputDir:   *output,
+		BaseURL:     *baseURL,
+		Title:       *title,
+		PageSize:    *pageSize,
+		Force:       *force,
+	})
+	if err != nil {
+		return err
+	}
+
+	result, err := builder.Build()
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
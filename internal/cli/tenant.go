@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/service"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+)
+
+// RunTenants implements the tenants subcommand: "tenants create -id ID -name NAME" creates a
+// workspace and "tenants list" prints every workspace, both as JSON
+func RunTenants(args []string, tenantService service.TenantService, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("expected a tenants command: create or list")
+	}
+
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+
+	switch args[0] {
+	case "create":
+		flags := flag.NewFlagSet("tenants create", flag.ContinueOnError)
+		id := flags.String("id", "", "tenant ID, also used as its subdomain")
+		name := flags.String("name", "", "display name")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		created, err := tenantService.CreateTenant(&models.TenantCreateRequest{ID: *id, Name: *name})
+		if err != nil {
+			return err
+		}
+		return encoder.Encode(created)
+	case "list":
+		tenants, err := tenantService.GetAllTenants()
+		if err != nil {
+			return err
+		}
+		return encoder.Encode(tenants)
+	default:
+		return fmt.Errorf("unknown tenants command %q, expected create or list", args[0])
+	}
+}
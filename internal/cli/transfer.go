@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/service"
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RunExport implements the export subcommand: it writes every blog post to a file or stdout
+func RunExport(args []string, transferService service.BlogTransferService, stdout io.Writer) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flags.String("format", "", "export format: jsonl or csv (default: from -output extension, else jsonl)")
+	output := flags.String("output", "", "file to write to (default: stdout)")
+	tenantID := flags.String("tenant", models.DefaultTenantID, "tenant whose posts are exported")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	transferService = transferService.WithTenant(*tenantID)
+
+	if *format == "" {
+		*format = formatFromPath(*output)
+	}
+	if err := service.ValidateExportFormat(*format); err != nil {
+		return err
+	}
+
+	var w io.Writer = stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	buffered := bufio.NewWriter(w)
+	count, err := transferService.Export(buffered, *format)
+	if err != nil {
+		return fmt.Errorf("export failed after %d posts: %w", count, err)
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d posts as %s\n", count, *format)
+	return nil
+}
+
+// RunImport implements the import subcommand: it reads blog posts from a file, a directory
+// of Markdown files or stdin and prints the import report as JSON
+func RunImport(args []string, transferService service.BlogTransferService, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := flags.String("format", "", "import format: jsonl, csv, wxr or markdown (default: from -input, else jsonl)")
+	input := flags.String("input", "", "file or Markdown directory to read from (default: stdin)")
+	dryRun := flags.Bool("dry-run", false, "report what would change without writing")
+	tenantID := flags.String("tenant", models.DefaultTenantID, "tenant the posts are imported into")
+	as := flags.String("as", os.Getenv("USER"), "name the changes are attributed to in the audit log")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	transferService = transferService.WithTenant(*tenantID).WithRequester(&models.Requester{Kind: models.RequesterKindCLI, Subject: *as})
+
+	if *input != "" {
+		info, err := os.Stat(*input)
+		if err != nil {
+			return fmt.Errorf("failed to open input: %w", err)
+		}
+		if info.IsDir() {
+			if *format != "" && *format != models.TransferFormatMarkdown {
+				return fmt.Errorf("a directory can only be imported as %s", models.TransferFormatMarkdown)
+			}
+			report, err := transferService.ImportMarkdownFS(os.DirFS(*input), *dryRun)
+			if err != nil {
+				return err
+			}
+			return printImportReport(report, stdout)
+		}
+	}
+
+	if *format == "" {
+		*format = formatFromPath(*input)
+	}
+
+	r := stdin
+	if *input != "" {
+		file, err := os.Open(*input)
+		if err != nil {
+			return fmt.Errorf("failed to open input file: %w", err)
+		}
+		defer file.Close()
+		r = file
+	}
+
+	report, err := transferService.Import(bufio.NewReader(r), *format, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	return printImportReport(report, stdout)
+}
+
+// printImportReport writes the report as indented JSON and fails when any record was rejected
+func printImportReport(report *models.ImportReport, stdout io.Writer) error {
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d records failed to import", report.Failed, report.Total)
+	}
+	return nil
+}
+
+// formatFromPath infers the transfer format from a file extension
+func formatFromPath(path string) string {
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(lower, ".tar"), strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return models.TransferFormatMarkdown
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return models.TransferFormatCSV
+	case ".xml", ".wxr":
+		return models.TransferFormatWXR
+	default:
+		return models.TransferFormatJSONL
+	}
+}
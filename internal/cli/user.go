@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/service"
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// RunUsers implements the user subcommand, which creates accounts without going through the
+// API, e.g. the first administrator of a tenant:
+//
+//	user create -email EMAIL [-role ROLE,...] [-tenant ID]
+//
+// The password is read from the first line of stdin, so that it stays out of the shell history.
+// When accountService is nil, accounts are disabled.
+func RunUsers(args []string, accountService service.AccountService, roleService service.RoleService, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 || args[0] != "create" {
+		return fmt.Errorf("expected a user command: create")
+	}
+	if accountService == nil {
+		return fmt.Errorf("accounts are disabled, set JWT_SECRET to enable them")
+	}
+
+	flags := flag.NewFlagSet("user create", flag.ContinueOnError)
+	tenantID := flags.String("tenant", models.DefaultTenantID, "tenant the account belongs to")
+	email := flags.String("email", "", "email address the account signs in with")
+	roles := flags.String("role", "", "comma-separated roles granted to the account, e.g. admin")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	password, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("failed to read the password: %w", err)
+	}
+	password = strings.TrimRight(password, "\r\n")
+
+	// Check the roles before the account exists, so that a typo leaves nothing behind
+	var grants []string
+	for _, role := range strings.Split(*roles, ",") {
+		if role = strings.TrimSpace(role); role == "" {
+			continue
+		}
+		if !rbac.ValidRole(role) {
+			return fmt.Errorf("unknown role %q", role)
+		}
+		grants = append(grants, role)
+	}
+
+	user, err := accountService.Register(*tenantID, &models.RegisterRequest{Email: *email, Password: password})
+	if err != nil {
+		return err
+	}
+	for _, role := range grants {
+		if _, err := roleService.AssignRole(*tenantID, &models.RoleAssignmentRequest{Subject: user.ID, Role: role}); err != nil {
+			return fmt.Errorf("created account %s, but failed to grant role %s: %w", user.ID, role, err)
+		}
+	}
+
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(user)
+}
@@ -0,0 +1,32 @@
+package config
+
+import "time"
+
+// AccountConfig holds the configuration of local accounts and their sessions
+type AccountConfig struct {
+	// AccessTokenTTL is the lifetime of the access tokens issued when signing in or refreshing
+	AccessTokenTTL time.Duration
+	// RefreshTokenTTL is how long a session lasts without being refreshed
+	RefreshTokenTTL time.Duration
+	// MaxFailedLogins is the number of failed sign-ins that locks an account for LockoutDuration
+	MaxFailedLogins  int
+	LockoutDuration  time.Duration
+	PasswordResetTTL time.Duration
+	// PasswordResetURL is the frontend page linked from password reset emails
+	PasswordResetURL string
+	// TOTPIssuer names the API in authenticator apps
+	TOTPIssuer string
+}
+
+// loadAccountConfig reads the account configuration
+func loadAccountConfig(l *loader) *AccountConfig {
+	return &AccountConfig{
+		AccessTokenTTL:   l.Duration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:  l.Duration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		MaxFailedLogins:  l.Int("LOGIN_MAX_FAILURES", 5),
+		LockoutDuration:  l.Duration("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+		PasswordResetTTL: l.Duration("PASSWORD_RESET_TTL", time.Hour),
+		PasswordResetURL: l.String("PASSWORD_RESET_URL", ""),
+		TOTPIssuer:       l.String("TOTP_ISSUER", "Blog Management"),
+	}
+}
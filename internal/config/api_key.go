@@ -0,0 +1,19 @@
+package config
+
+import "time"
+
+// APIKeyConfig holds API key configuration
+type APIKeyConfig struct {
+	// DefaultTTL is the lifetime of keys created without an expiry
+	DefaultTTL time.Duration
+	// RotationGrace is how long a rotated key keeps working when the rotation names no grace period
+	RotationGrace time.Duration
+}
+
+// loadAPIKeyConfig reads the API key configuration
+func loadAPIKeyConfig(l *loader) *APIKeyConfig {
+	return &APIKeyConfig{
+		DefaultTTL:    l.Duration("API_KEY_DEFAULT_TTL", 90*24*time.Hour),
+		RotationGrace: l.Duration("API_KEY_ROTATION_GRACE", 24*time.Hour),
+	}
+}
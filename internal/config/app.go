@@ -0,0 +1,140 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ServerConfig holds the addresses the APIs listen on
+type ServerConfig struct {
+	Port int
+	// GRPCHost is the interface the gRPC API listens on; only local clients reach it by default
+	GRPCHost string
+	GRPCPort int
+}
+
+// loadServerConfig reads the server configuration
+func loadServerConfig(l *loader) *ServerConfig {
+	cfg := &ServerConfig{
+		Port:     l.Int("SERVER_PORT", 8080),
+		GRPCHost: l.String("GRPC_HOST", "localhost"),
+		GRPCPort: l.Int("GRPC_PORT", 9090),
+	}
+	if cfg.Port > 65535 {
+		l.errorf("invalid SERVER_PORT: must be at most 65535")
+	}
+	if cfg.GRPCPort > 65535 {
+		l.errorf("invalid GRPC_PORT: must be at most 65535")
+	}
+	return cfg
+}
+
+// AppConfig is the complete configuration of the application
+type AppConfig struct {
+	// File is the configuration file that was read, if any
+	File string
+
+	Server      *ServerConfig
+	Database    *DatabaseConfig
+	Cache       *CacheConfig
+	APIKey      *APIKeyConfig
+	RateLimit   *RateLimitConfig
+	Idempotency *IdempotencyConfig
+	Webhook     *WebhookConfig
+	Outbox      *OutboxConfig
+	Tenant      *TenantConfig
+	GraphQL     *GraphQLConfig
+	Auth        *AuthConfig
+	Account     *AccountConfig
+	Mail        *MailConfig
+
+	settings []Setting
+}
+
+// loadAppConfig reads every part of the configuration
+func loadAppConfig(l *loader) *AppConfig {
+	return &AppConfig{
+		Server:      loadServerConfig(l),
+		Database:    loadDatabaseConfig(l),
+		Cache:       loadCacheConfig(l),
+		APIKey:      loadAPIKeyConfig(l),
+		RateLimit:   loadRateLimitConfig(l),
+		Idempotency: loadIdempotencyConfig(l),
+		Webhook:     loadWebhookConfig(l),
+		Outbox:      loadOutboxConfig(l),
+		Tenant:      loadTenantConfig(l),
+		GraphQL:     loadGraphQLConfig(l),
+		Auth:        loadAuthConfig(l),
+		Account:     loadAccountConfig(l),
+		Mail:        loadMailConfig(l),
+	}
+}
+
+// Settings returns the effective value of every setting and where it came from, sorted by key
+func (c *AppConfig) Settings() []Setting {
+	return append([]Setting(nil), c.settings...)
+}
+
+// Load loads the configuration in layers: the defaults, then the YAML, JSON or TOML file named
+// by -config or CONFIG_FILE, then environment variables, then the flags in args. Every setting
+// but the secrets has a flag named after its environment variable, e.g. -db-host for DB_HOST;
+// secrets would show in the process list, so they are set by the environment, the file, or the
+// file named by <KEY>_FILE or its flag, e.g. -db-password-file. Load returns the arguments after
+// the flags, and every invalid setting at once.
+func Load(name string, args []string, output io.Writer) (*AppConfig, []string, error) {
+	// The settings are known by reading them once with nothing but their defaults
+	defaults := newLoader()
+	loadAppConfig(defaults)
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(output)
+	configFile := flags.String("config", os.Getenv("CONFIG_FILE"), "YAML, JSON or TOML configuration file (env CONFIG_FILE)")
+	for _, setting := range defaults.Settings() {
+		if setting.Secret {
+			flags.String(flagName(setting.Key+secretFileSuffix), "", "file to read "+setting.Key+" from")
+			continue
+		}
+		flags.String(flagName(setting.Key), setting.Value, "sets "+setting.Key)
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+	flagValues := make(map[string]string)
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name != "config" {
+			flagValues[strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))] = f.Value.String()
+		}
+	})
+
+	fileValues := map[string]string{}
+	if *configFile != "" {
+		var err error
+		if fileValues, err = readConfigFile(*configFile); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	l := newLoader(mapLayer(SourceFile, fileValues), envLayer(), mapLayer(SourceFlag, flagValues))
+	cfg := loadAppConfig(l)
+	cfg.File = *configFile
+	cfg.settings = l.Settings()
+	for _, key := range slices.Sorted(maps.Keys(fileValues)) {
+		if !l.known(key) {
+			l.errorf("unknown setting %s in %s", key, *configFile)
+		}
+	}
+	if err := l.err(); err != nil {
+		return nil, nil, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, flags.Args(), nil
+}
+
+// flagName returns the name of the flag of a setting
+func flagName(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFile writes a file into a temporary directory and returns its path
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// settingsByKey returns the settings of a configuration by their keys
+func settingsByKey(cfg *AppConfig) map[string]Setting {
+	settings := make(map[string]Setting)
+	for _, setting := range cfg.Settings() {
+		settings[setting.Key] = setting
+	}
+	return settings
+}
+
+func TestLoad_Layers(t *testing.T) {
+	file := writeFile(t, "config.yaml", `
+db:
+  host: db.internal
+  name: from_file
+  replica_urls: [postgres://replica-1/blog, postgres://replica-2/blog]
+CACHE_SIZE: 50
+server:
+  port: 8081
+`)
+	t.Setenv("DB_NAME", "from_env")
+	t.Setenv("SERVER_PORT", "8082")
+
+	cfg, args, err := Load("blog-api", []string{"-config", file, "-server-port", "8083", "export", "-format", "csv"}, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"export", "-format", "csv"}, args)
+
+	assert.Equal(t, "db.internal", cfg.Database.Host)
+	assert.Equal(t, "from_env", cfg.Database.DBName)
+	assert.Equal(t, []string{"postgres://replica-1/blog?connect_timeout=5", "postgres://replica-2/blog?connect_timeout=5"}, cfg.Database.ReplicaURLs)
+	assert.Equal(t, 50, cfg.Cache.Size)
+	assert.Equal(t, 8083, cfg.Server.Port)
+	assert.Equal(t, time.Minute, cfg.Cache.TTL)
+
+	settings := settingsByKey(cfg)
+	assert.Equal(t, SourceDefault, settings["CACHE_TTL"].Source)
+	assert.Equal(t, SourceFile, settings["DB_HOST"].Source)
+	assert.Equal(t, SourceEnv, settings["DB_NAME"].Source)
+	assert.Equal(t, SourceFlag, settings["SERVER_PORT"].Source)
+	assert.Equal(t, file, cfg.File)
+}
+
+func TestLoad_TOML(t *testing.T) {
+	file := writeFile(t, "config.toml", `
+CACHE_SIZE = 50
+
+[db]
+host = "db.internal"
+replica_urls = ["postgres://replica-1/blog", "postgres://replica-2/blog"]
+
+[rate_limit.default]
+read = "300/1m"
+`)
+
+	cfg, _, err := Load("blog-api", []string{"-config", file}, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, "db.internal", cfg.Database.Host)
+	assert.Equal(t, 50, cfg.Cache.Size)
+	assert.Len(t, cfg.Database.ReplicaURLs, 2)
+	assert.Equal(t, SourceFile, settingsByKey(cfg)["RATE_LIMIT_DEFAULT_READ"].Source)
+
+	_, _, err = Load("blog-api", []string{"-config", writeFile(t, "tables.toml", "[[db]]\nhost = \"a\"\n")}, io.Discard)
+	assert.ErrorContains(t, err, "list items must be plain values")
+}
+
+func TestLoad_Secrets(t *testing.T) {
+	t.Setenv("DB_PASSWORD_FILE", writeFile(t, "password", "s3cret\n"))
+	t.Setenv("JWT_SECRET", "signing-key")
+
+	cfg, _, err := Load("blog-api", nil, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", cfg.Database.Password)
+	assert.Equal(t, "signing-key", cfg.Auth.JWTSecret)
+
+	settings := settingsByKey(cfg)
+	assert.Equal(t, "<redacted>", settings["DB_PASSWORD"].Redacted())
+	assert.Equal(t, "<redacted>", settings["JWT_SECRET"].Redacted())
+	assert.Equal(t, "", settings["OUTBOX_HTTP_SECRET"].Redacted())
+	assert.Equal(t, "localhost", settings["DB_HOST"].Redacted())
+
+	// Secrets have no flags, as arguments show in the process list, but their files do
+	_, _, err = Load("blog-api", []string{"-jwt-secret", "from-flag"}, io.Discard)
+	assert.ErrorContains(t, err, "flag provided but not defined: -jwt-secret")
+	cfg, _, err = Load("blog-api", []string{"-jwt-secret-file", writeFile(t, "jwt", "from-file\n")}, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, "from-file", cfg.Auth.JWTSecret)
+	assert.Equal(t, SourceFlag, settingsByKey(cfg)["JWT_SECRET"].Source)
+
+	// A secret set both ways in one layer is ambiguous
+	t.Setenv("DB_PASSWORD", "other")
+	_, _, err = Load("blog-api", nil, io.Discard)
+	assert.ErrorContains(t, err, "DB_PASSWORD and DB_PASSWORD_FILE are both set")
+}
+
+func TestLoad_ReportsEveryError(t *testing.T) {
+	file := writeFile(t, "config.yml", "db:\n  hots: typo\n")
+	t.Setenv("DB_DRIVER", "mysql")
+	t.Setenv("CACHE_TTL", "soon")
+	t.Setenv("RBAC_ENABLED", "yes please")
+
+	_, _, err := Load("blog-api", []string{"-config", file, "-webhook-max-attempts", "0"}, io.Discard)
+	require.Error(t, err)
+	for _, message := range []string{
+		`invalid DB_DRIVER "mysql"`,
+		"invalid CACHE_TTL",
+		`invalid RBAC_ENABLED "yes please"`,
+		"invalid WEBHOOK_MAX_ATTEMPTS",
+		"unknown setting DB_HOTS",
+	} {
+		assert.ErrorContains(t, err, message)
+	}
+}
+
+func TestLoad_RejectsUnknownFileFormats(t *testing.T) {
+	_, _, err := Load("blog-api", []string{"-config", writeFile(t, "config.ini", "")}, io.Discard)
+	assert.ErrorContains(t, err, "unsupported configuration file format")
+}
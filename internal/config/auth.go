@@ -0,0 +1,29 @@
+package config
+
+import "BlogManagment/internal/rbac"
+
+// AuthConfig holds authentication configuration
+type AuthConfig struct {
+	JWTSecret string
+	// RBACEnabled restricts authenticated callers to the permissions of their roles. When disabled
+	// they may also change every post, as before roles were introduced. Anonymous callers may only
+	// read and administration needs the admin role either way.
+	RBACEnabled bool
+	// TwoFactorRoles are only held by users who signed in with a second factor
+	TwoFactorRoles []string
+}
+
+// loadAuthConfig reads the authentication configuration
+func loadAuthConfig(l *loader) *AuthConfig {
+	cfg := &AuthConfig{
+		JWTSecret:      l.Secret("JWT_SECRET", ""),
+		RBACEnabled:    l.Bool("RBAC_ENABLED", false),
+		TwoFactorRoles: l.List("TWO_FACTOR_REQUIRED_ROLES", false),
+	}
+	for _, role := range cfg.TwoFactorRoles {
+		if !rbac.ValidRole(role) {
+			l.errorf("invalid TWO_FACTOR_REQUIRED_ROLES: unknown role %q", role)
+		}
+	}
+	return cfg
+}
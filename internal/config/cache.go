@@ -0,0 +1,29 @@
+package config
+
+import "time"
+
+// CacheConfig holds the configuration of the blog post cache
+type CacheConfig struct {
+	Enabled bool
+	Size    int
+	TTL     time.Duration
+	// MaxAge is how long clients may reuse a post they read, sent in the Cache-Control header
+	MaxAge time.Duration
+}
+
+// loadCacheConfig reads the cache configuration
+func loadCacheConfig(l *loader) *CacheConfig {
+	cfg := &CacheConfig{
+		Enabled: l.Bool("CACHE_ENABLED", true),
+		Size:    l.Int("CACHE_SIZE", 1000),
+		TTL:     l.Duration("CACHE_TTL", time.Minute),
+		MaxAge:  l.Duration("CACHE_MAX_AGE", 0),
+	}
+	if cfg.TTL <= 0 {
+		l.errorf("invalid CACHE_TTL: must be positive")
+	}
+	if cfg.MaxAge < 0 {
+		l.errorf("invalid CACHE_MAX_AGE: must not be negative")
+	}
+	return cfg
+}
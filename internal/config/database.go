@@ -1,69 +1,527 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
 
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
+// Database drivers
+const (
+	DatabaseDriverPostgres = "postgres"
+	DatabaseDriverSQLite   = "sqlite"
+)
+
+// Blog post stores
+const (
+	BlogStoreDatabase = "database"
+	BlogStoreMemory   = "memory"
+)
+
+// sslModes are the accepted values of DB_SSLMODE, from least to most secure
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// maxConnectBackoff caps the delay between two attempts to reach the database at startup
+const maxConnectBackoff = 30 * time.Second
+
+// sleep waits between two attempts to reach the database; tests replace it
+var sleep = time.Sleep
+
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
+	Driver string
+	// URL is a PostgreSQL connection string that replaces the individual connection settings
+	URL      string
 	Host     string
 	Port     string
 	User     string
 	Password string
 	DBName   string
+	// Path is the database file of the SQLite driver
+	Path string
+	// BlogStore selects where blog posts are kept; the memory store loses them on restart
+	BlogStore string
+	// LogLevel is the level of SQL logging: silent, error, warn or info
+	LogLevel string
+	// AutoMigrate migrates the schema on connect; when disabled it is left to the migrate command
+	AutoMigrate bool
+	// ReplicaURLs are the connection strings of PostgreSQL read replicas of the database
+	ReplicaURLs []string
+	// ReplicaMaxLag is how far a replica may trail the primary and still serve reads
+	ReplicaMaxLag time.Duration
+	// ReplicaCheckInterval is how often the lag of every replica is measured
+	ReplicaCheckInterval time.Duration
+	// ReadYourWritesWindow is how long a caller reads from the primary after it wrote
+	ReadYourWritesWindow time.Duration
+
+	// MaxOpenConns and MaxIdleConns size the connection pool of every database
+	MaxOpenConns int
+	MaxIdleConns int
+	// ConnMaxLifetime and ConnMaxIdleTime close connections that are old or unused, so that
+	// failovers and load balancer changes are picked up
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	// StatementTimeout makes PostgreSQL cancel statements running longer; zero disables it
+	StatementTimeout time.Duration
+
+	// SSLMode is the libpq TLS mode; the verify modes check the server against SSLRootCert
+	SSLMode     string
+	SSLRootCert string
+	// SSLCert and SSLKey are the client certificate and its key, for certificate authentication
+	SSLCert string
+	SSLKey  string
+
+	// ConnectTimeout limits each attempt to reach PostgreSQL
+	ConnectTimeout time.Duration
+	// ConnectAttempts is how often the database is tried at startup, waiting ConnectBackoff
+	// after the first failure and twice as long after every further one
+	ConnectAttempts int
+	ConnectBackoff  time.Duration
 }
 
-// NewDatabaseConfig creates a new database configuration from environment variables
-func NewDatabaseConfig() *DatabaseConfig {
-	return &DatabaseConfig{
-		Host:     getEnv("DB_HOST", "localhost"),
-		Port:     getEnv("DB_PORT", "5432"),
-		User:     getEnv("DB_USER", "postgres"),
-		Password: getEnv("DB_PASSWORD", "password"),
-		DBName:   getEnv("DB_NAME", "blog_management"),
+// databaseLogLevels maps DB_LOG_LEVEL values to GORM log levels
+var databaseLogLevels = map[string]logger.LogLevel{
+	"silent": logger.Silent,
+	"error":  logger.Error,
+	"warn":   logger.Warn,
+	"info":   logger.Info,
+}
+
+// loadDatabaseConfig reads the database configuration
+func loadDatabaseConfig(l *loader) *DatabaseConfig {
+	cfg := &DatabaseConfig{
+		Driver:    l.String("DB_DRIVER", DatabaseDriverPostgres),
+		URL:       l.Secret("DATABASE_URL", ""),
+		Host:      l.String("DB_HOST", "localhost"),
+		Port:      l.String("DB_PORT", "5432"),
+		User:      l.String("DB_USER", "postgres"),
+		Password:  l.Secret("DB_PASSWORD", "password"),
+		DBName:    l.String("DB_NAME", "blog_management"),
+		Path:      l.String("DB_PATH", "blog.db"),
+		BlogStore: l.String("BLOG_STORE", BlogStoreDatabase),
+		LogLevel:  l.String("DB_LOG_LEVEL", "info"),
+
+		AutoMigrate: l.Bool("DB_AUTO_MIGRATE", true),
+
+		ReplicaURLs:          l.List("DB_REPLICA_URLS", true),
+		ReplicaMaxLag:        l.Duration("DB_REPLICA_MAX_LAG", 5*time.Second),
+		ReplicaCheckInterval: l.Duration("DB_REPLICA_CHECK_INTERVAL", 5*time.Second),
+		ReadYourWritesWindow: l.Duration("DB_READ_YOUR_WRITES_WINDOW", 10*time.Second),
+	}
+	if cfg.Driver != DatabaseDriverPostgres && cfg.Driver != DatabaseDriverSQLite {
+		l.errorf("invalid DB_DRIVER %q", cfg.Driver)
+	}
+	if cfg.BlogStore != BlogStoreDatabase && cfg.BlogStore != BlogStoreMemory {
+		l.errorf("invalid BLOG_STORE %q", cfg.BlogStore)
+	}
+	if _, ok := databaseLogLevels[cfg.LogLevel]; !ok {
+		l.errorf("invalid DB_LOG_LEVEL %q", cfg.LogLevel)
+	}
+	if len(cfg.ReplicaURLs) > 0 && cfg.Driver != DatabaseDriverPostgres {
+		l.errorf("DB_REPLICA_URLS requires DB_DRIVER=%s", DatabaseDriverPostgres)
+	}
+	if cfg.ReplicaCheckInterval <= 0 {
+		l.errorf("invalid DB_REPLICA_CHECK_INTERVAL: must be positive")
 	}
+
+	cfg.loadPool(l)
+	cfg.loadTLS(l)
+	cfg.loadConnect(l)
+	if cfg.Driver == DatabaseDriverPostgres {
+		cfg.applyToURLs(l)
+	}
+	return cfg
 }
 
-// Connect establishes a database connection
-func (c *DatabaseConfig) Connect() (*gorm.DB, error) {
-	// Prefer DATABASE_URL if set
-	databaseURL := os.Getenv("DATABASE_URL")
-	var dsn string
-	if databaseURL != "" {
-		dsn = databaseURL
+// loadPool reads the connection pool sizing and the statement timeout
+func (c *DatabaseConfig) loadPool(l *loader) {
+	c.MaxOpenConns = l.Int("DB_MAX_OPEN_CONNS", 25)
+	c.MaxIdleConns = l.Int("DB_MAX_IDLE_CONNS", 10)
+	c.ConnMaxLifetime = l.Duration("DB_CONN_MAX_LIFETIME", 30*time.Minute)
+	c.ConnMaxIdleTime = l.Duration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute)
+	c.StatementTimeout = l.Duration("DB_STATEMENT_TIMEOUT", 0)
+
+	if c.MaxIdleConns > c.MaxOpenConns {
+		l.errorf("invalid DB_MAX_IDLE_CONNS: must not exceed DB_MAX_OPEN_CONNS (%d)", c.MaxOpenConns)
+	}
+	if c.ConnMaxLifetime < 0 || c.ConnMaxIdleTime < 0 || c.StatementTimeout < 0 {
+		l.errorf("database timeouts must not be negative")
+	}
+}
+
+// loadTLS reads the TLS mode and certificate files of PostgreSQL connections
+func (c *DatabaseConfig) loadTLS(l *loader) {
+	c.SSLMode = l.String("DB_SSLMODE", "disable")
+	c.SSLRootCert = l.String("DB_SSLROOTCERT", "")
+	c.SSLCert = l.String("DB_SSLCERT", "")
+	c.SSLKey = l.String("DB_SSLKEY", "")
+
+	if !slices.Contains(sslModes, c.SSLMode) {
+		l.errorf("invalid DB_SSLMODE %q, expected one of: %s", c.SSLMode, strings.Join(sslModes, " "))
+	}
+	if (c.SSLCert == "") != (c.SSLKey == "") {
+		l.errorf("DB_SSLCERT and DB_SSLKEY must be set together")
+	}
+	for _, file := range []struct{ name, path string }{
+		{"DB_SSLROOTCERT", c.SSLRootCert}, {"DB_SSLCERT", c.SSLCert}, {"DB_SSLKEY", c.SSLKey},
+	} {
+		if file.path == "" {
+			continue
+		}
+		if _, err := os.Stat(file.path); err != nil {
+			l.errorf("invalid %s: %w", file.name, err)
+		}
+	}
+}
+
+// loadConnect reads how the database is reached at startup
+func (c *DatabaseConfig) loadConnect(l *loader) {
+	c.ConnectTimeout = l.Duration("DB_CONNECT_TIMEOUT", 5*time.Second)
+	c.ConnectAttempts = l.Int("DB_CONNECT_ATTEMPTS", 10)
+	c.ConnectBackoff = l.Duration("DB_CONNECT_BACKOFF", time.Second)
+
+	if c.ConnectTimeout < time.Second {
+		l.errorf("invalid DB_CONNECT_TIMEOUT: must be at least 1s")
+	}
+	if c.ConnectBackoff <= 0 {
+		l.errorf("invalid DB_CONNECT_BACKOFF: must be positive")
+	}
+}
+
+// dsnParam is a parameter of a PostgreSQL connection string taken from a setting
+type dsnParam struct {
+	key, value string
+	setting    string
+	// given is set when the setting was configured rather than left at its default
+	given bool
+}
+
+// applyToURLs adds the timeouts, and the TLS settings that were configured, to DATABASE_URL and
+// DB_REPLICA_URLS. Parameters a connection string sets itself are kept, but a value other than
+// the one configured is an error. TLS settings left at their defaults are not added, as
+// DB_SSLMODE defaults to disable where connection strings fall back to prefer.
+func (c *DatabaseConfig) applyToURLs(l *loader) {
+	params := []dsnParam{{
+		key: "connect_timeout", value: strconv.Itoa(int(c.ConnectTimeout / time.Second)),
+		setting: "DB_CONNECT_TIMEOUT", given: l.given("DB_CONNECT_TIMEOUT"),
+	}}
+	if c.StatementTimeout > 0 {
+		params = append(params, dsnParam{
+			key: "statement_timeout", value: strconv.FormatInt(c.StatementTimeout.Milliseconds(), 10),
+			setting: "DB_STATEMENT_TIMEOUT", given: true,
+		})
+	}
+	for _, tls := range []struct{ key, setting, value string }{
+		{"sslmode", "DB_SSLMODE", c.SSLMode},
+		{"sslrootcert", "DB_SSLROOTCERT", c.SSLRootCert},
+		{"sslcert", "DB_SSLCERT", c.SSLCert},
+		{"sslkey", "DB_SSLKEY", c.SSLKey},
+	} {
+		if l.given(tls.setting) {
+			params = append(params, dsnParam{key: tls.key, value: tls.value, setting: tls.setting, given: true})
+		}
+	}
+
+	var err error
+	if c.URL != "" {
+		if c.URL, err = withParams(c.URL, params); err != nil {
+			l.errorf("invalid DATABASE_URL: %w", err)
+		}
+	}
+	for i := range c.ReplicaURLs {
+		if c.ReplicaURLs[i], err = withParams(c.ReplicaURLs[i], params); err != nil {
+			l.errorf("invalid DB_REPLICA_URLS entry %d: %w", i+1, err)
+		}
+	}
+}
+
+// withParams adds the parameters a PostgreSQL connection string, either a URL or key/value
+// pairs, does not set itself. Errors leave out the connection string, as it may hold a password.
+func withParams(dsn string, params []dsnParam) (string, error) {
+	var u *url.URL
+	var query url.Values
+	var existing map[string]string
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		var err error
+		if u, err = url.Parse(dsn); err != nil {
+			return "", errors.New("malformed URL")
+		}
+		query = u.Query()
+		existing = make(map[string]string, len(query))
+		for key := range query {
+			existing[key] = query.Get(key)
+		}
 	} else {
-		dsn = fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=UTC",
-			c.Host, c.User, c.Password, c.DBName, c.Port)
+		var err error
+		if existing, err = parseKeyValueDSN(dsn); err != nil {
+			return "", err
+		}
+	}
+
+	for _, param := range params {
+		value, ok := existing[param.key]
+		switch {
+		case ok && param.given && value != param.value:
+			return "", fmt.Errorf("%s=%s conflicts with %s=%s", param.key, value, param.setting, param.value)
+		case ok:
+		case u != nil:
+			query.Set(param.key, param.value)
+		default:
+			dsn += " " + param.key + "=" + dsnValue(param.value)
+		}
+	}
+	if u != nil {
+		u.RawQuery = query.Encode()
+		return u.String(), nil
 	}
+	return dsn, nil
+}
+
+// parseKeyValueDSN returns the parameters of a key/value connection string, unquoting values
+// the way dsnValue quotes them
+func parseKeyValueDSN(dsn string) (map[string]string, error) {
+	const space = " \t\r\n"
+	params := make(map[string]string)
+	for rest := strings.TrimLeft(dsn, space); rest != ""; rest = strings.TrimLeft(rest, space) {
+		key, value, ok := strings.Cut(rest, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, space) {
+			return nil, errors.New("malformed connection string")
+		}
+		rest = strings.TrimLeft(value, space)
+
+		var unquoted strings.Builder
+		if quoted, ok := strings.CutPrefix(rest, "'"); ok {
+			rest = quoted
+			for {
+				if rest == "" {
+					return nil, errors.New("malformed connection string: unterminated quote")
+				}
+				c := rest[0]
+				rest = rest[1:]
+				if c == '\\' && rest != "" {
+					c, rest = rest[0], rest[1:]
+				} else if c == '\'' {
+					break
+				}
+				unquoted.WriteByte(c)
+			}
+		} else {
+			end := strings.IndexAny(rest, space)
+			if end < 0 {
+				end = len(rest)
+			}
+			unquoted.WriteString(rest[:end])
+			rest = rest[end:]
+		}
+		params[key] = unquoted.String()
+	}
+	return params, nil
+}
+
+// DSN returns the connection string, preferring DATABASE_URL if set. The timeouts and the TLS
+// settings that were configured are added to DATABASE_URL when the configuration is loaded.
+func (c *DatabaseConfig) DSN() string {
+	if c.Driver == DatabaseDriverSQLite {
+		return SQLiteDSN(c.Path)
+	}
+	if c.URL != "" {
+		return c.URL
+	}
+
+	params := []string{
+		"host=" + dsnValue(c.Host),
+		"user=" + dsnValue(c.User),
+		"password=" + dsnValue(c.Password),
+		"dbname=" + dsnValue(c.DBName),
+		"port=" + dsnValue(c.Port),
+		"sslmode=" + c.SSLMode,
+	}
+	for _, file := range [][2]string{{"sslrootcert", c.SSLRootCert}, {"sslcert", c.SSLCert}, {"sslkey", c.SSLKey}} {
+		if file[1] != "" {
+			params = append(params, file[0]+"="+dsnValue(file[1]))
+		}
+	}
+	params = append(params, "connect_timeout="+strconv.Itoa(int(c.ConnectTimeout/time.Second)))
+	if c.StatementTimeout > 0 {
+		params = append(params, "statement_timeout="+strconv.FormatInt(c.StatementTimeout.Milliseconds(), 10))
+	}
+	return strings.Join(append(params, "TimeZone=UTC"), " ")
+}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
-	})
+// dsnValue quotes a value of a key/value connection string when it is empty or contains
+// spaces, quotes or backslashes
+func dsnValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// SQLiteDSN returns the connection string of an SQLite database file. Writers wait for each
+// other instead of failing with "database is locked", and transactions take the write lock when
+// they begin, so that two of them never deadlock upgrading their read locks.
+func SQLiteDSN(path string) string {
+	return "file:" + path + "?_busy_timeout=5000&_journal_mode=WAL&_txlock=immediate&_foreign_keys=1"
+}
+
+// Connect establishes a database connection, migrating the schema if AutoMigrate is set
+func (c *DatabaseConfig) Connect() (*gorm.DB, error) {
+	dialector := postgres.Open(c.DSN())
+	if c.Driver == DatabaseDriverSQLite {
+		dialector = sqlite.Open(c.DSN())
+	}
+
+	db, err := c.open("Database", dialector)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
-
-	// Auto migrate the schema
-	if err := db.AutoMigrate(&models.Blog{}); err != nil {
-		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	if err := c.configurePool(db); err != nil {
+		closeDB(db)
+		return nil, err
+	}
+	if !c.AutoMigrate {
+		log.Println("Database connected")
+		return db, nil
+	}
+	if err := Migrate(db); err != nil {
+		closeDB(db)
+		return nil, err
 	}
 
 	log.Println("Database connected and migrated successfully")
 	return db, nil
 }
 
-// getEnv gets an environment variable or returns a default value
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// Migrate brings the schema of a database up to date
+func Migrate(db *gorm.DB) error {
+	// Posts created before workspaces were introduced belong to the default tenant, which must
+	// exist before the tenant foreign key of blogs is added
+	if err := db.AutoMigrate(&models.Tenant{}); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	defaultTenant := &models.Tenant{ID: models.DefaultTenantID, Name: "Default"}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(defaultTenant).Error; err != nil {
+		return fmt.Errorf("failed to create default tenant: %w", err)
+	}
+
+	// Auto migrate the schema
+	if err := db.AutoMigrate(&models.Blog{}, &models.BlogTag{}, &models.RateLimitBucket{}, &models.IdempotencyRecord{},
+		&models.Webhook{}, &models.WebhookDelivery{}, &models.OutboxEvent{}, &models.OutboxCursor{},
+		&models.RoleAssignment{}, &models.AccessDenial{}, &models.APIKey{}, &models.AuditEntry{},
+		&models.User{}, &models.Session{}, &models.RefreshToken{}, &models.PasswordResetToken{},
+		&models.RecoveryCode{}); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	if err := repository.ProtectAuditLog(db); err != nil {
+		return fmt.Errorf("failed to protect audit log: %w", err)
+	}
+
+	// Slugs are unique per tenant, replacing the global index of single-tenant deployments
+	if db.Migrator().HasIndex(&models.Blog{}, "idx_blogs_slug") {
+		if err := db.Migrator().DropIndex(&models.Blog{}, "idx_blogs_slug"); err != nil {
+			return fmt.Errorf("failed to migrate database: %w", err)
+		}
+	}
+	return nil
+}
+
+// ConnectReplicas opens every read replica, trying each like the primary at startup.
+// Replicas follow the schema of the primary, so they are not migrated, and a replica that is
+// still down does not stop the application: it stays out of rotation until a health check
+// reaches it. Replicas are named by their position in DB_REPLICA_URLS, which keeps credentials
+// out of logs.
+func (c *DatabaseConfig) ConnectReplicas() ([]repository.Replica, error) {
+	replicas := make([]repository.Replica, 0, len(c.ReplicaURLs))
+	for i, url := range c.ReplicaURLs {
+		name := fmt.Sprintf("replica-%d", i+1)
+		db, err := c.open("Replica "+strconv.Itoa(i+1), postgres.Open(url))
+		if err != nil {
+			log.Printf("Replica %d not reachable, leaving it out of rotation: %v", i+1, err)
+			config := c.gormConfig()
+			config.DisableAutomaticPing = true
+			db, err = gorm.Open(postgres.Open(url), config)
+		}
+		if err == nil {
+			if err = c.configurePool(db); err != nil {
+				closeDB(db)
+			}
+		}
+		if err != nil {
+			for _, replica := range replicas {
+				closeDB(replica.DB)
+			}
+			return nil, fmt.Errorf("failed to connect to replica %d: %w", i+1, err)
+		}
+		replicas = append(replicas, repository.Replica{Name: name, DB: db})
+	}
+	return replicas, nil
+}
+
+// open opens a database, trying again with exponential backoff while it cannot be reached,
+// so that the application can start before the database is ready. name is used in logs.
+func (c *DatabaseConfig) open(name string, dialector gorm.Dialector) (*gorm.DB, error) {
+	backoff := c.ConnectBackoff
+	for attempt := 1; ; attempt++ {
+		db, err := gorm.Open(dialector, c.gormConfig())
+		if err == nil {
+			return db, nil
+		}
+		if db != nil {
+			closeDB(db)
+		}
+		if attempt >= c.ConnectAttempts {
+			return nil, err
+		}
+		log.Printf("%s not reachable (attempt %d of %d), retrying in %s: %v", name, attempt, c.ConnectAttempts, backoff, err)
+		sleep(backoff)
+		backoff = min(2*backoff, maxConnectBackoff)
+	}
+}
+
+// closeDB closes the connections of a database that is given up on
+func closeDB(db *gorm.DB) {
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+}
+
+// configurePool applies the pool sizing and connection lifetimes to a database
+func (c *DatabaseConfig) configurePool(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to configure connection pool: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(c.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(c.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(c.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(c.ConnMaxIdleTime)
+	return nil
+}
+
+// gormConfig returns the GORM configuration with the configured SQL logging. Logs go to stderr
+// so that commands writing to stdout are not interleaved with them.
+func (c *DatabaseConfig) gormConfig() *gorm.Config {
+	return &gorm.Config{
+		Logger: logger.New(log.New(os.Stderr, "\r\n", log.LstdFlags), logger.Config{
+			SlowThreshold: 200 * time.Millisecond,
+			LogLevel:      databaseLogLevels[c.LogLevel],
+			Colorful:      true,
+		}),
 	}
-	return defaultValue
 }
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+)
+
+// loadTestDatabaseConfig loads the database configuration from values alone
+func loadTestDatabaseConfig(values map[string]string) (*DatabaseConfig, error) {
+	l := newLoader(mapLayer(SourceEnv, values))
+	cfg := loadDatabaseConfig(l)
+	return cfg, l.err()
+}
+
+func TestDSNValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "secret", want: "secret"},
+		{value: "", want: "''"},
+		{value: "two words", want: "'two words'"},
+		{value: "it's", want: `'it\'s'`},
+		{value: `back\slash`, want: `'back\\slash'`},
+		{value: "p@ss=word", want: "p@ss=word"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, dsnValue(tt.value))
+
+			params, err := parseKeyValueDSN("password=" + dsnValue(tt.value) + " sslmode=disable")
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{"password": tt.value, "sslmode": "disable"}, params, "values read back unchanged")
+		})
+	}
+}
+
+func TestDatabaseConfig_DSN(t *testing.T) {
+	base := DatabaseConfig{
+		Driver: DatabaseDriverPostgres, Host: "db", Port: "5432", User: "blog", Password: "secret",
+		DBName: "blog_management", SSLMode: "disable", ConnectTimeout: 5 * time.Second,
+	}
+
+	tests := []struct {
+		name   string
+		change func(c *DatabaseConfig)
+		want   string
+	}{
+		{
+			name:   "defaults",
+			change: func(c *DatabaseConfig) {},
+			want:   "host=db user=blog password=secret dbname=blog_management port=5432 sslmode=disable connect_timeout=5 TimeZone=UTC",
+		},
+		{
+			name:   "quoted password",
+			change: func(c *DatabaseConfig) { c.Password = `it's a \secret` },
+			want:   `host=db user=blog password='it\'s a \\secret' dbname=blog_management port=5432 sslmode=disable connect_timeout=5 TimeZone=UTC`,
+		},
+		{
+			name:   "empty password",
+			change: func(c *DatabaseConfig) { c.Password = "" },
+			want:   "host=db user=blog password='' dbname=blog_management port=5432 sslmode=disable connect_timeout=5 TimeZone=UTC",
+		},
+		{
+			name: "certificates",
+			change: func(c *DatabaseConfig) {
+				c.SSLMode, c.SSLRootCert, c.SSLCert, c.SSLKey = "verify-full", "/etc/ssl/ca.pem", "/etc/ssl/client cert.pem", "/etc/ssl/client.key"
+			},
+			want: "host=db user=blog password=secret dbname=blog_management port=5432 sslmode=verify-full " +
+				"sslrootcert=/etc/ssl/ca.pem sslcert='/etc/ssl/client cert.pem' sslkey=/etc/ssl/client.key connect_timeout=5 TimeZone=UTC",
+		},
+		{
+			name:   "statement timeout in milliseconds",
+			change: func(c *DatabaseConfig) { c.StatementTimeout = 1500 * time.Millisecond },
+			want:   "host=db user=blog password=secret dbname=blog_management port=5432 sslmode=disable connect_timeout=5 statement_timeout=1500 TimeZone=UTC",
+		},
+		{
+			name:   "URL",
+			change: func(c *DatabaseConfig) { c.URL = "postgres://blog:secret@db/blog_management" },
+			want:   "postgres://blog:secret@db/blog_management",
+		},
+		{
+			name:   "SQLite",
+			change: func(c *DatabaseConfig) { c.Driver, c.Path = DatabaseDriverSQLite, "blog.db" },
+			want:   SQLiteDSN("blog.db"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base
+			tt.change(&cfg)
+			assert.Equal(t, tt.want, cfg.DSN())
+		})
+	}
+}
+
+func TestLoadDatabaseConfig_TLS(t *testing.T) {
+	cert := writeFile(t, "client.pem", "certificate")
+	key := writeFile(t, "client.key", "key")
+
+	tests := []struct {
+		name    string
+		values  map[string]string
+		wantErr string
+	}{
+		{name: "certificates", values: map[string]string{"DB_SSLMODE": "verify-full", "DB_SSLROOTCERT": cert, "DB_SSLCERT": cert, "DB_SSLKEY": key}},
+		{name: "unknown mode", values: map[string]string{"DB_SSLMODE": "always"}, wantErr: `invalid DB_SSLMODE "always"`},
+		{name: "certificate without key", values: map[string]string{"DB_SSLCERT": cert}, wantErr: "DB_SSLCERT and DB_SSLKEY must be set together"},
+		{name: "missing file", values: map[string]string{"DB_SSLROOTCERT": cert + ".missing"}, wantErr: "invalid DB_SSLROOTCERT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadTestDatabaseConfig(tt.values)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadDatabaseConfig_AppliesSettingsToURLs(t *testing.T) {
+	cert := writeFile(t, "ca.pem", "certificate")
+
+	tests := []struct {
+		name         string
+		values       map[string]string
+		wantURL      string
+		wantReplicas []string
+		wantErr      string
+	}{
+		{
+			name:    "timeouts",
+			values:  map[string]string{"DATABASE_URL": "postgres://blog:secret@db/blog?application_name=api", "DB_STATEMENT_TIMEOUT": "2s"},
+			wantURL: "postgres://blog:secret@db/blog?application_name=api&connect_timeout=5&statement_timeout=2000",
+		},
+		{
+			name:    "key/value pairs",
+			values:  map[string]string{"DATABASE_URL": "host=db password='s3cret word'", "DB_STATEMENT_TIMEOUT": "2s"},
+			wantURL: "host=db password='s3cret word' connect_timeout=5 statement_timeout=2000",
+		},
+		{
+			name:         "replicas",
+			values:       map[string]string{"DB_REPLICA_URLS": "postgres://reader@replica-1/blog,postgres://reader@replica-2/blog", "DB_STATEMENT_TIMEOUT": "1s"},
+			wantReplicas: []string{"postgres://reader@replica-1/blog?connect_timeout=5&statement_timeout=1000", "postgres://reader@replica-2/blog?connect_timeout=5&statement_timeout=1000"},
+		},
+		{
+			name:    "TLS settings that were configured",
+			values:  map[string]string{"DATABASE_URL": "postgres://db/blog", "DB_SSLMODE": "verify-ca", "DB_SSLROOTCERT": cert},
+			wantURL: "postgres://db/blog?connect_timeout=5&sslmode=verify-ca&sslrootcert=" + url.QueryEscape(cert),
+		},
+		{
+			name:    "parameters of the URL",
+			values:  map[string]string{"DATABASE_URL": "postgres://db/blog?sslmode=require&connect_timeout=2"},
+			wantURL: "postgres://db/blog?connect_timeout=2&sslmode=require",
+		},
+		{
+			name:    "conflicting statement timeout",
+			values:  map[string]string{"DATABASE_URL": "postgres://blog:secret@db/blog?statement_timeout=500", "DB_STATEMENT_TIMEOUT": "2s"},
+			wantErr: "invalid DATABASE_URL: statement_timeout=500 conflicts with DB_STATEMENT_TIMEOUT=2000",
+		},
+		{
+			name:    "conflicting TLS mode",
+			values:  map[string]string{"DB_REPLICA_URLS": "host=replica sslmode=disable", "DB_SSLMODE": "require"},
+			wantErr: "invalid DB_REPLICA_URLS entry 1: sslmode=disable conflicts with DB_SSLMODE=require",
+		},
+		{
+			name:    "malformed key/value pairs",
+			values:  map[string]string{"DATABASE_URL": "host=db password='secret", "DB_STATEMENT_TIMEOUT": "2s"},
+			wantErr: "invalid DATABASE_URL: malformed connection string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := loadTestDatabaseConfig(tt.values)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.NotContains(t, err.Error(), "secret", "connection strings stay out of errors")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantURL, cfg.URL)
+			assert.Equal(t, tt.wantReplicas, cfg.ReplicaURLs)
+		})
+	}
+}
+
+func TestDatabaseConfig_OpenRetriesWithBackoff(t *testing.T) {
+	var waits []time.Duration
+	sleep = func(d time.Duration) { waits = append(waits, d) }
+	t.Cleanup(func() { sleep = time.Sleep })
+
+	cfg := &DatabaseConfig{LogLevel: "silent", ConnectAttempts: 6, ConnectBackoff: 10 * time.Second}
+	// Nothing listens on port 1, so every attempt is refused at once
+	_, err := cfg.open("Database", postgres.Open("host=127.0.0.1 port=1 user=blog dbname=blog sslmode=disable connect_timeout=1"))
+
+	assert.Error(t, err)
+	assert.Equal(t, []time.Duration{10 * time.Second, 20 * time.Second, 30 * time.Second, 30 * time.Second, 30 * time.Second}, waits,
+		"one wait between every two of the attempts, doubling up to the cap")
+}
@@ -0,0 +1,12 @@
+package config
+
+// GraphQLConfig holds the configuration of the GraphQL endpoint
+type GraphQLConfig struct {
+	// MaxCost is the highest estimated cost of an operation that is executed
+	MaxCost int
+}
+
+// loadGraphQLConfig reads the GraphQL configuration
+func loadGraphQLConfig(l *loader) *GraphQLConfig {
+	return &GraphQLConfig{MaxCost: l.Int("GRAPHQL_MAX_COST", 1000)}
+}
@@ -0,0 +1,13 @@
+package config
+
+import "time"
+
+// IdempotencyConfig holds Idempotency-Key handling configuration
+type IdempotencyConfig struct {
+	TTL time.Duration
+}
+
+// loadIdempotencyConfig reads the idempotency configuration
+func loadIdempotencyConfig(l *loader) *IdempotencyConfig {
+	return &IdempotencyConfig{TTL: l.Duration("IDEMPOTENCY_TTL", 24*time.Hour)}
+}
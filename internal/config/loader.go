@@ -0,0 +1,263 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Layers a setting can come from, from the lowest to the highest precedence
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// secretFileSuffix names the variant of a secret setting that holds the path of a file to read
+// the secret from, e.g. DB_PASSWORD_FILE
+const secretFileSuffix = "_FILE"
+
+// Setting is the effective value of a configuration key and the layer it came from. Keys are
+// the names of the environment variables.
+type Setting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+	Secret bool   `json:"secret,omitempty"`
+}
+
+// Redacted returns the value of the setting, or a placeholder if it is a secret that is set
+func (s Setting) Redacted() string {
+	if s.Secret && s.Value != "" {
+		return "<redacted>"
+	}
+	return s.Value
+}
+
+// layer is the settings of one source
+type layer struct {
+	name   string
+	lookup func(key string) (string, bool)
+}
+
+// loader reads settings from layered sources, remembering the effective value of every key it
+// was asked for and collecting every invalid value instead of stopping at the first
+type loader struct {
+	layers   []layer
+	settings []Setting
+	index    map[string]int
+	errs     []error
+}
+
+// newLoader creates a loader over layers given from the lowest to the highest precedence
+func newLoader(layers ...layer) *loader {
+	return &loader{layers: layers, index: make(map[string]int)}
+}
+
+// mapLayer returns a layer over fixed values
+func mapLayer(name string, values map[string]string) layer {
+	return layer{name: name, lookup: func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}}
+}
+
+// envLayer returns a layer over the environment. Empty variables count as unset, as lines like
+// "DB_REPLICA_URLS=" in config.env should not replace a default.
+func envLayer() layer {
+	return layer{name: SourceEnv, lookup: func(key string) (string, bool) {
+		value := os.Getenv(key)
+		return value, value != ""
+	}}
+}
+
+// lookup returns the value of a key from the highest layer that sets it. A secret may instead be
+// read from the file named by <key>_FILE.
+func (l *loader) lookup(key, defaultValue string, secret bool) string {
+	setting := Setting{Key: key, Value: defaultValue, Source: SourceDefault, Secret: secret}
+	for _, layer := range l.layers {
+		value, ok := layer.lookup(key)
+		path, fromFile := "", false
+		if secret {
+			path, fromFile = layer.lookup(key + secretFileSuffix)
+		}
+		switch {
+		case ok && fromFile:
+			l.errorf("%s and %s%s are both set in the %s", key, key, secretFileSuffix, layer.name)
+		case ok:
+			setting.Value, setting.Source = value, layer.name
+		case fromFile:
+			content, err := os.ReadFile(path)
+			if err != nil {
+				l.errorf("invalid %s%s: %w", key, secretFileSuffix, err)
+				continue
+			}
+			setting.Value, setting.Source = strings.TrimRight(string(content), "\r\n"), layer.name
+		}
+	}
+
+	if i, ok := l.index[key]; ok {
+		l.settings[i] = setting
+	} else {
+		l.index[key] = len(l.settings)
+		l.settings = append(l.settings, setting)
+	}
+	return setting.Value
+}
+
+// known reports whether a key, or the secret read from the file it names, has been read
+func (l *loader) known(key string) bool {
+	if _, ok := l.index[key]; ok {
+		return true
+	}
+	i, ok := l.index[strings.TrimSuffix(key, secretFileSuffix)]
+	return ok && strings.HasSuffix(key, secretFileSuffix) && l.settings[i].Secret
+}
+
+// given reports whether a setting that has been read was set rather than left at its default
+func (l *loader) given(key string) bool {
+	i, ok := l.index[key]
+	return ok && l.settings[i].Source != SourceDefault
+}
+
+// errorf records an invalid setting
+func (l *loader) errorf(format string, args ...interface{}) {
+	l.errs = append(l.errs, fmt.Errorf(format, args...))
+}
+
+// err returns every invalid setting found so far, or nil
+func (l *loader) err() error {
+	return errors.Join(l.errs...)
+}
+
+// String returns a setting
+func (l *loader) String(key, defaultValue string) string {
+	return l.lookup(key, defaultValue, false)
+}
+
+// Secret returns a setting that is never printed and may be read from a file
+func (l *loader) Secret(key, defaultValue string) string {
+	return l.lookup(key, defaultValue, true)
+}
+
+// List returns a comma-separated setting without empty entries
+func (l *loader) List(key string, secret bool) []string {
+	var items []string
+	for _, item := range strings.Split(l.lookup(key, "", secret), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Bool returns a boolean setting
+func (l *loader) Bool(key string, defaultValue bool) bool {
+	value := l.lookup(key, strconv.FormatBool(defaultValue), false)
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		l.errorf("invalid %s %q: must be true or false", key, value)
+		return defaultValue
+	}
+	return b
+}
+
+// Duration returns a duration setting
+func (l *loader) Duration(key string, defaultValue time.Duration) time.Duration {
+	value := l.lookup(key, defaultValue.String(), false)
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		l.errorf("invalid %s: %w", key, err)
+		return defaultValue
+	}
+	return duration
+}
+
+// Int returns a setting that must be a positive integer
+func (l *loader) Int(key string, defaultValue int) int {
+	value := l.lookup(key, strconv.Itoa(defaultValue), false)
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		l.errorf("invalid %s: must be a positive integer", key)
+		return defaultValue
+	}
+	return number
+}
+
+// Settings returns the effective value of every key read, sorted by key
+func (l *loader) Settings() []Setting {
+	settings := append([]Setting(nil), l.settings...)
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings
+}
+
+// readConfigFile reads a YAML, JSON or TOML configuration file into settings. Keys are the
+// names of the environment variables, case-insensitive, and nested mappings join their keys
+// with underscores, so "db: {host: x}" or a [db] table with host = "x" sets DB_HOST. Lists
+// become comma-separated values.
+func readConfigFile(path string) (map[string]string, error) {
+	// YAML is a superset of JSON, so one decoder reads both
+	unmarshal := yaml.Unmarshal
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml", ".json":
+	case ".toml":
+		unmarshal = toml.Unmarshal
+	default:
+		return nil, fmt.Errorf("unsupported configuration file format %q, expected .yaml, .yml, .json or .toml", ext)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file: %w", err)
+	}
+
+	var document map[string]interface{}
+	if err := unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+	values := make(map[string]string)
+	if err := flattenConfig("", document, values); err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// flattenConfig adds the values of a decoded configuration mapping to values
+func flattenConfig(prefix string, mapping map[string]interface{}, values map[string]string) error {
+	for name, value := range mapping {
+		key := strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+		switch value := value.(type) {
+		case nil:
+		case map[string]interface{}:
+			if err := flattenConfig(key, value, values); err != nil {
+				return err
+			}
+		case []map[string]interface{}:
+			return fmt.Errorf("%s: list items must be plain values", key)
+		case []interface{}:
+			items := make([]string, len(value))
+			for i, item := range value {
+				switch item.(type) {
+				case map[string]interface{}, []interface{}:
+					return fmt.Errorf("%s: list items must be plain values", key)
+				}
+				items[i] = fmt.Sprint(item)
+			}
+			values[key] = strings.Join(items, ",")
+		default:
+			values[key] = fmt.Sprint(value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package config
+
+import "BlogManagment/internal/mail"
+
+// MailConfig holds the configuration of outgoing email
+type MailConfig struct {
+	// Driver selects how email is delivered: "log" writes it to the application log and "file"
+	// to a file per message in Dir
+	Driver string
+	Dir    string
+	From   string
+}
+
+// loadMailConfig reads the mail configuration
+func loadMailConfig(l *loader) *MailConfig {
+	return &MailConfig{
+		Driver: l.String("MAIL_DRIVER", mail.DriverLog),
+		Dir:    l.String("MAIL_DIR", "mail"),
+		From:   l.String("MAIL_FROM", "no-reply@localhost"),
+	}
+}
@@ -0,0 +1,34 @@
+package config
+
+import "time"
+
+// OutboxConfig holds the configuration of the outbox relay and its sinks
+type OutboxConfig struct {
+	// PollInterval is how often the outbox is checked for new events
+	PollInterval time.Duration
+	// BatchSize is the number of events handed to a sink at once
+	BatchSize int
+	// Retention is how long events are kept after every sink has processed them
+	Retention time.Duration
+	// LogEvents enables the log sink
+	LogEvents bool
+	// HTTPURL enables the HTTP sink, which posts batches of events to this URL
+	HTTPURL string
+	// HTTPSecret signs the requests of the HTTP sink when set
+	HTTPSecret string
+	// HTTPTimeout bounds a single request of the HTTP sink
+	HTTPTimeout time.Duration
+}
+
+// loadOutboxConfig reads the outbox configuration
+func loadOutboxConfig(l *loader) *OutboxConfig {
+	return &OutboxConfig{
+		PollInterval: l.Duration("OUTBOX_POLL_INTERVAL", time.Second),
+		BatchSize:    l.Int("OUTBOX_BATCH_SIZE", 100),
+		Retention:    l.Duration("OUTBOX_RETENTION", 7*24*time.Hour),
+		LogEvents:    l.Bool("OUTBOX_LOG_EVENTS", false),
+		HTTPURL:      l.String("OUTBOX_HTTP_URL", ""),
+		HTTPSecret:   l.Secret("OUTBOX_HTTP_SECRET", ""),
+		HTTPTimeout:  l.Duration("OUTBOX_HTTP_TIMEOUT", 10*time.Second),
+	}
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"strings"
+	"time"
+
+	"BlogManagment/internal/ratelimit"
+)
+
+// Rate limit store backends
+const (
+	RateLimitStoreMemory   = "memory"
+	RateLimitStorePostgres = "postgres"
+)
+
+// GroupLimits holds the separate read and write budgets of a route group
+type GroupLimits struct {
+	Read  ratelimit.Limit
+	Write ratelimit.Limit
+}
+
+// RateLimitConfig holds rate limiting configuration
+type RateLimitConfig struct {
+	Enabled bool
+	Store   string
+	IdleTTL time.Duration
+	Default GroupLimits
+	Groups  map[string]GroupLimits
+}
+
+// RateLimitGroups are the route groups with budgets of their own
+var RateLimitGroups = []string{"blog", "transfer", "webhooks", "events", "graphql", "auth"}
+
+// loadRateLimitConfig reads the rate limit configuration. Per group budgets are read from
+// RATE_LIMIT_<GROUP>_READ and RATE_LIMIT_<GROUP>_WRITE.
+func loadRateLimitConfig(l *loader) *RateLimitConfig {
+	cfg := &RateLimitConfig{
+		Enabled: l.Bool("RATE_LIMIT_ENABLED", true),
+		Store:   l.String("RATE_LIMIT_STORE", RateLimitStoreMemory),
+		IdleTTL: l.Duration("RATE_LIMIT_IDLE_TTL", 10*time.Minute),
+		Default: loadGroupLimits(l, "RATE_LIMIT_DEFAULT", GroupLimits{}, "300/1m", "60/1m"),
+		Groups:  make(map[string]GroupLimits),
+	}
+	if cfg.Store != RateLimitStoreMemory && cfg.Store != RateLimitStorePostgres {
+		l.errorf("invalid RATE_LIMIT_STORE %q", cfg.Store)
+	}
+
+	for _, group := range RateLimitGroups {
+		prefix := "RATE_LIMIT_" + strings.ToUpper(strings.ReplaceAll(group, "-", "_"))
+		cfg.Groups[group] = loadGroupLimits(l, prefix, cfg.Default, "", "")
+	}
+	return cfg
+}
+
+// For returns the budgets of a route group, falling back to the defaults
+func (c *RateLimitConfig) For(group string) GroupLimits {
+	if limits, ok := c.Groups[group]; ok {
+		return limits
+	}
+	return c.Default
+}
+
+// loadGroupLimits reads <prefix>_READ and <prefix>_WRITE, keeping fallback values when unset
+func loadGroupLimits(l *loader, prefix string, fallback GroupLimits, defaultRead, defaultWrite string) GroupLimits {
+	limits := fallback
+
+	if value := l.String(prefix+"_READ", defaultRead); value != "" {
+		limit, err := ratelimit.ParseLimit(value)
+		if err != nil {
+			l.errorf("invalid %s_READ: %w", prefix, err)
+		} else {
+			limits.Read = limit
+		}
+	}
+
+	if value := l.String(prefix+"_WRITE", defaultWrite); value != "" {
+		limit, err := ratelimit.ParseLimit(value)
+		if err != nil {
+			l.errorf("invalid %s_WRITE: %w", prefix, err)
+		} else {
+			limits.Write = limit
+		}
+	}
+
+	return limits
+}
@@ -0,0 +1,20 @@
+package config
+
+import "strings"
+
+// TenantConfig holds the configuration of tenant resolution
+type TenantConfig struct {
+	// BaseDomain is the domain whose subdomains name tenants, e.g. "blogs.example.com" serves
+	// the tenant "acme" on acme.blogs.example.com; subdomains are ignored when empty
+	BaseDomain string
+	// Header is the request header naming the tenant
+	Header string
+}
+
+// loadTenantConfig reads the tenant configuration
+func loadTenantConfig(l *loader) *TenantConfig {
+	return &TenantConfig{
+		BaseDomain: strings.ToLower(strings.Trim(l.String("TENANT_BASE_DOMAIN", ""), ".")),
+		Header:     l.String("TENANT_HEADER", "X-Tenant-ID"),
+	}
+}
@@ -0,0 +1,31 @@
+package config
+
+import "time"
+
+// WebhookConfig holds webhook delivery configuration
+type WebhookConfig struct {
+	// PollInterval is how often the delivery queue is checked for due deliveries
+	PollInterval time.Duration
+	// Timeout bounds a single delivery request
+	Timeout time.Duration
+	// MaxAttempts is the number of attempts before a delivery becomes a dead letter
+	MaxAttempts int
+	// BackoffBase is the delay after the first failed attempt; it doubles with every further failure
+	BackoffBase time.Duration
+	// BackoffMax caps the delay between attempts
+	BackoffMax time.Duration
+	// Concurrency is the number of deliveries sent in parallel
+	Concurrency int
+}
+
+// loadWebhookConfig reads the webhook configuration
+func loadWebhookConfig(l *loader) *WebhookConfig {
+	return &WebhookConfig{
+		PollInterval: l.Duration("WEBHOOK_POLL_INTERVAL", 2*time.Second),
+		Timeout:      l.Duration("WEBHOOK_TIMEOUT", 10*time.Second),
+		MaxAttempts:  l.Int("WEBHOOK_MAX_ATTEMPTS", 8),
+		BackoffBase:  l.Duration("WEBHOOK_BACKOFF_BASE", 30*time.Second),
+		BackoffMax:   l.Duration("WEBHOOK_BACKOFF_MAX", 6*time.Hour),
+		Concurrency:  l.Int("WEBHOOK_CONCURRENCY", 4),
+	}
+}
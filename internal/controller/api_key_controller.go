@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/repository"
+	"BlogManagment/internal/service"
+	"BlogManagment/internal/tenant"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIKeyController handles HTTP requests for the API keys of a tenant
+type APIKeyController struct {
+	apiKeyService service.APIKeyService
+}
+
+// NewAPIKeyController creates a new API key controller instance
+func NewAPIKeyController(apiKeyService service.APIKeyService) *APIKeyController {
+	return &APIKeyController{apiKeyService: apiKeyService}
+}
+
+// CreateAPIKey handles POST /api/api-keys
+// @Summary Create an API key
+// @Description Create an API key for a machine client, limited to the given scopes. The key is only returned in this response.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param key body models.APIKeyCreateRequest true "API key data"
+// @Success 201 {object} map[string]interface{} "API key created successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request - validation error"
+// @Failure 403 {object} map[string]interface{} "Forbidden - the caller lacks a permission in the scopes"
+// @Router /api-keys [post]
+func (c *APIKeyController) CreateAPIKey(ctx *fiber.Ctx) error {
+	var request models.APIKeyCreateRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid request body",
+			"message": err.Error(),
+		})
+	}
+
+	key, err := c.apiKeyService.CreateAPIKey(tenant.FromCtx(ctx), rbac.FromContext(ctx.UserContext()), &request)
+	if err != nil {
+		if errors.Is(err, rbac.ErrForbidden) {
+			return forbidden(ctx, err)
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Failed to create API key",
+			"message": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "API key created successfully",
+		"data":    key,
+	})
+}
+
+// GetAllAPIKeys handles GET /api/api-keys
+// @Summary Get all API keys
+// @Description Retrieve the API keys of the tenant, newest first, without their secrets
+// @Tags api-keys
+// @Produce json
+// @Success 200 {object} map[string]interface{} "API keys retrieved successfully"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api-keys [get]
+func (c *APIKeyController) GetAllAPIKeys(ctx *fiber.Ctx) error {
+	keys, err := c.apiKeyService.ListAPIKeys(tenant.FromCtx(ctx))
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to retrieve API keys",
+			"message": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "API keys retrieved successfully",
+		"data":    keys,
+		"count":   len(keys),
+	})
+}
+
+// GetAPIKey handles GET /api/api-keys/:id
+// @Summary Get an API key by ID
+// @Description Retrieve an API key, including when it was last used, without its secret
+// @Tags api-keys
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 200 {object} map[string]interface{} "API key retrieved successfully"
+// @Failure 404 {object} map[string]interface{} "API key not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api-keys/{id} [get]
+func (c *APIKeyController) GetAPIKey(ctx *fiber.Ctx) error {
+	key, err := c.apiKeyService.GetAPIKey(tenant.FromCtx(ctx), ctx.Params("id"))
+	if err != nil {
+		return apiKeyError(ctx, "Failed to retrieve API key", err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "API key retrieved successfully",
+		"data":    key,
+	})
+}
+
+// RotateAPIKey handles POST /api/api-keys/:id/rotate
+// @Summary Rotate an API key
+// @Description Replace an API key with a new one of the same name and scopes. The old key keeps working for the grace period. The new key is only returned in this response.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param id path string true "API key ID"
+// @Param rotation body models.APIKeyRotateRequest false "Grace period of the old key"
+// @Success 201 {object} map[string]interface{} "API key rotated successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request - invalid grace period"
+// @Failure 404 {object} map[string]interface{} "API key not found"
+// @Failure 409 {object} map[string]interface{} "API key was revoked, expired or already rotated"
+// @Router /api-keys/{id}/rotate [post]
+func (c *APIKeyController) RotateAPIKey(ctx *fiber.Ctx) error {
+	var request models.APIKeyRotateRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&request); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid request body",
+				"message": err.Error(),
+			})
+		}
+	}
+
+	key, err := c.apiKeyService.RotateAPIKey(tenant.FromCtx(ctx), ctx.Params("id"), &request)
+	if err != nil {
+		return apiKeyError(ctx, "Failed to rotate API key", err)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "API key rotated successfully",
+		"data":    key,
+	})
+}
+
+// RevokeAPIKey handles DELETE /api/api-keys/:id
+// @Summary Revoke an API key
+// @Description Disable an API key immediately. The key stays listed as revoked.
+// @Tags api-keys
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 200 {object} map[string]interface{} "API key revoked successfully"
+// @Failure 404 {object} map[string]interface{} "API key not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api-keys/{id} [delete]
+func (c *APIKeyController) RevokeAPIKey(ctx *fiber.Ctx) error {
+	if err := c.apiKeyService.RevokeAPIKey(tenant.FromCtx(ctx), ctx.Params("id")); err != nil {
+		return apiKeyError(ctx, "Failed to revoke API key", err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "API key revoked successfully",
+	})
+}
+
+// apiKeyError responds to a failed API key operation
+func apiKeyError(ctx *fiber.Ctx, title string, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrAPIKeyNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error":   "API key not found",
+			"message": "The requested API key does not exist",
+		})
+	case errors.Is(err, repository.ErrAPIKeyNotRotatable):
+		return ctx.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":   title,
+			"message": "Only active keys that have not been rotated can be rotated",
+		})
+	case errors.Is(err, service.ErrInvalidGracePeriod):
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   title,
+			"message": err.Error(),
+		})
+	default:
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   title,
+			"message": err.Error(),
+		})
+	}
+}
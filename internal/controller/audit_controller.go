@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/service"
+	"BlogManagment/internal/tenant"
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// AuditController handles HTTP requests for the audit log of a tenant
+type AuditController struct {
+	auditService service.AuditService
+}
+
+// NewAuditController creates a new audit controller instance
+func NewAuditController(auditService service.AuditService) *AuditController {
+	return &AuditController{auditService: auditService}
+}
+
+// GetAuditEntries handles GET /api/audit
+// @Summary Get audit log entries
+// @Description Retrieve a page of the audit log of the tenant, newest first. Pass next_before back as before to fetch the following page.
+// @Tags audit
+// @Produce json
+// @Param subject query string false "Only entries of this principal"
+// @Param action query string false "Only entries of this action (post.create, post.update or post.delete)"
+// @Param target_id query string false "Only entries about this post"
+// @Param since query string false "Only entries recorded at or after this RFC 3339 time"
+// @Param until query string false "Only entries recorded before this RFC 3339 time"
+// @Param before query int false "Only entries with a smaller ID"
+// @Param limit query int false "Number of entries, at most 500" default(50)
+// @Success 200 {object} map[string]interface{} "Audit entries retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request - invalid filter"
+// @Router /audit [get]
+func (c *AuditController) GetAuditEntries(ctx *fiber.Ctx) error {
+	filter, err := parseAuditFilter(ctx)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid audit filter",
+			"message": err.Error(),
+		})
+	}
+	limit, err := strconv.Atoi(ctx.Query("limit", "0"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid audit filter",
+			"message": "limit must be a number",
+		})
+	}
+
+	page, err := c.auditService.ListAuditEntries(tenant.FromCtx(ctx), filter, limit)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid audit filter",
+			"message": err.Error(),
+		})
+	}
+
+	response := fiber.Map{
+		"message": "Audit entries retrieved successfully",
+		"data":    page.Entries,
+		"count":   len(page.Entries),
+	}
+	if page.NextBefore != 0 {
+		response["next_before"] = page.NextBefore
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// ExportAuditEntries handles GET /api/audit/export
+// @Summary Export audit log entries
+// @Description Stream every audit entry of the tenant matching the filter as JSON Lines or CSV, oldest first
+// @Tags audit
+// @Produce plain
+// @Param format query string false "Export format (jsonl or csv)" default(jsonl)
+// @Param subject query string false "Only entries of this principal"
+// @Param action query string false "Only entries of this action (post.create, post.update or post.delete)"
+// @Param target_id query string false "Only entries about this post"
+// @Param since query string false "Only entries recorded at or after this RFC 3339 time"
+// @Param until query string false "Only entries recorded before this RFC 3339 time"
+// @Success 200 {string} string "Exported audit entries"
+// @Failure 400 {object} map[string]interface{} "Bad request - unsupported format or invalid filter"
+// @Router /audit/export [get]
+func (c *AuditController) ExportAuditEntries(ctx *fiber.Ctx) error {
+	format := ctx.Query("format", models.TransferFormatJSONL)
+	if err := service.ValidateExportFormat(format); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid export format",
+			"message": err.Error(),
+		})
+	}
+	filter, err := parseAuditFilter(ctx)
+	if err == nil {
+		err = service.ValidateAuditFilter(filter)
+	}
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid audit filter",
+			"message": err.Error(),
+		})
+	}
+
+	contentType := "application/x-ndjson"
+	if format == models.TransferFormatCSV {
+		contentType = "text/csv; charset=utf-8"
+	}
+	filename := fmt.Sprintf("audit-export-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+
+	ctx.Set(fiber.HeaderContentType, contentType)
+	ctx.Set(fiber.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	tenantID := tenant.FromCtx(ctx)
+	ctx.Status(fiber.StatusOK)
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// The status line is already sent, so failures can only be logged
+		count, err := c.auditService.ExportAuditEntries(w, tenantID, filter, format)
+		if err != nil {
+			log.Printf("Audit export failed after %d entries: %v", count, err)
+		}
+		if err := w.Flush(); err != nil {
+			log.Printf("Audit export failed to flush: %v", err)
+		}
+	})
+
+	return nil
+}
+
+// parseAuditFilter reads the audit filter from the query of a request. The filter is used
+// after the request is done when exporting, so it must not share Fiber's buffers.
+func parseAuditFilter(ctx *fiber.Ctx) (*models.AuditFilter, error) {
+	filter := &models.AuditFilter{
+		Subject:  utils.CopyString(ctx.Query("subject")),
+		Action:   utils.CopyString(ctx.Query("action")),
+		TargetID: utils.CopyString(ctx.Query("target_id")),
+	}
+
+	var err error
+	if filter.Since, err = parseAuditTime(ctx, "since"); err != nil {
+		return nil, err
+	}
+	if filter.Until, err = parseAuditTime(ctx, "until"); err != nil {
+		return nil, err
+	}
+
+	if before := ctx.Query("before"); before != "" {
+		id, err := strconv.ParseInt(before, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, errors.New("before must be the id of an entry")
+		}
+		filter.Before = id
+	}
+	return filter, nil
+}
+
+// parseAuditTime reads an optional RFC 3339 time from the query parameter param
+func parseAuditTime(ctx *fiber.Ctx, param string) (*time.Time, error) {
+	value := ctx.Query(param)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC 3339 time such as 2024-01-02T15:04:05Z", param)
+	}
+	return &parsed, nil
+}
@@ -0,0 +1,459 @@
+package controller
+
+import (
+	"BlogManagment/internal/auth"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"BlogManagment/internal/service"
+	"BlogManagment/internal/tenant"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuthController handles HTTP requests for local accounts: registration, signing in and out,
+// sessions, password resets and two-factor authentication
+type AuthController struct {
+	accountService service.AccountService
+}
+
+// NewAuthController creates a new auth controller instance
+func NewAuthController(accountService service.AccountService) *AuthController {
+	return &AuthController{accountService: accountService}
+}
+
+// Register handles POST /api/auth/register
+// @Summary Create an account
+// @Description Create a local account of the tenant with an email address and a password of at least 12 characters
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param account body models.RegisterRequest true "Account data"
+// @Success 201 {object} map[string]interface{} "Account created successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request - validation error"
+// @Failure 401 {object} map[string]interface{} "Registration is closed to anonymous callers without RBAC"
+// @Failure 403 {object} map[string]interface{} "Registration needs role:manage without RBAC"
+// @Failure 409 {object} map[string]interface{} "An account with this email already exists"
+// @Router /auth/register [post]
+func (c *AuthController) Register(ctx *fiber.Ctx) error {
+	var request models.RegisterRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return invalidBody(ctx, err)
+	}
+
+	user, err := c.accountService.Register(tenant.FromCtx(ctx), &request)
+	if err != nil {
+		status := fiber.StatusBadRequest
+		if errors.Is(err, service.ErrEmailTaken) {
+			status = fiber.StatusConflict
+		}
+		return ctx.Status(status).JSON(fiber.Map{
+			"error":   "Failed to create account",
+			"message": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Account created successfully",
+		"data":    user,
+	})
+}
+
+// Login handles POST /api/auth/login
+// @Summary Sign in
+// @Description Start a session with the credentials of an account. Returns a short-lived access token, sent as a bearer token, and a refresh token. Accounts with two-factor authentication also send totp_code or recovery_code. Accounts are locked for a while after repeated failures.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body models.LoginRequest true "Credentials"
+// @Success 200 {object} map[string]interface{} "Signed in successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request - validation error"
+// @Failure 401 {object} map[string]interface{} "Invalid email or password, or a two-factor code is required or invalid"
+// @Failure 423 {object} map[string]interface{} "Account locked after too many failed attempts"
+// @Router /auth/login [post]
+func (c *AuthController) Login(ctx *fiber.Ctx) error {
+	var request models.LoginRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return invalidBody(ctx, err)
+	}
+
+	tokens, err := c.accountService.Login(tenant.FromCtx(ctx), &request, ctx.Get(fiber.HeaderUserAgent), ctx.IP())
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrTwoFactorRequired):
+			return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Two-factor authentication required",
+				"message": err.Error(),
+			})
+		case errors.Is(err, service.ErrInvalidCredentials), errors.Is(err, service.ErrInvalidTwoFactorCode):
+			return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Unauthorized",
+				"message": err.Error(),
+			})
+		case isAccountLocked(err):
+			return accountLocked(ctx, err)
+		default:
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Failed to sign in",
+				"message": err.Error(),
+			})
+		}
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Signed in successfully",
+		"data":    tokens,
+	})
+}
+
+// Refresh handles POST /api/auth/refresh
+// @Summary Refresh a session
+// @Description Exchange a refresh token for a new access token and a new refresh token. Each refresh token works once; using it again revokes the session.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param token body models.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} map[string]interface{} "Session refreshed successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request - validation error"
+// @Failure 401 {object} map[string]interface{} "Invalid, expired or reused refresh token"
+// @Router /auth/refresh [post]
+func (c *AuthController) Refresh(ctx *fiber.Ctx) error {
+	refreshToken, err := parseRefreshToken(ctx)
+	if err != nil {
+		return invalidBody(ctx, err)
+	}
+
+	tokens, err := c.accountService.Refresh(tenant.FromCtx(ctx), refreshToken)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) || errors.Is(err, service.ErrRefreshTokenReused) {
+			return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Unauthorized",
+				"message": err.Error(),
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to refresh session",
+			"message": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Session refreshed successfully",
+		"data":    tokens,
+	})
+}
+
+// Logout handles POST /api/auth/logout
+// @Summary Sign out
+// @Description End the session of a refresh token. Access tokens already issued keep working until they expire.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param token body models.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} map[string]interface{} "Signed out successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request - validation error"
+// @Router /auth/logout [post]
+func (c *AuthController) Logout(ctx *fiber.Ctx) error {
+	refreshToken, err := parseRefreshToken(ctx)
+	if err != nil {
+		return invalidBody(ctx, err)
+	}
+
+	if err := c.accountService.Logout(tenant.FromCtx(ctx), refreshToken); err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to sign out",
+			"message": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Signed out successfully",
+	})
+}
+
+// GetSessions handles GET /api/auth/sessions
+// @Summary List sessions
+// @Description Retrieve the active sessions of the signed-in user, most recently used first
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Sessions retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Router /auth/sessions [get]
+func (c *AuthController) GetSessions(ctx *fiber.Ctx) error {
+	userID, ok := signedInUser(ctx)
+	if !ok {
+		return notSignedIn(ctx)
+	}
+
+	sessions, err := c.accountService.ListSessions(tenant.FromCtx(ctx), userID)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to retrieve sessions",
+			"message": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Sessions retrieved successfully",
+		"data":    sessions,
+		"count":   len(sessions),
+	})
+}
+
+// RevokeSession handles DELETE /api/auth/sessions/:id
+// @Summary Revoke a session
+// @Description End a session of the signed-in user, such as one on a lost device
+// @Tags auth
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} map[string]interface{} "Session revoked successfully"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "Session not found"
+// @Router /auth/sessions/{id} [delete]
+func (c *AuthController) RevokeSession(ctx *fiber.Ctx) error {
+	userID, ok := signedInUser(ctx)
+	if !ok {
+		return notSignedIn(ctx)
+	}
+
+	if err := c.accountService.RevokeSession(tenant.FromCtx(ctx), userID, ctx.Params("id")); err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error":   "Session not found",
+				"message": "The requested session does not exist",
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to revoke session",
+			"message": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Session revoked successfully",
+	})
+}
+
+// RequestPasswordReset handles POST /api/auth/password-reset
+// @Summary Ask for a password reset
+// @Description Email a password reset token to the account with the given email. The response is the same whether or not the account exists.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param reset body models.PasswordResetRequest true "Email of the account"
+// @Success 202 {object} map[string]interface{} "Password reset email sent if the account exists"
+// @Failure 400 {object} map[string]interface{} "Bad request - validation error"
+// @Router /auth/password-reset [post]
+func (c *AuthController) RequestPasswordReset(ctx *fiber.Ctx) error {
+	var request models.PasswordResetRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return invalidBody(ctx, err)
+	}
+
+	if err := c.accountService.RequestPasswordReset(tenant.FromCtx(ctx), &request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Failed to request password reset",
+			"message": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "If an account with this email exists, a password reset email has been sent",
+	})
+}
+
+// ResetPassword handles POST /api/auth/password-reset/confirm
+// @Summary Reset a password
+// @Description Choose a new password with the token of a password reset email. Every session of the account is ended.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param reset body models.PasswordResetConfirmRequest true "Reset token and new password"
+// @Success 200 {object} map[string]interface{} "Password reset successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request - invalid or expired token, or validation error"
+// @Router /auth/password-reset/confirm [post]
+func (c *AuthController) ResetPassword(ctx *fiber.Ctx) error {
+	var request models.PasswordResetConfirmRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return invalidBody(ctx, err)
+	}
+
+	if err := c.accountService.ResetPassword(tenant.FromCtx(ctx), &request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Failed to reset password",
+			"message": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Password reset successfully",
+	})
+}
+
+// EnrollTOTP handles POST /api/auth/2fa/enroll
+// @Summary Enroll an authenticator app
+// @Description Create the secret of an authenticator app for the signed-in user. Two-factor authentication is turned on once a first code is confirmed; enrolling again replaces the secret.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Authenticator app enrolled, confirm it with a code"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 409 {object} map[string]interface{} "Two-factor authentication is already enabled"
+// @Router /auth/2fa/enroll [post]
+func (c *AuthController) EnrollTOTP(ctx *fiber.Ctx) error {
+	userID, ok := signedInUser(ctx)
+	if !ok {
+		return notSignedIn(ctx)
+	}
+
+	enrollment, err := c.accountService.EnrollTOTP(tenant.FromCtx(ctx), userID)
+	if err != nil {
+		return twoFactorError(ctx, "Failed to enroll authenticator app", err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Authenticator app enrolled, confirm it with a code",
+		"data":    enrollment,
+	})
+}
+
+// ConfirmTOTP handles POST /api/auth/2fa/confirm
+// @Summary Turn on two-factor authentication
+// @Description Confirm the enrolled authenticator app with a code. Returns recovery codes, which are only shown once.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param code body models.TwoFactorCodeRequest true "Code of the authenticator app"
+// @Success 200 {object} map[string]interface{} "Two-factor authentication enabled"
+// @Failure 400 {object} map[string]interface{} "Bad request - invalid code, or no authenticator app enrolled"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 409 {object} map[string]interface{} "Two-factor authentication is already enabled"
+// @Router /auth/2fa/confirm [post]
+func (c *AuthController) ConfirmTOTP(ctx *fiber.Ctx) error {
+	userID, ok := signedInUser(ctx)
+	if !ok {
+		return notSignedIn(ctx)
+	}
+
+	var request models.TwoFactorCodeRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return invalidBody(ctx, err)
+	}
+
+	codes, err := c.accountService.ConfirmTOTP(tenant.FromCtx(ctx), userID, &request)
+	if err != nil {
+		return twoFactorError(ctx, "Failed to enable two-factor authentication", err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Two-factor authentication enabled",
+		"data":    codes,
+	})
+}
+
+// DisableTOTP handles POST /api/auth/2fa/disable
+// @Summary Turn off two-factor authentication
+// @Description Turn off two-factor authentication of the signed-in user with a code of the authenticator app or a recovery code. Wrong codes count as failed sign-ins.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param code body models.TwoFactorCodeRequest true "Code of the authenticator app, or a recovery code"
+// @Success 200 {object} map[string]interface{} "Two-factor authentication disabled"
+// @Failure 400 {object} map[string]interface{} "Bad request - invalid code, or two-factor authentication is not enabled"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 423 {object} map[string]interface{} "Account locked after too many failed attempts"
+// @Router /auth/2fa/disable [post]
+func (c *AuthController) DisableTOTP(ctx *fiber.Ctx) error {
+	userID, ok := signedInUser(ctx)
+	if !ok {
+		return notSignedIn(ctx)
+	}
+
+	var request models.TwoFactorCodeRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return invalidBody(ctx, err)
+	}
+
+	if err := c.accountService.DisableTOTP(tenant.FromCtx(ctx), userID, &request); err != nil {
+		return twoFactorError(ctx, "Failed to disable two-factor authentication", err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Two-factor authentication disabled",
+	})
+}
+
+// twoFactorError responds to a failed change of the two-factor authentication of an account
+func twoFactorError(ctx *fiber.Ctx, title string, err error) error {
+	status := fiber.StatusBadRequest
+	switch {
+	case isAccountLocked(err):
+		return accountLocked(ctx, err)
+	case errors.Is(err, service.ErrTwoFactorEnabled):
+		status = fiber.StatusConflict
+	case errors.Is(err, repository.ErrUserNotFound):
+		status = fiber.StatusNotFound
+	}
+	return ctx.Status(status).JSON(fiber.Map{
+		"error":   title,
+		"message": err.Error(),
+	})
+}
+
+// isAccountLocked reports whether err is an AccountLockedError
+func isAccountLocked(err error) bool {
+	var locked *service.AccountLockedError
+	return errors.As(err, &locked)
+}
+
+// accountLocked responds to a request refused because the account is locked, telling the
+// client when to try again
+func accountLocked(ctx *fiber.Ctx, err error) error {
+	var locked *service.AccountLockedError
+	errors.As(err, &locked)
+	retryAfter := math.Ceil(time.Until(locked.Until).Seconds())
+	ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Max(retryAfter, 1))))
+	return ctx.Status(fiber.StatusLocked).JSON(fiber.Map{
+		"error":   "Account locked",
+		"message": err.Error(),
+	})
+}
+
+// invalidBody responds to a request whose body cannot be parsed
+func invalidBody(ctx *fiber.Ctx, err error) error {
+	return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error":   "Invalid request body",
+		"message": err.Error(),
+	})
+}
+
+// parseRefreshToken reads the refresh token from the body of a request
+func parseRefreshToken(ctx *fiber.Ctx) (string, error) {
+	var request models.RefreshTokenRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return "", err
+	}
+	if request.RefreshToken == "" {
+		return "", errors.New("refresh_token is required")
+	}
+	return request.RefreshToken, nil
+}
+
+// signedInUser returns the ID of the user who signed in to make the request
+func signedInUser(ctx *fiber.Ctx) (string, bool) {
+	principal := auth.PrincipalFromCtx(ctx)
+	if principal == nil || principal.Kind != auth.KindUser {
+		return "", false
+	}
+	return principal.Subject, true
+}
+
+// notSignedIn responds to a request that needs a signed-in user
+func notSignedIn(ctx *fiber.Ctx) error {
+	return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+		"error":   "Unauthorized",
+		"message": "Sign in with the bearer token of a user to manage the account",
+	})
+}
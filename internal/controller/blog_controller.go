@@ -1,9 +1,13 @@
 package controller
 
 import (
+	"BlogManagment/internal/audit"
 	"BlogManagment/internal/models"
+	"BlogManagment/internal/rbac"
 	"BlogManagment/internal/service"
-	_ "strconv"
+	"BlogManagment/internal/tenant"
+	"errors"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -18,6 +22,28 @@ func NewBlogController(blogService service.BlogService) *BlogController {
 	return &BlogController{blogService: blogService}
 }
 
+// blogs returns the blog service scoped to the tenant of the request and restricted to the
+// permissions of its caller, attributing its changes to the requester in the audit log
+func (c *BlogController) blogs(ctx *fiber.Ctx) service.BlogService {
+	blogService := c.blogService.WithTenant(tenant.FromCtx(ctx)).WithRequester(audit.FromContext(ctx.UserContext()))
+	return service.WithAccessControl(blogService, rbac.FromContext(ctx.UserContext()))
+}
+
+// forbidden responds to a request refused because its caller lacks a permission:
+// with 401 for anonymous callers and 403 for authenticated ones
+func forbidden(ctx *fiber.Ctx, err error) error {
+	if rbac.FromContext(ctx.UserContext()).Subject == "" {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error":   "Unauthorized",
+			"message": "Authentication required: " + err.Error(),
+		})
+	}
+	return ctx.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"error":   "Forbidden",
+		"message": err.Error(),
+	})
+}
+
 // CreateBlog handles POST /api/blog-post
 // @Summary Create a new blog post
 // @Description Create a new blog post with title, description, and body
@@ -27,6 +53,8 @@ func NewBlogController(blogService service.BlogService) *BlogController {
 // @Param blog body models.BlogCreateRequest true "Blog post data"
 // @Success 201 {object} map[string]interface{} "Blog post created successfully"
 // @Failure 400 {object} map[string]interface{} "Bad request - validation error"
+// @Failure 401 {object} map[string]interface{} "Authentication required"
+// @Failure 403 {object} map[string]interface{} "Forbidden - missing permission"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /blog-post [post]
 func (c *BlogController) CreateBlog(ctx *fiber.Ctx) error {
@@ -39,8 +67,11 @@ func (c *BlogController) CreateBlog(ctx *fiber.Ctx) error {
 		})
 	}
 
-	blog, err := c.blogService.CreateBlog(&request)
+	blog, err := c.blogs(ctx).CreateBlog(&request)
 	if err != nil {
+		if errors.Is(err, rbac.ErrForbidden) {
+			return forbidden(ctx, err)
+		}
 		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error":   "Failed to create blog post",
 			"message": err.Error(),
@@ -74,7 +105,7 @@ func (c *BlogController) GetBlogByID(ctx *fiber.Ctx) error {
 		})
 	}
 
-	blog, err := c.blogService.GetBlogByID(id)
+	blog, err := c.blogs(ctx).GetBlogByID(id)
 	if err != nil {
 		if err.Error() == "blog post not found" {
 			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -96,15 +127,24 @@ func (c *BlogController) GetBlogByID(ctx *fiber.Ctx) error {
 
 // GetAllBlogs handles GET /api/blog-post
 // @Summary Get all blog posts
-// @Description Retrieve all blog posts from the database
+// @Description Retrieve all blog posts from the database. Passing limit, after, status or tag returns a single page, newest first; next_cursor is then set when more posts follow and is passed back as after.
 // @Tags blog
 // @Accept json
 // @Produce json
+// @Param limit query int false "Page size, at most 100" default(20)
+// @Param after query string false "next_cursor of the previous page"
+// @Param status query string false "Publication state (draft or published)"
+// @Param tag query string false "Only posts with this tag"
 // @Success 200 {object} map[string]interface{} "Blog posts retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request - invalid page parameters"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /blog-post [get]
 func (c *BlogController) GetAllBlogs(ctx *fiber.Ctx) error {
-	blogs, err := c.blogService.GetAllBlogs()
+	if isPageRequest(ctx) {
+		return c.getBlogPage(ctx)
+	}
+
+	blogs, err := c.blogs(ctx).GetAllBlogs()
 	if err != nil {
 		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error":   "Failed to retrieve blog posts",
@@ -119,6 +159,50 @@ func (c *BlogController) GetAllBlogs(ctx *fiber.Ctx) error {
 	})
 }
 
+// getBlogPage responds with a single page of blog posts
+func (c *BlogController) getBlogPage(ctx *fiber.Ctx) error {
+	limit, err := strconv.Atoi(ctx.Query("limit", "0"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid page parameters",
+			"message": "limit must be a number",
+		})
+	}
+
+	page, err := c.blogs(ctx).ListBlogs(&models.BlogListQuery{
+		Status: ctx.Query("status"),
+		Tag:    ctx.Query("tag"),
+		First:  limit,
+		After:  ctx.Query("after"),
+	})
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid page parameters",
+			"message": err.Error(),
+		})
+	}
+
+	response := fiber.Map{
+		"message": "Blog posts retrieved successfully",
+		"data":    page.Posts,
+		"count":   len(page.Posts),
+	}
+	if page.HasNextPage {
+		response["next_cursor"] = page.EndCursor
+	}
+	return ctx.Status(fiber.StatusOK).JSON(response)
+}
+
+// isPageRequest reports whether a listing asks for a single page rather than every post
+func isPageRequest(ctx *fiber.Ctx) bool {
+	for _, param := range []string{"limit", "after", "status", "tag"} {
+		if ctx.Query(param) != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // UpdateBlog handles PATCH /api/blog-post/:id
 // @Summary Update a blog post
 // @Description Update an existing blog post by ID with partial data
@@ -130,6 +214,8 @@ func (c *BlogController) GetAllBlogs(ctx *fiber.Ctx) error {
 // @Success 200 {object} map[string]interface{} "Blog post updated successfully"
 // @Failure 400 {object} map[string]interface{} "Bad request - validation error"
 // @Failure 404 {object} map[string]interface{} "Blog post not found"
+// @Failure 401 {object} map[string]interface{} "Authentication required"
+// @Failure 403 {object} map[string]interface{} "Forbidden - missing permission"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /blog-post/{id} [patch]
 func (c *BlogController) UpdateBlog(ctx *fiber.Ctx) error {
@@ -149,8 +235,11 @@ func (c *BlogController) UpdateBlog(ctx *fiber.Ctx) error {
 		})
 	}
 
-	blog, err := c.blogService.UpdateBlog(id, &request)
+	blog, err := c.blogs(ctx).UpdateBlog(id, &request)
 	if err != nil {
+		if errors.Is(err, rbac.ErrForbidden) {
+			return forbidden(ctx, err)
+		}
 		if err.Error() == "blog post not found" {
 			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"error":   "Blog post not found",
@@ -179,6 +268,8 @@ func (c *BlogController) UpdateBlog(ctx *fiber.Ctx) error {
 // @Success 200 {object} map[string]interface{} "Blog post deleted successfully"
 // @Failure 400 {object} map[string]interface{} "Bad request - invalid ID"
 // @Failure 404 {object} map[string]interface{} "Blog post not found"
+// @Failure 401 {object} map[string]interface{} "Authentication required"
+// @Failure 403 {object} map[string]interface{} "Forbidden - missing permission"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /blog-post/{id} [delete]
 func (c *BlogController) DeleteBlog(ctx *fiber.Ctx) error {
@@ -190,8 +281,11 @@ func (c *BlogController) DeleteBlog(ctx *fiber.Ctx) error {
 		})
 	}
 
-	err := c.blogService.DeleteBlog(id)
+	err := c.blogs(ctx).DeleteBlog(id)
 	if err != nil {
+		if errors.Is(err, rbac.ErrForbidden) {
+			return forbidden(ctx, err)
+		}
 		if err.Error() == "blog post not found" {
 			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"error":   "Blog post not found",
@@ -208,3 +302,61 @@ func (c *BlogController) DeleteBlog(ctx *fiber.Ctx) error {
 		"message": "Blog post deleted successfully",
 	})
 }
+
+// BulkBlogs handles POST /api/blog-post/bulk
+// @Summary Create, update and delete blog posts in bulk
+// @Description Apply a batch of operations. In atomic mode (default) the batch runs in a single transaction and is rolled back if any operation fails; in partial mode each operation is applied and reported individually.
+// @Tags blog
+// @Accept json
+// @Produce json
+// @Param request body models.BlogBulkRequest true "Bulk operations"
+// @Success 200 {object} map[string]interface{} "Bulk operation completed"
+// @Failure 400 {object} map[string]interface{} "Bad request - validation error or rolled back batch"
+// @Failure 404 {object} map[string]interface{} "Rolled back batch - blog post not found"
+// @Failure 409 {object} map[string]interface{} "Rolled back batch - slug already in use"
+// @Failure 401 {object} map[string]interface{} "Authentication required"
+// @Failure 403 {object} map[string]interface{} "Forbidden - missing permission"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /blog-post/bulk [post]
+func (c *BlogController) BulkBlogs(ctx *fiber.Ctx) error {
+	var request models.BlogBulkRequest
+
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid request body",
+			"message": err.Error(),
+		})
+	}
+
+	result, err := c.blogs(ctx).BulkBlogs(&request)
+	if err != nil {
+		if errors.Is(err, rbac.ErrForbidden) {
+			return forbidden(ctx, err)
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Failed to process bulk request",
+			"message": err.Error(),
+		})
+	}
+
+	if !result.Committed {
+		// Report the rolled back batch with the status of the operation that caused it
+		status := fiber.StatusBadRequest
+		for _, item := range result.Results {
+			if item.Error != "" && item.Status != fiber.StatusFailedDependency {
+				status = item.Status
+				break
+			}
+		}
+		return ctx.Status(status).JSON(fiber.Map{
+			"error":   "Bulk operation rolled back",
+			"message": "No operations were applied because at least one operation failed",
+			"data":    result,
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Bulk operation completed",
+		"data":    result,
+	})
+}
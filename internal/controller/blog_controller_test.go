@@ -1,7 +1,11 @@
 package controller
 
 import (
+	"BlogManagment/internal/audit"
 	"BlogManagment/internal/models"
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/service"
+	"BlogManagment/internal/tenant"
 	"bytes"
 	"encoding/json"
 	"errors"
@@ -12,11 +16,15 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockBlogService is a mock implementation of BlogService
 type MockBlogService struct {
 	mock.Mock
+	// tenantID is the tenant the controller scoped the service to
+	tenantID  string
+	requester *models.Requester
 }
 
 func (m *MockBlogService) CreateBlog(request *models.BlogCreateRequest) (*models.BlogResponse, error) {
@@ -43,6 +51,38 @@ func (m *MockBlogService) GetAllBlogs() ([]models.BlogResponse, error) {
 	return args.Get(0).([]models.BlogResponse), args.Error(1)
 }
 
+func (m *MockBlogService) GetPublishedBlogs() ([]models.BlogResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BlogResponse), args.Error(1)
+}
+
+func (m *MockBlogService) ListBlogs(query *models.BlogListQuery) (*models.BlogPage, error) {
+	args := m.Called(query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogPage), args.Error(1)
+}
+
+func (m *MockBlogService) GetBlogBySlug(slug string) (*models.BlogResponse, error) {
+	args := m.Called(slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogResponse), args.Error(1)
+}
+
+func (m *MockBlogService) CountBlogsByTags(query *models.TagCountQuery) (map[string]int, error) {
+	args := m.Called(query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
 func (m *MockBlogService) UpdateBlog(id string, request *models.BlogUpdateRequest) (*models.BlogResponse, error) {
 	args := m.Called(id, request)
 	if args.Get(0) == nil {
@@ -56,14 +96,42 @@ func (m *MockBlogService) DeleteBlog(id string) error {
 	return args.Error(0)
 }
 
+func (m *MockBlogService) BulkBlogs(request *models.BlogBulkRequest) (*models.BlogBulkResponse, error) {
+	args := m.Called(request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogBulkResponse), args.Error(1)
+}
+
+// WithTenant records the tenant and returns the mock itself, so expectations cover every tenant
+func (m *MockBlogService) WithTenant(tenantID string) service.BlogService {
+	m.tenantID = tenantID
+	return m
+}
+
+// WithRequester records the requester and returns the mock itself
+func (m *MockBlogService) WithRequester(requester *models.Requester) service.BlogService {
+	m.requester = requester
+	return m
+}
+
 // setupTestApp creates a test Fiber app with the blog controller
 func setupTestApp() (*fiber.App, *MockBlogService) {
-	app := fiber.New()
+	// Strict routing keeps "/api/blog-post/" from falling through to the list handler
+	app := fiber.New(fiber.Config{StrictRouting: true})
 	mockService := &MockBlogService{}
 	controller := NewBlogController(mockService)
 
+	// Access control is covered by the tests with an actor of their own
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(rbac.NewContext(c.UserContext(), rbac.Unrestricted("")))
+		return c.Next()
+	})
+
 	// Setup routes for testing
 	app.Post("/api/blog-post", controller.CreateBlog)
+	app.Post("/api/blog-post/bulk", controller.BulkBlogs)
 	app.Get("/api/blog-post", controller.GetAllBlogs)
 	app.Get("/api/blog-post/:id", controller.GetBlogByID)
 	app.Patch("/api/blog-post/:id", controller.UpdateBlog)
@@ -186,6 +254,25 @@ func TestBlogController_GetBlogByID_Success(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestBlogController_GetBlogByID_ScopedToTenant(t *testing.T) {
+	mockService := &MockBlogService{}
+	controller := NewBlogController(mockService)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		tenant.Set(c, "acme")
+		return c.Next()
+	})
+	app.Get("/api/blog-post/:id", controller.GetBlogByID)
+
+	mockService.On("GetBlogByID", "1").Return(&models.BlogResponse{ID: "1", Status: models.BlogStatusPublished}, nil)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/api/blog-post/1", nil))
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "acme", mockService.tenantID)
+	mockService.AssertExpectations(t)
+}
+
 func TestBlogController_GetBlogByID_EmptyID(t *testing.T) {
 	app, _ := setupTestApp()
 
@@ -252,6 +339,38 @@ func TestBlogController_GetAllBlogs_Success(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestBlogController_GetAllBlogs_Page(t *testing.T) {
+	app, mockService := setupTestApp()
+
+	query := &models.BlogListQuery{Status: "published", Tag: "go", First: 2, After: "abc"}
+	page := &models.BlogPage{Posts: []models.BlogResponse{{ID: "1"}, {ID: "2"}}, EndCursor: "def", HasNextPage: true}
+	mockService.On("ListBlogs", query).Return(page, nil)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/api/blog-post?limit=2&after=abc&status=published&tag=go", nil))
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&result)
+
+	assert.Equal(t, float64(2), result["count"])
+	assert.Equal(t, "def", result["next_cursor"])
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "GetAllBlogs")
+}
+
+func TestBlogController_GetAllBlogs_InvalidPage(t *testing.T) {
+	app, mockService := setupTestApp()
+	mockService.On("ListBlogs", mock.Anything).Return(nil, errors.New("invalid cursor"))
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/api/blog-post?limit=abc", nil))
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	mockService.AssertNotCalled(t, "ListBlogs", mock.Anything)
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/api/blog-post?after=abc", nil))
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
 func TestBlogController_GetAllBlogs_ServiceError(t *testing.T) {
 	app, mockService := setupTestApp()
 
@@ -369,6 +488,28 @@ func TestBlogController_DeleteBlog_Success(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestBlogController_AttributesChangesToRequester(t *testing.T) {
+	app := fiber.New()
+	mockService := &MockBlogService{}
+	controller := NewBlogController(mockService)
+	requester := &models.Requester{Kind: "user", Subject: "alice", IP: "203.0.113.7", RequestID: "req-1"}
+	app.Use(func(c *fiber.Ctx) error {
+		ctx := rbac.NewContext(c.UserContext(), rbac.Unrestricted("alice"))
+		c.SetUserContext(audit.NewContext(ctx, requester))
+		return c.Next()
+	})
+	app.Delete("/api/blog-post/:id", controller.DeleteBlog)
+
+	mockService.On("DeleteBlog", "post-1").Return(nil)
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/api/blog-post/post-1", nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Same(t, requester, mockService.requester)
+	mockService.AssertExpectations(t)
+}
+
 func TestBlogController_DeleteBlog_NotFound(t *testing.T) {
 	app, mockService := setupTestApp()
 
@@ -393,3 +534,73 @@ func TestBlogController_DeleteBlog_NotFound(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestBlogController_BulkBlogs_Success(t *testing.T) {
+	app, mockService := setupTestApp()
+
+	expected := &models.BlogBulkResponse{
+		Mode:      models.BulkModeAtomic,
+		Committed: true,
+		Succeeded: 1,
+		Results:   []models.BlogBulkItemResult{{Index: 0, Action: models.BulkActionDelete, ID: "abc", Status: 200}},
+	}
+	mockService.On("BulkBlogs", mock.AnythingOfType("*models.BlogBulkRequest")).Return(expected, nil)
+
+	body := []byte(`{"operations": [{"action": "delete", "id": "abc"}]}`)
+	req := httptest.NewRequest("POST", "/api/blog-post/bulk", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&result)
+
+	assert.Equal(t, "Bulk operation completed", result["message"])
+	data := result["data"].(map[string]interface{})
+	assert.Equal(t, true, data["committed"])
+	assert.Len(t, data["results"], 1)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestBlogController_BulkBlogs_RolledBack(t *testing.T) {
+	app, mockService := setupTestApp()
+
+	expected := &models.BlogBulkResponse{
+		Mode:   models.BulkModeAtomic,
+		Failed: 2,
+		Results: []models.BlogBulkItemResult{
+			{Index: 0, Action: models.BulkActionDelete, ID: "a", Status: fiber.StatusFailedDependency, Error: "not applied"},
+			{Index: 1, Action: models.BulkActionDelete, ID: "b", Status: fiber.StatusNotFound, Error: "blog post not found"},
+		},
+	}
+	mockService.On("BulkBlogs", mock.AnythingOfType("*models.BlogBulkRequest")).Return(expected, nil)
+
+	body := []byte(`{"operations": [{"action": "delete", "id": "a"}, {"action": "delete", "id": "b"}]}`)
+	req := httptest.NewRequest("POST", "/api/blog-post/bulk", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+	var result map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&result)
+
+	assert.Equal(t, "Bulk operation rolled back", result["error"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestBlogController_BulkBlogs_InvalidBody(t *testing.T) {
+	app, _ := setupTestApp()
+
+	req := httptest.NewRequest("POST", "/api/blog-post/bulk", bytes.NewReader([]byte(`{"operations": [{"action": "create", "data": "oops"}]}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"BlogManagment/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CacheController handles HTTP requests about the blog post cache
+type CacheController struct {
+	blogCache *service.BlogCache
+}
+
+// NewCacheController creates a new cache controller instance
+func NewCacheController(blogCache *service.BlogCache) *CacheController {
+	return &CacheController{blogCache: blogCache}
+}
+
+// GetStats handles GET /api/admin/cache
+// @Summary Get blog cache statistics
+// @Description Retrieve the hit, miss, eviction and load counters of the blog post cache since the server started. Loads counts the reads that reached the database; concurrent misses for the same post share one load.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Cache statistics retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Authentication required"
+// @Failure 403 {object} map[string]interface{} "Forbidden - missing permission"
+// @Router /admin/cache [get]
+func (c *CacheController) GetStats(ctx *fiber.Ctx) error {
+	stats := c.blogCache.Stats()
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":   "Cache statistics retrieved successfully",
+		"data":      stats,
+		"hit_ratio": stats.HitRatio(),
+		"ttl":       c.blogCache.TTL().String(),
+	})
+}
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/stream"
+	"BlogManagment/internal/tenant"
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// heartbeatInterval is how often an idle stream sends a comment to keep proxies from closing it
+const heartbeatInterval = 15 * time.Second
+
+// EventController handles HTTP requests for the live stream of blog post events
+type EventController struct {
+	broker *stream.Broker
+}
+
+// NewEventController creates a new event controller instance
+func NewEventController(broker *stream.Broker) *EventController {
+	return &EventController{broker: broker}
+}
+
+// Stream handles GET /api/events/stream
+// @Summary Stream blog post events
+// @Description Push blog post lifecycle events as Server-Sent Events. Events of drafts are only sent to their author and to callers with post:update:any. The id of each message can be sent back in the Last-Event-ID header, or the last_event_id parameter, to resume after a disconnect.
+// @Tags events
+// @Produce text/event-stream
+// @Param types query string false "Comma separated event types to receive (post.created, post.updated, post.deleted, post.published)"
+// @Param tag query string false "Only receive events for posts with this tag; post.deleted events are always sent"
+// @Param last_event_id query int false "Resume after this event ID when the Last-Event-ID header cannot be set"
+// @Success 200 {string} string "Event stream"
+// @Failure 400 {object} map[string]interface{} "Bad request - invalid filter or event ID"
+// @Router /events/stream [get]
+func (c *EventController) Stream(ctx *fiber.Ctx) error {
+	filter, err := parseStreamFilter(ctx.Query("types"), ctx.Query("tag"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid event filter",
+			"message": err.Error(),
+		})
+	}
+	filter.TenantID = tenant.FromCtx(ctx)
+	filter.Actor = rbac.FromContext(ctx.UserContext())
+
+	lastEventID := ctx.Get("Last-Event-ID", ctx.Query("last_event_id"))
+	var position int64
+	if lastEventID != "" {
+		if position, err = strconv.ParseInt(lastEventID, 10, 64); err != nil || position < 0 {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid event ID",
+				"message": "Last-Event-ID must be the id of a previous message",
+			})
+		}
+	}
+
+	ctx.Set(fiber.HeaderContentType, "text/event-stream")
+	ctx.Set(fiber.HeaderCacheControl, "no-cache")
+	ctx.Set(fiber.HeaderConnection, "keep-alive")
+	ctx.Set("X-Accel-Buffering", "no")
+	ctx.Status(fiber.StatusOK)
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		c.stream(w, filter, position, lastEventID != "")
+	})
+
+	return nil
+}
+
+// stream writes messages until the client disconnects or falls too far behind. Live messages
+// are buffered while missed events are replayed, and those already replayed are skipped.
+func (c *EventController) stream(w *bufio.Writer, filter stream.Filter, position int64, resume bool) {
+	subscription := c.broker.Subscribe(filter)
+	defer c.broker.Unsubscribe(subscription)
+
+	fmt.Fprintf(w, "retry: 3000\n\n")
+	if err := w.Flush(); err != nil {
+		return
+	}
+
+	if resume {
+		var err error
+		position, err = c.broker.Replay(position, filter, func(message *stream.Message) error {
+			return writeEvent(w, message)
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case message, ok := <-subscription.C:
+			if !ok {
+				// Dropped for falling behind; the client reconnects with its Last-Event-ID
+				return
+			}
+			if message.Sequence <= position {
+				continue
+			}
+			if err := writeEvent(w, message); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			fmt.Fprintf(w, ": heartbeat\n\n")
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeEvent writes a message in the text/event-stream format and flushes it
+func writeEvent(w *bufio.Writer, message *stream.Message) error {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", message.Sequence, message.Event.Type, message.Payload)
+	return w.Flush()
+}
+
+// parseStreamFilter parses the types and tag query parameters
+func parseStreamFilter(types, tag string) (stream.Filter, error) {
+	var eventTypes []string
+	for _, eventType := range strings.Split(types, ",") {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	return stream.NewFilter(eventTypes, tag)
+}
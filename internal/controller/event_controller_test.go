@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"BlogManagment/internal/models"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupEventApp() *fiber.App {
+	app := fiber.New()
+	app.Get("/api/events/stream", NewEventController(nil).Stream)
+	return app
+}
+
+func TestEventController_Stream_InvalidType(t *testing.T) {
+	resp, err := setupEventApp().Test(httptest.NewRequest("GET", "/api/events/stream?types=post.created,post.read", nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func TestEventController_Stream_InvalidLastEventID(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/events/stream", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+
+	resp, err := setupEventApp().Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func TestParseStreamFilter(t *testing.T) {
+	filter, err := parseStreamFilter(" post.created , post.deleted,", " golang ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{models.EventPostCreated, models.EventPostDeleted}, filter.Types)
+	assert.Equal(t, "golang", filter.Tag)
+
+	filter, err = parseStreamFilter("", "")
+	assert.NoError(t, err)
+	assert.Empty(t, filter.Types)
+}
@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"BlogManagment/internal/gql"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GraphQLController handles GraphQL requests
+type GraphQLController struct {
+	executor *gql.Executor
+}
+
+// NewGraphQLController creates a new GraphQL controller instance
+func NewGraphQLController(executor *gql.Executor) *GraphQLController {
+	return &GraphQLController{executor: executor}
+}
+
+// Query handles POST /graphql.
+// Requests rejected before execution, because they cannot be parsed, are invalid or exceed the
+// cost limit, are answered with 400; errors raised while resolving fields are returned with 200
+// next to the data that could be resolved, as usual for GraphQL.
+func (c *GraphQLController) Query(ctx *fiber.Ctx) error {
+	var request gql.Request
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid request body",
+			"message": err.Error(),
+		})
+	}
+
+	if strings.TrimSpace(request.Query) == "" {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Query is required",
+			"message": "Please provide a GraphQL query in the query field",
+		})
+	}
+
+	result, rejected := c.executor.Execute(ctx.UserContext(), &request)
+	if rejected {
+		return ctx.Status(fiber.StatusBadRequest).JSON(result)
+	}
+	return ctx.Status(fiber.StatusOK).JSON(result)
+}
@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"BlogManagment/internal/gql"
+	"BlogManagment/internal/models"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupGraphQLApp(t *testing.T) (*fiber.App, *MockBlogService) {
+	mockService := &MockBlogService{}
+	executor, err := gql.NewExecutor(mockService, 100)
+	require.NoError(t, err)
+
+	app := fiber.New()
+	app.Post("/graphql", NewGraphQLController(executor).Query)
+	return app, mockService
+}
+
+func postGraphQL(t *testing.T, app *fiber.App, body string) (int, map[string]interface{}) {
+	req := httptest.NewRequest("POST", "/graphql", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	return resp.StatusCode, response
+}
+
+func TestGraphQLController_Query_Success(t *testing.T) {
+	app, mockService := setupGraphQLApp(t)
+	mockService.On("GetBlogByID", "1").Return(&models.BlogResponse{ID: "1", Title: "Hello", Status: models.BlogStatusPublished}, nil)
+
+	status, response := postGraphQL(t, app, `{"query": "query($id: ID!) { post(id: $id) { title } }", "variables": {"id": "1"}}`)
+
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, map[string]interface{}{"post": map[string]interface{}{"title": "Hello"}}, response["data"])
+	mockService.AssertExpectations(t)
+}
+
+func TestGraphQLController_Query_Rejected(t *testing.T) {
+	app, mockService := setupGraphQLApp(t)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"invalid body", `{"query": `},
+		{"missing query", `{"variables": {}}`},
+		{"syntax error", `{"query": "{ post(id: \"1\") {"}`},
+		{"unknown field", `{"query": "{ post(id: \"1\") { author } }"}`},
+		{"cost limit", `{"query": "{ posts(first: 100) { nodes { id } } }"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, _ := postGraphQL(t, app, tt.body)
+			assert.Equal(t, fiber.StatusBadRequest, status)
+		})
+	}
+
+	mockService.AssertNotCalled(t, "ListBlogs", mock.Anything)
+}
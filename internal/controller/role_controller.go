@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/repository"
+	"BlogManagment/internal/service"
+	"BlogManagment/internal/tenant"
+	"errors"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RoleController handles HTTP requests for the role assignments of a tenant and its access denial log
+type RoleController struct {
+	roleService service.RoleService
+}
+
+// NewRoleController creates a new role controller instance
+func NewRoleController(roleService service.RoleService) *RoleController {
+	return &RoleController{roleService: roleService}
+}
+
+// GetRoles handles GET /api/admin/roles
+// @Summary List roles and their permissions
+// @Description Retrieve every role together with the permissions it grants
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Roles retrieved successfully"
+// @Router /admin/roles [get]
+func (c *RoleController) GetRoles(ctx *fiber.Ctx) error {
+	roles := make([]fiber.Map, 0)
+	for _, role := range rbac.Roles() {
+		roles = append(roles, fiber.Map{"role": role, "permissions": rbac.PermissionsOf(role)})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Roles retrieved successfully",
+		"data":    roles,
+		"count":   len(roles),
+	})
+}
+
+// GetRoleAssignments handles GET /api/admin/role-assignments
+// @Summary List role assignments
+// @Description Retrieve the role assignments of the tenant, optionally only those of one subject
+// @Tags admin
+// @Produce json
+// @Param subject query string false "Only the roles of this subject"
+// @Success 200 {object} map[string]interface{} "Role assignments retrieved successfully"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/role-assignments [get]
+func (c *RoleController) GetRoleAssignments(ctx *fiber.Ctx) error {
+	assignments, err := c.roleService.ListRoleAssignments(tenant.FromCtx(ctx), ctx.Query("subject"))
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to retrieve role assignments",
+			"message": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Role assignments retrieved successfully",
+		"data":    assignments,
+		"count":   len(assignments),
+	})
+}
+
+// AssignRole handles POST /api/admin/role-assignments
+// @Summary Assign a role
+// @Description Grant a role to a subject in the tenant. Assigning a role the subject already holds succeeds.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param assignment body models.RoleAssignmentRequest true "Role assignment"
+// @Success 201 {object} map[string]interface{} "Role assigned successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request - validation error"
+// @Router /admin/role-assignments [post]
+func (c *RoleController) AssignRole(ctx *fiber.Ctx) error {
+	var request models.RoleAssignmentRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid request body",
+			"message": err.Error(),
+		})
+	}
+
+	assignment, err := c.roleService.AssignRole(tenant.FromCtx(ctx), &request)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Failed to assign role",
+			"message": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Role assigned successfully",
+		"data":    assignment,
+	})
+}
+
+// RevokeRole handles DELETE /api/admin/role-assignments/:subject/:role
+// @Summary Revoke a role
+// @Description Remove a role from a subject in the tenant
+// @Tags admin
+// @Produce json
+// @Param subject path string true "Subject"
+// @Param role path string true "Role"
+// @Success 200 {object} map[string]interface{} "Role revoked successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request - invalid subject"
+// @Failure 404 {object} map[string]interface{} "Role assignment not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/role-assignments/{subject}/{role} [delete]
+func (c *RoleController) RevokeRole(ctx *fiber.Ctx) error {
+	// Subjects such as e-mail addresses may be percent-encoded
+	subject, err := url.PathUnescape(ctx.Params("subject"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid subject",
+			"message": err.Error(),
+		})
+	}
+
+	err = c.roleService.RevokeRole(tenant.FromCtx(ctx), subject, ctx.Params("role"))
+	if err != nil {
+		if errors.Is(err, repository.ErrRoleAssignmentNotFound) {
+			return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error":   "Role assignment not found",
+				"message": "The subject does not hold this role",
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to revoke role",
+			"message": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Role revoked successfully",
+	})
+}
+
+// GetAccessDenials handles GET /api/admin/access-denials
+// @Summary List denied requests
+// @Description Retrieve the requests of authenticated callers that were refused for a missing permission, newest first
+// @Tags admin
+// @Produce json
+// @Param subject query string false "Only the denials of this subject"
+// @Param limit query int false "Maximum number of denials" default(50)
+// @Success 200 {object} map[string]interface{} "Access denials retrieved successfully"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /admin/access-denials [get]
+func (c *RoleController) GetAccessDenials(ctx *fiber.Ctx) error {
+	denials, err := c.roleService.ListAccessDenials(tenant.FromCtx(ctx), ctx.Query("subject"), ctx.QueryInt("limit"))
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to retrieve access denials",
+			"message": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Access denials retrieved successfully",
+		"data":    denials,
+		"count":   len(denials),
+	})
+}
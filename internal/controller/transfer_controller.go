@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"BlogManagment/internal/audit"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/service"
+	"BlogManagment/internal/tenant"
+	"bufio"
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TransferController handles HTTP requests for exporting and importing blog posts
+type TransferController struct {
+	transferService service.BlogTransferService
+}
+
+// NewTransferController creates a new transfer controller instance
+func NewTransferController(transferService service.BlogTransferService) *TransferController {
+	return &TransferController{transferService: transferService}
+}
+
+// Export handles GET /api/export
+// @Summary Export all blog posts
+// @Description Stream every blog post as JSON Lines or CSV, oldest first
+// @Tags transfer
+// @Produce plain
+// @Param format query string false "Export format (jsonl or csv)" default(jsonl)
+// @Success 200 {string} string "Exported blog posts"
+// @Failure 400 {object} map[string]interface{} "Bad request - unsupported format"
+// @Router /export [get]
+func (c *TransferController) Export(ctx *fiber.Ctx) error {
+	format := ctx.Query("format", models.TransferFormatJSONL)
+	if err := service.ValidateExportFormat(format); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid export format",
+			"message": err.Error(),
+		})
+	}
+
+	contentType := "application/x-ndjson"
+	if format == models.TransferFormatCSV {
+		contentType = "text/csv; charset=utf-8"
+	}
+	filename := fmt.Sprintf("blog-export-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+
+	ctx.Set(fiber.HeaderContentType, contentType)
+	ctx.Set(fiber.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	transferService := c.transferService.WithTenant(tenant.FromCtx(ctx))
+	ctx.Status(fiber.StatusOK)
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// The status line is already sent, so failures can only be logged
+		count, err := transferService.Export(w, format)
+		if err != nil {
+			log.Printf("Export failed after %d posts: %v", count, err)
+		}
+		if err := w.Flush(); err != nil {
+			log.Printf("Export failed to flush: %v", err)
+		}
+	})
+
+	return nil
+}
+
+// Import handles POST /api/import
+// @Summary Import blog posts
+// @Description Import blog posts from JSON Lines, CSV, a WordPress WXR export or a tar archive of Markdown files with YAML front matter. Each record is validated and upserted by ID or slug. With dry_run=true nothing is written.
+// @Tags transfer
+// @Accept plain
+// @Produce json
+// @Param format query string false "Import format (jsonl, csv, wxr or markdown); inferred from the Content-Type when omitted"
+// @Param dry_run query bool false "Report what would change without writing"
+// @Param file body string true "JSON Lines, CSV, WXR or tar(.gz) content"
+// @Success 200 {object} map[string]interface{} "Import report"
+// @Failure 400 {object} map[string]interface{} "Bad request - unsupported format or unreadable file"
+// @Router /import [post]
+func (c *TransferController) Import(ctx *fiber.Ctx) error {
+	format := ctx.Query("format")
+	if format == "" {
+		format = formatFromContentType(ctx.Get(fiber.HeaderContentType))
+	}
+	dryRun := ctx.QueryBool("dry_run", false)
+
+	report, err := c.transferService.WithTenant(tenant.FromCtx(ctx)).WithRequester(audit.FromContext(ctx.UserContext())).Import(bytes.NewReader(ctx.Body()), format, dryRun)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Failed to import blog posts",
+			"message": err.Error(),
+		})
+	}
+
+	message := "Import completed"
+	if dryRun {
+		message = "Dry run completed, no changes were written"
+	}
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": message,
+		"data":    report,
+	})
+}
+
+// formatFromContentType infers the import format from a request's Content-Type
+func formatFromContentType(contentType string) string {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	switch strings.ToLower(strings.TrimSpace(mediaType)) {
+	case "text/csv":
+		return models.TransferFormatCSV
+	case "application/xml", "text/xml", "application/rss+xml":
+		return models.TransferFormatWXR
+	case "application/x-tar", "application/gzip", "application/x-gzip":
+		return models.TransferFormatMarkdown
+	default:
+		return models.TransferFormatJSONL
+	}
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/service"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTransferService is a mock implementation of BlogTransferService
+type MockTransferService struct {
+	mock.Mock
+}
+
+func (m *MockTransferService) Export(w io.Writer, format string) (int, error) {
+	args := m.Called(w, format)
+	io.WriteString(w, args.String(2))
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTransferService) Import(r io.Reader, format string, dryRun bool) (*models.ImportReport, error) {
+	args := m.Called(r, format, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ImportReport), args.Error(1)
+}
+
+func (m *MockTransferService) ImportMarkdownFS(fsys fs.FS, dryRun bool) (*models.ImportReport, error) {
+	args := m.Called(fsys, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ImportReport), args.Error(1)
+}
+
+// WithTenant returns the mock itself, so expectations cover every tenant
+func (m *MockTransferService) WithTenant(tenantID string) service.BlogTransferService {
+	return m
+}
+
+// WithRequester returns the mock itself
+func (m *MockTransferService) WithRequester(requester *models.Requester) service.BlogTransferService {
+	return m
+}
+
+// setupTransferTestApp creates a test Fiber app with the transfer controller
+func setupTransferTestApp() (*fiber.App, *MockTransferService) {
+	app := fiber.New()
+	mockService := &MockTransferService{}
+	controller := NewTransferController(mockService)
+
+	app.Get("/api/export", controller.Export)
+	app.Post("/api/import", controller.Import)
+
+	return app, mockService
+}
+
+func TestTransferController_Export_CSV(t *testing.T) {
+	app, mockService := setupTransferTestApp()
+
+	mockService.On("Export", mock.Anything, "csv").Return(1, nil, "id,slug\n1,first\n")
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/api/export?format=csv", nil))
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Contains(t, resp.Header.Get("Content-Disposition"), ".csv")
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "id,slug\n1,first\n", string(body))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestTransferController_Export_InvalidFormat(t *testing.T) {
+	app, _ := setupTransferTestApp()
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/api/export?format=xml", nil))
+
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func TestTransferController_Import_DryRunFromCSVBody(t *testing.T) {
+	app, mockService := setupTransferTestApp()
+
+	report := &models.ImportReport{Format: "csv", DryRun: true, Total: 1, Created: 1}
+	mockService.On("Import", mock.Anything, "csv", true).Return(report, nil)
+
+	req := httptest.NewRequest("POST", "/api/import?dry_run=true", strings.NewReader("title,body\nT,B\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&result)
+
+	assert.Equal(t, "Dry run completed, no changes were written", result["message"])
+	assert.Equal(t, float64(1), result["data"].(map[string]interface{})["created"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestTransferController_Import_WXRFromXMLBody(t *testing.T) {
+	app, mockService := setupTransferTestApp()
+
+	report := &models.ImportReport{Format: "wxr", Total: 2, Created: 1, Skipped: 1}
+	mockService.On("Import", mock.Anything, "wxr", false).Return(report, nil)
+
+	req := httptest.NewRequest("POST", "/api/import", strings.NewReader("<rss></rss>"))
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	resp, _ := app.Test(req)
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,243 @@
+package controller
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"BlogManagment/internal/service"
+	"BlogManagment/internal/tenant"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// WebhookController handles HTTP requests for webhook subscriptions and their deliveries
+type WebhookController struct {
+	webhookService service.WebhookService
+}
+
+// NewWebhookController creates a new webhook controller instance
+func NewWebhookController(webhookService service.WebhookService) *WebhookController {
+	return &WebhookController{webhookService: webhookService}
+}
+
+// webhooks returns the webhook service for the tenant of the request
+func (c *WebhookController) webhooks(ctx *fiber.Ctx) service.WebhookService {
+	return c.webhookService.WithTenant(tenant.FromCtx(ctx))
+}
+
+// CreateWebhook handles POST /api/webhooks
+// @Summary Register a webhook
+// @Description Subscribe a URL to blog post events. A signing secret is generated when none is given and is only returned in this response.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body models.WebhookCreateRequest true "Webhook data"
+// @Success 201 {object} map[string]interface{} "Webhook created successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request - validation error"
+// @Router /webhooks [post]
+func (c *WebhookController) CreateWebhook(ctx *fiber.Ctx) error {
+	var request models.WebhookCreateRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid request body",
+			"message": err.Error(),
+		})
+	}
+
+	webhook, err := c.webhooks(ctx).CreateWebhook(&request)
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Failed to create webhook",
+			"message": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Webhook created successfully",
+		"data":    webhook,
+	})
+}
+
+// GetAllWebhooks handles GET /api/webhooks
+// @Summary Get all webhooks
+// @Description Retrieve every webhook subscription
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Webhooks retrieved successfully"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /webhooks [get]
+func (c *WebhookController) GetAllWebhooks(ctx *fiber.Ctx) error {
+	webhooks, err := c.webhooks(ctx).GetAllWebhooks()
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "Failed to retrieve webhooks",
+			"message": err.Error(),
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Webhooks retrieved successfully",
+		"data":    webhooks,
+		"count":   len(webhooks),
+	})
+}
+
+// GetWebhook handles GET /api/webhooks/:id
+// @Summary Get a webhook by ID
+// @Description Retrieve a webhook subscription; the secret is never included
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} map[string]interface{} "Webhook retrieved successfully"
+// @Failure 404 {object} map[string]interface{} "Webhook not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /webhooks/{id} [get]
+func (c *WebhookController) GetWebhook(ctx *fiber.Ctx) error {
+	webhook, err := c.webhooks(ctx).GetWebhook(ctx.Params("id"))
+	if err != nil {
+		return webhookError(ctx, err, fiber.StatusInternalServerError, "Failed to retrieve webhook")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Webhook retrieved successfully",
+		"data":    webhook,
+	})
+}
+
+// UpdateWebhook handles PATCH /api/webhooks/:id
+// @Summary Update a webhook
+// @Description Update a webhook with partial data. Setting a new secret rotates it and returns it once.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param webhook body models.WebhookUpdateRequest true "Webhook update data"
+// @Success 200 {object} map[string]interface{} "Webhook updated successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request - validation error"
+// @Failure 404 {object} map[string]interface{} "Webhook not found"
+// @Router /webhooks/{id} [patch]
+func (c *WebhookController) UpdateWebhook(ctx *fiber.Ctx) error {
+	var request models.WebhookUpdateRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Invalid request body",
+			"message": err.Error(),
+		})
+	}
+
+	webhook, err := c.webhooks(ctx).UpdateWebhook(ctx.Params("id"), &request)
+	if err != nil {
+		return webhookError(ctx, err, fiber.StatusBadRequest, "Failed to update webhook")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Webhook updated successfully",
+		"data":    webhook,
+	})
+}
+
+// DeleteWebhook handles DELETE /api/webhooks/:id
+// @Summary Delete a webhook
+// @Description Delete a webhook together with its delivery log
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} map[string]interface{} "Webhook deleted successfully"
+// @Failure 404 {object} map[string]interface{} "Webhook not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /webhooks/{id} [delete]
+func (c *WebhookController) DeleteWebhook(ctx *fiber.Ctx) error {
+	if err := c.webhooks(ctx).DeleteWebhook(ctx.Params("id")); err != nil {
+		return webhookError(ctx, err, fiber.StatusInternalServerError, "Failed to delete webhook")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Webhook deleted successfully",
+	})
+}
+
+// ListWebhookDeliveries handles GET /api/webhooks/:id/deliveries
+// @Summary List the deliveries of a webhook
+// @Description Retrieve the delivery log of a webhook, newest first
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param status query string false "Delivery status (pending, succeeded or dead)"
+// @Param limit query int false "Maximum number of deliveries" default(50)
+// @Success 200 {object} map[string]interface{} "Deliveries retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request - invalid status"
+// @Failure 404 {object} map[string]interface{} "Webhook not found"
+// @Router /webhooks/{id}/deliveries [get]
+func (c *WebhookController) ListWebhookDeliveries(ctx *fiber.Ctx) error {
+	return c.listDeliveries(ctx, ctx.Params("id"))
+}
+
+// ListDeliveries handles GET /api/webhooks/deliveries
+// @Summary List the deliveries of every webhook
+// @Description Retrieve the delivery log across all webhooks, newest first. Use status=dead to list the dead-letter queue.
+// @Tags webhooks
+// @Produce json
+// @Param status query string false "Delivery status (pending, succeeded or dead)"
+// @Param limit query int false "Maximum number of deliveries" default(50)
+// @Success 200 {object} map[string]interface{} "Deliveries retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request - invalid status"
+// @Router /webhooks/deliveries [get]
+func (c *WebhookController) ListDeliveries(ctx *fiber.Ctx) error {
+	return c.listDeliveries(ctx, "")
+}
+
+// listDeliveries lists the deliveries of one webhook, or of all webhooks when webhookID is empty
+func (c *WebhookController) listDeliveries(ctx *fiber.Ctx, webhookID string) error {
+	deliveries, err := c.webhooks(ctx).ListDeliveries(webhookID, ctx.Query("status"), ctx.QueryInt("limit"))
+	if err != nil {
+		return webhookError(ctx, err, fiber.StatusBadRequest, "Failed to retrieve deliveries")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Deliveries retrieved successfully",
+		"data":    deliveries,
+		"count":   len(deliveries),
+	})
+}
+
+// RetryDelivery handles POST /api/webhooks/deliveries/:id/retry
+// @Summary Retry a dead delivery
+// @Description Put a dead letter back on the queue with a fresh set of attempts
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Delivery ID"
+// @Success 200 {object} map[string]interface{} "Delivery queued for retry"
+// @Failure 400 {object} map[string]interface{} "Bad request - delivery is not dead"
+// @Failure 404 {object} map[string]interface{} "Delivery not found"
+// @Router /webhooks/deliveries/{id}/retry [post]
+func (c *WebhookController) RetryDelivery(ctx *fiber.Ctx) error {
+	delivery, err := c.webhooks(ctx).RetryDelivery(ctx.Params("id"))
+	if err != nil {
+		return webhookError(ctx, err, fiber.StatusBadRequest, "Failed to retry delivery")
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Delivery queued for retry",
+		"data":    delivery,
+	})
+}
+
+// webhookError maps missing webhooks and deliveries to 404 and any other error to status
+func webhookError(ctx *fiber.Ctx, err error, status int, message string) error {
+	switch {
+	case errors.Is(err, repository.ErrWebhookNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error":   "Webhook not found",
+			"message": "The requested webhook does not exist",
+		})
+	case errors.Is(err, repository.ErrDeliveryNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error":   "Delivery not found",
+			"message": "The requested webhook delivery does not exist",
+		})
+	}
+	return ctx.Status(status).JSON(fiber.Map{
+		"error":   message,
+		"message": err.Error(),
+	})
+}
@@ -0,0 +1,98 @@
+package gql
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// maxTagsPerPost bounds the tags of a post, matching the validation of create and update requests
+const maxTagsPerPost = 20
+
+// queryCost estimates the work of an operation before it runs. Every selected field costs one,
+// and the fields below a list cost as many times as the list can hold items: the first argument
+// of posts, or the maximum number of tags of a post.
+func queryCost(doc *ast.Document, operationName string, variables map[string]interface{}) (int, error) {
+	var operation *ast.OperationDefinition
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, definition := range doc.Definitions {
+		switch definition := definition.(type) {
+		case *ast.OperationDefinition:
+			if operationName == "" || (definition.Name != nil && definition.Name.Value == operationName) {
+				operation = definition
+			}
+		case *ast.FragmentDefinition:
+			fragments[definition.Name.Value] = definition
+		}
+	}
+	if operation == nil {
+		return 0, fmt.Errorf("unknown operation %q", operationName)
+	}
+
+	c := &costCalculator{fragments: fragments, variables: variables}
+	return c.selectionSet(operation.SelectionSet), nil
+}
+
+// costCalculator walks the selections of a validated document
+type costCalculator struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+func (c *costCalculator) selectionSet(set *ast.SelectionSet) int {
+	if set == nil {
+		return 0
+	}
+
+	cost := 0
+	for _, selection := range set.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			cost += 1 + c.multiplier(selection)*c.selectionSet(selection.SelectionSet)
+		case *ast.InlineFragment:
+			cost += c.selectionSet(selection.SelectionSet)
+		case *ast.FragmentSpread:
+			// Validation has rejected fragment cycles by now
+			if fragment, ok := c.fragments[selection.Name.Value]; ok {
+				cost += c.selectionSet(fragment.SelectionSet)
+			}
+		}
+	}
+	return cost
+}
+
+// multiplier returns the number of items a list field can return
+func (c *costCalculator) multiplier(field *ast.Field) int {
+	switch field.Name.Value {
+	case "posts":
+		for _, argument := range field.Arguments {
+			if argument.Name.Value == "first" {
+				if first, ok := c.intValue(argument.Value); ok && first > 0 {
+					return first
+				}
+			}
+		}
+		return defaultFirst
+	case "tags":
+		return maxTagsPerPost
+	}
+	return 1
+}
+
+// intValue resolves an integer literal or variable
+func (c *costCalculator) intValue(value ast.Value) (int, bool) {
+	switch value := value.(type) {
+	case *ast.IntValue:
+		n, err := strconv.Atoi(value.Value)
+		return n, err == nil
+	case *ast.Variable:
+		switch n := c.variables[value.Name.Value].(type) {
+		case int:
+			return n, true
+		case float64:
+			return int(n), true
+		}
+	}
+	return 0, false
+}
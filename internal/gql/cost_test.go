@@ -0,0 +1,63 @@
+package gql
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryCost(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		operation string
+		variables map[string]interface{}
+		expected  int
+	}{
+		{"single field", `{ post(id: "1") { title } }`, "", nil, 2},
+		{"default page size", `{ posts { nodes { id } } }`, "", nil, 1 + defaultFirst*2},
+		{"literal page size", `{ posts(first: 3) { nodes { id tags { name } } } }`, "", nil, 1 + 3*(1+1+1+maxTagsPerPost)},
+		{"variable page size", `query($n: Int) { posts(first: $n) { nodes { id } } }`, "", map[string]interface{}{"n": float64(5)}, 1 + 5*2},
+		{"fragments", `{ post(id: "1") { ...f ... on Post { body } } } fragment f on Post { id title }`, "", nil, 4},
+		{"named operation", `query A { post(id: "1") { id } } query B { posts(first: 2) { nodes { id } } }`, "B", nil, 1 + 2*2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+			require.NoError(t, err)
+
+			cost, err := queryCost(doc, tt.operation, tt.variables)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cost)
+		})
+	}
+}
+
+func TestLoader_BatchesAndCaches(t *testing.T) {
+	var batches [][]int
+	loader := NewLoader(func(keys []int) (map[int]int, error) {
+		batches = append(batches, keys)
+		values := make(map[int]int, len(keys))
+		for _, key := range keys {
+			values[key] = key * 10
+		}
+		return values, nil
+	})
+
+	first, second, again := loader.Load(1), loader.Load(2), loader.Load(1)
+	value, err := second()
+	require.NoError(t, err)
+	assert.Equal(t, 20, value)
+	value, _ = first()
+	assert.Equal(t, 10, value)
+	value, _ = again()
+	assert.Equal(t, 10, value)
+
+	// Loaded keys are served from the cache
+	value, _ = loader.Load(2)()
+	assert.Equal(t, 20, value)
+	assert.Equal(t, [][]int{{1, 2}}, batches)
+}
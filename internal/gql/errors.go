@@ -0,0 +1,50 @@
+package gql
+
+import (
+	"errors"
+
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/repository"
+)
+
+// Error codes reported in the extensions of GraphQL errors
+const (
+	CodeBadRequest   = "BAD_REQUEST"
+	CodeNotFound     = "NOT_FOUND"
+	CodeConflict     = "CONFLICT"
+	CodeForbidden    = "FORBIDDEN"
+	CodeCostExceeded = "COST_LIMIT_EXCEEDED"
+)
+
+// codedError is a resolver error carrying a machine-readable code in its extensions
+type codedError struct {
+	err  error
+	code string
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+// Extensions implements gqlerrors.ExtendedError
+func (e *codedError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+// resolverError attaches the code matching a service error, mirroring the status codes of the REST API
+func resolverError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrBlogNotFound):
+		return &codedError{err: err, code: CodeNotFound}
+	case errors.Is(err, repository.ErrSlugConflict):
+		return &codedError{err: err, code: CodeConflict}
+	case errors.Is(err, rbac.ErrForbidden):
+		return &codedError{err: err, code: CodeForbidden}
+	default:
+		return &codedError{err: err, code: CodeBadRequest}
+	}
+}
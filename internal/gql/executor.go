@@ -0,0 +1,73 @@
+package gql
+
+import (
+	"context"
+	"fmt"
+
+	"BlogManagment/internal/service"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Request is a GraphQL request as posted by clients
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Executor runs GraphQL requests against the blog service
+type Executor struct {
+	schema      graphql.Schema
+	blogService service.BlogService
+	maxCost     int
+}
+
+// NewExecutor creates an executor that rejects operations whose estimated cost exceeds maxCost
+func NewExecutor(blogService service.BlogService, maxCost int) (*Executor, error) {
+	schema, err := NewSchema(blogService)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GraphQL schema: %w", err)
+	}
+	return &Executor{schema: schema, blogService: blogService, maxCost: maxCost}, nil
+}
+
+// Execute runs a request. Requests that cannot be parsed, fail validation or exceed the cost
+// limit are rejected before any resolver runs, which is reported by rejected.
+func (e *Executor) Execute(ctx context.Context, request *Request) (result *graphql.Result, rejected bool) {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(request.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}, true
+	}
+
+	if validation := graphql.ValidateDocument(&e.schema, doc, nil); !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}, true
+	}
+
+	cost, err := queryCost(doc, request.OperationName, request.Variables)
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}, true
+	}
+	if cost > e.maxCost {
+		costErr := &codedError{
+			err:  fmt.Errorf("query cost %d exceeds the limit of %d", cost, e.maxCost),
+			code: CodeCostExceeded,
+		}
+		formatted := gqlerrors.FormatError(costErr)
+		formatted.Extensions = costErr.Extensions()
+		return &graphql.Result{Errors: []gqlerrors.FormattedError{formatted}}, true
+	}
+
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        e.schema,
+		AST:           doc,
+		OperationName: request.OperationName,
+		Args:          request.Variables,
+		Context:       withLoaders(ctx, requestBlogs(ctx, e.blogService)),
+	}), false
+}
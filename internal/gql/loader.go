@@ -0,0 +1,96 @@
+package gql
+
+import (
+	"context"
+	"sync"
+
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/service"
+)
+
+// Loader batches lookups made while a query executes. Resolvers call Load, which queues the key
+// and returns a thunk; the executor runs thunks only after every sibling field has been resolved,
+// so the first thunk fetches all queued keys with a single call to fetch.
+type Loader[K comparable, V any] struct {
+	fetch func(keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	pending []K
+	queued  map[K]bool
+	values  map[K]V
+	errs    map[K]error
+}
+
+// NewLoader creates a loader that resolves batches of keys with fetch.
+// Keys missing from the map returned by fetch resolve to the zero value.
+func NewLoader[K comparable, V any](fetch func(keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:  fetch,
+		queued: make(map[K]bool),
+		values: make(map[K]V),
+		errs:   make(map[K]error),
+	}
+}
+
+// Load queues key for the next batch and returns a thunk yielding its value
+func (l *Loader[K, V]) Load(key K) func() (interface{}, error) {
+	l.mu.Lock()
+	if _, done := l.values[key]; !done && l.errs[key] == nil && !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		return l.result(key)
+	}
+}
+
+// result returns the value of key, fetching the pending batch first if it has not been loaded yet
+func (l *Loader[K, V]) result(key K) (V, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.queued[key] {
+		l.flush()
+	}
+	return l.values[key], l.errs[key]
+}
+
+// flush fetches every pending key. The caller must hold l.mu.
+func (l *Loader[K, V]) flush() {
+	keys := l.pending
+	l.pending = nil
+
+	values, err := l.fetch(keys)
+	for _, key := range keys {
+		delete(l.queued, key)
+		if err != nil {
+			l.errs[key] = err
+			continue
+		}
+		l.values[key] = values[key]
+	}
+}
+
+// loaders holds the batch loaders of a single request.
+// They are created per request so that cached values never outlive the query.
+type loaders struct {
+	tagCounts *Loader[string, int]
+}
+
+type loadersKey struct{}
+
+// withLoaders returns a context carrying fresh loaders backed by blogService
+func withLoaders(ctx context.Context, blogService service.BlogService) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &loaders{
+		tagCounts: NewLoader(func(names []string) (map[string]int, error) {
+			return blogService.CountBlogsByTags(&models.TagCountQuery{Names: names})
+		}),
+	})
+}
+
+// loadersFrom returns the loaders of the request executing in ctx
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
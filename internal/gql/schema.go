@@ -0,0 +1,316 @@
+package gql
+
+import (
+	"context"
+	"errors"
+
+	"BlogManagment/internal/audit"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/repository"
+	"BlogManagment/internal/service"
+	"BlogManagment/internal/tenant"
+
+	"github.com/graphql-go/graphql"
+)
+
+// defaultFirst is the page size of the posts query when first is not given
+const defaultFirst = 20
+
+// requestBlogs returns blogService for the tenant carried by ctx, checked against the
+// permissions of the actor it carries and attributing changes to the requester it carries
+func requestBlogs(ctx context.Context, blogService service.BlogService) service.BlogService {
+	scoped := blogService.WithTenant(tenant.FromContext(ctx)).WithRequester(audit.FromContext(ctx))
+	return service.WithAccessControl(scoped, rbac.FromContext(ctx))
+}
+
+// NewSchema builds the GraphQL schema served by blogService. Resolvers act on the posts of
+// the tenant carried by the request context, with the permissions of the actor it carries,
+// and attribute changes to the requester it carries.
+func NewSchema(blogService service.BlogService) (graphql.Schema, error) {
+	blogs := func(ctx context.Context) service.BlogService {
+		return requestBlogs(ctx, blogService)
+	}
+
+	postStatus := graphql.NewEnum(graphql.EnumConfig{
+		Name:        "PostStatus",
+		Description: "Publication state of a post",
+		Values: graphql.EnumValueConfigMap{
+			"DRAFT":     &graphql.EnumValueConfig{Value: models.BlogStatusDraft},
+			"PUBLISHED": &graphql.EnumValueConfig{Value: models.BlogStatusPublished},
+		},
+	})
+
+	tagType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Tag",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(string), nil
+				},
+			},
+			"postCount": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "Number of posts the caller may read carrying the tag; loaded for every tag in the response at once",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadersFrom(p.Context).tagCounts.Load(p.Source.(string)), nil
+				},
+			},
+		},
+	})
+
+	postType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Post",
+		Fields: graphql.Fields{
+			"id":          postField(graphql.NewNonNull(graphql.ID), func(post *models.BlogResponse) interface{} { return post.ID }),
+			"slug":        postField(graphql.NewNonNull(graphql.String), func(post *models.BlogResponse) interface{} { return post.Slug }),
+			"title":       postField(graphql.NewNonNull(graphql.String), func(post *models.BlogResponse) interface{} { return post.Title }),
+			"description": postField(graphql.NewNonNull(graphql.String), func(post *models.BlogResponse) interface{} { return post.Description }),
+			"body":        postField(graphql.NewNonNull(graphql.String), func(post *models.BlogResponse) interface{} { return post.Body }),
+			"status":      postField(graphql.NewNonNull(postStatus), func(post *models.BlogResponse) interface{} { return post.Status }),
+			"publishedAt": postField(graphql.DateTime, func(post *models.BlogResponse) interface{} { return post.PublishedAt }),
+			"createdAt":   postField(graphql.NewNonNull(graphql.DateTime), func(post *models.BlogResponse) interface{} { return post.CreatedAt }),
+			"updatedAt":   postField(graphql.NewNonNull(graphql.DateTime), func(post *models.BlogResponse) interface{} { return post.UpdatedAt }),
+			"tags":        postField(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(tagType))), func(post *models.BlogResponse) interface{} { return post.Tags }),
+		},
+	})
+
+	pageInfoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.Fields{
+			"endCursor": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if cursor := p.Source.(*models.BlogPage).EndCursor; cursor != "" {
+						return cursor, nil
+					}
+					return nil, nil
+				},
+			},
+			"hasNextPage": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*models.BlogPage).HasNextPage, nil
+				},
+			},
+		},
+	})
+
+	edgeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PostEdge",
+		Fields: graphql.Fields{
+			"cursor": postField(graphql.NewNonNull(graphql.String), func(post *models.BlogResponse) interface{} {
+				return service.EncodeBlogCursor(&models.BlogCursor{CreatedAt: post.CreatedAt, ID: post.ID})
+			}),
+			"node": postField(graphql.NewNonNull(postType), func(post *models.BlogResponse) interface{} { return post }),
+		},
+	})
+
+	connectionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PostConnection",
+		Fields: graphql.Fields{
+			"edges": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(edgeType))),
+				Resolve: pagePosts,
+			},
+			"nodes": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(postType))),
+				Resolve: pagePosts,
+			},
+			"pageInfo": &graphql.Field{
+				Type: graphql.NewNonNull(pageInfoType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source, nil
+				},
+			},
+		},
+	})
+
+	postInputFields := func(required bool) graphql.InputObjectConfigFieldMap {
+		title, body := graphql.Input(graphql.String), graphql.Input(graphql.String)
+		if required {
+			title, body = graphql.NewNonNull(graphql.String), graphql.NewNonNull(graphql.String)
+		}
+		return graphql.InputObjectConfigFieldMap{
+			"slug":        &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"title":       &graphql.InputObjectFieldConfig{Type: title},
+			"description": &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"body":        &graphql.InputObjectFieldConfig{Type: body},
+			"tags":        &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+			"status":      &graphql.InputObjectFieldConfig{Type: postStatus},
+		}
+	}
+	createInput := graphql.NewInputObject(graphql.InputObjectConfig{Name: "CreatePostInput", Fields: postInputFields(true)})
+	updateInput := graphql.NewInputObject(graphql.InputObjectConfig{Name: "UpdatePostInput", Fields: postInputFields(false)})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"posts": &graphql.Field{
+				Type:        graphql.NewNonNull(connectionType),
+				Description: "Posts matching the filters, newest first",
+				Args: graphql.FieldConfigArgument{
+					"first":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultFirst},
+					"after":  &graphql.ArgumentConfig{Type: graphql.String},
+					"status": &graphql.ArgumentConfig{Type: postStatus},
+					"tag":    &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					first, _ := p.Args["first"].(int)
+					after, _ := p.Args["after"].(string)
+					status, _ := p.Args["status"].(string)
+					tag, _ := p.Args["tag"].(string)
+
+					page, err := blogs(p.Context).ListBlogs(&models.BlogListQuery{Status: status, Tag: tag, First: first, After: after})
+					if err != nil {
+						return nil, resolverError(err)
+					}
+					return page, nil
+				},
+			},
+			"post": &graphql.Field{
+				Type:        postType,
+				Description: "A single post by ID or slug, or null if it does not exist",
+				Args: graphql.FieldConfigArgument{
+					"id":   &graphql.ArgumentConfig{Type: graphql.ID},
+					"slug": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, hasID := p.Args["id"].(string)
+					slug, hasSlug := p.Args["slug"].(string)
+					if hasID == hasSlug {
+						return nil, &codedError{err: errors.New("exactly one of id and slug is required"), code: CodeBadRequest}
+					}
+
+					var post *models.BlogResponse
+					var err error
+					if hasID {
+						post, err = blogs(p.Context).GetBlogByID(id)
+					} else {
+						post, err = blogs(p.Context).GetBlogBySlug(slug)
+					}
+					if errors.Is(err, repository.ErrBlogNotFound) {
+						return nil, nil
+					}
+					if err != nil {
+						return nil, resolverError(err)
+					}
+					return post, nil
+				},
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createPost": &graphql.Field{
+				Type: graphql.NewNonNull(postType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(createInput)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					input := p.Args["input"].(map[string]interface{})
+					request := &models.BlogCreateRequest{}
+					request.Slug, _ = input["slug"].(string)
+					request.Title, _ = input["title"].(string)
+					request.Description, _ = input["description"].(string)
+					request.Body, _ = input["body"].(string)
+					request.Status, _ = input["status"].(string)
+					if tags, ok := input["tags"].([]interface{}); ok {
+						request.Tags = stringList(tags)
+					}
+
+					post, err := blogs(p.Context).CreateBlog(request)
+					if err != nil {
+						return nil, resolverError(err)
+					}
+					return post, nil
+				},
+			},
+			"updatePost": &graphql.Field{
+				Type:        graphql.NewNonNull(postType),
+				Description: "Updates the fields given in input and leaves the others unchanged",
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(updateInput)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					input := p.Args["input"].(map[string]interface{})
+					request := &models.BlogUpdateRequest{
+						Slug:        optionalString(input, "slug"),
+						Title:       optionalString(input, "title"),
+						Description: optionalString(input, "description"),
+						Body:        optionalString(input, "body"),
+						Status:      optionalString(input, "status"),
+					}
+					if tags, ok := input["tags"].([]interface{}); ok {
+						list := stringList(tags)
+						request.Tags = &list
+					}
+
+					post, err := blogs(p.Context).UpdateBlog(p.Args["id"].(string), request)
+					if err != nil {
+						return nil, resolverError(err)
+					}
+					return post, nil
+				},
+			},
+			"deletePost": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if err := blogs(p.Context).DeleteBlog(p.Args["id"].(string)); err != nil {
+						return nil, resolverError(err)
+					}
+					return true, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+// postField builds a field resolved from the post it belongs to
+func postField(fieldType graphql.Output, get func(post *models.BlogResponse) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: fieldType,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return get(p.Source.(*models.BlogResponse)), nil
+		},
+	}
+}
+
+// pagePosts resolves the posts of a page as pointers, the source type of post fields
+func pagePosts(p graphql.ResolveParams) (interface{}, error) {
+	page := p.Source.(*models.BlogPage)
+	posts := make([]*models.BlogResponse, len(page.Posts))
+	for i := range page.Posts {
+		posts[i] = &page.Posts[i]
+	}
+	return posts, nil
+}
+
+// optionalString returns a pointer to the string argument name, or nil when it was not given
+func optionalString(args map[string]interface{}, name string) *string {
+	value, ok := args[name].(string)
+	if !ok {
+		return nil
+	}
+	return &value
+}
+
+// stringList converts a list argument to strings
+func stringList(values []interface{}) []string {
+	list := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
@@ -0,0 +1,316 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/repository"
+	"BlogManagment/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockBlogService is a mock implementation of BlogService
+type MockBlogService struct {
+	mock.Mock
+}
+
+func (m *MockBlogService) CreateBlog(request *models.BlogCreateRequest) (*models.BlogResponse, error) {
+	args := m.Called(request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogResponse), args.Error(1)
+}
+
+func (m *MockBlogService) GetBlogByID(id string) (*models.BlogResponse, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogResponse), args.Error(1)
+}
+
+func (m *MockBlogService) GetAllBlogs() ([]models.BlogResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BlogResponse), args.Error(1)
+}
+
+func (m *MockBlogService) GetPublishedBlogs() ([]models.BlogResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BlogResponse), args.Error(1)
+}
+
+func (m *MockBlogService) ListBlogs(query *models.BlogListQuery) (*models.BlogPage, error) {
+	args := m.Called(query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogPage), args.Error(1)
+}
+
+func (m *MockBlogService) GetBlogBySlug(slug string) (*models.BlogResponse, error) {
+	args := m.Called(slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogResponse), args.Error(1)
+}
+
+func (m *MockBlogService) CountBlogsByTags(query *models.TagCountQuery) (map[string]int, error) {
+	args := m.Called(query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+func (m *MockBlogService) UpdateBlog(id string, request *models.BlogUpdateRequest) (*models.BlogResponse, error) {
+	args := m.Called(id, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogResponse), args.Error(1)
+}
+
+func (m *MockBlogService) DeleteBlog(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockBlogService) BulkBlogs(request *models.BlogBulkRequest) (*models.BlogBulkResponse, error) {
+	args := m.Called(request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogBulkResponse), args.Error(1)
+}
+
+// WithTenant returns the mock itself, so expectations cover every tenant
+func (m *MockBlogService) WithTenant(tenantID string) service.BlogService {
+	return m
+}
+
+// WithRequester returns the mock itself
+func (m *MockBlogService) WithRequester(requester *models.Requester) service.BlogService {
+	return m
+}
+
+// testPosts returns a published post and a draft
+func testPosts() []models.BlogResponse {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	return []models.BlogResponse{
+		{ID: "1", Slug: "first", Title: "First", Tags: []string{"go", "web"}, Status: models.BlogStatusPublished, PublishedAt: &now, CreatedAt: now},
+		{ID: "2", Slug: "second", Title: "Second", Tags: []string{"go", "rust"}, Status: models.BlogStatusDraft, CreatedAt: now.Add(-time.Hour)},
+	}
+}
+
+// newMockBlogService creates a mock that looks up the test posts by ID and slug, so that
+// tests only need to set up the calls they are interested in
+func newMockBlogService() *MockBlogService {
+	m := &MockBlogService{}
+	for _, post := range testPosts() {
+		m.On("GetBlogByID", post.ID).Return(&post, nil).Maybe()
+		m.On("GetBlogBySlug", post.Slug).Return(&post, nil).Maybe()
+	}
+	m.On("GetBlogByID", mock.Anything).Return(nil, repository.ErrBlogNotFound).Maybe()
+	m.On("GetBlogBySlug", mock.Anything).Return(nil, repository.ErrBlogNotFound).Maybe()
+	return m
+}
+
+// execute runs query on behalf of a caller with every permission and decodes the JSON response
+func execute(t *testing.T, executor *Executor, query string, variables map[string]interface{}) (map[string]interface{}, bool) {
+	t.Helper()
+	return executeAs(t, executor, rbac.Unrestricted(""), query, variables)
+}
+
+// executeAs runs query on behalf of actor and decodes the JSON response
+func executeAs(t *testing.T, executor *Executor, actor *rbac.Actor, query string, variables map[string]interface{}) (map[string]interface{}, bool) {
+	t.Helper()
+	result, rejected := executor.Execute(rbac.NewContext(context.Background(), actor), &Request{Query: query, Variables: variables})
+	body, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &response))
+	return response, rejected
+}
+
+func TestExecutor_PostsBatchesTagCounts(t *testing.T) {
+	blogService := newMockBlogService()
+	executor, err := NewExecutor(blogService, 1000)
+	require.NoError(t, err)
+
+	blogService.On("ListBlogs", &models.BlogListQuery{Status: models.BlogStatusPublished, Tag: "go", First: 10}).
+		Return(&models.BlogPage{Posts: testPosts(), EndCursor: "next", HasNextPage: true}, nil)
+	var counted *models.TagCountQuery
+	blogService.On("CountBlogsByTags", mock.Anything).Run(func(args mock.Arguments) {
+		counted = args.Get(0).(*models.TagCountQuery)
+	}).Return(map[string]int{"go": 2, "web": 3, "rust": 4}, nil)
+
+	response, rejected := execute(t, executor, `{
+		posts(first: 10, status: PUBLISHED, tag: "go") {
+			edges { cursor node { id status tags { name postCount } } }
+			pageInfo { endCursor hasNextPage }
+		}
+	}`, nil)
+
+	assert.False(t, rejected)
+	assert.Nil(t, response["errors"])
+
+	posts := response["data"].(map[string]interface{})["posts"].(map[string]interface{})
+	edges := posts["edges"].([]interface{})
+	require.Len(t, edges, 2)
+	node := edges[0].(map[string]interface{})["node"].(map[string]interface{})
+	assert.Equal(t, "PUBLISHED", node["status"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "go", "postCount": float64(2)},
+		map[string]interface{}{"name": "web", "postCount": float64(3)},
+	}, node["tags"])
+	assert.Equal(t, map[string]interface{}{"endCursor": "next", "hasNextPage": true}, posts["pageInfo"])
+
+	// Tags of every post are counted with one call, and each tag only once
+	blogService.AssertNumberOfCalls(t, "CountBlogsByTags", 1)
+	assert.ElementsMatch(t, []string{"go", "web", "rust"}, counted.Names)
+	blogService.AssertExpectations(t)
+}
+
+func TestExecutor_TagCountsOnlyCountReadablePosts(t *testing.T) {
+	blogService := newMockBlogService()
+	executor, err := NewExecutor(blogService, 1000)
+	require.NoError(t, err)
+
+	blogService.On("ListBlogs", mock.Anything).Return(&models.BlogPage{Posts: testPosts()[:1]}, nil)
+	blogService.On("CountBlogsByTags", mock.MatchedBy(func(query *models.TagCountQuery) bool {
+		return query.PublishedOnly
+	})).Return(map[string]int{"go": 1, "web": 1}, nil)
+
+	response, _ := executeAs(t, executor, &rbac.Actor{}, `{ posts(first: 10) { edges { node { tags { postCount } } } } }`, nil)
+
+	assert.Nil(t, response["errors"], "drafts of others are not counted for anonymous callers")
+	blogService.AssertExpectations(t)
+}
+
+func TestExecutor_PostByIDOrSlug(t *testing.T) {
+	executor, err := NewExecutor(newMockBlogService(), 1000)
+	require.NoError(t, err)
+
+	response, _ := execute(t, executor, `{ a: post(id: "2") { title } b: post(slug: "first") { title } c: post(slug: "missing") { title } }`, nil)
+	assert.Nil(t, response["errors"])
+	assert.Equal(t, map[string]interface{}{
+		"a": map[string]interface{}{"title": "Second"},
+		"b": map[string]interface{}{"title": "First"},
+		"c": nil,
+	}, response["data"])
+
+	response, rejected := execute(t, executor, `{ post(id: "1", slug: "first") { title } }`, nil)
+	assert.False(t, rejected)
+	errs := response["errors"].([]interface{})
+	require.Len(t, errs, 1)
+	assert.Equal(t, map[string]interface{}{"code": CodeBadRequest}, errs[0].(map[string]interface{})["extensions"])
+}
+
+func TestExecutor_Mutations(t *testing.T) {
+	blogService := newMockBlogService()
+	executor, err := NewExecutor(blogService, 1000)
+	require.NoError(t, err)
+
+	blogService.On("CreateBlog", &models.BlogCreateRequest{Title: "Hello", Body: "World", Tags: []string{"go"}, Status: models.BlogStatusDraft}).
+		Return(&models.BlogResponse{ID: "new", Title: "Hello", Body: "World", Tags: []string{"go"}, Status: models.BlogStatusDraft}, nil)
+	response, _ := execute(t, executor, `mutation($input: CreatePostInput!) { createPost(input: $input) { id title status } }`,
+		map[string]interface{}{"input": map[string]interface{}{"title": "Hello", "body": "World", "tags": []interface{}{"go"}, "status": "DRAFT"}})
+	assert.Nil(t, response["errors"])
+	assert.Equal(t, map[string]interface{}{"id": "new", "title": "Hello", "status": "DRAFT"},
+		response["data"].(map[string]interface{})["createPost"])
+
+	var updated *models.BlogUpdateRequest
+	blogService.On("UpdateBlog", "1", mock.Anything).Run(func(args mock.Arguments) {
+		updated = args.Get(1).(*models.BlogUpdateRequest)
+	}).Return(&testPosts()[0], nil)
+	response, _ = execute(t, executor, `mutation { updatePost(id: "1", input: {title: "Renamed", tags: []}) { id } }`, nil)
+	assert.Nil(t, response["errors"])
+	require.NotNil(t, updated.Title)
+	assert.Equal(t, "Renamed", *updated.Title)
+	assert.Nil(t, updated.Body)
+	require.NotNil(t, updated.Tags)
+	assert.Empty(t, *updated.Tags)
+
+	blogService.On("UpdateBlog", "missing", mock.Anything).Return(nil, repository.ErrBlogNotFound)
+	response, _ = execute(t, executor, `mutation { updatePost(id: "missing", input: {title: "x"}) { id } }`, nil)
+	errs := response["errors"].([]interface{})
+	require.Len(t, errs, 1)
+	assert.Equal(t, map[string]interface{}{"code": CodeNotFound}, errs[0].(map[string]interface{})["extensions"])
+
+	blogService.On("DeleteBlog", "2").Return(nil)
+	response, _ = execute(t, executor, `mutation { deletePost(id: "2") }`, nil)
+	assert.Equal(t, map[string]interface{}{"deletePost": true}, response["data"])
+	blogService.AssertExpectations(t)
+}
+
+func TestExecutor_MutationsRequirePermissions(t *testing.T) {
+	blogService := newMockBlogService()
+	executor, err := NewExecutor(blogService, 1000)
+	require.NoError(t, err)
+	author := rbac.NewActor("bob", []string{rbac.RoleAuthor}, nil)
+
+	// Authors cannot publish, so their posts are created as drafts
+	blogService.On("CreateBlog", &models.BlogCreateRequest{Title: "Hello", Body: "World", Status: models.BlogStatusDraft, AuthorID: "bob"}).
+		Return(&models.BlogResponse{ID: "new", Title: "Hello", Body: "World", Status: models.BlogStatusDraft, AuthorID: "bob"}, nil)
+	response, _ := executeAs(t, executor, author, `mutation { createPost(input: {title: "Hello", body: "World"}) { id } }`, nil)
+	assert.Nil(t, response["errors"])
+
+	response, _ = executeAs(t, executor, author, `mutation { deletePost(id: "1") }`, nil)
+	errs := response["errors"].([]interface{})
+	require.Len(t, errs, 1)
+	assert.Equal(t, map[string]interface{}{"code": CodeForbidden}, errs[0].(map[string]interface{})["extensions"])
+	blogService.AssertNotCalled(t, "DeleteBlog", mock.Anything)
+
+	response, _ = executeAs(t, executor, &rbac.Actor{}, `mutation { createPost(input: {title: "Hello", body: "World"}) { id } }`, nil)
+	errs = response["errors"].([]interface{})
+	require.Len(t, errs, 1)
+	assert.Equal(t, map[string]interface{}{"code": CodeForbidden}, errs[0].(map[string]interface{})["extensions"])
+	blogService.AssertExpectations(t)
+}
+
+func TestExecutor_RejectsInvalidQueries(t *testing.T) {
+	executor, err := NewExecutor(newMockBlogService(), 1000)
+	require.NoError(t, err)
+
+	_, rejected := execute(t, executor, `{ posts {`, nil)
+	assert.True(t, rejected)
+
+	response, rejected := execute(t, executor, `{ posts { nodes { author } } }`, nil)
+	assert.True(t, rejected)
+	assert.NotEmpty(t, response["errors"])
+}
+
+func TestExecutor_CostLimit(t *testing.T) {
+	blogService := newMockBlogService()
+	executor, err := NewExecutor(blogService, 100)
+	require.NoError(t, err)
+
+	// 1 + 50 * (1 + (1 + 1 + 20 * 2)) exceeds the limit
+	response, rejected := execute(t, executor, `query($first: Int) { posts(first: $first) { nodes { id tags { name postCount } } } }`,
+		map[string]interface{}{"first": float64(50)})
+	assert.True(t, rejected)
+	errs := response["errors"].([]interface{})
+	require.Len(t, errs, 1)
+	assert.Equal(t, map[string]interface{}{"code": CodeCostExceeded}, errs[0].(map[string]interface{})["extensions"])
+
+	blogService.On("ListBlogs", mock.Anything).Return(&models.BlogPage{Posts: testPosts()}, nil)
+	response, rejected = execute(t, executor, `{ posts(first: 5) { nodes { id title } } }`, nil)
+	assert.False(t, rejected)
+	assert.Nil(t, response["errors"])
+}
@@ -0,0 +1,111 @@
+package grpcapi
+
+import (
+	"context"
+
+	"BlogManagment/internal/auth"
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/tenant"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AuthorizationMetadataKey is the metadata key carrying the credentials of a call, as
+// "Bearer <token>" or "ApiKey <key>" like the Authorization header of the HTTP API
+const AuthorizationMetadataKey = "authorization"
+
+// ActorResolver resolves the permissions of a principal in a tenant; middleware.Authorizer
+// implements it, so that calls hold the same permissions as HTTP requests
+type ActorResolver interface {
+	Actor(tenantID string, principal *auth.Principal, method, path string) (*rbac.Actor, error)
+}
+
+// Authenticator authenticates calls with the verifiers of the HTTP API and scopes them to their
+// tenant and to the actor of their principal. Calls without credentials are anonymous and may
+// only read; invalid credentials are rejected.
+type Authenticator struct {
+	bearer       auth.Verifier
+	apiKeys      auth.Verifier
+	resolver     *tenant.Resolver
+	requireClaim bool
+	actors       ActorResolver
+}
+
+// NewAuthenticator creates an authenticator verifying bearer tokens and API keys, either of which
+// may be nil to disable its scheme. With requireClaim, credentials without a tenant claim are
+// only accepted in the default tenant.
+func NewAuthenticator(bearer, apiKeys auth.Verifier, resolver *tenant.Resolver, requireClaim bool, actors ActorResolver) *Authenticator {
+	return &Authenticator{bearer: bearer, apiKeys: apiKeys, resolver: resolver, requireClaim: requireClaim, actors: actors}
+}
+
+// UnaryInterceptor authenticates unary calls
+func (a *Authenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor authenticates streaming calls
+func (a *Authenticator) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authenticate(stream.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// authenticatedStream is a server stream whose context carries the principal, tenant and actor of the call
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate returns a context carrying the principal, tenant and actor of a call
+func (a *Authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	principal, err := auth.Authenticate(firstValue(md, AuthorizationMetadataKey), a.bearer, a.apiKeys)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	tenantID, err := resolveTenant(md, a.resolver, principal, a.requireClaim)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := a.actors.Actor(tenantID, principal, "GRPC", method)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	ctx = tenant.NewContext(ctx, tenantID)
+	ctx = rbac.NewContext(ctx, actor)
+	return newPrincipalContext(ctx, principal), nil
+}
+
+// principalKey is the context key holding the principal of a call
+type principalKey struct{}
+
+// newPrincipalContext returns a context carrying the principal of a call; nil for anonymous calls
+func newPrincipalContext(ctx context.Context, principal *auth.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// principalFromContext returns the principal of a call, or nil for anonymous calls
+func principalFromContext(ctx context.Context) *auth.Principal {
+	principal, _ := ctx.Value(principalKey{}).(*auth.Principal)
+	return principal
+}
@@ -0,0 +1,141 @@
+package grpcapi
+
+import (
+	"context"
+
+	"BlogManagment/api/blogpb"
+	"BlogManagment/internal/models"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CreatePost creates a post
+func (s *Server) CreatePost(ctx context.Context, req *blogpb.CreatePostRequest) (*blogpb.Post, error) {
+	postStatus, err := statusFromProto(req.GetStatus())
+	if err != nil {
+		return nil, err
+	}
+
+	post, err := s.blogs(ctx).CreateBlog(&models.BlogCreateRequest{
+		Slug:        req.GetSlug(),
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		Body:        req.GetBody(),
+		Tags:        req.GetTags(),
+		Status:      postStatus,
+	})
+	if err != nil {
+		return nil, statusError(err, codes.InvalidArgument)
+	}
+	return newPost(post), nil
+}
+
+// GetPost fetches a post by ID or slug
+func (s *Server) GetPost(ctx context.Context, req *blogpb.GetPostRequest) (*blogpb.Post, error) {
+	var post *models.BlogResponse
+	var err error
+	switch lookup := req.GetLookup().(type) {
+	case *blogpb.GetPostRequest_Id:
+		if lookup.Id == "" {
+			return nil, status.Error(codes.InvalidArgument, "blog ID is required")
+		}
+		post, err = s.blogs(ctx).GetBlogByID(lookup.Id)
+	case *blogpb.GetPostRequest_Slug:
+		if lookup.Slug == "" {
+			return nil, status.Error(codes.InvalidArgument, "blog slug is required")
+		}
+		post, err = s.blogs(ctx).GetBlogBySlug(lookup.Slug)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "id or slug is required")
+	}
+	if err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	return newPost(post), nil
+}
+
+// ListPosts lists a page of posts, newest first
+func (s *Server) ListPosts(ctx context.Context, req *blogpb.ListPostsRequest) (*blogpb.ListPostsResponse, error) {
+	postStatus, err := statusFromProto(req.GetStatus())
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.blogs(ctx).ListBlogs(&models.BlogListQuery{
+		Status: postStatus,
+		Tag:    req.GetTag(),
+		First:  int(req.GetPageSize()),
+		After:  req.GetPageToken(),
+	})
+	if err != nil {
+		return nil, statusError(err, codes.InvalidArgument)
+	}
+
+	response := &blogpb.ListPostsResponse{Posts: make([]*blogpb.Post, len(page.Posts))}
+	for i := range page.Posts {
+		response.Posts[i] = newPost(&page.Posts[i])
+	}
+	if page.HasNextPage {
+		response.NextPageToken = page.EndCursor
+	}
+	return response, nil
+}
+
+// UpdatePost changes the fields set in the request
+func (s *Server) UpdatePost(ctx context.Context, req *blogpb.UpdatePostRequest) (*blogpb.Post, error) {
+	request := &models.BlogUpdateRequest{
+		Slug:        req.Slug,
+		Title:       req.Title,
+		Description: req.Description,
+		Body:        req.Body,
+	}
+	if req.Tags != nil {
+		tags := req.Tags.GetNames()
+		request.Tags = &tags
+	}
+	if req.Status != nil {
+		postStatus, err := statusFromProto(req.GetStatus())
+		if err != nil {
+			return nil, err
+		}
+		request.Status = &postStatus
+	}
+
+	post, err := s.blogs(ctx).UpdateBlog(req.GetId(), request)
+	if err != nil {
+		return nil, statusError(err, codes.InvalidArgument)
+	}
+	return newPost(post), nil
+}
+
+// DeletePost deletes a post
+func (s *Server) DeletePost(ctx context.Context, req *blogpb.DeletePostRequest) (*blogpb.DeletePostResponse, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "blog ID is required")
+	}
+	if err := s.blogs(ctx).DeleteBlog(req.GetId()); err != nil {
+		return nil, statusError(err, codes.Internal)
+	}
+	return &blogpb.DeletePostResponse{}, nil
+}
+
+// newPost converts a blog response to its protobuf message
+func newPost(post *models.BlogResponse) *blogpb.Post {
+	message := &blogpb.Post{
+		Id:          post.ID,
+		Slug:        post.Slug,
+		Title:       post.Title,
+		Description: post.Description,
+		Body:        post.Body,
+		Tags:        post.Tags,
+		Status:      statusToProto(post.Status),
+		CreatedAt:   timestamppb.New(post.CreatedAt),
+		UpdatedAt:   timestamppb.New(post.UpdatedAt),
+	}
+	if post.PublishedAt != nil {
+		message.PublishedAt = timestamppb.New(*post.PublishedAt)
+	}
+	return message
+}
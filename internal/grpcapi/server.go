@@ -0,0 +1,99 @@
+// Package grpcapi serves the blog API over gRPC for internal consumers, using the protobuf
+// definitions in api/proto.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"BlogManagment/api/blogpb"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/repository"
+	"BlogManagment/internal/service"
+	"BlogManagment/internal/stream"
+	"BlogManagment/internal/tenant"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements blogpb.BlogServiceServer on top of the blog service
+type Server struct {
+	blogpb.UnimplementedBlogServiceServer
+	blogService service.BlogService
+	broker      *stream.Broker
+}
+
+// NewServer creates a new gRPC blog server. WatchPosts streams the events of broker.
+func NewServer(blogService service.BlogService, broker *stream.Broker) *Server {
+	return &Server{blogService: blogService, broker: broker}
+}
+
+// RequestIDMetadataKey is the metadata key carrying the request ID recorded in the audit log
+const RequestIDMetadataKey = "x-request-id"
+
+// blogs returns the blog service scoped to the tenant of the call and checked against its
+// actor, attributing its changes to the caller in the audit log
+func (s *Server) blogs(ctx context.Context) service.BlogService {
+	scoped := s.blogService.WithTenant(tenant.FromContext(ctx)).WithRequester(requester(ctx))
+	return service.WithAccessControl(scoped, rbac.FromContext(ctx))
+}
+
+// requester identifies the caller of a call by its principal, the address of its peer and its request ID
+func requester(ctx context.Context) *models.Requester {
+	md, _ := metadata.FromIncomingContext(ctx)
+	requester := &models.Requester{RequestID: firstValue(md, RequestIDMetadataKey)}
+	if principal := principalFromContext(ctx); principal != nil {
+		requester.Kind = principal.Kind
+		requester.Subject = principal.Subject
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		requester.IP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(requester.IP); err == nil {
+			requester.IP = host
+		}
+	}
+	return requester
+}
+
+// statusError converts a service error to a gRPC status, mirroring the status codes of the REST API.
+// fallback is used for errors without a more specific code.
+func statusError(err error, fallback codes.Code) error {
+	switch {
+	case errors.Is(err, repository.ErrBlogNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, repository.ErrSlugConflict):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, rbac.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return status.Error(fallback, err.Error())
+}
+
+// statusToProto converts a publication state to its protobuf enum
+func statusToProto(postStatus string) blogpb.PostStatus {
+	switch postStatus {
+	case models.BlogStatusDraft:
+		return blogpb.PostStatus_POST_STATUS_DRAFT
+	case models.BlogStatusPublished:
+		return blogpb.PostStatus_POST_STATUS_PUBLISHED
+	}
+	return blogpb.PostStatus_POST_STATUS_UNSPECIFIED
+}
+
+// statusFromProto converts a protobuf publication state; unspecified becomes the empty string
+func statusFromProto(postStatus blogpb.PostStatus) (string, error) {
+	switch postStatus {
+	case blogpb.PostStatus_POST_STATUS_UNSPECIFIED:
+		return "", nil
+	case blogpb.PostStatus_POST_STATUS_DRAFT:
+		return models.BlogStatusDraft, nil
+	case blogpb.PostStatus_POST_STATUS_PUBLISHED:
+		return models.BlogStatusPublished, nil
+	}
+	return "", status.Errorf(codes.InvalidArgument, "unknown post status %d", postStatus)
+}
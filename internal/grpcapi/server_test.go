@@ -0,0 +1,471 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"BlogManagment/api/blogpb"
+	"BlogManagment/internal/auth"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/repository"
+	"BlogManagment/internal/service"
+	"BlogManagment/internal/stream"
+	"BlogManagment/internal/tenant"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// MockBlogService is a mock implementation of BlogService
+type MockBlogService struct {
+	mock.Mock
+	// tenantID is the tenant the server scoped the service to
+	tenantID  string
+	requester *models.Requester
+}
+
+func (m *MockBlogService) CreateBlog(request *models.BlogCreateRequest) (*models.BlogResponse, error) {
+	args := m.Called(request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogResponse), args.Error(1)
+}
+
+func (m *MockBlogService) GetBlogByID(id string) (*models.BlogResponse, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogResponse), args.Error(1)
+}
+
+func (m *MockBlogService) GetAllBlogs() ([]models.BlogResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BlogResponse), args.Error(1)
+}
+
+func (m *MockBlogService) GetPublishedBlogs() ([]models.BlogResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BlogResponse), args.Error(1)
+}
+
+func (m *MockBlogService) ListBlogs(query *models.BlogListQuery) (*models.BlogPage, error) {
+	args := m.Called(query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogPage), args.Error(1)
+}
+
+func (m *MockBlogService) GetBlogBySlug(slug string) (*models.BlogResponse, error) {
+	args := m.Called(slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogResponse), args.Error(1)
+}
+
+func (m *MockBlogService) CountBlogsByTags(query *models.TagCountQuery) (map[string]int, error) {
+	args := m.Called(query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+func (m *MockBlogService) UpdateBlog(id string, request *models.BlogUpdateRequest) (*models.BlogResponse, error) {
+	args := m.Called(id, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogResponse), args.Error(1)
+}
+
+func (m *MockBlogService) DeleteBlog(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockBlogService) BulkBlogs(request *models.BlogBulkRequest) (*models.BlogBulkResponse, error) {
+	args := m.Called(request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogBulkResponse), args.Error(1)
+}
+
+// WithTenant records the tenant and returns the mock itself
+func (m *MockBlogService) WithTenant(tenantID string) service.BlogService {
+	m.tenantID = tenantID
+	return m
+}
+
+// WithRequester records the requester and returns the mock itself
+func (m *MockBlogService) WithRequester(requester *models.Requester) service.BlogService {
+	m.requester = requester
+	return m
+}
+
+// MockTenantRepository is a mock implementation of TenantRepository
+type MockTenantRepository struct {
+	mock.Mock
+}
+
+func (m *MockTenantRepository) Create(tenant *models.Tenant) error {
+	args := m.Called(tenant)
+	return args.Error(0)
+}
+
+func (m *MockTenantRepository) GetByID(id string) (*models.Tenant, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Tenant), args.Error(1)
+}
+
+func (m *MockTenantRepository) GetAll() ([]models.Tenant, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Tenant), args.Error(1)
+}
+
+// newTestOutbox opens an empty in-memory SQLite outbox and returns a repository over it
+func newTestOutbox(t *testing.T) (*gorm.DB, repository.OutboxRepository) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+uuid.New().String()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.OutboxEvent{}))
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db, repository.NewOutboxRepository(db)
+}
+
+// appendEvent commits an event about a published post with tags to the outbox
+func appendEvent(t *testing.T, db *gorm.DB, eventType string, tags ...string) {
+	appendPostEvent(t, db, eventType, &models.BlogResponse{ID: "post", Status: models.BlogStatusPublished, Tags: tags})
+}
+
+// appendPostEvent commits an event about post to the outbox
+func appendPostEvent(t *testing.T, db *gorm.DB, eventType string, post *models.BlogResponse) {
+	t.Helper()
+	event := &models.BlogEvent{ID: uuid.New().String(), Type: eventType, OccurredAt: time.Now(), Data: post}
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+	require.NoError(t, db.Create(&models.OutboxEvent{
+		EventID:     event.ID,
+		Type:        eventType,
+		AggregateID: post.ID,
+		Payload:     payload,
+		CreatedAt:   event.OccurredAt,
+	}).Error)
+}
+
+// testSecret signs the bearer tokens of the tests
+const testSecret = "test-secret"
+
+// MockActorResolver is a mock implementation of ActorResolver
+type MockActorResolver struct {
+	mock.Mock
+}
+
+func (m *MockActorResolver) Actor(tenantID string, principal *auth.Principal, method, path string) (*rbac.Actor, error) {
+	args := m.Called(tenantID, principal, method, path)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*rbac.Actor), args.Error(1)
+}
+
+// setupServer serves the API over an in-memory connection, authenticating calls with bearer
+// tokens signed with testSecret, and returns a client for it. Anonymous callers may only read,
+// and every authenticated one is an editor.
+func setupServer(t *testing.T) (blogpb.BlogServiceClient, *MockBlogService, *gorm.DB, *stream.Broker) {
+	blogService := &MockBlogService{}
+	db, outbox := newTestOutbox(t)
+	broker := stream.NewBroker(outbox)
+
+	tenants := &MockTenantRepository{}
+	tenants.On("GetByID", "acme").Return(&models.Tenant{ID: "acme"}, nil)
+	tenants.On("GetByID", mock.Anything).Return(nil, repository.ErrTenantNotFound)
+
+	actors := &MockActorResolver{}
+	actors.On("Actor", mock.Anything, (*auth.Principal)(nil), "GRPC", mock.Anything).Return(rbac.NewActor("", nil, nil), nil)
+	actors.On("Actor", mock.Anything, mock.AnythingOfType("*auth.Principal"), "GRPC", mock.Anything).
+		Return(rbac.NewActor("alice", []string{rbac.RoleEditor}, nil), nil)
+	authenticator := NewAuthenticator(auth.NewJWTVerifier(testSecret), nil, tenant.NewResolver(tenants, ""), true, actors)
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(authenticator.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(authenticator.StreamInterceptor()))
+	blogpb.RegisterBlogServiceServer(grpcServer, NewServer(blogService, broker))
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return blogpb.NewBlogServiceClient(conn), blogService, db, broker
+}
+
+// signedIn returns a context whose calls carry a bearer token of alice, restricted to tenantID when it is set
+func signedIn(t *testing.T, tenantID string) context.Context {
+	token, _, err := auth.NewJWTIssuer(testSecret, time.Hour).Issue("alice", tenantID, false)
+	require.NoError(t, err)
+	return metadata.AppendToOutgoingContext(context.Background(), AuthorizationMetadataKey, "Bearer "+token)
+}
+
+func TestServer_CreatePost(t *testing.T) {
+	client, blogService, _, _ := setupServer(t)
+	blogService.On("CreateBlog", &models.BlogCreateRequest{Title: "Hello", Body: "World", Tags: []string{"go"}, Status: models.BlogStatusDraft, AuthorID: "alice"}).
+		Return(&models.BlogResponse{ID: "1", Slug: "hello", Title: "Hello", Status: models.BlogStatusDraft, CreatedAt: time.Unix(100, 0)}, nil)
+	blogService.On("CreateBlog", mock.MatchedBy(func(request *models.BlogCreateRequest) bool { return request.Slug == "taken" })).
+		Return(nil, repository.ErrSlugConflict)
+
+	ctx := signedIn(t, "")
+	post, err := client.CreatePost(ctx, &blogpb.CreatePostRequest{
+		Title: "Hello", Body: "World", Tags: []string{"go"}, Status: blogpb.PostStatus_POST_STATUS_DRAFT,
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, blogService.requester)
+	assert.Equal(t, "alice", blogService.requester.Subject, "changes are attributed to the caller in the audit log")
+	assert.Equal(t, "1", post.GetId())
+	assert.Equal(t, blogpb.PostStatus_POST_STATUS_DRAFT, post.GetStatus())
+	assert.Equal(t, int64(100), post.GetCreatedAt().GetSeconds())
+	assert.Nil(t, post.GetPublishedAt())
+
+	_, err = client.CreatePost(ctx, &blogpb.CreatePostRequest{Title: "Hello", Body: "World", Slug: "taken"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	blogService.AssertExpectations(t)
+}
+
+func TestServer_GetPost(t *testing.T) {
+	client, blogService, _, _ := setupServer(t)
+	blogService.On("GetBlogByID", "1").Return(&models.BlogResponse{ID: "1", Title: "Hello", Status: models.BlogStatusPublished}, nil)
+	blogService.On("GetBlogByID", "2").Return(nil, repository.ErrBlogNotFound)
+	blogService.On("GetBlogBySlug", "hello").Return(&models.BlogResponse{ID: "1", Slug: "hello", Status: models.BlogStatusPublished}, nil)
+	blogService.On("GetBlogBySlug", "draft").Return(&models.BlogResponse{ID: "3", Slug: "draft", Status: models.BlogStatusDraft}, nil)
+
+	post, err := client.GetPost(context.Background(), &blogpb.GetPostRequest{Lookup: &blogpb.GetPostRequest_Id{Id: "1"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", post.GetTitle())
+
+	post, err = client.GetPost(context.Background(), &blogpb.GetPostRequest{Lookup: &blogpb.GetPostRequest_Slug{Slug: "hello"}})
+	require.NoError(t, err)
+	assert.Equal(t, "hello", post.GetSlug())
+
+	_, err = client.GetPost(context.Background(), &blogpb.GetPostRequest{Lookup: &blogpb.GetPostRequest_Id{Id: "2"}})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetPost(context.Background(), &blogpb.GetPostRequest{Lookup: &blogpb.GetPostRequest_Slug{Slug: "draft"}})
+	assert.Equal(t, codes.NotFound, status.Code(err), "calls are anonymous, so drafts are hidden")
+
+	_, err = client.GetPost(context.Background(), &blogpb.GetPostRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_ListPosts(t *testing.T) {
+	client, blogService, _, _ := setupServer(t)
+	posts := []models.BlogResponse{{ID: "1"}, {ID: "2"}}
+	blogService.On("ListBlogs", &models.BlogListQuery{Status: models.BlogStatusPublished, Tag: "go", First: 2, PublishedOnly: true}).
+		Return(&models.BlogPage{Posts: posts, EndCursor: "cursor", HasNextPage: true}, nil)
+	blogService.On("ListBlogs", mock.MatchedBy(func(query *models.BlogListQuery) bool { return query.After == "cursor" })).
+		Return(&models.BlogPage{Posts: posts, EndCursor: "cursor"}, nil)
+
+	response, err := client.ListPosts(context.Background(), &blogpb.ListPostsRequest{PageSize: 2, Status: blogpb.PostStatus_POST_STATUS_PUBLISHED, Tag: "go"})
+	require.NoError(t, err)
+	assert.Len(t, response.GetPosts(), 2)
+	assert.Equal(t, "cursor", response.GetNextPageToken())
+
+	response, err = client.ListPosts(context.Background(), &blogpb.ListPostsRequest{PageToken: "cursor"})
+	require.NoError(t, err)
+	assert.Empty(t, response.GetNextPageToken())
+
+	_, err = client.ListPosts(context.Background(), &blogpb.ListPostsRequest{Status: blogpb.PostStatus(7)})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	blogService.AssertExpectations(t)
+}
+
+func TestServer_UpdatePost_OnlySetFields(t *testing.T) {
+	client, blogService, _, _ := setupServer(t)
+	var updated *models.BlogUpdateRequest
+	blogService.On("UpdateBlog", "1", mock.Anything).Run(func(args mock.Arguments) {
+		updated = args.Get(1).(*models.BlogUpdateRequest)
+	}).Return(&models.BlogResponse{ID: "1"}, nil)
+
+	_, err := client.UpdatePost(signedIn(t, ""), &blogpb.UpdatePostRequest{
+		Id:     "1",
+		Title:  proto.String("Renamed"),
+		Tags:   &blogpb.TagList{},
+		Status: blogpb.PostStatus_POST_STATUS_PUBLISHED.Enum(),
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, updated.Title)
+	assert.Equal(t, "Renamed", *updated.Title)
+	assert.Nil(t, updated.Body)
+	require.NotNil(t, updated.Tags)
+	assert.Empty(t, *updated.Tags)
+	assert.Equal(t, models.BlogStatusPublished, *updated.Status)
+}
+
+func TestServer_DeletePost(t *testing.T) {
+	client, blogService, _, _ := setupServer(t)
+	blogService.On("DeleteBlog", "1").Return(nil)
+	blogService.On("DeleteBlog", "2").Return(repository.ErrBlogNotFound)
+
+	ctx := metadata.AppendToOutgoingContext(signedIn(t, ""), RequestIDMetadataKey, "req-1")
+	_, err := client.DeletePost(ctx, &blogpb.DeletePostRequest{Id: "1"})
+	assert.NoError(t, err)
+	require.NotNil(t, blogService.requester)
+	assert.Equal(t, "req-1", blogService.requester.RequestID, "the request ID is recorded in the audit log")
+
+	_, err = client.DeletePost(ctx, &blogpb.DeletePostRequest{Id: "2"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServer_WatchPosts_ReplaysThenStreamsLive(t *testing.T) {
+	client, _, outbox, broker := setupServer(t)
+	appendEvent(t, outbox, models.EventPostCreated, "go")
+	appendEvent(t, outbox, models.EventPostCreated, "rust")
+	require.NoError(t, broker.Poll())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watch, err := client.WatchPosts(ctx, &blogpb.WatchPostsRequest{Tag: "go", AfterSequence: proto.Int64(0)})
+	require.NoError(t, err)
+
+	event, err := watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), event.GetSequence())
+	assert.Equal(t, []string{"go"}, event.GetPost().GetTags())
+
+	// The stream subscribed before replaying, so it receives events committed from now on
+	appendEvent(t, outbox, models.EventPostDeleted)
+	require.NoError(t, broker.Poll())
+
+	event, err = watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), event.GetSequence())
+	assert.Equal(t, models.EventPostDeleted, event.GetType())
+}
+
+func TestServer_WatchPosts_HidesDrafts(t *testing.T) {
+	client, _, outbox, _ := setupServer(t)
+	appendPostEvent(t, outbox, models.EventPostCreated, &models.BlogResponse{ID: "draft", Status: models.BlogStatusDraft, AuthorID: "bob"})
+	appendPostEvent(t, outbox, models.EventPostCreated, &models.BlogResponse{ID: "published", Status: models.BlogStatusPublished})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watch, err := client.WatchPosts(ctx, &blogpb.WatchPostsRequest{AfterSequence: proto.Int64(0)})
+	require.NoError(t, err)
+	event, err := watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "published", event.GetPost().GetId(), "anonymous callers never see drafts")
+
+	// Every authenticated caller of the tests is an editor, who may read every draft
+	watch, err = client.WatchPosts(signedIn(t, ""), &blogpb.WatchPostsRequest{AfterSequence: proto.Int64(0)})
+	require.NoError(t, err)
+	event, err = watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "draft", event.GetPost().GetId())
+}
+
+func TestServer_WatchPosts_InvalidType(t *testing.T) {
+	client, _, _, _ := setupServer(t)
+
+	watch, err := client.WatchPosts(context.Background(), &blogpb.WatchPostsRequest{Types: []string{"post.read"}})
+	require.NoError(t, err)
+
+	_, err = watch.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_TenantMetadata(t *testing.T) {
+	client, blogService, _, _ := setupServer(t)
+	blogService.On("GetBlogByID", "1").Return(&models.BlogResponse{ID: "1", Status: models.BlogStatusPublished}, nil)
+
+	_, err := client.GetPost(context.Background(), &blogpb.GetPostRequest{Lookup: &blogpb.GetPostRequest_Id{Id: "1"}})
+	require.NoError(t, err)
+	assert.Equal(t, models.DefaultTenantID, blogService.tenantID)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), TenantMetadataKey, "acme")
+	_, err = client.GetPost(ctx, &blogpb.GetPostRequest{Lookup: &blogpb.GetPostRequest_Id{Id: "1"}})
+	require.NoError(t, err)
+	assert.Equal(t, "acme", blogService.tenantID)
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), TenantMetadataKey, "initech")
+	_, err = client.GetPost(ctx, &blogpb.GetPostRequest{Lookup: &blogpb.GetPostRequest_Id{Id: "1"}})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	watch, err := client.WatchPosts(ctx, &blogpb.WatchPostsRequest{})
+	require.NoError(t, err)
+	_, err = watch.Recv()
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServer_Authentication(t *testing.T) {
+	client, blogService, _, _ := setupServer(t)
+	blogService.On("CreateBlog", mock.MatchedBy(func(request *models.BlogCreateRequest) bool { return request.AuthorID == "alice" })).
+		Return(&models.BlogResponse{ID: "1", Title: "Hello", Status: models.BlogStatusPublished}, nil)
+	create := &blogpb.CreatePostRequest{Title: "Hello", Body: "World"}
+
+	_, err := client.CreatePost(context.Background(), create)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "anonymous callers may only read")
+	blogService.AssertNotCalled(t, "CreateBlog", mock.Anything)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), AuthorizationMetadataKey, "Bearer forged")
+	_, err = client.GetPost(ctx, &blogpb.GetPostRequest{Lookup: &blogpb.GetPostRequest_Id{Id: "1"}})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// A token restricted to a tenant cannot be used for another one
+	ctx = metadata.AppendToOutgoingContext(signedIn(t, "acme"), TenantMetadataKey, models.DefaultTenantID)
+	_, err = client.CreatePost(ctx, create)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	blogService.AssertNotCalled(t, "CreateBlog", mock.Anything)
+
+	// Without a claim, credentials are only valid for the default tenant
+	ctx = metadata.AppendToOutgoingContext(signedIn(t, ""), TenantMetadataKey, "acme")
+	_, err = client.CreatePost(ctx, create)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	blogService.AssertNotCalled(t, "CreateBlog", mock.Anything)
+
+	_, err = client.CreatePost(signedIn(t, "acme"), create)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", blogService.tenantID)
+	blogService.AssertNumberOfCalls(t, "CreateBlog", 1)
+}
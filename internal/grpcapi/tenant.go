@@ -0,0 +1,48 @@
+package grpcapi
+
+import (
+	"errors"
+
+	"BlogManagment/internal/auth"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"BlogManagment/internal/tenant"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TenantMetadataKey is the metadata key naming the tenant of a call
+const TenantMetadataKey = "x-tenant-id"
+
+// resolveTenant returns the tenant of a call, named by the tenant claim of its principal, its
+// metadata or its authority. With requireClaim, credentials without a tenant claim are only
+// accepted in the default tenant, as in the HTTP API.
+func resolveTenant(md metadata.MD, resolver *tenant.Resolver, principal *auth.Principal, requireClaim bool) (string, error) {
+	claim := ""
+	if principal != nil {
+		claim = principal.TenantID
+	}
+
+	tenantID, err := resolver.Resolve(claim, firstValue(md, TenantMetadataKey), firstValue(md, ":authority"))
+	switch {
+	case errors.Is(err, repository.ErrTenantNotFound):
+		return "", status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, tenant.ErrTenantMismatch):
+		return "", status.Error(codes.PermissionDenied, err.Error())
+	case err != nil:
+		return "", status.Error(codes.Internal, err.Error())
+	case requireClaim && principal != nil && claim == "" && tenantID != models.DefaultTenantID:
+		return "", status.Error(codes.PermissionDenied, "credentials without a tenant claim are only valid for the default tenant")
+	}
+	return tenantID, nil
+}
+
+// firstValue returns the first value of a metadata key, or "" when it is missing
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
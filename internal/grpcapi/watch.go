@@ -0,0 +1,76 @@
+package grpcapi
+
+import (
+	"BlogManagment/api/blogpb"
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/stream"
+	"BlogManagment/internal/tenant"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// WatchPosts streams the events of the posts the caller may read until the client cancels or
+// falls too far behind.
+// Live events are buffered while missed events are replayed, and those already replayed are skipped.
+func (s *Server) WatchPosts(req *blogpb.WatchPostsRequest, srv blogpb.BlogService_WatchPostsServer) error {
+	filter, err := stream.NewFilter(req.GetTypes(), req.GetTag())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	filter.TenantID = tenant.FromContext(srv.Context())
+	filter.Actor = rbac.FromContext(srv.Context())
+	if req.GetAfterSequence() < 0 {
+		return status.Error(codes.InvalidArgument, "after_sequence must be the sequence of a previous event")
+	}
+
+	subscription := s.broker.Subscribe(filter)
+	defer s.broker.Unsubscribe(subscription)
+
+	var position int64
+	if req.AfterSequence != nil {
+		position, err = s.broker.Replay(req.GetAfterSequence(), filter, func(message *stream.Message) error {
+			return srv.Send(newPostEvent(message))
+		})
+		if err != nil {
+			// Errors from Send already carry a status; the others come from reading the outbox
+			if _, isStatus := status.FromError(err); isStatus {
+				return err
+			}
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	for {
+		select {
+		case <-srv.Context().Done():
+			return status.FromContextError(srv.Context().Err()).Err()
+		case message, ok := <-subscription.C:
+			if !ok {
+				// Dropped for falling behind; the client resumes from its last sequence
+				return status.Error(codes.ResourceExhausted, "fell too far behind, resume with after_sequence")
+			}
+			if message.Sequence <= position {
+				continue
+			}
+			if err := srv.Send(newPostEvent(message)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// newPostEvent converts a stream message to its protobuf message
+func newPostEvent(message *stream.Message) *blogpb.PostEvent {
+	event := &blogpb.PostEvent{
+		Sequence:   message.Sequence,
+		Id:         message.Event.ID,
+		Type:       message.Event.Type,
+		OccurredAt: timestamppb.New(message.Event.OccurredAt),
+	}
+	if message.Event.Data != nil {
+		event.Post = newPost(message.Event.Data)
+	}
+	return event
+}
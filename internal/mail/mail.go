@@ -0,0 +1,94 @@
+// Package mail sends the emails of the API, such as password reset links. Mailers are
+// pluggable; the log and file mailers deliver nothing and are meant for development.
+package mail
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Mailer drivers
+const (
+	DriverLog  = "log"
+	DriverFile = "file"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers messages
+type Mailer interface {
+	Send(message *Message) error
+}
+
+// New creates the mailer of a driver. Messages are sent from the address from; the file
+// driver writes them to dir.
+func New(driver, dir, from string) (Mailer, error) {
+	switch driver {
+	case DriverLog:
+		return NewLogMailer(from), nil
+	case DriverFile:
+		return NewFileMailer(dir, from)
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q, expected %s or %s", driver, DriverLog, DriverFile)
+	}
+}
+
+// LogMailer writes messages to the application log instead of sending them
+type LogMailer struct {
+	from string
+}
+
+// NewLogMailer creates a mailer that logs messages sent from the address from
+func NewLogMailer(from string) *LogMailer {
+	return &LogMailer{from: from}
+}
+
+// Send logs the message
+func (m *LogMailer) Send(message *Message) error {
+	log.Printf("Mail from %s to %s: %s\n%s", m.from, message.To, message.Subject, message.Body)
+	return nil
+}
+
+// FileMailer writes each message to a file of its own in a directory, in the Internet Message
+// Format, so that it can be opened with a mail client
+type FileMailer struct {
+	dir  string
+	from string
+	sent atomic.Int64
+}
+
+// NewFileMailer creates a mailer writing messages sent from the address from to dir, which is
+// created when missing
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+// Send writes the message to a new .eml file named after the time it was sent
+func (m *FileMailer) Send(message *Message) error {
+	now := time.Now().UTC()
+	name := fmt.Sprintf("%s-%d.eml", now.Format("20060102-150405.000000000"), m.sent.Add(1))
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "From: %s\r\n", m.from)
+	fmt.Fprintf(&content, "To: %s\r\n", message.To)
+	fmt.Fprintf(&content, "Subject: %s\r\n", message.Subject)
+	fmt.Fprintf(&content, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	content.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	content.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+
+	// Messages carry secrets such as reset tokens, so only the owner may read them
+	return os.WriteFile(filepath.Join(m.dir, name), []byte(content.String()), 0o600)
+}
@@ -0,0 +1,44 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMailer_Send(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	mailer, err := New(DriverFile, dir, "no-reply@example.com")
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, mailer.Send(&Message{To: "alice@example.com", Subject: "Reset your password", Body: "Line one\nLine two\n"}))
+	}
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2, "every message gets a file of its own")
+
+	info, err := files[0].Info()
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	content, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "From: no-reply@example.com\r\n")
+	assert.Contains(t, string(content), "To: alice@example.com\r\n")
+	assert.Contains(t, string(content), "Subject: Reset your password\r\n")
+	assert.Contains(t, string(content), "\r\n\r\nLine one\r\nLine two\r\n")
+}
+
+func TestNew_UnknownDriver(t *testing.T) {
+	_, err := New("smtp", "", "no-reply@example.com")
+	assert.ErrorContains(t, err, "unknown mail driver")
+
+	mailer, err := New(DriverLog, "", "no-reply@example.com")
+	require.NoError(t, err)
+	assert.NoError(t, mailer.Send(&Message{To: "alice@example.com", Subject: "Hello"}))
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"BlogManagment/internal/audit"
+	"BlogManagment/internal/auth"
+	"BlogManagment/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// maxRequestIDLength bounds the request IDs recorded in the audit log; clients may send their own
+const maxRequestIDLength = 100
+
+// IdentifyRequester is a middleware that stores the requester of every request, for the audit
+// log, in the user context: the principal, the client IP and the X-Request-ID set by the
+// requestid middleware. It must run after Authenticate and requestid.
+func IdentifyRequester() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// The requester is written to the database, so it must not share Fiber's buffers
+		requester := &models.Requester{
+			IP:        utils.CopyString(c.IP()),
+			RequestID: utils.CopyString(c.GetRespHeader(fiber.HeaderXRequestID)),
+		}
+		if len(requester.RequestID) > maxRequestIDLength {
+			requester.RequestID = requester.RequestID[:maxRequestIDLength]
+		}
+		if principal := auth.PrincipalFromCtx(c); principal != nil {
+			requester.Kind = principal.Kind
+			requester.Subject = principal.Subject
+		}
+
+		c.SetUserContext(audit.NewContext(c.UserContext(), requester))
+		return c.Next()
+	}
+}
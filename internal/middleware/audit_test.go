@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"BlogManagment/internal/audit"
+	"BlogManagment/internal/auth"
+	"BlogManagment/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentifyRequester(t *testing.T) {
+	bearer := newMockVerifier("token", &auth.Principal{Subject: "alice", Kind: auth.KindUser})
+
+	var requester *models.Requester
+	app := fiber.New()
+	app.Use(requestid.New(), Authenticate(bearer, nil), IdentifyRequester())
+	app.Get("/", func(c *fiber.Ctx) error {
+		requester = audit.FromContext(c.UserContext())
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer token")
+	req.Header.Set(fiber.HeaderXRequestID, "req-1")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.NotNil(t, requester)
+	assert.Equal(t, &models.Requester{Kind: auth.KindUser, Subject: "alice", IP: "0.0.0.0", RequestID: "req-1"}, requester)
+
+	req = httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderXRequestID, strings.Repeat("a", 200))
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Empty(t, requester.Subject, "anonymous requests have no principal")
+	assert.Len(t, requester.RequestID, maxRequestIDLength, "long request IDs are truncated")
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	assert.NotEmpty(t, requester.RequestID, "a request ID is generated when none is sent")
+	assert.Equal(t, requester.RequestID, resp.Header.Get(fiber.HeaderXRequestID))
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"BlogManagment/internal/auth"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Authenticate is a middleware that resolves the request principal from a bearer token or an
+// API key, sent as "Authorization: Bearer <token>" or "Authorization: ApiKey <key>". A nil
+// verifier leaves its scheme disabled. Requests without credentials continue anonymously;
+// invalid credentials are rejected.
+func Authenticate(bearer, apiKeys auth.Verifier) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, err := auth.Authenticate(c.Get(fiber.HeaderAuthorization), bearer, apiKeys)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Unauthorized",
+				"message": err.Error(),
+			})
+		}
+		if principal != nil {
+			auth.SetPrincipal(c, principal)
+		}
+		return c.Next()
+	}
+}
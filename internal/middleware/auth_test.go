@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"BlogManagment/internal/auth"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockVerifier is a mock implementation of Verifier
+type MockVerifier struct {
+	mock.Mock
+}
+
+func (m *MockVerifier) Verify(credentials string) (*auth.Principal, error) {
+	args := m.Called(credentials)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth.Principal), args.Error(1)
+}
+
+// newMockVerifier creates a mock that accepts a single credential
+func newMockVerifier(credentials string, principal *auth.Principal) *MockVerifier {
+	m := &MockVerifier{}
+	m.On("Verify", credentials).Return(principal, nil).Maybe()
+	m.On("Verify", mock.Anything).Return(nil, errors.New("invalid credentials")).Maybe()
+	return m
+}
+
+func TestAuthenticate(t *testing.T) {
+	bearer := newMockVerifier("token", &auth.Principal{Subject: "alice", Kind: auth.KindUser})
+	apiKeys := newMockVerifier("bk_key", &auth.Principal{Subject: "ci", Kind: auth.KindAPIKey})
+
+	tests := []struct {
+		name          string
+		apiKeys       auth.Verifier
+		authorization string
+		wantStatus    int
+		wantPrincipal string
+	}{
+		{name: "anonymous", apiKeys: apiKeys, wantStatus: fiber.StatusOK},
+		{name: "bearer token", apiKeys: apiKeys, authorization: "Bearer token", wantStatus: fiber.StatusOK, wantPrincipal: "user:alice"},
+		{name: "api key", apiKeys: apiKeys, authorization: "ApiKey bk_key", wantStatus: fiber.StatusOK, wantPrincipal: "api_key:ci"},
+		{name: "scheme is case-insensitive", apiKeys: apiKeys, authorization: "apikey bk_key", wantStatus: fiber.StatusOK, wantPrincipal: "api_key:ci"},
+		{name: "invalid api key", apiKeys: apiKeys, authorization: "ApiKey bk_other", wantStatus: fiber.StatusUnauthorized},
+		{name: "disabled scheme", authorization: "ApiKey bk_key", wantStatus: fiber.StatusOK},
+		{name: "unknown scheme", apiKeys: apiKeys, authorization: "Basic dXNlcjpwYXNz", wantStatus: fiber.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(Authenticate(bearer, tt.apiKeys))
+			app.Get("/", func(c *fiber.Ctx) error {
+				if principal := auth.PrincipalFromCtx(c); principal != nil {
+					return c.SendString(principal.ID())
+				}
+				return c.SendString("")
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantStatus == fiber.StatusOK {
+				body, _ := io.ReadAll(resp.Body)
+				assert.Equal(t, tt.wantPrincipal, string(body))
+			}
+		})
+	}
+}
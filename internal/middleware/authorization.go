@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"log"
+	"slices"
+	"time"
+
+	"BlogManagment/internal/auth"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/repository"
+	"BlogManagment/internal/tenant"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// Authorizer resolves the permissions of each caller from its roles in the tenant of the
+// request and guards routes with the permission they need. Refused requests of authenticated
+// callers are recorded in the access denial log.
+type Authorizer struct {
+	roles repository.RoleRepository
+	// enforce restricts authenticated callers to their roles; otherwise they are editors as well
+	enforce bool
+	// twoFactorRoles are only held by users who signed in with a second factor
+	twoFactorRoles map[string]bool
+}
+
+// NewAuthorizer creates an authorizer reading role assignments from roles. Unless enforce is
+// set, every authenticated caller also holds the editor role, so it may change every post as
+// before roles were introduced; administration needs the admin role either way, and anonymous
+// callers may only read. Users only hold the twoFactorRoles assigned to them when they signed
+// in with a second factor.
+func NewAuthorizer(roles repository.RoleRepository, enforce bool, twoFactorRoles ...string) *Authorizer {
+	a := &Authorizer{roles: roles, enforce: enforce, twoFactorRoles: make(map[string]bool, len(twoFactorRoles))}
+	for _, role := range twoFactorRoles {
+		a.twoFactorRoles[role] = true
+	}
+	return a
+}
+
+// Load is a middleware that stores the actor of the request, with the permissions of its roles,
+// in the user context. It must run after Authenticate and ResolveTenant.
+func (a *Authorizer) Load() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Fiber reuses the strings of a request once it is done, so keep copies for the denial log
+		actor, err := a.Actor(tenant.FromCtx(c), auth.PrincipalFromCtx(c), utils.CopyString(c.Method()), utils.CopyString(c.Path()))
+		if err != nil {
+			return err
+		}
+
+		c.SetUserContext(rbac.NewContext(c.UserContext(), actor))
+		return c.Next()
+	}
+}
+
+// Actor returns the actor of a principal in a tenant, with the permissions of its roles there.
+// API keys hold the permissions of their scopes instead, and a nil principal is anonymous.
+// Refusals are recorded in the access denial log under method and path.
+func (a *Authorizer) Actor(tenantID string, principal *auth.Principal, method, path string) (*rbac.Actor, error) {
+	subject := ""
+	if principal != nil {
+		subject = principal.Subject
+	}
+
+	var onDenied rbac.DenialFunc
+	if subject != "" {
+		onDenied = func(permissions []rbac.Permission) {
+			a.recordDenial(tenantID, subject, method, path, permissions)
+		}
+	}
+
+	if principal != nil && principal.Kind == auth.KindAPIKey {
+		permissions := make([]rbac.Permission, len(principal.Scopes))
+		for i, scope := range principal.Scopes {
+			permissions[i] = rbac.Permission(scope)
+		}
+		return rbac.NewScopedActor(subject, permissions, onDenied), nil
+	}
+
+	var roles []string
+	if subject != "" {
+		var err error
+		if roles, err = a.roles.GetRoles(tenantID, subject); err != nil {
+			return nil, err
+		}
+		if !a.enforce && !slices.Contains(roles, rbac.RoleEditor) {
+			roles = append(roles, rbac.RoleEditor)
+		}
+		if !principal.TwoFactor {
+			roles = a.withoutTwoFactorRoles(roles)
+		}
+	}
+	return rbac.NewActor(subject, roles, onDenied), nil
+}
+
+// withoutTwoFactorRoles drops the roles that need a second factor
+func (a *Authorizer) withoutTwoFactorRoles(roles []string) []string {
+	kept := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if !a.twoFactorRoles[role] {
+			kept = append(kept, role)
+		}
+	}
+	return kept
+}
+
+// Require returns a guard that lets a request through if its actor holds at least one of the
+// permissions. Anonymous callers are refused with 401, authenticated ones with 403.
+func (a *Authorizer) Require(permissions ...rbac.Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor := rbac.FromContext(c.UserContext())
+		if err := actor.Check(permissions...); err != nil {
+			return denied(c, actor, err)
+		}
+		return c.Next()
+	}
+}
+
+// denied responds to a request that was refused because its actor lacks a permission
+func denied(c *fiber.Ctx, actor *rbac.Actor, err error) error {
+	if actor.Subject == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error":   "Unauthorized",
+			"message": "Authentication required: " + err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"error":   "Forbidden",
+		"message": err.Error(),
+	})
+}
+
+// recordDenial adds a refused request to the access denial log
+func (a *Authorizer) recordDenial(tenantID, subject, method, path string, permissions []rbac.Permission) {
+	denial := &models.AccessDenial{
+		TenantID:   tenantID,
+		Subject:    subject,
+		Permission: rbac.Join(permissions, " "),
+		Method:     method,
+		Path:       path,
+		CreatedAt:  time.Now(),
+	}
+	if err := a.roles.RecordDenial(denial); err != nil {
+		log.Printf("Failed to record access denial: %v", err)
+	}
+}
@@ -0,0 +1,222 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"BlogManagment/internal/auth"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/rbac"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRoleRepository is a mock implementation of RoleRepository
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) Assign(assignment *models.RoleAssignment) error {
+	args := m.Called(assignment)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) Revoke(tenantID, subject, role string) error {
+	args := m.Called(tenantID, subject, role)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) GetRoles(tenantID, subject string) ([]string, error) {
+	args := m.Called(tenantID, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockRoleRepository) List(tenantID, subject string) ([]models.RoleAssignment, error) {
+	args := m.Called(tenantID, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RoleAssignment), args.Error(1)
+}
+
+func (m *MockRoleRepository) RecordDenial(denial *models.AccessDenial) error {
+	args := m.Called(denial)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) ListDenials(tenantID, subject string, limit int) ([]models.AccessDenial, error) {
+	args := m.Called(tenantID, subject, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AccessDenial), args.Error(1)
+}
+
+// newMockRoleRepository creates a mock that holds the roles of each subject and accepts denials
+func newMockRoleRepository(roles map[string][]string) *MockRoleRepository {
+	m := &MockRoleRepository{}
+	for subject, held := range roles {
+		m.On("GetRoles", mock.Anything, subject).Return(held, nil).Maybe()
+	}
+	m.On("GetRoles", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	m.On("RecordDenial", mock.Anything).Return(nil).Maybe()
+	return m
+}
+
+func newAuthorizationApp(authorizer *Authorizer, subject string) *fiber.App {
+	app := fiber.New()
+	if subject != "" {
+		app.Use(func(c *fiber.Ctx) error {
+			auth.SetPrincipal(c, &auth.Principal{Subject: subject, Kind: auth.KindUser})
+			return c.Next()
+		})
+	}
+	app.Use(authorizer.Load())
+	app.Delete("/posts/:id", authorizer.Require(rbac.PostDeleteOwn, rbac.PostDeleteAny), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	return app
+}
+
+func TestAuthorizer_Require(t *testing.T) {
+	roles := newMockRoleRepository(map[string][]string{"alice": {rbac.RoleEditor}, "bob": {rbac.RoleReviewer}})
+
+	tests := []struct {
+		name       string
+		subject    string
+		wantStatus int
+	}{
+		{name: "permitted", subject: "alice", wantStatus: fiber.StatusNoContent},
+		{name: "missing permission", subject: "bob", wantStatus: fiber.StatusForbidden},
+		{name: "no roles", subject: "carol", wantStatus: fiber.StatusForbidden},
+		{name: "anonymous", wantStatus: fiber.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := newAuthorizationApp(NewAuthorizer(roles, true), tt.subject).Test(httptest.NewRequest("DELETE", "/posts/1", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestAuthorizer_RecordsDenials(t *testing.T) {
+	roles := newMockRoleRepository(map[string][]string{"bob": {rbac.RoleReviewer}})
+	authorizer := NewAuthorizer(roles, true)
+
+	_, err := newAuthorizationApp(authorizer, "bob").Test(httptest.NewRequest("DELETE", "/posts/1", nil))
+	require.NoError(t, err)
+	_, err = newAuthorizationApp(authorizer, "").Test(httptest.NewRequest("DELETE", "/posts/2", nil))
+	require.NoError(t, err)
+
+	roles.AssertNumberOfCalls(t, "RecordDenial", 1)
+	var denial *models.AccessDenial
+	for _, call := range roles.Calls {
+		if call.Method == "RecordDenial" {
+			denial = call.Arguments.Get(0).(*models.AccessDenial)
+		}
+	}
+	require.NotNil(t, denial, "anonymous denials are not recorded")
+	assert.Equal(t, models.DefaultTenantID, denial.TenantID)
+	assert.Equal(t, "bob", denial.Subject)
+	assert.Equal(t, "post:delete:own post:delete:any", denial.Permission)
+	assert.Equal(t, "DELETE", denial.Method)
+	assert.Equal(t, "/posts/1", denial.Path)
+}
+
+func TestAuthorizer_TwoFactorRoles(t *testing.T) {
+	roles := newMockRoleRepository(map[string][]string{"alice": {rbac.RoleAuthor, rbac.RoleAdmin}})
+	authorizer := NewAuthorizer(roles, true, rbac.RoleAdmin)
+
+	for _, twoFactor := range []bool{false, true} {
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			auth.SetPrincipal(c, &auth.Principal{Subject: "alice", Kind: auth.KindUser, TwoFactor: twoFactor})
+			return c.Next()
+		})
+		app.Use(authorizer.Load())
+		app.Post("/posts", authorizer.Require(rbac.PostCreate), func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusCreated)
+		})
+		app.Delete("/posts/:id", authorizer.Require(rbac.PostDeleteAny), func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusNoContent)
+		})
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/posts", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode, "other roles are held either way")
+
+		resp, err = app.Test(httptest.NewRequest("DELETE", "/posts/1", nil))
+		require.NoError(t, err)
+		if twoFactor {
+			assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+		} else {
+			assert.Equal(t, fiber.StatusForbidden, resp.StatusCode, "admin needs a second factor")
+		}
+	}
+}
+
+func TestAuthorizer_APIKeyScopes(t *testing.T) {
+	roles := newMockRoleRepository(map[string][]string{"ci": {rbac.RoleAdmin}})
+
+	for name, authorizer := range map[string]*Authorizer{"enabled": NewAuthorizer(roles, true), "disabled": NewAuthorizer(roles, false)} {
+		t.Run(name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				auth.SetPrincipal(c, &auth.Principal{Subject: "ci", Kind: auth.KindAPIKey, Scopes: []string{"post:create"}})
+				return c.Next()
+			})
+			app.Use(authorizer.Load())
+			app.Post("/posts", authorizer.Require(rbac.PostCreate), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusCreated)
+			})
+			app.Delete("/posts/:id", authorizer.Require(rbac.PostDeleteAny), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusNoContent)
+			})
+
+			resp, err := app.Test(httptest.NewRequest("POST", "/posts", nil))
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+			resp, err = app.Test(httptest.NewRequest("DELETE", "/posts/1", nil))
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusForbidden, resp.StatusCode, "keys are limited to their scopes, not the roles of their subject")
+		})
+	}
+}
+
+func TestAuthorizer_NotEnforced(t *testing.T) {
+	roles := newMockRoleRepository(map[string][]string{"alice": {rbac.RoleAdmin}})
+	authorizer := NewAuthorizer(roles, false)
+
+	tests := []struct {
+		name       string
+		subject    string
+		path       string
+		wantStatus int
+	}{
+		{name: "posts of authenticated callers", subject: "carol", path: "/posts/1", wantStatus: fiber.StatusNoContent},
+		{name: "posts of anonymous callers", path: "/posts/1", wantStatus: fiber.StatusUnauthorized},
+		{name: "administration of admins", subject: "alice", path: "/roles/editor", wantStatus: fiber.StatusNoContent},
+		{name: "administration of other callers", subject: "carol", path: "/roles/editor", wantStatus: fiber.StatusForbidden},
+		{name: "administration of anonymous callers", path: "/roles/editor", wantStatus: fiber.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newAuthorizationApp(authorizer, tt.subject)
+			app.Delete("/roles/:role", authorizer.Require(rbac.RoleManage), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusNoContent)
+			})
+
+			resp, err := app.Test(httptest.NewRequest("DELETE", tt.path, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CacheControl is a middleware that lets clients reuse successful responses for maxAge.
+// Responses depend on the tenant and the caller, so they are never stored by shared caches;
+// with a maxAge of zero clients must check back before every reuse.
+func CacheControl(maxAge time.Duration) fiber.Handler {
+	value := "private, no-cache"
+	if seconds := int(maxAge / time.Second); seconds > 0 {
+		value = "private, max-age=" + strconv.Itoa(seconds)
+	}
+
+	return func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+		if c.Response().StatusCode() == fiber.StatusOK {
+			c.Set(fiber.HeaderCacheControl, value)
+		} else {
+			c.Set(fiber.HeaderCacheControl, "no-store")
+		}
+		return nil
+	}
+}
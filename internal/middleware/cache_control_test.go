@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCacheControlApp(maxAge time.Duration) *fiber.App {
+	app := fiber.New()
+	app.Use(CacheControl(maxAge))
+	app.Get("/ok", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+	app.Get("/missing", func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusNotFound).SendString("missing")
+	})
+	return app
+}
+
+func TestCacheControl(t *testing.T) {
+	tests := []struct {
+		name   string
+		maxAge time.Duration
+		path   string
+		want   string
+	}{
+		{name: "max age", maxAge: 30 * time.Second, path: "/ok", want: "private, max-age=30"},
+		{name: "no max age", path: "/ok", want: "private, no-cache"},
+		{name: "error", maxAge: 30 * time.Second, path: "/missing", want: "no-store"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := newCacheControlApp(tt.maxAge).Test(httptest.NewRequest("GET", tt.path, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, resp.Header.Get(fiber.HeaderCacheControl))
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"BlogManagment/internal/auth"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"BlogManagment/internal/tenant"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HeaderIdempotencyKey is the request header carrying the client supplied idempotency key
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// maxIdempotencyKeyLength bounds the size of client supplied keys
+const maxIdempotencyKeyLength = 255
+
+// maxIdempotencyReserveAttempts bounds how often a key released by its owner between our attempt
+// to reserve it and our attempt to read it is reserved again
+const maxIdempotencyReserveAttempts = 3
+
+// Idempotency is a middleware that makes POST requests carrying an Idempotency-Key header safe to retry.
+// The first response for a key is stored for ttl and replayed for identical retries; reusing a key
+// with a different request is rejected with 422.
+func Idempotency(repo repository.IdempotencyRepository, ttl time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(HeaderIdempotencyKey)
+		if key == "" || c.Method() != fiber.MethodPost {
+			return c.Next()
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Invalid idempotency key",
+				"message": "Idempotency-Key must be at most 255 characters",
+			})
+		}
+
+		scope := idempotencyScope(c)
+		record := &models.IdempotencyRecord{
+			Scope:       scope,
+			Key:         key,
+			RequestHash: hashRequest(c),
+			ExpiresAt:   time.Now().Add(ttl),
+		}
+
+		existing, err := reserveIdempotencyKey(repo, record)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return replayIdempotentResponse(c, existing, record.RequestHash)
+		}
+
+		if err := c.Next(); err != nil {
+			releaseIdempotencyKey(repo, scope, key)
+			return err
+		}
+
+		// Server errors are not stored so that the client can retry them
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			releaseIdempotencyKey(repo, scope, key)
+			return nil
+		}
+
+		record.StatusCode = status
+		record.ContentType = string(c.Response().Header.ContentType())
+		record.ResponseBody = append([]byte(nil), c.Response().Body()...)
+		if err := repo.Complete(record); err != nil {
+			log.Printf("Failed to store idempotent response for key %q: %v", key, err)
+		}
+
+		return nil
+	}
+}
+
+// reserveIdempotencyKey reserves the key of a record, or returns the record already stored for it.
+// A request holding the key may release it after a server error just before the record is read,
+// in which case the key is free to be reserved again.
+func reserveIdempotencyKey(repo repository.IdempotencyRepository, record *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	for attempt := 1; ; attempt++ {
+		reserved, err := repo.Reserve(record)
+		if err != nil || reserved {
+			return nil, err
+		}
+
+		existing, err := repo.Get(record.Scope, record.Key)
+		if errors.Is(err, repository.ErrIdempotencyKeyNotFound) && attempt < maxIdempotencyReserveAttempts {
+			continue
+		}
+		return existing, err
+	}
+}
+
+// replayIdempotentResponse answers a retried request from the stored record
+func replayIdempotentResponse(c *fiber.Ctx, record *models.IdempotencyRecord, requestHash string) error {
+	if record.RequestHash != requestHash {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error":   "Idempotency key reused",
+			"message": "Idempotency-Key was already used with a different request",
+		})
+	}
+
+	if !record.Completed {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":   "Request in progress",
+			"message": "A request with this Idempotency-Key is still being processed",
+		})
+	}
+
+	c.Set("Idempotent-Replayed", "true")
+	if record.ContentType != "" {
+		c.Set(fiber.HeaderContentType, record.ContentType)
+	}
+	return c.Status(record.StatusCode).Send(record.ResponseBody)
+}
+
+// releaseIdempotencyKey removes a reservation after a failed request
+func releaseIdempotencyKey(repo repository.IdempotencyRepository, scope, key string) {
+	if err := repo.Release(scope, key); err != nil {
+		log.Printf("Failed to release idempotency key %q: %v", key, err)
+	}
+}
+
+// idempotencyScope namespaces keys per tenant and caller so that clients cannot collide with each
+// other. Anonymous callers are told apart by their address.
+func idempotencyScope(c *fiber.Ctx) string {
+	scope := "anonymous:" + c.IP()
+	if principal := auth.PrincipalFromCtx(c); principal != nil {
+		scope = principal.ID()
+	}
+	if tenantID := tenant.FromCtx(c); tenantID != models.DefaultTenantID {
+		scope = tenantID + "/" + scope
+	}
+	return scope
+}
+
+// hashRequest fingerprints the method, path, query string and body of a request
+func hashRequest(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method()))
+	hash.Write([]byte{'\n'})
+	hash.Write([]byte(c.Path()))
+	hash.Write([]byte{'\n'})
+	hash.Write(c.Request().URI().QueryString())
+	hash.Write([]byte{'\n'})
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
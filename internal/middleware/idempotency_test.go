@@ -0,0 +1,216 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestIdempotencyRepository returns an idempotency repository over an empty in-memory SQLite database
+func newTestIdempotencyRepository(t *testing.T) repository.IdempotencyRepository {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+uuid.New().String()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.IdempotencyRecord{}))
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	return repository.NewIdempotencyRepository(db)
+}
+
+// MockIdempotencyRepository is a mock implementation of IdempotencyRepository
+type MockIdempotencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyRepository) Reserve(record *models.IdempotencyRecord) (bool, error) {
+	args := m.Called(record)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) Get(scope, key string) (*models.IdempotencyRecord, error) {
+	args := m.Called(scope, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IdempotencyRecord), args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) Complete(record *models.IdempotencyRecord) error {
+	args := m.Called(record)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) Release(scope, key string) error {
+	args := m.Called(scope, key)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyRepository) DeleteExpired(now time.Time) (int64, error) {
+	args := m.Called(now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// setupIdempotentApp creates a test Fiber app whose POST handler counts invocations
+func setupIdempotentApp(t *testing.T, status int) (*fiber.App, *int) {
+	calls := 0
+	app := fiber.New()
+	app.Use(Idempotency(newTestIdempotencyRepository(t), time.Hour))
+	app.Post("/blog", func(c *fiber.Ctx) error {
+		calls++
+		return c.Status(status).JSON(fiber.Map{"call": calls})
+	})
+	return app, &calls
+}
+
+func postWithKey(app *fiber.App, key, body string) (int, string, string) {
+	req := httptest.NewRequest("POST", "/blog", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(HeaderIdempotencyKey, key)
+	}
+	resp, _ := app.Test(req)
+	respBody, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(respBody), resp.Header.Get("Idempotent-Replayed")
+}
+
+func TestIdempotency_ReplaysStoredResponse(t *testing.T) {
+	app, calls := setupIdempotentApp(t, fiber.StatusCreated)
+
+	status, body, replayed := postWithKey(app, "key-1", `{"title":"a"}`)
+	assert.Equal(t, fiber.StatusCreated, status)
+	assert.Equal(t, `{"call":1}`, body)
+	assert.Empty(t, replayed)
+
+	status, body, replayed = postWithKey(app, "key-1", `{"title":"a"}`)
+	assert.Equal(t, fiber.StatusCreated, status)
+	assert.Equal(t, `{"call":1}`, body)
+	assert.Equal(t, "true", replayed)
+	assert.Equal(t, 1, *calls)
+}
+
+func TestIdempotency_RejectsKeyReuseWithDifferentBody(t *testing.T) {
+	app, calls := setupIdempotentApp(t, fiber.StatusCreated)
+
+	status, _, _ := postWithKey(app, "key-1", `{"title":"a"}`)
+	assert.Equal(t, fiber.StatusCreated, status)
+
+	status, _, _ = postWithKey(app, "key-1", `{"title":"b"}`)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, status)
+	assert.Equal(t, 1, *calls)
+}
+
+func TestIdempotency_WithoutKeyAlwaysExecutes(t *testing.T) {
+	app, calls := setupIdempotentApp(t, fiber.StatusCreated)
+
+	postWithKey(app, "", `{"title":"a"}`)
+	postWithKey(app, "", `{"title":"a"}`)
+	assert.Equal(t, 2, *calls)
+}
+
+func TestIdempotency_ServerErrorsAreNotStored(t *testing.T) {
+	app, calls := setupIdempotentApp(t, fiber.StatusInternalServerError)
+
+	postWithKey(app, "key-1", `{"title":"a"}`)
+	status, _, replayed := postWithKey(app, "key-1", `{"title":"a"}`)
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+	assert.Empty(t, replayed)
+	assert.Equal(t, 2, *calls)
+}
+
+func TestIdempotency_RejectsRequestInProgress(t *testing.T) {
+	repo := newTestIdempotencyRepository(t)
+	app := fiber.New()
+	app.Use(Idempotency(repo, time.Hour))
+	app.Post("/blog", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	// Simulate a concurrent request that reserved the key but has not finished yet
+	hash := sha256.Sum256([]byte("POST\n/blog\n\n"))
+	reserved, err := repo.Reserve(&models.IdempotencyRecord{
+		Scope:       "anonymous:0.0.0.0",
+		Key:         "key-1",
+		RequestHash: hex.EncodeToString(hash[:]),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	status, _, _ := postWithKey(app, "key-1", "")
+	assert.Equal(t, fiber.StatusConflict, status)
+}
+
+func TestIdempotency_ReservesKeyReleasedMeanwhile(t *testing.T) {
+	// The request holding the key fails between the attempts of this one to reserve and to read it
+	repo := &MockIdempotencyRepository{}
+	repo.On("Reserve", mock.Anything).Return(false, nil).Once()
+	repo.On("Get", "anonymous:0.0.0.0", "key-1").Return(nil, repository.ErrIdempotencyKeyNotFound).Once()
+	repo.On("Reserve", mock.Anything).Return(true, nil).Once()
+	repo.On("Complete", mock.Anything).Return(nil)
+	app := fiber.New()
+	app.Use(Idempotency(repo, time.Hour))
+	app.Post("/blog", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	status, _, _ := postWithKey(app, "key-1", `{"title":"a"}`)
+	assert.Equal(t, fiber.StatusCreated, status)
+	repo.AssertExpectations(t)
+}
+
+func TestIdempotency_RejectsKeyReuseWithDifferentQuery(t *testing.T) {
+	app, calls := setupIdempotentApp(t, fiber.StatusCreated)
+
+	post := func(target string) int {
+		req := httptest.NewRequest("POST", target, bytes.NewReader([]byte(`{"title":"a"}`)))
+		req.Header.Set(HeaderIdempotencyKey, "key-1")
+		resp, _ := app.Test(req)
+		return resp.StatusCode
+	}
+	assert.Equal(t, fiber.StatusCreated, post("/blog?publish=true"))
+	assert.Equal(t, fiber.StatusUnprocessableEntity, post("/blog?publish=false"))
+	assert.Equal(t, fiber.StatusCreated, post("/blog?publish=true"))
+	assert.Equal(t, 1, *calls)
+}
+
+func TestIdempotency_ScopesAnonymousCallersByAddress(t *testing.T) {
+	calls := 0
+	app := fiber.New(fiber.Config{ProxyHeader: fiber.HeaderXForwardedFor})
+	app.Use(Idempotency(newTestIdempotencyRepository(t), time.Hour))
+	app.Post("/blog", func(c *fiber.Ctx) error {
+		calls++
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"call": calls})
+	})
+
+	post := func(address string) string {
+		req := httptest.NewRequest("POST", "/blog", bytes.NewReader([]byte(`{"title":"a"}`)))
+		req.Header.Set(HeaderIdempotencyKey, "key-1")
+		req.Header.Set(fiber.HeaderXForwardedFor, address)
+		resp, _ := app.Test(req)
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+	assert.Equal(t, `{"call":1}`, post("192.0.2.1"))
+	assert.Equal(t, `{"call":2}`, post("192.0.2.2"), "another client does not get the response of the first")
+	assert.Equal(t, `{"call":1}`, post("192.0.2.1"))
+}
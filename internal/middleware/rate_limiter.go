@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"BlogManagment/internal/auth"
+	"BlogManagment/internal/config"
+	"BlogManagment/internal/ratelimit"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RateLimiter builds token bucket rate limiting middleware for route groups
+type RateLimiter struct {
+	store   ratelimit.Store
+	config  *config.RateLimitConfig
+	keyFunc func(c *fiber.Ctx) string
+	now     func() time.Time
+}
+
+// NewRateLimiter creates a new rate limiter backed by the given bucket store
+func NewRateLimiter(store ratelimit.Store, cfg *config.RateLimitConfig) *RateLimiter {
+	return &RateLimiter{
+		store:   store,
+		config:  cfg,
+		keyFunc: ClientKey,
+		now:     time.Now,
+	}
+}
+
+// ClientKey identifies the caller by authenticated principal (API key or JWT subject),
+// falling back to the client IP for anonymous requests
+func ClientKey(c *fiber.Ctx) string {
+	if principal := auth.PrincipalFromCtx(c); principal != nil {
+		return principal.ID()
+	}
+	return "ip:" + c.IP()
+}
+
+// For returns a middleware enforcing the read and write budgets of a route group.
+// A nil or disabled limiter lets every request through.
+func (rl *RateLimiter) For(group string) fiber.Handler {
+	if rl == nil || !rl.config.Enabled {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	limits := rl.config.For(group)
+
+	return func(c *fiber.Ctx) error {
+		limit, budget := limits.Write, "write"
+		if isReadMethod(c.Method()) {
+			limit, budget = limits.Read, "read"
+		}
+		if limit.Unlimited() {
+			return c.Next()
+		}
+
+		key := group + ":" + budget + ":" + rl.keyFunc(c)
+		result, err := rl.store.Take(c.Context(), key, limit, rl.now())
+		if err != nil {
+			// Fail open so that a store outage does not take the API down
+			log.Printf("Rate limit store error: %v", err)
+			return c.Next()
+		}
+
+		c.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		c.Set("RateLimit-Policy", strconv.Itoa(limit.Burst)+";w="+strconv.Itoa(ceilSeconds(limit.Period)))
+
+		if !result.Allowed {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error":   "Too many requests",
+				"message": "Rate limit exceeded, retry after " + strconv.Itoa(ceilSeconds(result.RetryAfter)) + " seconds",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// isReadMethod reports whether a request method counts against the read budget
+func isReadMethod(method string) bool {
+	switch method {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// ceilSeconds rounds a duration up to whole seconds
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
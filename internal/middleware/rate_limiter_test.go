@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"BlogManagment/internal/auth"
+	"BlogManagment/internal/config"
+	"BlogManagment/internal/ratelimit"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupRateLimitedApp creates a test Fiber app with a rate limited route group
+func setupRateLimitedApp(limits config.GroupLimits) *fiber.App {
+	cfg := &config.RateLimitConfig{
+		Enabled: true,
+		Groups:  map[string]config.GroupLimits{"blog": limits},
+	}
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(time.Minute), cfg)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		if subject := c.Get("X-Test-Subject"); subject != "" {
+			auth.SetPrincipal(c, &auth.Principal{Subject: subject, Kind: auth.KindUser})
+		}
+		return c.Next()
+	})
+
+	group := app.Group("/blog", limiter.For("blog"))
+	group.Get("/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+	group.Post("/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+
+	return app
+}
+
+func TestRateLimiter_SetsHeadersAndRejects(t *testing.T) {
+	app := setupRateLimitedApp(config.GroupLimits{
+		Read:  ratelimit.Limit{Burst: 2, Period: time.Minute},
+		Write: ratelimit.Limit{Burst: 1, Period: time.Minute},
+	})
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/blog", nil))
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("RateLimit-Limit"))
+	assert.Equal(t, "1", resp.Header.Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", resp.Header.Get("RateLimit-Reset"))
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/blog", nil))
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/blog", nil))
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "0", resp.Header.Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", resp.Header.Get("Retry-After"))
+}
+
+func TestRateLimiter_SeparateReadAndWriteBudgets(t *testing.T) {
+	app := setupRateLimitedApp(config.GroupLimits{
+		Read:  ratelimit.Limit{Burst: 5, Period: time.Minute},
+		Write: ratelimit.Limit{Burst: 1, Period: time.Minute},
+	})
+
+	resp, _ := app.Test(httptest.NewRequest("POST", "/blog", nil))
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest("POST", "/blog", nil))
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/blog", nil))
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "5", resp.Header.Get("RateLimit-Limit"))
+}
+
+func TestRateLimiter_KeysByPrincipal(t *testing.T) {
+	app := setupRateLimitedApp(config.GroupLimits{
+		Read: ratelimit.Limit{Burst: 1, Period: time.Minute},
+	})
+
+	req := httptest.NewRequest("GET", "/blog", nil)
+	req.Header.Set("X-Test-Subject", "alice")
+	resp, _ := app.Test(req)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", "/blog", nil)
+	req.Header.Set("X-Test-Subject", "bob")
+	resp, _ = app.Test(req)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", "/blog", nil)
+	req.Header.Set("X-Test-Subject", "alice")
+	resp, _ = app.Test(req)
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+}
+
+func TestRateLimiter_NilLimiterAllowsAll(t *testing.T) {
+	var limiter *RateLimiter
+
+	app := fiber.New()
+	app.Get("/", limiter.For("blog"), func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("RateLimit-Limit"))
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"errors"
+
+	"BlogManagment/internal/auth"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"BlogManagment/internal/tenant"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// ResolveTenant is a middleware that resolves the tenant of every request from the tenant claim
+// of its credentials, the header or the subdomain, and scopes the request to it.
+// With requireClaim, credentials without a tenant claim are only accepted in the default tenant,
+// for deployments where nothing else ties a caller to its tenants.
+// It must run after Authenticate so that the claim is known.
+func ResolveTenant(resolver *tenant.Resolver, header string, requireClaim bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal := auth.PrincipalFromCtx(c)
+		claim := ""
+		if principal != nil {
+			claim = principal.TenantID
+		}
+
+		// The tenant outlives the request in streams and logs, so it must not share Fiber's buffers
+		tenantID, err := resolver.Resolve(claim, utils.CopyString(c.Get(header)), utils.CopyString(c.Hostname()))
+		switch {
+		case errors.Is(err, repository.ErrTenantNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error":   "Tenant not found",
+				"message": "The requested tenant does not exist",
+			})
+		case errors.Is(err, tenant.ErrTenantMismatch):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error":   "Forbidden",
+				"message": err.Error(),
+			})
+		case err != nil:
+			return err
+		case requireClaim && principal != nil && claim == "" && tenantID != models.DefaultTenantID:
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error":   "Forbidden",
+				"message": "credentials without a tenant claim are only valid for the default tenant",
+			})
+		}
+
+		tenant.Set(c, tenantID)
+		return c.Next()
+	}
+}
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"BlogManagment/internal/auth"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"BlogManagment/internal/tenant"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTenantRepository is a mock implementation of TenantRepository
+type MockTenantRepository struct {
+	mock.Mock
+}
+
+func (m *MockTenantRepository) Create(tenant *models.Tenant) error {
+	args := m.Called(tenant)
+	return args.Error(0)
+}
+
+func (m *MockTenantRepository) GetByID(id string) (*models.Tenant, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Tenant), args.Error(1)
+}
+
+func (m *MockTenantRepository) GetAll() ([]models.Tenant, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Tenant), args.Error(1)
+}
+
+// newMockTenantRepository creates a mock that knows the tenants with the given IDs
+func newMockTenantRepository(ids ...string) *MockTenantRepository {
+	m := &MockTenantRepository{}
+	for _, id := range ids {
+		m.On("GetByID", id).Return(&models.Tenant{ID: id}, nil).Maybe()
+	}
+	m.On("GetByID", mock.Anything).Return(nil, repository.ErrTenantNotFound).Maybe()
+	return m
+}
+
+// newTenantApp responds with the tenant of each request, made by principal unless it is nil
+func newTenantApp(principal *auth.Principal, requireClaim bool) *fiber.App {
+	resolver := tenant.NewResolver(newMockTenantRepository("acme", "globex"), "blog.example.com")
+
+	app := fiber.New()
+	if principal != nil {
+		app.Use(func(c *fiber.Ctx) error {
+			auth.SetPrincipal(c, principal)
+			return c.Next()
+		})
+	}
+	app.Use(ResolveTenant(resolver, "X-Tenant-ID", requireClaim))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(tenant.FromCtx(c))
+	})
+	return app
+}
+
+func TestResolveTenant(t *testing.T) {
+	tests := []struct {
+		name  string
+		claim string
+		// unclaimed authenticates the request with credentials without a tenant claim
+		unclaimed    bool
+		requireClaim bool
+		header       string
+		host         string
+		wantStatus   int
+		wantTenant   string
+	}{
+		{name: "default", wantStatus: fiber.StatusOK, wantTenant: models.DefaultTenantID},
+		{name: "header", header: "acme", wantStatus: fiber.StatusOK, wantTenant: "acme"},
+		{name: "subdomain", host: "globex.blog.example.com", wantStatus: fiber.StatusOK, wantTenant: "globex"},
+		{name: "claim", claim: "acme", wantStatus: fiber.StatusOK, wantTenant: "acme"},
+		{name: "unknown tenant", header: "initech", wantStatus: fiber.StatusNotFound},
+		{name: "token of another tenant", claim: "acme", header: "globex", wantStatus: fiber.StatusForbidden},
+		{name: "token without claim", unclaimed: true, header: "acme", wantStatus: fiber.StatusOK, wantTenant: "acme"},
+		{name: "claim required", unclaimed: true, requireClaim: true, header: "acme", wantStatus: fiber.StatusForbidden},
+		{name: "claim required by subdomain", unclaimed: true, requireClaim: true, host: "globex.blog.example.com", wantStatus: fiber.StatusForbidden},
+		{name: "claim required in default tenant", unclaimed: true, requireClaim: true, wantStatus: fiber.StatusOK, wantTenant: models.DefaultTenantID},
+		{name: "claim required of anonymous callers", requireClaim: true, header: "acme", wantStatus: fiber.StatusOK, wantTenant: "acme"},
+		{name: "claim required and given", claim: "acme", requireClaim: true, header: "acme", wantStatus: fiber.StatusOK, wantTenant: "acme"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+			if tt.header != "" {
+				req.Header.Set("X-Tenant-ID", tt.header)
+			}
+
+			var principal *auth.Principal
+			if tt.claim != "" || tt.unclaimed {
+				principal = &auth.Principal{Subject: "alice", Kind: auth.KindUser, TenantID: tt.claim}
+			}
+			resp, err := newTenantApp(principal, tt.requireClaim).Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantTenant != "" {
+				body, _ := io.ReadAll(resp.Body)
+				assert.Equal(t, tt.wantTenant, string(body))
+			}
+		})
+	}
+}
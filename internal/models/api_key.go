@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// API key states
+const (
+	APIKeyStatusActive  = "active"
+	APIKeyStatusExpired = "expired"
+	APIKeyStatusRevoked = "revoked"
+)
+
+// APIKey authenticates a machine client, such as a CI job, within a tenant. Only a hash of its
+// secret is stored. Scopes hold the permissions the key is limited to.
+type APIKey struct {
+	ID         string    `gorm:"primaryKey;type:varchar(36)"`
+	TenantID   string    `gorm:"type:varchar(63);not null;index"`
+	Name       string    `gorm:"type:varchar(100);not null"`
+	SecretHash string    `gorm:"type:varchar(64);not null"`
+	Scopes     []string  `gorm:"type:jsonb;serializer:json;not null"`
+	CreatedBy  string    `gorm:"type:varchar(255)"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	// ReplacedBy is the ID of the key created when this one was rotated
+	ReplacedBy string    `gorm:"type:varchar(36)"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
+
+// Status returns whether the key is active, expired or revoked at the given time
+func (k *APIKey) Status(now time.Time) string {
+	switch {
+	case k.RevokedAt != nil:
+		return APIKeyStatusRevoked
+	case !now.Before(k.ExpiresAt):
+		return APIKeyStatusExpired
+	default:
+		return APIKeyStatusActive
+	}
+}
+
+// APIKeyCreateRequest represents the request structure for creating an API key. Each scope is a
+// permission, or a role standing for all of its permissions. Keys expire after the default
+// lifetime unless an expiry is given.
+// @Description Request model for creating an API key
+type APIKeyCreateRequest struct {
+	Name      string     `json:"name" validate:"required,max=100" example:"CI deploy"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,required" example:"post:create,post:publish"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2024-01-01T00:00:00Z"`
+}
+
+// APIKeyRotateRequest represents the request structure for rotating an API key. The old key
+// keeps working for the grace period, e.g. "24h", so that clients can switch over.
+// @Description Request model for rotating an API key
+type APIKeyRotateRequest struct {
+	GracePeriod string `json:"grace_period,omitempty" example:"24h"`
+}
+
+// APIKeyResponse represents an API key. The key itself is only included when it was just
+// created or rotated.
+// @Description API key
+type APIKeyResponse struct {
+	ID         string     `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name       string     `json:"name" example:"CI deploy"`
+	Scopes     []string   `json:"scopes" example:"post:create,post:publish"`
+	Status     string     `json:"status" example:"active"`
+	Key        string     `json:"key,omitempty" example:"bk_550e8400e29b41d4a716446655440000_3f2a..."`
+	CreatedBy  string     `json:"created_by,omitempty" example:"alice"`
+	ExpiresAt  time.Time  `json:"expires_at" example:"2024-01-01T00:00:00Z"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" example:"2023-06-01T12:00:00Z"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" example:"2023-06-02T12:00:00Z"`
+	ReplacedBy string     `json:"replaced_by,omitempty" example:"9b2d6f3e-1c4a-4f6e-8d1a-2b3c4d5e6f70"`
+	CreatedAt  time.Time  `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// Audited actions
+const (
+	AuditActionPostCreate = "post.create"
+	AuditActionPostUpdate = "post.update"
+	AuditActionPostDelete = "post.delete"
+)
+
+// AuditActions lists every audited action
+var AuditActions = []string{AuditActionPostCreate, AuditActionPostUpdate, AuditActionPostDelete}
+
+// RequesterKindCLI is the kind of requesters making changes from the command line
+const RequesterKindCLI = "cli"
+
+// Requester identifies who made a request and from where, for the audit log. Kind is the kind
+// of the authenticated principal, or RequesterKindCLI; Kind and Subject are empty for
+// anonymous callers.
+type Requester struct {
+	Kind      string
+	Subject   string
+	IP        string
+	RequestID string
+}
+
+// Session identifies the caller across requests: the authenticated principal, or the address
+// of anonymous callers. It is empty for a nil requester.
+func (r *Requester) Session() string {
+	switch {
+	case r == nil:
+		return ""
+	case r.Subject != "":
+		return r.Kind + ":" + r.Subject
+	default:
+		return "ip:" + r.IP
+	}
+}
+
+// FieldChange is the value of a field before and after a change; nil for fields that did not exist
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// AuditEntry records a change to a post: who made it, from where, and which fields changed.
+// Entries are append-only; the database refuses to update or delete them.
+// @Description Audit log entry
+type AuditEntry struct {
+	ID            int64                  `json:"id" gorm:"primaryKey;autoIncrement" example:"1"`
+	TenantID      string                 `json:"-" gorm:"type:varchar(63);not null;index:idx_audit_entries_tenant,priority:1"`
+	Action        string                 `json:"action" gorm:"type:varchar(50);not null" example:"post.update"`
+	TargetID      string                 `json:"target_id" gorm:"type:varchar(36);not null;index" example:"550e8400-e29b-41d4-a716-446655440000"`
+	PrincipalKind string                 `json:"principal_kind,omitempty" gorm:"type:varchar(20)" example:"user"`
+	Subject       string                 `json:"subject,omitempty" gorm:"type:varchar(255);index" example:"alice"`
+	IP            string                 `json:"ip,omitempty" gorm:"type:varchar(45)" example:"203.0.113.7"`
+	RequestID     string                 `json:"request_id,omitempty" gorm:"type:varchar(100)" example:"3f1c2b9e-8d4a-4e7b-9f61-2a5c7d8e9b10"`
+	Changes       map[string]FieldChange `json:"changes" gorm:"type:jsonb;serializer:json;not null" swaggertype:"object"`
+	CreatedAt     time.Time              `json:"created_at" gorm:"not null;index:idx_audit_entries_tenant,priority:2" example:"2023-01-01T00:00:00Z"`
+}
+
+// AuditFilter selects audit entries. Empty fields match everything.
+type AuditFilter struct {
+	Subject  string
+	Action   string
+	TargetID string
+	Since    *time.Time
+	Until    *time.Time
+	// Before only matches entries with a smaller ID, to page through the log newest first
+	Before int64
+}
+
+// AuditPage is a page of audit entries, newest first. NextBefore continues the listing
+// after the last entry and is zero on the last page.
+type AuditPage struct {
+	Entries    []AuditEntry
+	NextBefore int64
+}
@@ -6,41 +6,129 @@ import (
 	"gorm.io/gorm"
 )
 
+// Publication states of a blog post
+const (
+	BlogStatusDraft     = "draft"
+	BlogStatusPublished = "published"
+)
+
 // Blog represents a blog post in the system
 // @Description Blog post entity with all required fields
 type Blog struct {
 	ID          string         `json:"id" gorm:"primaryKey;type:varchar(36)" example:"550e8400-e29b-41d4-a716-446655440000"`
+	TenantID    string         `json:"-" gorm:"type:varchar(63);not null;default:default;uniqueIndex:idx_blogs_tenant_slug,priority:1,where:deleted_at IS NULL AND slug <> ''"`
+	Slug        string         `json:"slug" gorm:"type:varchar(255);uniqueIndex:idx_blogs_tenant_slug,priority:2" example:"my-first-blog-post"`
 	Title       string         `json:"title" gorm:"type:varchar(255);not null" example:"My First Blog Post"`
 	Description string         `json:"description" gorm:"type:text" example:"This is a brief description of my blog post"`
 	Body        string         `json:"body" gorm:"type:text;not null" example:"This is the main content of my blog post..."`
+	Tags        []BlogTag      `json:"tags" gorm:"foreignKey:BlogID;constraint:OnDelete:CASCADE"`
+	Status      string         `json:"status" gorm:"type:varchar(20);not null;default:published;index" example:"published"`
+	AuthorID    string         `json:"author_id,omitempty" gorm:"type:varchar(255);index" example:"alice"`
+	PublishedAt *time.Time     `json:"published_at" example:"2023-01-01T00:00:00Z"`
 	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
 	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-01T00:00:00Z"`
 	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	Tenant      *Tenant        `json:"-" gorm:"foreignKey:TenantID;constraint:OnDelete:RESTRICT" swaggerignore:"true"`
+}
+
+// BlogTag associates a tag with a blog post
+type BlogTag struct {
+	BlogID   string `json:"-" gorm:"primaryKey;type:varchar(36)"`
+	Name     string `json:"name" gorm:"primaryKey;type:varchar(50);index" example:"golang"`
+	TenantID string `json:"-" gorm:"type:varchar(63);not null;default:default;index"`
+}
+
+// IsPublished reports whether the post is publicly visible
+func (b *Blog) IsPublished() bool {
+	return b.Status == BlogStatusPublished
+}
+
+// TagNames returns the names of the post's tags
+func (b *Blog) TagNames() []string {
+	names := make([]string, len(b.Tags))
+	for i, tag := range b.Tags {
+		names[i] = tag.Name
+	}
+	return names
 }
 
 // BlogCreateRequest represents the request structure for creating a blog post
 // @Description Request model for creating a new blog post
 type BlogCreateRequest struct {
-	Title       string `json:"title" validate:"required,min=1,max=255" example:"My First Blog Post"`
-	Description string `json:"description" validate:"max=1000" example:"This is a brief description of my blog post"`
-	Body        string `json:"body" validate:"required,min=1" example:"This is the main content of my blog post..."`
+	Slug        string   `json:"slug,omitempty" validate:"omitempty,max=255" example:"my-first-blog-post"`
+	Title       string   `json:"title" validate:"required,min=1,max=255" example:"My First Blog Post"`
+	Description string   `json:"description" validate:"max=1000" example:"This is a brief description of my blog post"`
+	Body        string   `json:"body" validate:"required,min=1" example:"This is the main content of my blog post..."`
+	Tags        []string `json:"tags,omitempty" validate:"omitempty,max=20,dive,max=50" example:"golang,fiber"`
+	Status      string   `json:"status,omitempty" validate:"omitempty,oneof=draft published" example:"published"`
+	// AuthorID is the subject of the caller creating the post; it cannot be set by clients
+	AuthorID string `json:"-"`
 }
 
 // BlogUpdateRequest represents the request structure for updating a blog post
 // @Description Request model for updating an existing blog post
 type BlogUpdateRequest struct {
-	Title       *string `json:"title,omitempty" validate:"omitempty,min=1,max=255" example:"Updated Blog Post Title"`
-	Description *string `json:"description,omitempty" validate:"omitempty,max=1000" example:"Updated description"`
-	Body        *string `json:"body,omitempty" validate:"omitempty,min=1" example:"Updated blog post content..."`
+	Slug        *string   `json:"slug,omitempty" validate:"omitempty,min=1,max=255" example:"updated-blog-post-title"`
+	Title       *string   `json:"title,omitempty" validate:"omitempty,min=1,max=255" example:"Updated Blog Post Title"`
+	Description *string   `json:"description,omitempty" validate:"omitempty,max=1000" example:"Updated description"`
+	Body        *string   `json:"body,omitempty" validate:"omitempty,min=1" example:"Updated blog post content..."`
+	Tags        *[]string `json:"tags,omitempty" validate:"omitempty,max=20,dive,max=50" example:"golang,fiber"`
+	Status      *string   `json:"status,omitempty" validate:"omitempty,oneof=draft published" example:"published"`
 }
 
 // BlogResponse represents the response structure for blog posts
 // @Description Response model for blog post data
 type BlogResponse struct {
-	ID          string    `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	Title       string    `json:"title" example:"My First Blog Post"`
-	Description string    `json:"description" example:"This is a brief description of my blog post"`
-	Body        string    `json:"body" example:"This is the main content of my blog post..."`
-	CreatedAt   time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
-	UpdatedAt   time.Time `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+	ID          string     `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Slug        string     `json:"slug" example:"my-first-blog-post"`
+	Title       string     `json:"title" example:"My First Blog Post"`
+	Description string     `json:"description" example:"This is a brief description of my blog post"`
+	Body        string     `json:"body" example:"This is the main content of my blog post..."`
+	Tags        []string   `json:"tags" example:"golang,fiber"`
+	Status      string     `json:"status" example:"published"`
+	AuthorID    string     `json:"author_id,omitempty" example:"alice"`
+	PublishedAt *time.Time `json:"published_at,omitempty" example:"2023-01-01T00:00:00Z"`
+	CreatedAt   time.Time  `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt   time.Time  `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// BlogCursor marks a position in the (created_at, id) ordering of blog posts
+type BlogCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// BlogFilter narrows a listing of blog posts. Empty fields match every post.
+type BlogFilter struct {
+	Status string
+	Tag    string
+	// PublishedOnly leaves out unpublished posts, except those written by DraftsOf when it is set
+	PublishedOnly bool
+	DraftsOf      string
+}
+
+// BlogListQuery selects a page of blog posts, newest first
+type BlogListQuery struct {
+	Status string `json:"status" validate:"omitempty,oneof=draft published"`
+	Tag    string `json:"tag" validate:"max=50"`
+	First  int    `json:"first" validate:"min=0,max=100"`
+	After  string `json:"after"`
+	// PublishedOnly and DraftsOf hide the drafts of other authors; they are set by access control, not by clients
+	PublishedOnly bool   `json:"-"`
+	DraftsOf      string `json:"-"`
+}
+
+// TagCountQuery counts the blog posts carrying each of Names
+type TagCountQuery struct {
+	Names []string
+	// PublishedOnly and DraftsOf hide the drafts of other authors; they are set by access control, not by clients
+	PublishedOnly bool
+	DraftsOf      string
+}
+
+// BlogPage is a page of blog posts. EndCursor continues the listing after the last post.
+type BlogPage struct {
+	Posts       []BlogResponse `json:"posts"`
+	EndCursor   string         `json:"end_cursor,omitempty"`
+	HasNextPage bool           `json:"has_next_page"`
 }
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Bulk operation actions
+const (
+	BulkActionCreate = "create"
+	BulkActionUpdate = "update"
+	BulkActionDelete = "delete"
+)
+
+// Bulk execution modes
+const (
+	BulkModeAtomic  = "atomic"
+	BulkModePartial = "partial"
+)
+
+// BlogBulkRequest represents a batch of create, update and delete operations
+// @Description Request model for bulk blog post operations
+type BlogBulkRequest struct {
+	Mode       string              `json:"mode" validate:"omitempty,oneof=atomic partial" example:"atomic"`
+	Operations []BlogBulkOperation `json:"operations" validate:"required,min=1"`
+}
+
+// BlogBulkOperation represents a single operation of a bulk request.
+// Data is decoded into Create or Update depending on Action.
+// @Description A single create, update or delete operation
+type BlogBulkOperation struct {
+	Action string             `json:"action" validate:"required,oneof=create update delete" example:"update"`
+	ID     string             `json:"id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Data   json.RawMessage    `json:"data,omitempty" swaggertype:"object"`
+	Create *BlogCreateRequest `json:"-"`
+	Update *BlogUpdateRequest `json:"-"`
+}
+
+// UnmarshalJSON decodes the operation and its action specific payload
+func (o *BlogBulkOperation) UnmarshalJSON(data []byte) error {
+	type rawOperation BlogBulkOperation
+	var raw rawOperation
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*o = BlogBulkOperation(raw)
+
+	if len(o.Data) == 0 || string(o.Data) == "null" {
+		return nil
+	}
+
+	switch o.Action {
+	case BulkActionCreate:
+		o.Create = &BlogCreateRequest{}
+		if err := json.Unmarshal(o.Data, o.Create); err != nil {
+			return fmt.Errorf("invalid create data: %w", err)
+		}
+	case BulkActionUpdate:
+		o.Update = &BlogUpdateRequest{}
+		if err := json.Unmarshal(o.Data, o.Update); err != nil {
+			return fmt.Errorf("invalid update data: %w", err)
+		}
+	}
+	return nil
+}
+
+// BlogBulkItemResult represents the outcome of a single bulk operation
+// @Description Result of a single bulk operation
+type BlogBulkItemResult struct {
+	Index  int           `json:"index" example:"0"`
+	Action string        `json:"action" example:"update"`
+	ID     string        `json:"id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Status int           `json:"status" example:"200"`
+	Error  string        `json:"error,omitempty" example:"blog post not found"`
+	Data   *BlogResponse `json:"data,omitempty"`
+}
+
+// BlogBulkResponse represents the response structure for bulk operations
+// @Description Response model for bulk blog post operations
+type BlogBulkResponse struct {
+	Mode      string               `json:"mode" example:"atomic"`
+	Committed bool                 `json:"committed" example:"true"`
+	Succeeded int                  `json:"succeeded" example:"2"`
+	Failed    int                  `json:"failed" example:"0"`
+	Results   []BlogBulkItemResult `json:"results"`
+}
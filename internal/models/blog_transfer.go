@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Transfer formats supported by export and import
+const (
+	TransferFormatJSONL = "jsonl"
+	TransferFormatCSV   = "csv"
+)
+
+// Formats that can only be imported
+const (
+	TransferFormatWXR      = "wxr"
+	TransferFormatMarkdown = "markdown"
+)
+
+// Import actions reported per item
+const (
+	ImportActionCreate = "create"
+	ImportActionUpdate = "update"
+	ImportActionSkip   = "skip"
+	ImportActionError  = "error"
+)
+
+// BlogImportRecord represents a single blog post read from an import file
+type BlogImportRecord struct {
+	ID          string     `json:"id,omitempty" validate:"omitempty,max=36"`
+	Slug        string     `json:"slug,omitempty" validate:"omitempty,max=255"`
+	Title       string     `json:"title" validate:"required,max=255"`
+	Description string     `json:"description,omitempty" validate:"max=1000"`
+	Body        string     `json:"body" validate:"required"`
+	Tags        []string   `json:"tags,omitempty" validate:"omitempty,max=20,dive,max=50"`
+	Status      string     `json:"status,omitempty" validate:"omitempty,oneof=draft published"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// ImportItemResult represents the outcome of importing a single record
+// @Description Result of importing a single record
+type ImportItemResult struct {
+	Line   int    `json:"line,omitempty" example:"3"`
+	Source string `json:"source,omitempty" example:"posts/my-first-blog-post.md"`
+	ID     string `json:"id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Slug   string `json:"slug,omitempty" example:"my-first-blog-post"`
+	Action string `json:"action" example:"create"`
+	Reason string `json:"reason,omitempty" example:"status \"draft\" is not imported"`
+	Error  string `json:"error,omitempty" example:"title is required"`
+}
+
+// ImportReport summarizes an import run
+// @Description Report of an import run
+type ImportReport struct {
+	Format  string             `json:"format" example:"jsonl"`
+	DryRun  bool               `json:"dry_run" example:"false"`
+	Total   int                `json:"total" example:"3"`
+	Created int                `json:"created" example:"2"`
+	Updated int                `json:"updated" example:"1"`
+	Skipped int                `json:"skipped" example:"0"`
+	Failed  int                `json:"failed" example:"0"`
+	Items   []ImportItemResult `json:"items"`
+}
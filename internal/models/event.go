@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Blog post lifecycle event types
+const (
+	EventPostCreated   = "post.created"
+	EventPostUpdated   = "post.updated"
+	EventPostDeleted   = "post.deleted"
+	EventPostPublished = "post.published"
+)
+
+// EventTypes lists every event type that can be subscribed to
+var EventTypes = []string{EventPostCreated, EventPostUpdated, EventPostDeleted, EventPostPublished}
+
+// BlogEvent describes a change to a blog post. Data holds the post after the change;
+// for post.deleted it only carries the post ID.
+// @Description Blog post lifecycle event
+type BlogEvent struct {
+	ID         string        `json:"id" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	TenantID   string        `json:"tenant_id,omitempty" example:"acme"`
+	Type       string        `json:"type" example:"post.created"`
+	OccurredAt time.Time     `json:"occurred_at" example:"2023-01-01T00:00:00Z"`
+	Data       *BlogResponse `json:"data"`
+}
+
+// Tenant returns the tenant the event belongs to. Events recorded before workspaces were
+// introduced carry no tenant and belong to the default one.
+func (e *BlogEvent) Tenant() string {
+	if e.TenantID == "" {
+		return DefaultTenantID
+	}
+	return e.TenantID
+}
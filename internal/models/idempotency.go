@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// IdempotencyRecord stores the outcome of a request made with an Idempotency-Key header
+type IdempotencyRecord struct {
+	Scope        string    `gorm:"primaryKey;type:varchar(255)"`
+	Key          string    `gorm:"primaryKey;type:varchar(255)"`
+	RequestHash  string    `gorm:"type:varchar(64);not null"`
+	Completed    bool      `gorm:"not null;default:false"`
+	StatusCode   int       `gorm:"not null;default:0"`
+	ContentType  string    `gorm:"type:varchar(255)"`
+	ResponseBody []byte    `gorm:"type:bytea"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+	ExpiresAt    time.Time `gorm:"not null;index"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// ReindexReport describes what a reindex repaired
+type ReindexReport struct {
+	// SlugsAssigned is the number of posts without a slug that were given one
+	SlugsAssigned int `json:"slugs_assigned"`
+	// SkippedPosts are the IDs of posts without a slug whose title yields none
+	SkippedPosts []string `json:"skipped_posts,omitempty"`
+	// Tables are the tables whose indexes were rebuilt
+	Tables []string `json:"tables"`
+}
+
+// PurgeReport describes the posts removed from the trash for good
+type PurgeReport struct {
+	Purged        int64     `json:"purged"`
+	DeletedBefore time.Time `json:"deleted_before"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// OutboxEvent is a blog post event stored in the same transaction as the change it describes.
+// Sequence numbers are assigned in commit order, so relay sinks can track their progress with
+// a single position.
+type OutboxEvent struct {
+	Sequence    int64     `json:"sequence" gorm:"primaryKey;autoIncrement"`
+	EventID     string    `json:"event_id" gorm:"type:varchar(36);not null;uniqueIndex"`
+	Type        string    `json:"type" gorm:"type:varchar(50);not null"`
+	AggregateID string    `json:"aggregate_id" gorm:"type:varchar(36);not null"`
+	TenantID    string    `json:"tenant_id" gorm:"type:varchar(63);not null;default:default"`
+	Payload     []byte    `json:"-" gorm:"type:bytea;not null"`
+	CreatedAt   time.Time `json:"created_at" gorm:"not null;index"`
+}
+
+// OutboxCursor is the sequence number of the last event a relay sink has processed
+type OutboxCursor struct {
+	Sink      string    `gorm:"primaryKey;type:varchar(50)"`
+	Position  int64     `gorm:"not null;default:0"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// RateLimitBucket is the persisted state of a token bucket shared between replicas
+type RateLimitBucket struct {
+	Key        string    `gorm:"primaryKey;type:varchar(255)"`
+	Tokens     float64   `gorm:"not null"`
+	RefilledAt time.Time `gorm:"not null;index"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// RoleAssignment grants a role to a subject within a tenant
+// @Description Role held by a user in a tenant
+type RoleAssignment struct {
+	TenantID  string    `json:"-" gorm:"primaryKey;type:varchar(63)"`
+	Subject   string    `json:"subject" gorm:"primaryKey;type:varchar(255)" example:"alice"`
+	Role      string    `json:"role" gorm:"primaryKey;type:varchar(20)" example:"editor"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+}
+
+// RoleAssignmentRequest represents the request structure for assigning a role
+type RoleAssignmentRequest struct {
+	Subject string `json:"subject" validate:"required,max=255" example:"alice"`
+	Role    string `json:"role" validate:"required,oneof=admin editor author reviewer" example:"editor"`
+}
+
+// AccessDenial records a request that was refused because the caller lacked a permission
+// @Description Denied access attempt
+type AccessDenial struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement" example:"1"`
+	TenantID   string    `json:"-" gorm:"type:varchar(63);not null;index:idx_access_denials_tenant,priority:1"`
+	Subject    string    `json:"subject" gorm:"type:varchar(255);not null" example:"alice"`
+	Permission string    `json:"permission" gorm:"type:varchar(255);not null" example:"post:delete:any"`
+	Method     string    `json:"method" gorm:"type:varchar(10);not null" example:"DELETE"`
+	Path       string    `json:"path" gorm:"type:text;not null" example:"/api/blog-post/550e8400-e29b-41d4-a716-446655440000"`
+	CreatedAt  time.Time `json:"created_at" gorm:"not null;index:idx_access_denials_tenant,priority:2" example:"2023-01-01T00:00:00Z"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// DefaultTenantID is the tenant of single-tenant deployments and of every post created
+// before workspaces were introduced
+const DefaultTenantID = "default"
+
+// Tenant is a workspace whose posts are isolated from those of every other tenant.
+// The ID doubles as the subdomain the workspace is served on.
+// @Description Tenant workspace
+type Tenant struct {
+	ID        string    `json:"id" gorm:"primaryKey;type:varchar(63)" example:"acme"`
+	Name      string    `json:"name" gorm:"type:varchar(255);not null" example:"Acme Engineering Blog"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-01T00:00:00Z"`
+}
+
+// TenantCreateRequest represents the request structure for creating a tenant
+type TenantCreateRequest struct {
+	ID   string `json:"id" validate:"required,max=63" example:"acme"`
+	Name string `json:"name" validate:"required,max=255" example:"Acme Engineering Blog"`
+}
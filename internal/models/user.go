@@ -0,0 +1,177 @@
+package models
+
+import "time"
+
+// Reasons for revoking a session
+const (
+	SessionRevokedLogout        = "logout"
+	SessionRevokedByUser        = "revoked"
+	SessionRevokedReuse         = "refresh_token_reuse"
+	SessionRevokedPasswordReset = "password_reset"
+)
+
+// User is a local account of a tenant that signs in with an email address and a password.
+// Only an argon2id hash of the password is stored.
+type User struct {
+	ID           string `gorm:"primaryKey;type:varchar(36)"`
+	TenantID     string `gorm:"type:varchar(63);not null;uniqueIndex:idx_users_tenant_email,priority:1"`
+	Email        string `gorm:"type:varchar(255);not null;uniqueIndex:idx_users_tenant_email,priority:2"`
+	PasswordHash string `gorm:"type:varchar(255);not null"`
+	// FailedLogins counts the failed sign-ins since the last successful one or lockout
+	FailedLogins int `gorm:"not null;default:0"`
+	LockedUntil  *time.Time
+	// TOTPSecret is the secret of the authenticator app of the user. Two-factor authentication
+	// is only required once TOTPEnabledAt is set, when the user has confirmed a first code.
+	TOTPSecret    string `gorm:"type:varchar(64)"`
+	TOTPEnabledAt *time.Time
+	// TOTPLastCounter is the period of the last code accepted, so that each code works once
+	TOTPLastCounter int64     `gorm:"not null;default:0"`
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
+}
+
+// TwoFactorEnabled reports whether the user has to sign in with a second factor
+func (u *User) TwoFactorEnabled() bool {
+	return u.TOTPEnabledAt != nil
+}
+
+// Session is a signed-in device of a user. It lasts as long as its refresh tokens keep being
+// rotated, until it is revoked.
+type Session struct {
+	ID            string    `gorm:"primaryKey;type:varchar(36)"`
+	TenantID      string    `gorm:"type:varchar(63);not null;index:idx_sessions_user,priority:1"`
+	UserID        string    `gorm:"type:varchar(36);not null;index:idx_sessions_user,priority:2"`
+	UserAgent     string    `gorm:"type:varchar(255)"`
+	IP            string    `gorm:"type:varchar(45)"`
+	ExpiresAt     time.Time `gorm:"not null"`
+	LastUsedAt    time.Time `gorm:"not null"`
+	RevokedAt     *time.Time
+	RevokedReason string `gorm:"type:varchar(50)"`
+	// TwoFactor records that the user signed in with a second factor
+	TwoFactor bool      `gorm:"not null;default:false"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// RefreshToken renews the access token of a session. Each token is used once: refreshing
+// rotates it to a new one, and using a rotated token again revokes the session.
+type RefreshToken struct {
+	ID        string    `gorm:"primaryKey;type:varchar(36)"`
+	SessionID string    `gorm:"type:varchar(36);not null;index"`
+	Session   *Session  `gorm:"constraint:OnDelete:CASCADE"`
+	TokenHash string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	RotatedAt *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// PasswordResetToken lets a user who forgot their password choose a new one, once
+type PasswordResetToken struct {
+	ID        string    `gorm:"primaryKey;type:varchar(36)"`
+	TenantID  string    `gorm:"type:varchar(63);not null"`
+	UserID    string    `gorm:"type:varchar(36);not null;index"`
+	TokenHash string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// RecoveryCode lets a user who lost their authenticator app sign in, once. They are issued
+// when two-factor authentication is enabled; only their hashes are stored.
+type RecoveryCode struct {
+	ID        string `gorm:"primaryKey;type:varchar(36)"`
+	TenantID  string `gorm:"type:varchar(63);not null"`
+	UserID    string `gorm:"type:varchar(36);not null;index"`
+	CodeHash  string `gorm:"type:varchar(64);not null;uniqueIndex"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// RegisterRequest represents the request structure for creating an account
+// @Description Request model for creating an account
+type RegisterRequest struct {
+	Email    string `json:"email" validate:"required,email,max=255" example:"alice@example.com"`
+	Password string `json:"password" validate:"required,min=12,max=128" example:"correct horse battery staple"`
+}
+
+// LoginRequest represents the request structure for signing in. Accounts with two-factor
+// authentication also need the code of their authenticator app, or a recovery code.
+// @Description Request model for signing in
+type LoginRequest struct {
+	Email        string `json:"email" validate:"required" example:"alice@example.com"`
+	Password     string `json:"password" validate:"required" example:"correct horse battery staple"`
+	TOTPCode     string `json:"totp_code,omitempty" validate:"omitempty,max=10" example:"123456"`
+	RecoveryCode string `json:"recovery_code,omitempty" validate:"omitempty,max=32" example:"3f9a2-c41e7-08b5d-e6a13"`
+}
+
+// RefreshTokenRequest carries the refresh token of a session, to refresh it or sign out
+// @Description Request model for refreshing a session or signing out
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required" example:"rt_9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+}
+
+// PasswordResetRequest represents the request structure for asking for a password reset email
+// @Description Request model for asking for a password reset
+type PasswordResetRequest struct {
+	Email string `json:"email" validate:"required,email,max=255" example:"alice@example.com"`
+}
+
+// PasswordResetConfirmRequest represents the request structure for choosing a new password
+// with the token of a password reset email
+// @Description Request model for choosing a new password
+type PasswordResetConfirmRequest struct {
+	Token    string `json:"token" validate:"required" example:"pr_2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"`
+	Password string `json:"password" validate:"required,min=12,max=128" example:"another horse battery staple"`
+}
+
+// TwoFactorCodeRequest carries the code of an authenticator app, or a recovery code where
+// accepted, to confirm or disable two-factor authentication
+// @Description Request model for confirming or disabling two-factor authentication
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required,max=32" example:"123456"`
+}
+
+// UserResponse represents an account, without its password
+// @Description Account response model
+type UserResponse struct {
+	ID               string    `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Email            string    `json:"email" example:"alice@example.com"`
+	TwoFactorEnabled bool      `json:"two_factor_enabled" example:"false"`
+	CreatedAt        time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// TOTPEnrollment is the secret of a new authenticator app. The otpauth URI is usually shown as
+// a QR code; the secret can be typed in instead.
+// @Description Authenticator app enrollment
+type TOTPEnrollment struct {
+	Secret string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	URI    string `json:"otpauth_uri" example:"otpauth://totp/Blog%20Management:alice@example.com?algorithm=SHA1&digits=6&issuer=Blog+Management&period=30&secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+}
+
+// RecoveryCodesResponse lists the recovery codes of an account. They are only shown once.
+// @Description Recovery codes
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"3f9a2-c41e7-08b5d-e6a13,9b0c4-11d2e-f7a36-5c8e0"`
+}
+
+// AuthTokens are the tokens of a session: a short-lived access token sent as a bearer token and
+// a refresh token exchanged for new tokens when it expires
+// @Description Tokens of a session
+type AuthTokens struct {
+	AccessToken           string    `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenType             string    `json:"token_type" example:"Bearer"`
+	ExpiresIn             int64     `json:"expires_in" example:"900"`
+	RefreshToken          string    `json:"refresh_token" example:"rt_9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at" example:"2023-01-31T00:00:00Z"`
+	SessionID             string    `json:"session_id" example:"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`
+}
+
+// SessionResponse represents an active session of the signed-in user
+// @Description Session response model
+type SessionResponse struct {
+	ID         string    `json:"id" example:"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`
+	UserAgent  string    `json:"user_agent,omitempty" example:"Mozilla/5.0"`
+	IP         string    `json:"ip,omitempty" example:"203.0.113.7"`
+	CreatedAt  time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	LastUsedAt time.Time `json:"last_used_at" example:"2023-01-02T00:00:00Z"`
+	ExpiresAt  time.Time `json:"expires_at" example:"2023-01-31T00:00:00Z"`
+}
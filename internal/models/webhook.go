@@ -0,0 +1,88 @@
+package models
+
+import "time"
+
+// Webhook delivery states
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSucceeded = "succeeded"
+	DeliveryStatusDead      = "dead"
+)
+
+// Webhook is a subscription that receives the blog post events of its tenant over HTTP
+// @Description Webhook subscription
+type Webhook struct {
+	ID          string    `json:"id" gorm:"primaryKey;type:varchar(36)" example:"550e8400-e29b-41d4-a716-446655440000"`
+	TenantID    string    `json:"-" gorm:"type:varchar(63);not null;default:default;index"`
+	URL         string    `json:"url" gorm:"type:text;not null" example:"https://search.example.com/hooks/blog"`
+	Description string    `json:"description" gorm:"type:text" example:"Search indexer"`
+	Events      []string  `json:"events" gorm:"type:jsonb;serializer:json;not null" example:"post.created,post.updated"`
+	Secret      string    `json:"-" gorm:"type:varchar(100);not null"`
+	Active      bool      `json:"active" gorm:"not null;default:true" example:"true"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-01T00:00:00Z"`
+}
+
+// Subscribes reports whether the webhook receives events of the given type
+func (w *Webhook) Subscribes(eventType string) bool {
+	for _, event := range w.Events {
+		if event == eventType || event == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is a queued event for a single webhook together with the outcome of its
+// latest attempt. Deliveries that exhaust their attempts become dead letters.
+// @Description Delivery of an event to a webhook
+type WebhookDelivery struct {
+	ID             string     `json:"id" gorm:"primaryKey;type:varchar(36)" example:"9b2d6f3e-1c4a-4f6e-8d1a-2b3c4d5e6f70"`
+	TenantID       string     `json:"-" gorm:"type:varchar(63);not null;default:default;index"`
+	WebhookID      string     `json:"webhook_id" gorm:"type:varchar(36);not null;uniqueIndex:idx_webhook_deliveries_event,priority:1" example:"550e8400-e29b-41d4-a716-446655440000"`
+	EventID        string     `json:"event_id" gorm:"type:varchar(36);not null;uniqueIndex:idx_webhook_deliveries_event,priority:2" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	EventType      string     `json:"event_type" gorm:"type:varchar(50);not null" example:"post.created"`
+	Payload        []byte     `json:"-" gorm:"type:bytea;not null"`
+	Status         string     `json:"status" gorm:"type:varchar(20);not null;index:idx_webhook_deliveries_due,priority:1" example:"pending"`
+	Attempts       int        `json:"attempts" gorm:"not null;default:0" example:"1"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" gorm:"not null;index:idx_webhook_deliveries_due,priority:2" example:"2023-01-01T00:00:30Z"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty" example:"2023-01-01T00:00:00Z"`
+	ResponseStatus int        `json:"response_status,omitempty" example:"503"`
+	LastError      string     `json:"last_error,omitempty" gorm:"type:text" example:"unexpected status 503"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"autoCreateTime" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"autoUpdateTime" example:"2023-01-01T00:00:00Z"`
+}
+
+// WebhookCreateRequest represents the request structure for creating a webhook
+// @Description Request model for creating a webhook
+type WebhookCreateRequest struct {
+	URL         string   `json:"url" validate:"required,url,max=2000" example:"https://search.example.com/hooks/blog"`
+	Description string   `json:"description" validate:"max=255" example:"Search indexer"`
+	Events      []string `json:"events" validate:"required,min=1,dive,oneof=* post.created post.updated post.deleted post.published" example:"post.created,post.updated"`
+	Secret      string   `json:"secret,omitempty" validate:"omitempty,min=16,max=100" example:"a-long-random-shared-secret"`
+	Active      *bool    `json:"active,omitempty" example:"true"`
+}
+
+// WebhookUpdateRequest represents the request structure for updating a webhook
+// @Description Request model for updating a webhook
+type WebhookUpdateRequest struct {
+	URL         *string   `json:"url,omitempty" validate:"omitempty,url,max=2000" example:"https://search.example.com/hooks/blog"`
+	Description *string   `json:"description,omitempty" validate:"omitempty,max=255" example:"Search indexer"`
+	Events      *[]string `json:"events,omitempty" validate:"omitempty,min=1,dive,oneof=* post.created post.updated post.deleted post.published" example:"post.created"`
+	Secret      *string   `json:"secret,omitempty" validate:"omitempty,min=16,max=100" example:"a-new-long-random-secret"`
+	Active      *bool     `json:"active,omitempty" example:"false"`
+}
+
+// WebhookResponse represents a webhook. The secret is only included when it was just
+// created or rotated.
+// @Description Webhook subscription
+type WebhookResponse struct {
+	ID          string    `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	URL         string    `json:"url" example:"https://search.example.com/hooks/blog"`
+	Description string    `json:"description" example:"Search indexer"`
+	Events      []string  `json:"events" example:"post.created,post.updated"`
+	Active      bool      `json:"active" example:"true"`
+	Secret      string    `json:"secret,omitempty" example:"whsec_3f2a..."`
+	CreatedAt   time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+}
@@ -0,0 +1,91 @@
+// Package outbox relays the blog post events recorded in the outbox table to sinks.
+package outbox
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maxBatchesPerRun bounds the work done for a sink in a single run so that a large backlog
+// does not hold its cursor lock indefinitely
+const maxBatchesPerRun = 20
+
+// Sink receives outbox events. Deliver is called with consecutive batches in sequence order;
+// a batch that fails is delivered again, so sinks see every event at least once and must
+// tolerate duplicates, e.g. by the event ID.
+type Sink interface {
+	// Name identifies the cursor of the sink. Renaming a sink replays the retained events.
+	Name() string
+	Deliver(ctx context.Context, events []models.OutboxEvent) error
+}
+
+// Relay delivers outbox events to every sink. Each sink keeps its own cursor, so a failing
+// sink is retried from where it stopped without holding back the others.
+type Relay struct {
+	repo      repository.OutboxRepository
+	sinks     []Sink
+	batchSize int
+}
+
+// NewRelay creates a new relay
+func NewRelay(repo repository.OutboxRepository, batchSize int, sinks ...Sink) *Relay {
+	return &Relay{repo: repo, sinks: sinks, batchSize: batchSize}
+}
+
+// RunOnce delivers pending events to every sink in parallel and returns the errors of the sinks that failed
+func (r *Relay) RunOnce(ctx context.Context) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(r.sinks))
+	for i, sink := range r.sinks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := r.drain(ctx, sink); err != nil {
+				errs[i] = fmt.Errorf("sink %s: %w", sink.Name(), err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// drain delivers batches to a sink until it has caught up. Another relay already working on
+// the sink is not an error.
+func (r *Relay) drain(ctx context.Context, sink Sink) error {
+	for range maxBatchesPerRun {
+		full := false
+		err := r.repo.Advance(sink.Name(), func(position int64) (int64, error) {
+			events, err := r.repo.ListAfter(position, r.batchSize)
+			if err != nil || len(events) == 0 {
+				return position, err
+			}
+			if err := sink.Deliver(ctx, events); err != nil {
+				return position, err
+			}
+			full = len(events) == r.batchSize
+			return events[len(events)-1].Sequence, nil
+		})
+		if errors.Is(err, repository.ErrCursorLocked) {
+			return nil
+		}
+		if err != nil || !full || ctx.Err() != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Prune deletes events older than retention that every sink has processed
+func (r *Relay) Prune(retention time.Duration) (int64, error) {
+	names := make([]string, len(r.sinks))
+	for i, sink := range r.sinks {
+		names[i] = sink.Name()
+	}
+	return r.repo.DeleteProcessed(names, time.Now().Add(-retention))
+}
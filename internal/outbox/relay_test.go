@@ -0,0 +1,217 @@
+package outbox
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"BlogManagment/internal/webhook"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestOutbox opens an in-memory SQLite outbox holding count events and returns a repository over it
+func newTestOutbox(t *testing.T, count int) (*gorm.DB, repository.OutboxRepository) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+uuid.New().String()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.OutboxEvent{}, &models.OutboxCursor{}))
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	for i := 1; i <= count; i++ {
+		payload, err := json.Marshal(&models.BlogEvent{ID: eventID(i), Type: models.EventPostCreated, Data: &models.BlogResponse{ID: "post"}})
+		require.NoError(t, err)
+		require.NoError(t, db.Create(&models.OutboxEvent{
+			EventID:     eventID(i),
+			Type:        models.EventPostCreated,
+			AggregateID: "post",
+			Payload:     payload,
+			CreatedAt:   time.Now(),
+		}).Error)
+	}
+	return db, repository.NewOutboxRepository(db)
+}
+
+func eventID(i int) string {
+	return "event-" + string(rune('a'+i-1))
+}
+
+// cursorOf returns the position stored for a sink
+func cursorOf(t *testing.T, db *gorm.DB, sink string) int64 {
+	t.Helper()
+	var cursor models.OutboxCursor
+	require.NoError(t, db.Where("sink = ?", sink).First(&cursor).Error)
+	return cursor.Position
+}
+
+// MockOutboxRepository is a mock implementation of OutboxRepository
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) ListAfter(position int64, limit int) ([]models.OutboxEvent, error) {
+	args := m.Called(position, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.OutboxEvent), args.Error(1)
+}
+
+func (m *MockOutboxRepository) LastSequence() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockOutboxRepository) Advance(sink string, fn func(position int64) (int64, error)) error {
+	args := m.Called(sink, fn)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) DeleteProcessed(sinks []string, before time.Time) (int64, error) {
+	args := m.Called(sinks, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// recordingSink records delivered event IDs and fails while failures is positive
+type recordingSink struct {
+	name      string
+	failures  int
+	delivered []string
+	batches   int
+}
+
+func (s *recordingSink) Name() string {
+	return s.name
+}
+
+func (s *recordingSink) Deliver(ctx context.Context, events []models.OutboxEvent) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("sink unavailable")
+	}
+	s.batches++
+	for _, event := range events {
+		s.delivered = append(s.delivered, event.EventID)
+	}
+	return nil
+}
+
+func TestRelay_RunOnce_DeliversInOrderInBatches(t *testing.T) {
+	db, repo := newTestOutbox(t, 5)
+	sink := &recordingSink{name: "log"}
+
+	relay := NewRelay(repo, 2, sink)
+	require.NoError(t, relay.RunOnce(context.Background()))
+
+	assert.Equal(t, []string{"event-a", "event-b", "event-c", "event-d", "event-e"}, sink.delivered)
+	assert.Equal(t, 3, sink.batches)
+	assert.Equal(t, int64(5), cursorOf(t, db, "log"))
+
+	// Nothing new to deliver
+	require.NoError(t, relay.RunOnce(context.Background()))
+	assert.Len(t, sink.delivered, 5)
+}
+
+func TestRelay_RunOnce_RetriesFailedSinkIndependently(t *testing.T) {
+	db, repo := newTestOutbox(t, 3)
+	healthy := &recordingSink{name: "log"}
+	failing := &recordingSink{name: "http", failures: 1}
+
+	relay := NewRelay(repo, 10, healthy, failing)
+	err := relay.RunOnce(context.Background())
+
+	assert.EqualError(t, err, "sink http: sink unavailable")
+	assert.Len(t, healthy.delivered, 3)
+	assert.Empty(t, failing.delivered)
+	assert.Equal(t, int64(0), cursorOf(t, db, "http"))
+
+	// The failed batch is delivered again on the next run
+	require.NoError(t, relay.RunOnce(context.Background()))
+	assert.Equal(t, []string{"event-a", "event-b", "event-c"}, failing.delivered)
+	assert.Len(t, healthy.delivered, 3)
+}
+
+func TestRelay_RunOnce_SkipsLockedSink(t *testing.T) {
+	repo := &MockOutboxRepository{}
+	repo.On("Advance", "log", mock.Anything).Return(repository.ErrCursorLocked)
+	sink := &recordingSink{name: "log"}
+
+	relay := NewRelay(repo, 10, sink)
+
+	assert.NoError(t, relay.RunOnce(context.Background()))
+	assert.Empty(t, sink.delivered)
+	repo.AssertNotCalled(t, "ListAfter", mock.Anything, mock.Anything)
+}
+
+func TestHTTPSink_Deliver(t *testing.T) {
+	var body []byte
+	var signature string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(webhook.HeaderSignature)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	_, repo := newTestOutbox(t, 2)
+	outboxEvents, err := repo.ListAfter(0, 10)
+	require.NoError(t, err)
+	sink := NewHTTPSink(server.URL, "secret", time.Second)
+
+	require.NoError(t, sink.Deliver(context.Background(), outboxEvents))
+
+	var events []models.BlogEvent
+	require.NoError(t, json.Unmarshal(body, &events))
+	assert.Len(t, events, 2)
+	assert.Equal(t, "event-a", events[0].ID)
+	assert.NoError(t, webhook.Verify("secret", signature, body, time.Minute, time.Now()))
+
+	status = http.StatusBadGateway
+	assert.EqualError(t, sink.Deliver(context.Background(), outboxEvents), "unexpected status 502")
+}
+
+// publisherFunc adapts a function to Publisher
+type publisherFunc func(event *models.BlogEvent) error
+
+func (f publisherFunc) Publish(event *models.BlogEvent) error {
+	return f(event)
+}
+
+func TestPublisherSink_Deliver(t *testing.T) {
+	_, repo := newTestOutbox(t, 3)
+	outboxEvents, err := repo.ListAfter(0, 10)
+	require.NoError(t, err)
+
+	var published []string
+	sink := NewPublisherSink("webhooks", publisherFunc(func(event *models.BlogEvent) error {
+		if event.ID == "event-c" {
+			return errors.New("queue unavailable")
+		}
+		published = append(published, event.ID)
+		return nil
+	}))
+
+	err = sink.Deliver(context.Background(), outboxEvents)
+
+	assert.EqualError(t, err, "event #3: queue unavailable")
+	assert.Equal(t, "webhooks", sink.Name())
+	assert.Equal(t, []string{"event-a", "event-b"}, published)
+}
@@ -0,0 +1,126 @@
+package outbox
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/webhook"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// LogSink writes a line per event to a logger
+type LogSink struct {
+	logger *log.Logger
+}
+
+// NewLogSink creates a sink that logs to logger, or to the standard logger when it is nil
+func NewLogSink(logger *log.Logger) *LogSink {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &LogSink{logger: logger}
+}
+
+// Name returns the cursor name of the sink
+func (s *LogSink) Name() string {
+	return "log"
+}
+
+// Deliver logs every event
+func (s *LogSink) Deliver(ctx context.Context, events []models.OutboxEvent) error {
+	for _, event := range events {
+		s.logger.Printf("Event #%d %s %s for post %s", event.Sequence, event.EventID, event.Type, event.AggregateID)
+	}
+	return nil
+}
+
+// HTTPSink posts each batch of events as a JSON array to a URL. When a secret is set the
+// body is signed like webhook deliveries, in the X-Webhook-Signature header.
+type HTTPSink struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewHTTPSink creates a sink that posts to url
+func NewHTTPSink(url, secret string, timeout time.Duration) *HTTPSink {
+	return &HTTPSink{url: url, secret: secret, client: &http.Client{Timeout: timeout}}
+}
+
+// Name returns the cursor name of the sink
+func (s *HTTPSink) Name() string {
+	return "http"
+}
+
+// Deliver posts the batch and treats any 2xx response as success
+func (s *HTTPSink) Deliver(ctx context.Context, events []models.OutboxEvent) error {
+	payloads := make([]json.RawMessage, len(events))
+	for i, event := range events {
+		payloads[i] = event.Payload
+	}
+	body, err := json.Marshal(payloads)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if s.secret != "" {
+		request.Header.Set(webhook.HeaderSignature, webhook.Sign(s.secret, time.Now(), body))
+	}
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+	return nil
+}
+
+// Publisher receives events one at a time
+type Publisher interface {
+	Publish(event *models.BlogEvent) error
+}
+
+// PublisherSink hands every event to a Publisher, such as the webhook service
+type PublisherSink struct {
+	name      string
+	publisher Publisher
+}
+
+// NewPublisherSink creates a sink with the given cursor name
+func NewPublisherSink(name string, publisher Publisher) *PublisherSink {
+	return &PublisherSink{name: name, publisher: publisher}
+}
+
+// Name returns the cursor name of the sink
+func (s *PublisherSink) Name() string {
+	return s.name
+}
+
+// Deliver decodes and publishes the events in order, stopping at the first failure
+func (s *PublisherSink) Deliver(ctx context.Context, events []models.OutboxEvent) error {
+	for _, event := range events {
+		var decoded models.BlogEvent
+		if err := json.Unmarshal(event.Payload, &decoded); err != nil {
+			return fmt.Errorf("event #%d: %w", event.Sequence, err)
+		}
+		if err := s.publisher.Publish(&decoded); err != nil {
+			return fmt.Errorf("event #%d: %w", event.Sequence, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit describes a token bucket: Burst tokens refilled evenly over Period
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// Unlimited reports whether the limit disables rate limiting
+func (l Limit) Unlimited() bool {
+	return l.Burst <= 0 || l.Period <= 0
+}
+
+// rate returns the refill rate in tokens per second
+func (l Limit) rate() float64 {
+	return float64(l.Burst) / l.Period.Seconds()
+}
+
+// String formats the limit in the same form accepted by ParseLimit
+func (l Limit) String() string {
+	if l.Unlimited() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", l.Burst, l.Period)
+}
+
+// ParseLimit parses limits such as "100/1m", "10/s" or "off"
+func ParseLimit(value string) (Limit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "off" || value == "0" {
+		return Limit{}, nil
+	}
+
+	countPart, periodPart, found := strings.Cut(value, "/")
+	if !found {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: expected <requests>/<period>", value)
+	}
+
+	burst, err := strconv.Atoi(strings.TrimSpace(countPart))
+	if err != nil || burst < 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: request count must be a non-negative integer", value)
+	}
+
+	periodPart = strings.TrimSpace(periodPart)
+	if periodPart != "" && !strings.ContainsAny(periodPart[:1], "0123456789") {
+		periodPart = "1" + periodPart
+	}
+	period, err := time.ParseDuration(periodPart)
+	if err != nil || period <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: period must be a positive duration", value)
+	}
+
+	return Limit{Burst: burst, Period: period}, nil
+}
+
+// Result is the outcome of taking a token from a bucket
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// Store persists token buckets keyed by client and route group
+type Store interface {
+	// Take consumes a token from the bucket identified by key
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+	// Cleanup drops buckets that have been idle for longer than the store's TTL
+	Cleanup(ctx context.Context, now time.Time) error
+}
+
+// bucket is the persisted state of a single token bucket
+type bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// take refills the bucket for the elapsed time and tries to consume one token
+func (b *bucket) take(limit Limit, now time.Time) Result {
+	rate := limit.rate()
+	capacity := float64(limit.Burst)
+
+	if b.UpdatedAt.IsZero() {
+		b.Tokens = capacity
+	} else if elapsed := now.Sub(b.UpdatedAt).Seconds(); elapsed > 0 {
+		b.Tokens = math.Min(capacity, b.Tokens+elapsed*rate)
+	}
+	b.UpdatedAt = now
+
+	result := Result{Limit: limit.Burst}
+	if b.Tokens >= 1 {
+		b.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.Tokens) / rate)
+	}
+
+	result.Remaining = int(math.Floor(b.Tokens))
+	result.Reset = secondsToDuration((capacity - b.Tokens) / rate)
+	return result
+}
+
+// secondsToDuration converts fractional seconds to a duration
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
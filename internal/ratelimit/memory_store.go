@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps token buckets in process memory.
+// It is suitable for single replica deployments and tests.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	idleTTL time.Duration
+}
+
+// NewMemoryStore creates a new in-memory bucket store.
+// Buckets untouched for longer than idleTTL are dropped by Cleanup.
+func NewMemoryStore(idleTTL time.Duration) *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		idleTTL: idleTTL,
+	}
+}
+
+// Take consumes a token from the bucket identified by key
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{}
+		s.buckets[key] = b
+	}
+	return b.take(limit, now), nil
+}
+
+// Cleanup removes buckets that have been idle for longer than the store's TTL
+func (s *MemoryStore) Cleanup(_ context.Context, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, b := range s.buckets {
+		if now.Sub(b.UpdatedAt) > s.idleTTL {
+			delete(s.buckets, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimit(t *testing.T) {
+	limit, err := ParseLimit("100/1m")
+	assert.NoError(t, err)
+	assert.Equal(t, Limit{Burst: 100, Period: time.Minute}, limit)
+
+	limit, err = ParseLimit("10/s")
+	assert.NoError(t, err)
+	assert.Equal(t, Limit{Burst: 10, Period: time.Second}, limit)
+
+	limit, err = ParseLimit("off")
+	assert.NoError(t, err)
+	assert.True(t, limit.Unlimited())
+
+	_, err = ParseLimit("100")
+	assert.Error(t, err)
+
+	_, err = ParseLimit("abc/1m")
+	assert.Error(t, err)
+
+	_, err = ParseLimit("10/never")
+	assert.Error(t, err)
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, func(t *testing.T, idleTTL time.Duration) Store {
+		return NewMemoryStore(idleTTL)
+	})
+}
+
+func TestMemoryStore_Cleanup(t *testing.T) {
+	store := NewMemoryStore(time.Minute)
+	limit := Limit{Burst: 1, Period: time.Minute}
+	now := time.Now()
+
+	store.Take(context.Background(), "client", limit, now)
+	assert.NoError(t, store.Cleanup(context.Background(), now.Add(2*time.Minute)))
+	assert.Empty(t, store.buckets)
+}
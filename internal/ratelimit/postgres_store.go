@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"BlogManagment/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresStore keeps token buckets in the database so that every replica
+// shares the same budget. Each take locks the bucket row for its transaction.
+type PostgresStore struct {
+	db      *gorm.DB
+	idleTTL time.Duration
+}
+
+// NewPostgresStore creates a new database-backed bucket store.
+// Buckets untouched for longer than idleTTL are dropped by Cleanup.
+func NewPostgresStore(db *gorm.DB, idleTTL time.Duration) *PostgresStore {
+	return &PostgresStore{db: db, idleTTL: idleTTL}
+}
+
+// Take consumes a token from the bucket identified by key
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	var result Result
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		row := models.RateLimitBucket{Key: key, Tokens: float64(limit.Burst), RefilledAt: now}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("key = ?", key).First(&row).Error; err != nil {
+			return err
+		}
+
+		b := bucket{Tokens: row.Tokens, UpdatedAt: row.RefilledAt}
+		result = b.take(limit, now)
+
+		return tx.Model(&row).Updates(map[string]interface{}{
+			"tokens":      b.Tokens,
+			"refilled_at": b.UpdatedAt,
+		}).Error
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return result, nil
+}
+
+// Cleanup removes buckets that have been idle for longer than the store's TTL
+func (s *PostgresStore) Cleanup(ctx context.Context, now time.Time) error {
+	return s.db.WithContext(ctx).
+		Where("refilled_at < ?", now.Add(-s.idleTTL)).
+		Delete(&models.RateLimitBucket{}).Error
+}
@@ -0,0 +1,52 @@
+package ratelimit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"BlogManagment/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestPostgresStore_SQLite runs the database store against SQLite, which shares its SQL
+func TestPostgresStore_SQLite(t *testing.T) {
+	testStore(t, func(t *testing.T, idleTTL time.Duration) Store {
+		return newDatabaseStore(t, sqlite.Open(sqliteDSN(t)), idleTTL)
+	})
+}
+
+// sqliteDSN returns a new database file that serialises write transactions, like the server
+func sqliteDSN(t *testing.T) string {
+	return "file:" + filepath.Join(t.TempDir(), "blog.db") + "?_busy_timeout=5000&_journal_mode=WAL&_txlock=immediate"
+}
+
+// TestPostgresStore runs against the database named by TEST_DATABASE_URL
+func TestPostgresStore(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	testStore(t, func(t *testing.T, idleTTL time.Duration) Store {
+		return newDatabaseStore(t, postgres.Open(dsn), idleTTL)
+	})
+}
+
+// newDatabaseStore migrates the bucket table and returns a store over it
+func newDatabaseStore(t *testing.T, dialector gorm.Dialector, idleTTL time.Duration) Store {
+	t.Helper()
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	require.NoError(t, db.AutoMigrate(&models.RateLimitBucket{}))
+	return NewPostgresStore(db, idleTTL)
+}
@@ -0,0 +1,121 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStoreFunc creates a store whose buckets idle for longer than idleTTL are dropped by Cleanup.
+// Stores may be shared by several tests, so the suite uses keys of its own.
+type newStoreFunc func(t *testing.T, idleTTL time.Duration) Store
+
+// storeTests are the behaviours every Store implementation must share
+var storeTests = []struct {
+	name string
+	run  func(t *testing.T, store Store, key func(name string) string)
+}{
+	{"exhausts burst", func(t *testing.T, store Store, key func(string) string) {
+		limit := Limit{Burst: 3, Period: 3 * time.Second}
+		now := storeTestTime
+
+		for i := 0; i < 3; i++ {
+			result, err := store.Take(context.Background(), key("client"), limit, now)
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+			assert.Equal(t, 3, result.Limit)
+			assert.Equal(t, 2-i, result.Remaining)
+		}
+
+		result, err := store.Take(context.Background(), key("client"), limit, now)
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 0, result.Remaining)
+		assert.Equal(t, time.Second, result.RetryAfter)
+		assert.Equal(t, 3*time.Second, result.Reset)
+	}},
+	{"refills", func(t *testing.T, store Store, key func(string) string) {
+		limit := Limit{Burst: 2, Period: 2 * time.Second}
+		now := storeTestTime
+
+		takeAt(t, store, key("client"), limit, now)
+		takeAt(t, store, key("client"), limit, now)
+		assert.False(t, takeAt(t, store, key("client"), limit, now.Add(500*time.Millisecond)).Allowed)
+		assert.True(t, takeAt(t, store, key("client"), limit, now.Add(time.Second)).Allowed)
+
+		// Refilling never exceeds the burst
+		result := takeAt(t, store, key("client"), limit, now.Add(time.Hour))
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 1, result.Remaining)
+	}},
+	{"separate keys", func(t *testing.T, store Store, key func(string) string) {
+		limit := Limit{Burst: 1, Period: time.Minute}
+		now := storeTestTime
+
+		assert.True(t, takeAt(t, store, key("a"), limit, now).Allowed)
+		assert.True(t, takeAt(t, store, key("b"), limit, now).Allowed)
+		assert.False(t, takeAt(t, store, key("a"), limit, now).Allowed)
+	}},
+	{"cleanup", func(t *testing.T, store Store, key func(string) string) {
+		limit := Limit{Burst: 1, Period: time.Minute}
+		now := storeTestTime
+
+		takeAt(t, store, key("idle"), limit, now)
+		takeAt(t, store, key("active"), limit, now.Add(90*time.Second))
+		require.NoError(t, store.Cleanup(context.Background(), now.Add(2*time.Minute)))
+
+		// A dropped bucket starts full again, a recent one keeps its state
+		assert.True(t, takeAt(t, store, key("idle"), limit, now.Add(2*time.Minute)).Allowed)
+		assert.False(t, takeAt(t, store, key("active"), limit, now.Add(2*time.Minute)).Allowed)
+	}},
+	{"concurrent takes", func(t *testing.T, store Store, key func(string) string) {
+		limit := Limit{Burst: 5, Period: time.Hour}
+		now := storeTestTime
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		allowed := 0
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := store.Take(context.Background(), key("client"), limit, now)
+				assert.NoError(t, err)
+				if result.Allowed {
+					mu.Lock()
+					allowed++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 5, allowed, "every token is taken exactly once")
+	}},
+}
+
+// storeTestTime is the clock of the suite, without the monotonic reading and with the precision
+// of the database stores
+var storeTestTime = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// testStore runs the shared suite against the stores created by newStore
+func testStore(t *testing.T, newStore newStoreFunc) {
+	for _, test := range storeTests {
+		t.Run(test.name, func(t *testing.T) {
+			prefix := uuid.New().String() + ":"
+			test.run(t, newStore(t, time.Minute), func(name string) string { return prefix + name })
+		})
+	}
+}
+
+// takeAt takes a token, failing the test on errors
+func takeAt(t *testing.T, store Store, key string, limit Limit, now time.Time) Result {
+	t.Helper()
+	result, err := store.Take(context.Background(), key, limit, now)
+	require.NoError(t, err)
+	return result
+}
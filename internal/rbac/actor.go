@@ -0,0 +1,100 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrForbidden is returned when an actor lacks the permission an action requires
+var ErrForbidden = errors.New("permission denied")
+
+// DenialFunc is called with the permissions of a failed check, any one of which would have
+// been sufficient
+type DenialFunc func(permissions []Permission)
+
+// Actor is the caller of a request together with the permissions it holds in the tenant of
+// the request. The zero value is an anonymous caller without permissions.
+type Actor struct {
+	// Subject identifies the caller; empty for anonymous callers
+	Subject string
+	// Roles are the roles the caller holds
+	Roles []string
+
+	permissions  map[Permission]bool
+	unrestricted bool
+	onDenied     DenialFunc
+}
+
+// NewActor creates an actor holding the permissions of its roles. onDenied, if not nil, is
+// called whenever a check fails.
+func NewActor(subject string, roles []string, onDenied DenialFunc) *Actor {
+	actor := &Actor{Subject: subject, Roles: roles, permissions: make(map[Permission]bool), onDenied: onDenied}
+	for _, role := range roles {
+		for _, permission := range rolePermissions[role] {
+			actor.permissions[permission] = true
+		}
+	}
+	return actor
+}
+
+// NewScopedActor creates an actor holding exactly the given permissions, such as a machine
+// client limited to the scopes of its API key
+func NewScopedActor(subject string, permissions []Permission, onDenied DenialFunc) *Actor {
+	actor := &Actor{Subject: subject, permissions: make(map[Permission]bool), onDenied: onDenied}
+	for _, permission := range permissions {
+		actor.permissions[permission] = true
+	}
+	return actor
+}
+
+// Unrestricted creates an actor holding every permission, used when access control is disabled
+func Unrestricted(subject string) *Actor {
+	return &Actor{Subject: subject, unrestricted: true}
+}
+
+// Can reports whether the actor holds a permission
+func (a *Actor) Can(permission Permission) bool {
+	return a.unrestricted || a.permissions[permission]
+}
+
+// Check returns nil if the actor holds at least one of the permissions, and an error wrapping
+// ErrForbidden otherwise
+func (a *Actor) Check(permissions ...Permission) error {
+	for _, permission := range permissions {
+		if a.Can(permission) {
+			return nil
+		}
+	}
+
+	if a.onDenied != nil {
+		a.onDenied(permissions)
+	}
+	return fmt.Errorf("%w: %s required", ErrForbidden, Join(permissions, " or "))
+}
+
+// Join concatenates the names of permissions, separated by sep
+func Join(permissions []Permission, sep string) string {
+	names := make([]string, len(permissions))
+	for i, permission := range permissions {
+		names[i] = string(permission)
+	}
+	return strings.Join(names, sep)
+}
+
+type contextKey struct{}
+
+// NewContext returns a context carrying the actor
+func NewContext(ctx context.Context, actor *Actor) context.Context {
+	return context.WithValue(ctx, contextKey{}, actor)
+}
+
+// FromContext returns the actor carried by ctx, or an anonymous actor without permissions
+// when there is none
+func FromContext(ctx context.Context) *Actor {
+	if actor, ok := ctx.Value(contextKey{}).(*Actor); ok && actor != nil {
+		return actor
+	}
+	return &Actor{}
+}
@@ -0,0 +1,106 @@
+// Package rbac defines the roles and permissions of the API and the actor whose permissions
+// are checked while a request is handled.
+package rbac
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Permission is the right to perform an action, written as "<resource>:<action>[:<scope>]".
+// Permissions scoped to "own" only apply to posts the caller wrote, "any" to every post.
+type Permission string
+
+// Permissions
+const (
+	PostCreate      Permission = "post:create"
+	PostUpdateOwn   Permission = "post:update:own"
+	PostUpdateAny   Permission = "post:update:any"
+	PostPublish     Permission = "post:publish"
+	PostDeleteOwn   Permission = "post:delete:own"
+	PostDeleteAny   Permission = "post:delete:any"
+	PostImport      Permission = "post:import"
+	PostExport      Permission = "post:export"
+	CommentModerate Permission = "comment:moderate"
+	WebhookManage   Permission = "webhook:manage"
+	RoleManage      Permission = "role:manage"
+	APIKeyManage    Permission = "apikey:manage"
+	AuditRead       Permission = "audit:read"
+)
+
+// Roles
+const (
+	RoleAdmin    = "admin"
+	RoleEditor   = "editor"
+	RoleAuthor   = "author"
+	RoleReviewer = "reviewer"
+)
+
+// rolePermissions lists the permissions granted by each role
+var rolePermissions = map[string][]Permission{
+	RoleAuthor:   {PostCreate, PostUpdateOwn, PostDeleteOwn},
+	RoleReviewer: {PostUpdateAny, PostPublish, CommentModerate},
+	RoleEditor: {PostCreate, PostUpdateOwn, PostUpdateAny, PostPublish, PostDeleteOwn, PostDeleteAny,
+		PostImport, PostExport, CommentModerate},
+	RoleAdmin: {PostCreate, PostUpdateOwn, PostUpdateAny, PostPublish, PostDeleteOwn, PostDeleteAny,
+		PostImport, PostExport, CommentModerate, WebhookManage, RoleManage, APIKeyManage, AuditRead},
+}
+
+// Roles returns the names of every role in alphabetical order
+func Roles() []string {
+	roles := make([]string, 0, len(rolePermissions))
+	for role := range rolePermissions {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// ValidRole reports whether role is a known role
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// ValidPermission reports whether permission is granted by any role
+func ValidPermission(permission Permission) bool {
+	for _, granted := range rolePermissions[RoleAdmin] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpandScopes resolves scopes, each a permission or the name of a role standing for its
+// permissions, to the permissions they grant, without duplicates. It fails on unknown scopes.
+func ExpandScopes(scopes []string) ([]Permission, error) {
+	var permissions []Permission
+	seen := make(map[Permission]bool)
+	add := func(permission Permission) {
+		if !seen[permission] {
+			seen[permission] = true
+			permissions = append(permissions, permission)
+		}
+	}
+
+	for _, scope := range scopes {
+		switch {
+		case ValidRole(scope):
+			for _, permission := range rolePermissions[scope] {
+				add(permission)
+			}
+		case ValidPermission(Permission(scope)):
+			add(Permission(scope))
+		default:
+			return nil, fmt.Errorf("unknown scope %q, expected a permission or one of the roles: %s", scope, strings.Join(Roles(), " "))
+		}
+	}
+	return permissions, nil
+}
+
+// PermissionsOf returns the permissions granted by a role, or nil for unknown roles
+func PermissionsOf(role string) []Permission {
+	return append([]Permission(nil), rolePermissions[role]...)
+}
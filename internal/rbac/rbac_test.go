@@ -0,0 +1,62 @@
+package rbac
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewActor_HoldsPermissionsOfItsRoles(t *testing.T) {
+	actor := NewActor("alice", []string{RoleAuthor, RoleReviewer}, nil)
+
+	assert.True(t, actor.Can(PostCreate))
+	assert.True(t, actor.Can(PostUpdateAny))
+	assert.True(t, actor.Can(PostPublish))
+	assert.False(t, actor.Can(PostDeleteAny))
+	assert.False(t, actor.Can(RoleManage))
+}
+
+func TestActor_Check(t *testing.T) {
+	var denied [][]Permission
+	actor := NewActor("alice", []string{RoleAuthor}, func(permissions []Permission) {
+		denied = append(denied, permissions)
+	})
+
+	assert.NoError(t, actor.Check(PostDeleteOwn, PostDeleteAny), "one permission is enough")
+	assert.Empty(t, denied)
+
+	err := actor.Check(PostPublish, RoleManage)
+	assert.ErrorIs(t, err, ErrForbidden)
+	assert.EqualError(t, err, "permission denied: post:publish or role:manage required")
+	assert.Equal(t, [][]Permission{{PostPublish, RoleManage}}, denied)
+}
+
+func TestActor_Anonymous(t *testing.T) {
+	actor := FromContext(context.Background())
+
+	assert.Empty(t, actor.Subject)
+	assert.ErrorIs(t, actor.Check(PostCreate), ErrForbidden)
+}
+
+func TestUnrestricted(t *testing.T) {
+	actor := FromContext(NewContext(context.Background(), Unrestricted("alice")))
+
+	assert.Equal(t, "alice", actor.Subject)
+	for _, permission := range PermissionsOf(RoleAdmin) {
+		assert.True(t, actor.Can(permission), permission)
+	}
+}
+
+func TestRoles(t *testing.T) {
+	assert.Equal(t, []string{RoleAdmin, RoleAuthor, RoleEditor, RoleReviewer}, Roles())
+	assert.True(t, ValidRole(RoleEditor))
+	assert.False(t, ValidRole("owner"))
+	assert.Nil(t, PermissionsOf("owner"))
+
+	for _, role := range Roles() {
+		for _, permission := range PermissionsOf(role) {
+			assert.Contains(t, PermissionsOf(RoleAdmin), permission, "admins hold every permission")
+		}
+	}
+}
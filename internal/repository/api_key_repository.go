@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"BlogManagment/internal/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// API key repository errors
+var (
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	// ErrAPIKeyNotRotatable is returned when rotating a key that was revoked or already rotated
+	ErrAPIKeyNotRotatable = errors.New("API key was revoked or already rotated")
+)
+
+// APIKeyRepository defines the interface for API key data operations. GetByID looks keys up
+// across tenants to authenticate requests; every other method is restricted to a single tenant.
+type APIKeyRepository interface {
+	Create(key *models.APIKey) error
+	GetByID(id string) (*models.APIKey, error)
+	Get(tenantID, id string) (*models.APIKey, error)
+	List(tenantID string) ([]models.APIKey, error)
+	Rotate(tenantID, id string, expiresAt time.Time, replacement *models.APIKey) error
+	Revoke(tenantID, id string, at time.Time) error
+	UpdateLastUsed(id string, at time.Time) error
+}
+
+// apiKeyRepository implements APIKeyRepository interface
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository instance
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// Create stores a new API key
+func (r *apiKeyRepository) Create(key *models.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+// GetByID retrieves an API key of any tenant
+func (r *apiKeyRepository) GetByID(id string) (*models.APIKey, error) {
+	var key models.APIKey
+	result := r.db.Where("id = ?", id).First(&key)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, result.Error
+	}
+	return &key, nil
+}
+
+// Get retrieves an API key of a tenant
+func (r *apiKeyRepository) Get(tenantID, id string) (*models.APIKey, error) {
+	var key models.APIKey
+	result := r.db.Where("tenant_id = ? AND id = ?", tenantID, id).First(&key)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, result.Error
+	}
+	return &key, nil
+}
+
+// List retrieves the API keys of a tenant, newest first
+func (r *apiKeyRepository) List(tenantID string) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	result := r.db.Where("tenant_id = ?", tenantID).Order("created_at DESC, id ASC").Find(&keys)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return keys, nil
+}
+
+// Rotate stores the replacement of a key and lets the old key expire at expiresAt, in one
+// transaction. A key can only be rotated once, and not after it was revoked.
+func (r *apiKeyRepository) Rotate(tenantID, id string, expiresAt time.Time, replacement *models.APIKey) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.APIKey{}).
+			Where("tenant_id = ? AND id = ? AND revoked_at IS NULL AND replaced_by = ''", tenantID, id).
+			Updates(map[string]interface{}{"expires_at": expiresAt, "replaced_by": replacement.ID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			if _, err := (&apiKeyRepository{db: tx}).Get(tenantID, id); err != nil {
+				return err
+			}
+			return ErrAPIKeyNotRotatable
+		}
+		return tx.Create(replacement).Error
+	})
+}
+
+// Revoke disables a key immediately. Revoking a revoked key is not an error.
+func (r *apiKeyRepository) Revoke(tenantID, id string, at time.Time) error {
+	result := r.db.Model(&models.APIKey{}).
+		Where("tenant_id = ? AND id = ? AND revoked_at IS NULL", tenantID, id).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		_, err := r.Get(tenantID, id)
+		return err
+	}
+	return nil
+}
+
+// UpdateLastUsed records when a key was last used to authenticate a request
+func (r *apiKeyRepository) UpdateLastUsed(id string, at time.Time) error {
+	return r.db.Model(&models.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", at).Error
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"BlogManagment/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAPIKey(tenantID string) *models.APIKey {
+	return &models.APIKey{
+		ID:         uuid.New().String(),
+		TenantID:   tenantID,
+		Name:       "CI",
+		SecretHash: "hash",
+		Scopes:     []string{"post:create"},
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+}
+
+func TestAPIKeyRepository_Rotate(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.APIKey{}))
+	repo := NewAPIKeyRepository(db)
+
+	old := newTestAPIKey("acme")
+	require.NoError(t, repo.Create(old))
+	graceEnd := time.Now().Add(time.Minute).Truncate(time.Second)
+
+	replacement := newTestAPIKey("acme")
+	require.NoError(t, repo.Rotate("acme", old.ID, graceEnd, replacement))
+
+	stored, err := repo.Get("acme", old.ID)
+	require.NoError(t, err)
+	assert.Equal(t, replacement.ID, stored.ReplacedBy)
+	assert.True(t, graceEnd.Equal(stored.ExpiresAt))
+	_, err = repo.GetByID(replacement.ID)
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, repo.Rotate("acme", old.ID, graceEnd, newTestAPIKey("acme")), ErrAPIKeyNotRotatable, "keys are rotated once")
+	assert.ErrorIs(t, repo.Rotate("globex", replacement.ID, graceEnd, newTestAPIKey("globex")), ErrAPIKeyNotFound)
+
+	require.NoError(t, repo.Revoke("acme", replacement.ID, time.Now()))
+	assert.ErrorIs(t, repo.Rotate("acme", replacement.ID, graceEnd, newTestAPIKey("acme")), ErrAPIKeyNotRotatable)
+
+	keys, err := repo.List("acme")
+	require.NoError(t, err)
+	assert.Len(t, keys, 2, "failed rotations store no key")
+}
+
+func TestAPIKeyRepository_Revoke(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.APIKey{}))
+	repo := NewAPIKeyRepository(db)
+
+	key := newTestAPIKey("acme")
+	require.NoError(t, repo.Create(key))
+
+	assert.ErrorIs(t, repo.Revoke("globex", key.ID, time.Now()), ErrAPIKeyNotFound)
+	require.NoError(t, repo.Revoke("acme", key.ID, time.Now()))
+	assert.NoError(t, repo.Revoke("acme", key.ID, time.Now()), "revoking twice is not an error")
+
+	stored, err := repo.GetByID(key.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, stored.RevokedAt)
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"BlogManagment/internal/models"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// AuditRepository defines the interface for reading the audit log. Entries are written by the
+// blog repository, in the transaction of the change they describe.
+type AuditRepository interface {
+	List(tenantID string, filter *models.AuditFilter, limit int) ([]models.AuditEntry, error)
+	ListAfter(tenantID string, filter *models.AuditFilter, after int64, limit int) ([]models.AuditEntry, error)
+}
+
+// auditRepository implements AuditRepository interface
+type auditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new audit repository instance
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// List retrieves up to limit entries of a tenant matching the filter, newest first
+func (r *auditRepository) List(tenantID string, filter *models.AuditFilter, limit int) ([]models.AuditEntry, error) {
+	var entries []models.AuditEntry
+	query := r.query(tenantID, filter).Order("id DESC").Limit(limit)
+	if filter != nil && filter.Before > 0 {
+		query = query.Where("id < ?", filter.Before)
+	}
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ListAfter retrieves up to limit entries of a tenant matching the filter with an ID greater
+// than after, oldest first
+func (r *auditRepository) ListAfter(tenantID string, filter *models.AuditFilter, after int64, limit int) ([]models.AuditEntry, error) {
+	var entries []models.AuditEntry
+	query := r.query(tenantID, filter).Where("id > ?", after).Order("id ASC").Limit(limit)
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// query selects the entries of a tenant matching the filter
+func (r *auditRepository) query(tenantID string, filter *models.AuditFilter) *gorm.DB {
+	query := r.db.Where("tenant_id = ?", tenantID)
+	if filter == nil {
+		return query
+	}
+	if filter.Subject != "" {
+		query = query.Where("subject = ?", filter.Subject)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("created_at < ?", *filter.Until)
+	}
+	return query
+}
+
+// ProtectAuditLog installs triggers that make the database refuse to update, delete or
+// truncate audit entries, so that the log stays append-only even for direct SQL access.
+// PostgreSQL and SQLite are supported.
+func ProtectAuditLog(db *gorm.DB) error {
+	var statements []string
+	switch dialect := db.Dialector.Name(); dialect {
+	case "postgres":
+		statements = []string{
+			`CREATE OR REPLACE FUNCTION audit_entries_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_entries is append-only';
+END;
+$$ LANGUAGE plpgsql`,
+			`DROP TRIGGER IF EXISTS audit_entries_append_only ON audit_entries`,
+			`CREATE TRIGGER audit_entries_append_only BEFORE UPDATE OR DELETE ON audit_entries
+	FOR EACH ROW EXECUTE FUNCTION audit_entries_append_only()`,
+			`DROP TRIGGER IF EXISTS audit_entries_no_truncate ON audit_entries`,
+			`CREATE TRIGGER audit_entries_no_truncate BEFORE TRUNCATE ON audit_entries
+	FOR EACH STATEMENT EXECUTE FUNCTION audit_entries_append_only()`,
+		}
+	case "sqlite":
+		statements = []string{
+			`CREATE TRIGGER IF NOT EXISTS audit_entries_no_update BEFORE UPDATE ON audit_entries
+BEGIN
+	SELECT RAISE(ABORT, 'audit_entries is append-only');
+END`,
+			`CREATE TRIGGER IF NOT EXISTS audit_entries_no_delete BEFORE DELETE ON audit_entries
+BEGIN
+	SELECT RAISE(ABORT, 'audit_entries is append-only');
+END`,
+		}
+	default:
+		return fmt.Errorf("cannot protect the audit log on %s", dialect)
+	}
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"BlogManagment/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newAuditTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := newTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.AuditEntry{}))
+	require.NoError(t, ProtectAuditLog(db))
+	return db
+}
+
+func newTestAuditEntry(action, targetID, subject string, createdAt time.Time) *models.AuditEntry {
+	return &models.AuditEntry{
+		Action:    action,
+		TargetID:  targetID,
+		Subject:   subject,
+		Changes:   map[string]models.FieldChange{"title": {Old: "Old", New: "New"}},
+		CreatedAt: createdAt,
+	}
+}
+
+func TestAuditRepository_List(t *testing.T) {
+	db := newAuditTestDB(t)
+	repo := NewAuditRepository(db)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, NewBlogRepository(db).WithTenant("acme").AppendAudit([]*models.AuditEntry{
+		newTestAuditEntry(models.AuditActionPostCreate, "post-1", "alice", start),
+		newTestAuditEntry(models.AuditActionPostUpdate, "post-1", "bob", start.Add(time.Hour)),
+		newTestAuditEntry(models.AuditActionPostDelete, "post-2", "alice", start.Add(2*time.Hour)),
+	}))
+	require.NoError(t, NewBlogRepository(db).WithTenant("globex").AppendAudit([]*models.AuditEntry{
+		newTestAuditEntry(models.AuditActionPostCreate, "post-3", "alice", start),
+	}))
+
+	entries, err := repo.List("acme", &models.AuditFilter{}, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 3, "entries of other tenants are not listed")
+	assert.Equal(t, "post-2", entries[0].TargetID, "newest first")
+	assert.Equal(t, models.FieldChange{Old: "Old", New: "New"}, entries[0].Changes["title"])
+
+	entries, err = repo.List("acme", &models.AuditFilter{Subject: "alice"}, 10)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	entries, err = repo.List("acme", &models.AuditFilter{Action: models.AuditActionPostUpdate}, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "bob", entries[0].Subject)
+
+	entries, err = repo.List("acme", &models.AuditFilter{TargetID: "post-1"}, 10)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	since, until := start.Add(time.Hour), start.Add(2*time.Hour)
+	entries, err = repo.List("acme", &models.AuditFilter{Since: &since, Until: &until}, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, models.AuditActionPostUpdate, entries[0].Action)
+
+	page, err := repo.List("acme", &models.AuditFilter{}, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	entries, err = repo.List("acme", &models.AuditFilter{Before: page[1].ID}, 2)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, models.AuditActionPostCreate, entries[0].Action)
+
+	entries, err = repo.ListAfter("acme", &models.AuditFilter{}, page[1].ID, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "post-2", entries[0].TargetID, "oldest first after the cursor")
+}
+
+func TestProtectAuditLog_RejectsChanges(t *testing.T) {
+	db := newAuditTestDB(t)
+	entry := newTestAuditEntry(models.AuditActionPostCreate, "post-1", "alice", time.Now())
+	require.NoError(t, NewBlogRepository(db).AppendAudit([]*models.AuditEntry{entry}))
+
+	err := db.Model(&models.AuditEntry{}).Where("id = ?", entry.ID).Update("subject", "mallory").Error
+	assert.ErrorContains(t, err, "append-only")
+	err = db.Delete(&models.AuditEntry{}, entry.ID).Error
+	assert.ErrorContains(t, err, "append-only")
+	err = db.Exec("DELETE FROM audit_entries").Error
+	assert.ErrorContains(t, err, "append-only")
+
+	var stored models.AuditEntry
+	require.NoError(t, db.First(&stored, entry.ID).Error)
+	assert.Equal(t, "alice", stored.Subject)
+
+	assert.NoError(t, ProtectAuditLog(db), "protecting the log again is a no-op")
+}
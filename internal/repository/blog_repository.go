@@ -2,46 +2,178 @@ package repository
 
 import (
 	"BlogManagment/internal/models"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"gorm.io/gorm"
 )
 
+// ErrBlogNotFound is returned when a blog post does not exist
+var ErrBlogNotFound = errors.New("blog post not found")
+
+// ErrSlugConflict is returned when a slug is already used by another blog post
+var ErrSlugConflict = errors.New("slug is already in use")
+
 // BlogRepository defines the interface for blog data operations
 type BlogRepository interface {
 	Create(blog *models.Blog) error
+	CreateBatch(blogs []*models.Blog) error
 	GetByID(id string) (*models.Blog, error)
+	GetByIDs(ids []string) ([]models.Blog, error)
+	GetBySlug(slug string) (*models.Blog, error)
 	GetAll() ([]models.Blog, error)
+	GetPublished() ([]models.Blog, error)
+	ListAfter(cursor *models.BlogCursor, limit int) ([]models.Blog, error)
+	List(filter *models.BlogFilter, before *models.BlogCursor, limit int) ([]models.Blog, error)
+	CountByTags(names []string, filter *models.BlogFilter) (map[string]int, error)
 	Update(blog *models.Blog) error
 	Delete(id string) error
+	Transaction(fn func(repo BlogRepository) error) error
+	AppendEvents(events []*models.BlogEvent) error
+	AppendAudit(entries []*models.AuditEntry) error
+	WithTenant(tenantID string) BlogRepository
+	// WithSession returns a repository whose reads see the writes the session made through any
+	// repository of the same store
+	WithSession(session string) BlogRepository
+	// Primary returns a repository that never reads from a replica, for reads a write depends on
+	Primary() BlogRepository
 }
 
-// blogRepository implements BlogRepository interface
+// OutboxChannel is the LISTEN/NOTIFY channel signalled whenever events are added to the outbox
+const OutboxChannel = "blog_events"
+
+// outboxLockKey is the advisory lock that serialises transactions writing to the outbox
+const outboxLockKey = 7_310_452_001
+
+// blogRepository implements BlogRepository interface. Every query is restricted to the posts
+// of tenantID, and every post it writes is assigned to that tenant. Writes go to db; with
+// replicas set, reads outside a transaction go to the replica chosen for the session.
 type blogRepository struct {
-	db *gorm.DB
+	db       *gorm.DB
+	tenantID string
+	replicas *ReplicaSet
+	session  string
+	// primary makes reads skip the replicas
+	primary bool
 }
 
-// NewBlogRepository creates a new blog repository instance
+// NewBlogRepository creates a new blog repository instance for the default tenant
 func NewBlogRepository(db *gorm.DB) BlogRepository {
-	return &blogRepository{db: db}
+	return &blogRepository{db: db, tenantID: models.DefaultTenantID}
+}
+
+// NewReplicatedBlogRepository creates a blog repository for the default tenant that writes to
+// the primary of replicas and reads from its replicas
+func NewReplicatedBlogRepository(replicas *ReplicaSet) BlogRepository {
+	return &blogRepository{db: replicas.Primary(), tenantID: models.DefaultTenantID, replicas: replicas}
+}
+
+// WithTenant returns a repository for the posts of another tenant
+func (r *blogRepository) WithTenant(tenantID string) BlogRepository {
+	return &blogRepository{db: r.db, tenantID: tenantID, replicas: r.replicas, session: r.session, primary: r.primary}
+}
+
+// WithSession returns a repository that reads from the primary for a while after the session wrote
+func (r *blogRepository) WithSession(session string) BlogRepository {
+	return &blogRepository{db: r.db, tenantID: r.tenantID, replicas: r.replicas, session: session, primary: r.primary}
+}
+
+// Primary returns a repository that reads from the primary. Its writes still count as writes
+// of the session.
+func (r *blogRepository) Primary() BlogRepository {
+	if r.replicas == nil {
+		return r
+	}
+	return &blogRepository{db: r.db, tenantID: r.tenantID, replicas: r.replicas, session: r.session, primary: true}
+}
+
+// reader returns the database that reads go to
+func (r *blogRepository) reader() *gorm.DB {
+	if r.replicas == nil || r.primary {
+		return r.db
+	}
+	return r.replicas.Reader(r.session)
+}
+
+// wrote records a successful write of the session, so that its reads go to the primary
+// until the replicas have caught up
+func (r *blogRepository) wrote() {
+	if r.replicas != nil {
+		r.replicas.MarkWrite(r.session)
+	}
 }
 
-// Create adds a new blog post to the database
+// Create adds a new blog post to the database.
+// A slug that is already taken gets a numeric suffix.
 func (r *blogRepository) Create(blog *models.Blog) error {
+	r.assignTenant(blog)
+	if err := r.assignUniqueSlugs([]*models.Blog{blog}); err != nil {
+		return err
+	}
+
 	result := r.db.Create(blog)
 	if result.Error != nil {
 		return result.Error
 	}
+	r.wrote()
+	return nil
+}
+
+// CreateBatch adds several blog posts to the database in a single statement
+func (r *blogRepository) CreateBatch(blogs []*models.Blog) error {
+	if len(blogs) == 0 {
+		return nil
+	}
+	for _, blog := range blogs {
+		r.assignTenant(blog)
+	}
+	if err := r.assignUniqueSlugs(blogs); err != nil {
+		return err
+	}
+	result := r.db.CreateInBatches(blogs, 100)
+	if result.Error != nil {
+		return result.Error
+	}
+	r.wrote()
 	return nil
 }
 
 // GetByID retrieves a blog post by its ID
 func (r *blogRepository) GetByID(id string) (*models.Blog, error) {
 	var blog models.Blog
-	result := r.db.Where("id = ?", id).First(&blog)
+	result := r.reader().Scopes(r.tenantScope, preloadTags).Where("id = ?", id).First(&blog)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrBlogNotFound
+		}
+		return nil, result.Error
+	}
+	return &blog, nil
+}
+
+// GetByIDs retrieves the blog posts with the given IDs; missing IDs are skipped
+func (r *blogRepository) GetByIDs(ids []string) ([]models.Blog, error) {
+	var blogs []models.Blog
+	if len(ids) == 0 {
+		return blogs, nil
+	}
+	result := r.reader().Scopes(r.tenantScope, preloadTags).Where("id IN ?", ids).Find(&blogs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return blogs, nil
+}
+
+// GetBySlug retrieves a blog post by its slug
+func (r *blogRepository) GetBySlug(slug string) (*models.Blog, error) {
+	var blog models.Blog
+	result := r.reader().Scopes(r.tenantScope, preloadTags).Where("slug = ?", slug).First(&blog)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, errors.New("blog post not found")
+			return nil, ErrBlogNotFound
 		}
 		return nil, result.Error
 	}
@@ -51,33 +183,281 @@ func (r *blogRepository) GetByID(id string) (*models.Blog, error) {
 // GetAll retrieves all blog posts from the database
 func (r *blogRepository) GetAll() ([]models.Blog, error) {
 	var blogs []models.Blog
-	result := r.db.Order("created_at DESC").Find(&blogs)
+	result := r.reader().Scopes(r.tenantScope, preloadTags).Order("created_at DESC").Find(&blogs)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return blogs, nil
 }
 
-// Update modifies an existing blog post
-func (r *blogRepository) Update(blog *models.Blog) error {
-	result := r.db.Save(blog)
+// GetPublished retrieves every published blog post, most recently published first
+func (r *blogRepository) GetPublished() ([]models.Blog, error) {
+	var blogs []models.Blog
+	result := r.reader().Scopes(r.tenantScope, preloadTags).
+		Where("status = ?", models.BlogStatusPublished).
+		Order("COALESCE(published_at, created_at) DESC, id DESC").
+		Find(&blogs)
 	if result.Error != nil {
-		return result.Error
+		return nil, result.Error
 	}
-	if result.RowsAffected == 0 {
-		return errors.New("blog post not found")
+	return blogs, nil
+}
+
+// ListAfter retrieves up to limit blog posts in (created_at, id) order,
+// starting after the given cursor or from the oldest post when cursor is nil
+func (r *blogRepository) ListAfter(cursor *models.BlogCursor, limit int) ([]models.Blog, error) {
+	var blogs []models.Blog
+	query := r.reader().Scopes(r.tenantScope, preloadTags).Order("created_at ASC, id ASC").Limit(limit)
+	if cursor != nil {
+		query = query.Where("created_at > ? OR (created_at = ? AND id > ?)", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
 	}
+	result := query.Find(&blogs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return blogs, nil
+}
+
+// List retrieves up to limit blog posts matching the filter, newest first in (created_at, id)
+// order, starting before the given cursor or from the newest post when cursor is nil.
+// Tags are matched case-insensitively.
+func (r *blogRepository) List(filter *models.BlogFilter, before *models.BlogCursor, limit int) ([]models.Blog, error) {
+	var blogs []models.Blog
+	query := r.reader().Scopes(r.tenantScope, preloadTags).Order("created_at DESC, id DESC").Limit(limit)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	query = query.Scopes(publishedOnlyScope(filter))
+	if filter.Tag != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM blog_tags WHERE blog_tags.blog_id = blogs.id AND LOWER(blog_tags.name) = LOWER(?))", filter.Tag)
+	}
+	if before != nil {
+		query = query.Where("created_at < ? OR (created_at = ? AND id < ?)", before.CreatedAt, before.CreatedAt, before.ID)
+	}
+	result := query.Find(&blogs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return blogs, nil
+}
+
+// CountByTags returns the number of blog posts carrying each of the given tags with a single query,
+// leaving out the drafts hidden by the PublishedOnly and DraftsOf fields of filter if it is given.
+// Tags match without case, like the tag filter of List. Tags without posts are missing from the result.
+func (r *blogRepository) CountByTags(names []string, filter *models.BlogFilter) (map[string]int, error) {
+	counts := make(map[string]int, len(names))
+	if len(names) == 0 {
+		return counts, nil
+	}
+
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+	}
+	var rows []struct {
+		Name  string
+		Count int
+	}
+	result := r.reader().Scopes(r.tenantScope).Model(&models.BlogTag{}).
+		Select("LOWER(blog_tags.name) AS name, COUNT(DISTINCT blog_tags.blog_id) AS count").
+		Joins("JOIN blogs ON blogs.id = blog_tags.blog_id AND blogs.deleted_at IS NULL").
+		Scopes(publishedOnlyScope(filter)).
+		Where("LOWER(blog_tags.name) IN ?", lowered).
+		Group("LOWER(blog_tags.name)").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	byName := make(map[string]int, len(rows))
+	for _, row := range rows {
+		byName[row.Name] = row.Count
+	}
+	for i, name := range names {
+		if count, ok := byName[lowered[i]]; ok {
+			counts[name] = count
+		}
+	}
+	return counts, nil
+}
+
+// Update modifies an existing blog post and replaces its tags
+func (r *blogRepository) Update(blog *models.Blog) error {
+	r.assignTenant(blog)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if blog.Slug != "" {
+			var count int64
+			if err := tx.Scopes(r.tenantScope).Model(&models.Blog{}).Where("slug = ? AND id <> ?", blog.Slug, blog.ID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return ErrSlugConflict
+			}
+		}
+
+		// Unlike Save, Updates never falls back to an upsert, which could take over the post of another tenant
+		result := tx.Scopes(r.tenantScope).Select("*").Updates(blog)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrBlogNotFound
+		}
+
+		// Save only adds tags, so remove the ones the post no longer has
+		query := tx.Where("blog_id = ?", blog.ID)
+		if names := blog.TagNames(); len(names) > 0 {
+			query = query.Where("name NOT IN ?", names)
+		}
+		return query.Delete(&models.BlogTag{}).Error
+	})
+	if err != nil {
+		return err
+	}
+	r.wrote()
 	return nil
 }
 
 // Delete removes a blog post from the database
 func (r *blogRepository) Delete(id string) error {
-	result := r.db.Where("id = ?", id).Delete(&models.Blog{})
+	result := r.db.Scopes(r.tenantScope).Where("id = ?", id).Delete(&models.Blog{})
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("blog post not found")
+		return ErrBlogNotFound
 	}
+	r.wrote()
 	return nil
-} 
\ No newline at end of file
+}
+
+// Transaction runs fn with a repository bound to a single database transaction.
+// The transaction is committed when fn returns nil and rolled back otherwise. Reads within
+// the transaction go to the primary.
+func (r *blogRepository) Transaction(fn func(repo BlogRepository) error) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&blogRepository{db: tx, tenantID: r.tenantID})
+	})
+	if err != nil {
+		return err
+	}
+	r.wrote()
+	return nil
+}
+
+// AppendEvents records events in the outbox. Called inside Transaction, the events are only
+// stored if the change they describe is committed. On PostgreSQL the advisory lock is held until
+// the transaction ends, so sequence numbers become visible in order and a relay reading past
+// the last committed event never skips one that commits later. SQLite runs one write transaction
+// at a time, which gives the same guarantee, and has no LISTEN/NOTIFY to wake listeners with.
+func (r *blogRepository) AppendEvents(events []*models.BlogEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	rows := make([]*models.OutboxEvent, len(events))
+	for i, event := range events {
+		event.TenantID = r.tenantID
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		rows[i] = &models.OutboxEvent{
+			EventID:     event.ID,
+			Type:        event.Type,
+			AggregateID: event.Data.ID,
+			TenantID:    r.tenantID,
+			Payload:     payload,
+			CreatedAt:   event.OccurredAt,
+		}
+	}
+
+	if r.db.Dialector.Name() != "postgres" {
+		return r.db.CreateInBatches(rows, 100).Error
+	}
+	if err := r.db.Exec("SELECT pg_advisory_xact_lock(?)", outboxLockKey).Error; err != nil {
+		return err
+	}
+	if err := r.db.CreateInBatches(rows, 100).Error; err != nil {
+		return err
+	}
+	// Listeners are woken up when the transaction commits; they read the events from the outbox
+	return r.db.Exec("SELECT pg_notify(?, ?)", OutboxChannel, strconv.FormatInt(rows[len(rows)-1].Sequence, 10)).Error
+}
+
+// AppendAudit adds entries to the audit log. Called inside Transaction, the entries are only
+// stored if the change they describe is committed.
+func (r *blogRepository) AppendAudit(entries []*models.AuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	for _, entry := range entries {
+		entry.TenantID = r.tenantID
+	}
+	return r.db.CreateInBatches(entries, 100).Error
+}
+
+// assignUniqueSlugs appends a numeric suffix to every slug that is already in use,
+// either in the database or earlier in the same batch
+func (r *blogRepository) assignUniqueSlugs(blogs []*models.Blog) error {
+	used := make(map[string]bool)
+
+	for _, blog := range blogs {
+		if blog.Slug == "" {
+			continue
+		}
+
+		var taken []string
+		if err := r.db.Scopes(r.tenantScope).Model(&models.Blog{}).
+			Where("(slug = ? OR slug LIKE ?) AND id <> ?", blog.Slug, blog.Slug+"-%", blog.ID).
+			Pluck("slug", &taken).Error; err != nil {
+			return err
+		}
+		for _, slug := range taken {
+			used[slug] = true
+		}
+
+		slug := blog.Slug
+		for n := 2; used[slug]; n++ {
+			slug = fmt.Sprintf("%s-%d", blog.Slug, n)
+		}
+		blog.Slug = slug
+		used[slug] = true
+	}
+
+	return nil
+}
+
+// assignTenant assigns a post and its tags to the tenant of the repository
+func (r *blogRepository) assignTenant(blog *models.Blog) {
+	blog.TenantID = r.tenantID
+	for i := range blog.Tags {
+		blog.Tags[i].TenantID = r.tenantID
+	}
+}
+
+// tenantScope restricts a query on blogs to the posts of the repository's tenant
+func (r *blogRepository) tenantScope(db *gorm.DB) *gorm.DB {
+	return db.Where("blogs.tenant_id = ?", r.tenantID)
+}
+
+// preloadTags loads the tags of every queried blog post in name order
+func preloadTags(db *gorm.DB) *gorm.DB {
+	return db.Preload("Tags", func(db *gorm.DB) *gorm.DB {
+		return db.Order("name ASC")
+	})
+}
+
+// publishedOnlyScope leaves out the unpublished posts of a filter with PublishedOnly set, except
+// those written by its DraftsOf author
+func publishedOnlyScope(filter *models.BlogFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		switch {
+		case filter == nil || !filter.PublishedOnly:
+			return db
+		case filter.DraftsOf != "":
+			return db.Where("blogs.status = ? OR blogs.author_id = ?", models.BlogStatusPublished, filter.DraftsOf)
+		default:
+			return db.Where("blogs.status = ?", models.BlogStatusPublished)
+		}
+	}
+}
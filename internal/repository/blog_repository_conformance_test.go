@@ -0,0 +1,347 @@
+package repository_test
+
+import (
+	"BlogManagment/internal/config"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newBlogRepositoryFunc creates an empty repository for a fresh tenant of a store. Several
+// repositories of one test may share a store, so the suite only relies on its own tenants.
+type newBlogRepositoryFunc func(t *testing.T) repository.BlogRepository
+
+func TestMemoryBlogRepository_Conformance(t *testing.T) {
+	testBlogRepository(t, func(t *testing.T) repository.BlogRepository {
+		return repository.NewMemoryBlogRepository().WithTenant(uuid.New().String())
+	})
+}
+
+func TestSQLiteBlogRepository_Conformance(t *testing.T) {
+	testBlogRepository(t, func(t *testing.T) repository.BlogRepository {
+		path := filepath.Join(t.TempDir(), "blog.db")
+		return newDatabaseBlogRepository(t, sqlite.Open(config.SQLiteDSN(path)))
+	})
+}
+
+// TestPostgresBlogRepository_Conformance runs against the database named by TEST_DATABASE_URL,
+// every test in a tenant of its own
+func TestPostgresBlogRepository_Conformance(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	testBlogRepository(t, func(t *testing.T) repository.BlogRepository {
+		return newDatabaseBlogRepository(t, postgres.Open(dsn))
+	})
+}
+
+// newDatabaseBlogRepository migrates the blog schema and returns a repository for a new tenant
+func newDatabaseBlogRepository(t *testing.T, dialector gorm.Dialector) repository.BlogRepository {
+	t.Helper()
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	require.NoError(t, db.AutoMigrate(&models.Tenant{}, &models.Blog{}, &models.BlogTag{}, &models.OutboxEvent{}, &models.AuditEntry{}))
+	tenant := &models.Tenant{ID: uuid.New().String(), Name: "Conformance"}
+	require.NoError(t, db.Create(tenant).Error)
+	return repository.NewBlogRepository(db).WithTenant(tenant.ID)
+}
+
+// conformanceBlog creates a published post; created orders posts the same way in every store
+func conformanceBlog(title, slug string, created int, tags ...string) *models.Blog {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(created) * time.Hour)
+	blog := &models.Blog{
+		ID:        uuid.New().String(),
+		Slug:      slug,
+		Title:     title,
+		Body:      "Body of " + title,
+		Status:    models.BlogStatusPublished,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	for _, name := range tags {
+		blog.Tags = append(blog.Tags, models.BlogTag{BlogID: blog.ID, Name: name})
+	}
+	return blog
+}
+
+func blogIDs(blogs []models.Blog) []string {
+	ids := make([]string, len(blogs))
+	for i, blog := range blogs {
+		ids[i] = blog.ID
+	}
+	return ids
+}
+
+// testBlogRepository checks that a BlogRepository behaves like the database repository the
+// services were written against
+func testBlogRepository(t *testing.T, newRepo newBlogRepositoryFunc) {
+	t.Run("not found", func(t *testing.T) {
+		repo := newRepo(t)
+		missing := uuid.New().String()
+
+		_, err := repo.GetByID(missing)
+		assert.ErrorIs(t, err, repository.ErrBlogNotFound)
+		_, err = repo.GetBySlug("missing")
+		assert.ErrorIs(t, err, repository.ErrBlogNotFound)
+		assert.ErrorIs(t, repo.Update(conformanceBlog("Missing", "missing", 0)), repository.ErrBlogNotFound)
+		assert.ErrorIs(t, repo.Delete(missing), repository.ErrBlogNotFound)
+
+		byIDs, err := repo.GetByIDs([]string{missing})
+		require.NoError(t, err)
+		assert.Empty(t, byIDs)
+		all, err := repo.GetAll()
+		require.NoError(t, err)
+		assert.Empty(t, all)
+	})
+
+	t.Run("create and read", func(t *testing.T) {
+		repo := newRepo(t)
+		post := conformanceBlog("Hello", "hello", 0, "zeta", "alpha")
+		require.NoError(t, repo.Create(post))
+
+		byID, err := repo.GetByID(post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Hello", byID.Title)
+		assert.Equal(t, []string{"alpha", "zeta"}, byID.TagNames(), "tags are loaded in name order")
+
+		bySlug, err := repo.GetBySlug("hello")
+		require.NoError(t, err)
+		assert.Equal(t, post.ID, bySlug.ID)
+
+		byID.Title = "Changed by the caller"
+		reread, err := repo.GetByID(post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Hello", reread.Title, "returned posts are copies")
+	})
+
+	t.Run("unique slugs", func(t *testing.T) {
+		repo := newRepo(t)
+		first := conformanceBlog("First", "hello", 0)
+		require.NoError(t, repo.Create(first))
+		second := conformanceBlog("Second", "hello", 1)
+		require.NoError(t, repo.Create(second))
+		assert.Equal(t, "hello-2", second.Slug)
+
+		batch := []*models.Blog{conformanceBlog("Third", "hello", 2), conformanceBlog("Fourth", "hello", 3)}
+		require.NoError(t, repo.CreateBatch(batch))
+		assert.Equal(t, "hello-3", batch[0].Slug)
+		assert.Equal(t, "hello-4", batch[1].Slug)
+
+		first.Slug = "hello-2"
+		assert.ErrorIs(t, repo.Update(first), repository.ErrSlugConflict)
+	})
+
+	t.Run("update replaces tags", func(t *testing.T) {
+		repo := newRepo(t)
+		post := conformanceBlog("Hello", "hello", 0, "go", "fiber")
+		require.NoError(t, repo.Create(post))
+
+		post.Title = "Hello again"
+		post.Tags = []models.BlogTag{{BlogID: post.ID, Name: "gorm"}, {BlogID: post.ID, Name: "go"}}
+		require.NoError(t, repo.Update(post))
+
+		stored, err := repo.GetByID(post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Hello again", stored.Title)
+		assert.Equal(t, []string{"go", "gorm"}, stored.TagNames())
+	})
+
+	t.Run("ordering", func(t *testing.T) {
+		repo := newRepo(t)
+		oldest := conformanceBlog("Oldest", "oldest", 0, "Go")
+		middle := conformanceBlog("Middle", "middle", 1)
+		middle.Status = models.BlogStatusDraft
+		middle.AuthorID = "bob"
+		newest := conformanceBlog("Newest", "newest", 2, "go")
+		// Published late, so it comes first among published posts despite being the oldest
+		publishedAt := newest.CreatedAt.Add(time.Hour)
+		oldest.PublishedAt = &publishedAt
+		require.NoError(t, repo.CreateBatch([]*models.Blog{middle, newest, oldest}))
+
+		all, err := repo.GetAll()
+		require.NoError(t, err)
+		assert.Equal(t, []string{newest.ID, middle.ID, oldest.ID}, blogIDs(all))
+
+		published, err := repo.GetPublished()
+		require.NoError(t, err)
+		assert.Equal(t, []string{oldest.ID, newest.ID}, blogIDs(published))
+
+		first, err := repo.ListAfter(nil, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{oldest.ID, middle.ID}, blogIDs(first))
+		rest, err := repo.ListAfter(&models.BlogCursor{CreatedAt: middle.CreatedAt, ID: middle.ID}, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{newest.ID}, blogIDs(rest))
+
+		listed, err := repo.List(&models.BlogFilter{}, nil, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{newest.ID, middle.ID, oldest.ID}, blogIDs(listed))
+		before, err := repo.List(&models.BlogFilter{}, &models.BlogCursor{CreatedAt: newest.CreatedAt, ID: newest.ID}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{middle.ID}, blogIDs(before))
+		drafts, err := repo.List(&models.BlogFilter{Status: models.BlogStatusDraft}, nil, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{middle.ID}, blogIDs(drafts))
+		visible, err := repo.List(&models.BlogFilter{PublishedOnly: true}, nil, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{newest.ID, oldest.ID}, blogIDs(visible))
+		ownDrafts, err := repo.List(&models.BlogFilter{PublishedOnly: true, DraftsOf: "bob"}, nil, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{newest.ID, middle.ID, oldest.ID}, blogIDs(ownDrafts))
+		taggedOwnDrafts, err := repo.List(&models.BlogFilter{Tag: "go", PublishedOnly: true, DraftsOf: "bob"}, nil, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{newest.ID, oldest.ID}, blogIDs(taggedOwnDrafts), "other filters still apply to own drafts")
+		tagged, err := repo.List(&models.BlogFilter{Tag: "GO"}, nil, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{newest.ID, oldest.ID}, blogIDs(tagged), "tags match without case")
+		counts, err := repo.CountByTags([]string{"GO", "go", "rust"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"GO": 2, "go": 2}, counts, "tags are counted like they are listed")
+		middle.Tags = []models.BlogTag{{Name: "go"}}
+		require.NoError(t, repo.Update(middle))
+		counts, err = repo.CountByTags([]string{"go"}, &models.BlogFilter{PublishedOnly: true})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"go": 2}, counts, "drafts are left out like they are listed")
+		counts, err = repo.CountByTags([]string{"go"}, &models.BlogFilter{PublishedOnly: true, DraftsOf: "bob"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"go": 3}, counts)
+	})
+
+	t.Run("soft delete", func(t *testing.T) {
+		repo := newRepo(t)
+		kept := conformanceBlog("Kept", "kept", 0, "go")
+		deleted := conformanceBlog("Deleted", "deleted", 1, "go")
+		require.NoError(t, repo.CreateBatch([]*models.Blog{kept, deleted}))
+		require.NoError(t, repo.Delete(deleted.ID))
+
+		_, err := repo.GetByID(deleted.ID)
+		assert.ErrorIs(t, err, repository.ErrBlogNotFound)
+		_, err = repo.GetBySlug("deleted")
+		assert.ErrorIs(t, err, repository.ErrBlogNotFound)
+		assert.ErrorIs(t, repo.Delete(deleted.ID), repository.ErrBlogNotFound)
+		assert.ErrorIs(t, repo.Update(deleted), repository.ErrBlogNotFound)
+
+		all, err := repo.GetAll()
+		require.NoError(t, err)
+		assert.Equal(t, []string{kept.ID}, blogIDs(all))
+		counts, err := repo.CountByTags([]string{"go", "rust"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"go": 1}, counts)
+
+		reused := conformanceBlog("Reused", "deleted", 2)
+		require.NoError(t, repo.Create(reused))
+		assert.Equal(t, "deleted", reused.Slug, "slugs of deleted posts can be reused")
+	})
+
+	t.Run("tenant isolation", func(t *testing.T) {
+		repo := newRepo(t)
+		post := conformanceBlog("Hello", "hello", 0)
+		require.NoError(t, repo.Create(post))
+
+		other := repo.WithTenant(uuid.New().String())
+		_, err := other.GetByID(post.ID)
+		assert.ErrorIs(t, err, repository.ErrBlogNotFound)
+		assert.ErrorIs(t, other.Delete(post.ID), repository.ErrBlogNotFound)
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		repo := newRepo(t)
+		rolledBack := conformanceBlog("Rolled back", "rolled-back", 0)
+		err := repo.Transaction(func(tx repository.BlogRepository) error {
+			require.NoError(t, tx.Create(rolledBack))
+			_, err := tx.GetByID(rolledBack.ID)
+			require.NoError(t, err, "writes are visible inside the transaction")
+			return fmt.Errorf("abort")
+		})
+		assert.EqualError(t, err, "abort")
+		_, err = repo.GetByID(rolledBack.ID)
+		assert.ErrorIs(t, err, repository.ErrBlogNotFound)
+
+		committed := conformanceBlog("Committed", "committed", 1)
+		require.NoError(t, repo.Transaction(func(tx repository.BlogRepository) error {
+			if err := tx.Create(committed); err != nil {
+				return err
+			}
+			event := &models.BlogEvent{ID: uuid.New().String(), Type: models.EventPostCreated, OccurredAt: time.Now(), Data: &models.BlogResponse{ID: committed.ID, Title: committed.Title}}
+			if err := tx.AppendEvents([]*models.BlogEvent{event}); err != nil {
+				return err
+			}
+			entry := &models.AuditEntry{Action: "post.create", TargetID: committed.ID, Changes: map[string]models.FieldChange{}, CreatedAt: time.Now()}
+			if err := tx.AppendAudit([]*models.AuditEntry{entry}); err != nil {
+				return err
+			}
+			assert.NotZero(t, entry.ID)
+			return nil
+		}))
+		_, err = repo.GetByID(committed.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("sessions and primary reads", func(t *testing.T) {
+		repo := newRepo(t)
+		session := repo.WithSession("user:alice")
+		post := conformanceBlog("Session", "session", 0)
+		require.NoError(t, session.Create(post))
+
+		for _, reader := range []repository.BlogRepository{repo, session, repo.Primary(), session.Primary()} {
+			found, err := reader.GetByID(post.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "Session", found.Title)
+		}
+	})
+
+	t.Run("concurrent writes", func(t *testing.T) {
+		repo := newRepo(t)
+		const writers, posts = 8, 10
+
+		var wg sync.WaitGroup
+		errs := make(chan error, writers*posts)
+		for w := 0; w < writers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < posts; i++ {
+					post := conformanceBlog("Post", fmt.Sprintf("post-%d-%d", w, i), w*posts+i, "go")
+					if err := repo.Create(post); err != nil {
+						errs <- err
+						return
+					}
+					if _, err := repo.GetAll(); err != nil {
+						errs <- err
+						return
+					}
+				}
+			}(w)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		all, err := repo.GetAll()
+		require.NoError(t, err)
+		assert.Len(t, all, writers*posts)
+		counts, err := repo.CountByTags([]string{"go"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, writers*posts, counts["go"])
+	})
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"BlogManagment/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB opens an empty in-memory SQLite database with the blog schema
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+uuid.New().String()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Tenant{}, &models.Blog{}, &models.BlogTag{}))
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+func newTestBlog(title, slug string, tags ...string) *models.Blog {
+	blog := &models.Blog{
+		ID:        uuid.New().String(),
+		Slug:      slug,
+		Title:     title,
+		Body:      "Body of " + title,
+		Status:    models.BlogStatusPublished,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	for _, name := range tags {
+		blog.Tags = append(blog.Tags, models.BlogTag{BlogID: blog.ID, Name: name})
+	}
+	return blog
+}
+
+func TestBlogRepository_TenantIsolation(t *testing.T) {
+	db := newTestDB(t)
+	acme := NewBlogRepository(db).WithTenant("acme")
+	globex := NewBlogRepository(db).WithTenant("globex")
+
+	post := newTestBlog("Acme news", "news", "go")
+	require.NoError(t, acme.Create(post))
+
+	t.Run("reads", func(t *testing.T) {
+		_, err := globex.GetByID(post.ID)
+		assert.ErrorIs(t, err, ErrBlogNotFound)
+
+		_, err = globex.GetBySlug("news")
+		assert.ErrorIs(t, err, ErrBlogNotFound)
+
+		all, err := globex.GetAll()
+		require.NoError(t, err)
+		assert.Empty(t, all)
+
+		published, err := globex.GetPublished()
+		require.NoError(t, err)
+		assert.Empty(t, published)
+
+		byIDs, err := globex.GetByIDs([]string{post.ID})
+		require.NoError(t, err)
+		assert.Empty(t, byIDs)
+
+		listed, err := globex.List(&models.BlogFilter{Tag: "go"}, nil, 10)
+		require.NoError(t, err)
+		assert.Empty(t, listed)
+
+		after, err := globex.ListAfter(nil, 10)
+		require.NoError(t, err)
+		assert.Empty(t, after)
+
+		counts, err := globex.CountByTags([]string{"go"}, nil)
+		require.NoError(t, err)
+		assert.Empty(t, counts)
+
+		own, err := acme.GetByID(post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Acme news", own.Title)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		hijack := newTestBlog("Hijacked", "news", "spam")
+		hijack.ID = post.ID
+		assert.ErrorIs(t, globex.Update(hijack), ErrBlogNotFound)
+
+		stored, err := acme.GetByID(post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Acme news", stored.Title)
+		assert.Equal(t, "acme", stored.TenantID)
+		assert.Equal(t, []string{"go"}, stored.TagNames())
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.ErrorIs(t, globex.Delete(post.ID), ErrBlogNotFound)
+
+		_, err := acme.GetByID(post.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		err := globex.Transaction(func(repo BlogRepository) error {
+			_, err := repo.GetByID(post.ID)
+			return err
+		})
+		assert.ErrorIs(t, err, ErrBlogNotFound)
+	})
+}
+
+func TestBlogRepository_SlugsAreUniquePerTenant(t *testing.T) {
+	db := newTestDB(t)
+	acme := NewBlogRepository(db).WithTenant("acme")
+	globex := NewBlogRepository(db).WithTenant("globex")
+
+	first := newTestBlog("Acme hello", "hello")
+	require.NoError(t, acme.Create(first))
+	second := newTestBlog("Globex hello", "hello")
+	require.NoError(t, globex.Create(second))
+	assert.Equal(t, "hello", second.Slug)
+
+	third := newTestBlog("Acme hello again", "hello")
+	require.NoError(t, acme.Create(third))
+	assert.Equal(t, "hello-2", third.Slug)
+
+	found, err := globex.GetBySlug("hello")
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, found.ID)
+
+	second.Slug = "hello-2"
+	assert.NoError(t, globex.Update(second), "slugs of other tenants do not conflict")
+}
+
+func TestBlogRepository_DefaultTenant(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewBlogRepository(db)
+
+	post := newTestBlog("Hello", "hello", "go")
+	require.NoError(t, repo.Create(post))
+	assert.Equal(t, models.DefaultTenantID, post.TenantID)
+	assert.Equal(t, models.DefaultTenantID, post.Tags[0].TenantID)
+
+	_, err := repo.WithTenant(models.DefaultTenantID).GetByID(post.ID)
+	assert.NoError(t, err)
+	_, err = repo.WithTenant("acme").GetByID(post.ID)
+	assert.ErrorIs(t, err, ErrBlogNotFound)
+}
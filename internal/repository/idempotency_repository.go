@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"BlogManagment/internal/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrIdempotencyKeyNotFound is returned when no record is stored for an idempotency key
+var ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+
+// IdempotencyRepository defines the interface for idempotency key storage
+type IdempotencyRepository interface {
+	Reserve(record *models.IdempotencyRecord) (bool, error)
+	Get(scope, key string) (*models.IdempotencyRecord, error)
+	Complete(record *models.IdempotencyRecord) error
+	Release(scope, key string) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+// idempotencyRepository implements IdempotencyRepository interface
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyRepository creates a new idempotency repository instance
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+// Reserve inserts a pending record for the key.
+// It returns false when an unexpired record for the same key already exists.
+func (r *idempotencyRepository) Reserve(record *models.IdempotencyRecord) (bool, error) {
+	reserved := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("scope = ? AND key = ? AND expires_at < ?", record.Scope, record.Key, time.Now()).
+			Delete(&models.IdempotencyRecord{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+		if result.Error != nil {
+			return result.Error
+		}
+		reserved = result.RowsAffected == 1
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return reserved, nil
+}
+
+// Get retrieves the record stored for a key
+func (r *idempotencyRepository) Get(scope, key string) (*models.IdempotencyRecord, error) {
+	var record models.IdempotencyRecord
+	result := r.db.Where("scope = ? AND key = ?", scope, key).First(&record)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrIdempotencyKeyNotFound
+		}
+		return nil, result.Error
+	}
+	return &record, nil
+}
+
+// Complete stores the response of a reserved key
+func (r *idempotencyRepository) Complete(record *models.IdempotencyRecord) error {
+	record.Completed = true
+	return r.db.Model(&models.IdempotencyRecord{}).
+		Where("scope = ? AND key = ?", record.Scope, record.Key).
+		Updates(map[string]interface{}{
+			"completed":     true,
+			"status_code":   record.StatusCode,
+			"content_type":  record.ContentType,
+			"response_body": record.ResponseBody,
+		}).Error
+}
+
+// Release removes a reserved key so that the request can be retried
+func (r *idempotencyRepository) Release(scope, key string) error {
+	return r.db.Where("scope = ? AND key = ?", scope, key).Delete(&models.IdempotencyRecord{}).Error
+}
+
+// DeleteExpired removes every record whose retention period has ended
+func (r *idempotencyRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", now).Delete(&models.IdempotencyRecord{})
+	return result.RowsAffected, result.Error
+}
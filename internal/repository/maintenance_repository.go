@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"BlogManagment/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaintenanceRepository defines the interface for the maintenance tasks of operators, which
+// work on the posts of every tenant at once
+type MaintenanceRepository interface {
+	PostsWithoutSlug() ([]models.Blog, error)
+	RebuildIndexes() ([]string, error)
+	PurgeDeleted(tenantID string, before time.Time) (int64, error)
+}
+
+// maintenanceRepository implements MaintenanceRepository interface
+type maintenanceRepository struct {
+	db *gorm.DB
+}
+
+// NewMaintenanceRepository creates a new maintenance repository instance
+func NewMaintenanceRepository(db *gorm.DB) MaintenanceRepository {
+	return &maintenanceRepository{db: db}
+}
+
+// PostsWithoutSlug retrieves the posts of every tenant that have no slug, such as posts created
+// before slugs were introduced, oldest first
+func (r *maintenanceRepository) PostsWithoutSlug() ([]models.Blog, error) {
+	var blogs []models.Blog
+	if err := r.db.Where("slug = ? OR slug IS NULL", "").Order("created_at ASC, id ASC").Find(&blogs).Error; err != nil {
+		return nil, err
+	}
+	return blogs, nil
+}
+
+// RebuildIndexes rebuilds the indexes of the post tables and refreshes the statistics the query
+// planner keeps about them, returning the tables it rebuilt. PostgreSQL rebuilds the indexes
+// concurrently, so the API keeps serving meanwhile.
+func (r *maintenanceRepository) RebuildIndexes() ([]string, error) {
+	tables := []string{"blogs", "blog_tags"}
+	for _, table := range tables {
+		statements := []string{"REINDEX " + table, "ANALYZE " + table}
+		if r.db.Dialector.Name() == "postgres" {
+			statements[0] = "REINDEX TABLE CONCURRENTLY " + table
+		}
+		for _, statement := range statements {
+			if err := r.db.Exec(statement).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+	return tables, nil
+}
+
+// PurgeDeleted permanently removes the posts deleted before the given time, and their tags,
+// from one tenant or from every tenant if tenantID is empty. It returns the number of posts
+// removed.
+func (r *maintenanceRepository) PurgeDeleted(tenantID string, before time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		trash := tx.Unscoped().Model(&models.Blog{}).Select("id").
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before)
+		if tenantID != "" {
+			trash = trash.Where("tenant_id = ?", tenantID)
+		}
+
+		if err := tx.Where("blog_id IN (?)", trash).Delete(&models.BlogTag{}).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Where("id IN (?)", trash).Delete(&models.Blog{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
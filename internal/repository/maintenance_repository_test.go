@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"BlogManagment/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestMaintenanceRepository_PostsWithoutSlug(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewMaintenanceRepository(db)
+
+	legacy := newTestBlog("Legacy", "")
+	require.NoError(t, NewBlogRepository(db).WithTenant("acme").Create(legacy))
+	require.NoError(t, NewBlogRepository(db).WithTenant("globex").Create(newTestBlog("Current", "current")))
+
+	blogs, err := repo.PostsWithoutSlug()
+	require.NoError(t, err)
+	require.Len(t, blogs, 1)
+	assert.Equal(t, legacy.ID, blogs[0].ID)
+	assert.Equal(t, "acme", blogs[0].TenantID)
+}
+
+func TestMaintenanceRepository_RebuildIndexes(t *testing.T) {
+	tables, err := NewMaintenanceRepository(newTestDB(t)).RebuildIndexes()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"blogs", "blog_tags"}, tables)
+}
+
+func TestMaintenanceRepository_PurgeDeleted(t *testing.T) {
+	db := newTestDB(t)
+	repo := NewMaintenanceRepository(db)
+	now := time.Now()
+
+	// createDeleted creates a post of a tenant that was deleted at the given time
+	createDeleted := func(tenantID string, deletedAt time.Time) *models.Blog {
+		blog := newTestBlog("Deleted", "", "go")
+		require.NoError(t, NewBlogRepository(db).WithTenant(tenantID).Create(blog))
+		require.NoError(t, db.Model(blog).Update("deleted_at", deletedAt).Error)
+		return blog
+	}
+	old := createDeleted("acme", now.Add(-48*time.Hour))
+	recent := createDeleted("acme", now.Add(-time.Hour))
+	otherTenant := createDeleted("globex", now.Add(-48*time.Hour))
+	live := newTestBlog("Live", "live", "go")
+	require.NoError(t, NewBlogRepository(db).WithTenant("acme").Create(live))
+
+	// exists reports whether a post is still stored, deleted or not
+	exists := func(blog *models.Blog) bool {
+		var count int64
+		require.NoError(t, db.Unscoped().Model(&models.Blog{}).Where("id = ?", blog.ID).Count(&count).Error)
+		return count > 0
+	}
+
+	purged, err := repo.PurgeDeleted("acme", now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	assert.False(t, exists(old))
+	assert.True(t, exists(recent), "deleted too recently")
+	assert.True(t, exists(otherTenant), "other tenant")
+	assert.True(t, exists(live))
+
+	var tags int64
+	require.NoError(t, db.Model(&models.BlogTag{}).Where("blog_id = ?", old.ID).Count(&tags).Error)
+	assert.Zero(t, tags, "tags of purged posts are removed")
+
+	// Without a tenant, the trash of every tenant is purged
+	purged, err = repo.PurgeDeleted("", now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+	assert.True(t, exists(live))
+	assert.NoError(t, db.First(&models.Blog{}, "id = ?", live.ID).Error)
+	assert.ErrorIs(t, db.Unscoped().First(&models.Blog{}, "id = ?", recent.ID).Error, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,505 @@
+package repository
+
+import (
+	"BlogManagment/internal/models"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// memoryBlogState is a snapshot of the posts, events and audit entries of a memory store.
+// Snapshots are never modified once published; writes modify a clone and publish it.
+type memoryBlogState struct {
+	blogs  map[string]*models.Blog
+	events []*models.BlogEvent
+	audit  []*models.AuditEntry
+}
+
+// clone returns a copy of the snapshot that can be modified without affecting it. Posts are
+// shared, so they must be replaced rather than modified.
+func (s *memoryBlogState) clone() *memoryBlogState {
+	blogs := make(map[string]*models.Blog, len(s.blogs))
+	for id, blog := range s.blogs {
+		blogs[id] = blog
+	}
+	return &memoryBlogState{
+		blogs:  blogs,
+		events: s.events[:len(s.events):len(s.events)],
+		audit:  s.audit[:len(s.audit):len(s.audit)],
+	}
+}
+
+// memoryBlogStore holds the posts of every tenant of a memory repository
+type memoryBlogStore struct {
+	mu          sync.RWMutex
+	state       *memoryBlogState
+	nextAuditID int64
+	now         func() time.Time
+	// outbox receives the events and audit entries of every committed change; nil keeps them
+	// in memory only
+	outbox BlogRepository
+}
+
+// memoryBlogRepository implements BlogRepository in memory, for tests and for running the API
+// without a database. It behaves like the database repository: posts are scoped to a tenant,
+// deleted posts are kept but hidden, and transactions are isolated and atomic. Transactions
+// are serialised, so they must not use other repositories of the same store.
+type memoryBlogRepository struct {
+	store    *memoryBlogStore
+	tenantID string
+	// tx is the snapshot of the transaction the repository is bound to, nil outside one
+	tx *memoryBlogState
+}
+
+// NewMemoryBlogRepository creates an empty in-memory blog repository for the default tenant.
+// It is safe for concurrent use. Its events are kept in memory only.
+func NewMemoryBlogRepository() BlogRepository {
+	return NewMemoryBlogRepositoryWithOutbox(nil)
+}
+
+// NewMemoryBlogRepositoryWithOutbox creates an empty in-memory blog repository that also
+// appends the events and audit entries of every change to outbox, typically a database
+// repository, so that outbox relays, event streams and the audit log see them. A change is
+// only kept if they are appended.
+func NewMemoryBlogRepositoryWithOutbox(outbox BlogRepository) BlogRepository {
+	store := &memoryBlogStore{
+		state:  &memoryBlogState{blogs: make(map[string]*models.Blog)},
+		now:    time.Now,
+		outbox: outbox,
+	}
+	return &memoryBlogRepository{store: store, tenantID: models.DefaultTenantID}
+}
+
+// WithTenant returns a repository for the posts of another tenant
+func (r *memoryBlogRepository) WithTenant(tenantID string) BlogRepository {
+	return &memoryBlogRepository{store: r.store, tenantID: tenantID, tx: r.tx}
+}
+
+// WithSession returns the repository itself; every read sees every committed write
+func (r *memoryBlogRepository) WithSession(session string) BlogRepository {
+	return r
+}
+
+// Primary returns the repository itself, since it has no replicas
+func (r *memoryBlogRepository) Primary() BlogRepository {
+	return r
+}
+
+// read runs fn on the current snapshot
+func (r *memoryBlogRepository) read(fn func(state *memoryBlogState) error) error {
+	if r.tx != nil {
+		return fn(r.tx)
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	return fn(r.store.state)
+}
+
+// write runs fn on a clone of the current snapshot and publishes it if fn succeeds, so that
+// every write is atomic
+func (r *memoryBlogRepository) write(fn func(state *memoryBlogState) error) error {
+	if r.tx != nil {
+		return fn(r.tx)
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	staged := r.store.state.clone()
+	if err := fn(staged); err != nil {
+		return err
+	}
+	if err := r.store.appendToOutbox(staged.events[len(r.store.state.events):], staged.audit[len(r.store.state.audit):]); err != nil {
+		return err
+	}
+	r.store.state = staged
+	return nil
+}
+
+// appendToOutbox appends the events and audit entries of a change to the outbox, one
+// transaction per tenant. The outbox numbers the audit entries itself.
+func (s *memoryBlogStore) appendToOutbox(events []*models.BlogEvent, entries []*models.AuditEntry) error {
+	if s.outbox == nil {
+		return nil
+	}
+
+	var tenants []string
+	seen := make(map[string]bool)
+	addTenant := func(tenantID string) {
+		if !seen[tenantID] {
+			seen[tenantID] = true
+			tenants = append(tenants, tenantID)
+		}
+	}
+
+	tenantEvents := make(map[string][]*models.BlogEvent)
+	for _, event := range events {
+		addTenant(event.TenantID)
+		copied := *event
+		tenantEvents[event.TenantID] = append(tenantEvents[event.TenantID], &copied)
+	}
+	tenantEntries := make(map[string][]*models.AuditEntry)
+	for _, entry := range entries {
+		addTenant(entry.TenantID)
+		copied := *entry
+		copied.ID = 0
+		tenantEntries[entry.TenantID] = append(tenantEntries[entry.TenantID], &copied)
+	}
+
+	for _, tenantID := range tenants {
+		err := s.outbox.WithTenant(tenantID).Transaction(func(repo BlogRepository) error {
+			if err := repo.AppendEvents(tenantEvents[tenantID]); err != nil {
+				return err
+			}
+			return repo.AppendAudit(tenantEntries[tenantID])
+		})
+		if err != nil {
+			return fmt.Errorf("failed to append to the outbox: %w", err)
+		}
+	}
+	return nil
+}
+
+// Create adds a new blog post. A slug that is already taken gets a numeric suffix.
+func (r *memoryBlogRepository) Create(blog *models.Blog) error {
+	return r.CreateBatch([]*models.Blog{blog})
+}
+
+// CreateBatch adds several blog posts; either all of them are added or none
+func (r *memoryBlogRepository) CreateBatch(blogs []*models.Blog) error {
+	if len(blogs) == 0 {
+		return nil
+	}
+	return r.write(func(state *memoryBlogState) error {
+		now := r.store.now()
+		used := r.usedSlugs(state)
+		for _, blog := range blogs {
+			if _, exists := state.blogs[blog.ID]; exists {
+				return fmt.Errorf("blog post %s already exists", blog.ID)
+			}
+			r.assignTenant(blog)
+			if blog.Slug != "" {
+				slug := blog.Slug
+				for n := 2; used[slug]; n++ {
+					slug = fmt.Sprintf("%s-%d", blog.Slug, n)
+				}
+				blog.Slug = slug
+				used[slug] = true
+			}
+			if blog.CreatedAt.IsZero() {
+				blog.CreatedAt = now
+			}
+			if blog.UpdatedAt.IsZero() {
+				blog.UpdatedAt = now
+			}
+			state.blogs[blog.ID] = copyBlog(blog)
+		}
+		return nil
+	})
+}
+
+// GetByID retrieves a blog post by its ID
+func (r *memoryBlogRepository) GetByID(id string) (*models.Blog, error) {
+	var found *models.Blog
+	err := r.read(func(state *memoryBlogState) error {
+		blog, ok := state.blogs[id]
+		if !ok || !r.visible(blog) {
+			return ErrBlogNotFound
+		}
+		found = copyBlog(blog)
+		return nil
+	})
+	return found, err
+}
+
+// GetByIDs retrieves the blog posts with the given IDs; missing IDs are skipped
+func (r *memoryBlogRepository) GetByIDs(ids []string) ([]models.Blog, error) {
+	blogs := []models.Blog{}
+	err := r.read(func(state *memoryBlogState) error {
+		seen := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			if blog, ok := state.blogs[id]; ok && r.visible(blog) && !seen[id] {
+				blogs = append(blogs, *copyBlog(blog))
+				seen[id] = true
+			}
+		}
+		return nil
+	})
+	return blogs, err
+}
+
+// GetBySlug retrieves a blog post by its slug
+func (r *memoryBlogRepository) GetBySlug(slug string) (*models.Blog, error) {
+	blogs, err := r.find(func(blog *models.Blog) bool { return blog.Slug == slug }, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(blogs) == 0 {
+		return nil, ErrBlogNotFound
+	}
+	return &blogs[0], nil
+}
+
+// GetAll retrieves all blog posts, newest first
+func (r *memoryBlogRepository) GetAll() ([]models.Blog, error) {
+	return r.find(nil, newestFirst)
+}
+
+// GetPublished retrieves every published blog post, most recently published first
+func (r *memoryBlogRepository) GetPublished() ([]models.Blog, error) {
+	return r.find(func(blog *models.Blog) bool { return blog.IsPublished() }, func(a, b *models.Blog) bool {
+		publishedA, publishedB := publicationTime(a), publicationTime(b)
+		if !publishedA.Equal(publishedB) {
+			return publishedA.After(publishedB)
+		}
+		return a.ID > b.ID
+	})
+}
+
+// ListAfter retrieves up to limit blog posts in (created_at, id) order,
+// starting after the given cursor or from the oldest post when cursor is nil
+func (r *memoryBlogRepository) ListAfter(cursor *models.BlogCursor, limit int) ([]models.Blog, error) {
+	blogs, err := r.find(func(blog *models.Blog) bool {
+		return cursor == nil || blog.CreatedAt.After(cursor.CreatedAt) || (blog.CreatedAt.Equal(cursor.CreatedAt) && blog.ID > cursor.ID)
+	}, func(a, b *models.Blog) bool { return newestFirst(b, a) })
+	return truncateBlogs(blogs, limit), err
+}
+
+// List retrieves up to limit blog posts matching the filter, newest first in (created_at, id)
+// order, starting before the given cursor or from the newest post when cursor is nil.
+// Tags are matched case-insensitively.
+func (r *memoryBlogRepository) List(filter *models.BlogFilter, before *models.BlogCursor, limit int) ([]models.Blog, error) {
+	blogs, err := r.find(func(blog *models.Blog) bool {
+		if filter.Status != "" && blog.Status != filter.Status {
+			return false
+		}
+		if hiddenBy(filter, blog) {
+			return false
+		}
+		if filter.Tag != "" && !hasTag(blog, filter.Tag) {
+			return false
+		}
+		return before == nil || blog.CreatedAt.Before(before.CreatedAt) || (blog.CreatedAt.Equal(before.CreatedAt) && blog.ID < before.ID)
+	}, newestFirst)
+	return truncateBlogs(blogs, limit), err
+}
+
+// CountByTags returns the number of blog posts carrying each of the given tags.
+// Tags match without case, like the tag filter of List. Tags without posts are missing from the result.
+// The drafts hidden by the PublishedOnly and DraftsOf fields of filter are left out if it is given.
+func (r *memoryBlogRepository) CountByTags(names []string, filter *models.BlogFilter) (map[string]int, error) {
+	counts := make(map[string]int, len(names))
+	err := r.read(func(state *memoryBlogState) error {
+		for _, blog := range state.blogs {
+			if !r.visible(blog) || hiddenBy(filter, blog) {
+				continue
+			}
+			for _, name := range names {
+				if hasTag(blog, name) {
+					counts[name]++
+				}
+			}
+		}
+		return nil
+	})
+	return counts, err
+}
+
+// Update modifies an existing blog post and replaces its tags
+func (r *memoryBlogRepository) Update(blog *models.Blog) error {
+	return r.write(func(state *memoryBlogState) error {
+		if blog.Slug != "" {
+			for _, other := range state.blogs {
+				if r.visible(other) && other.ID != blog.ID && other.Slug == blog.Slug {
+					return ErrSlugConflict
+				}
+			}
+		}
+		existing, ok := state.blogs[blog.ID]
+		if !ok || !r.visible(existing) {
+			return ErrBlogNotFound
+		}
+
+		r.assignTenant(blog)
+		if blog.CreatedAt.IsZero() {
+			blog.CreatedAt = existing.CreatedAt
+		}
+		blog.UpdatedAt = r.store.now()
+		state.blogs[blog.ID] = copyBlog(blog)
+		return nil
+	})
+}
+
+// Delete hides a blog post; it is kept in the store, like a soft-deleted row
+func (r *memoryBlogRepository) Delete(id string) error {
+	return r.write(func(state *memoryBlogState) error {
+		existing, ok := state.blogs[id]
+		if !ok || !r.visible(existing) {
+			return ErrBlogNotFound
+		}
+		deleted := copyBlog(existing)
+		deleted.DeletedAt = gorm.DeletedAt{Time: r.store.now(), Valid: true}
+		state.blogs[id] = deleted
+		return nil
+	})
+}
+
+// Transaction runs fn with a repository bound to a snapshot of the store. The snapshot is
+// published when fn returns nil and discarded otherwise. Transactions run one at a time.
+func (r *memoryBlogRepository) Transaction(fn func(repo BlogRepository) error) error {
+	return r.write(func(state *memoryBlogState) error {
+		staged := state.clone()
+		if err := fn(&memoryBlogRepository{store: r.store, tenantID: r.tenantID, tx: staged}); err != nil {
+			return err
+		}
+		*state = *staged
+		return nil
+	})
+}
+
+// AppendEvents records events, in the outbox of the repository too if it has one. Called
+// inside Transaction, the events are only recorded if the change they describe is committed.
+func (r *memoryBlogRepository) AppendEvents(events []*models.BlogEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return r.write(func(state *memoryBlogState) error {
+		for _, event := range events {
+			event.TenantID = r.tenantID
+			copied := *event
+			state.events = append(state.events, &copied)
+		}
+		return nil
+	})
+}
+
+// AppendAudit adds entries to the audit log. Called inside Transaction, the entries are only
+// kept if the change they describe is committed.
+func (r *memoryBlogRepository) AppendAudit(entries []*models.AuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return r.write(func(state *memoryBlogState) error {
+		for _, entry := range entries {
+			// IDs are only taken by the transaction holding the lock, like a database sequence
+			r.store.nextAuditID++
+			entry.ID = r.store.nextAuditID
+			entry.TenantID = r.tenantID
+			if entry.CreatedAt.IsZero() {
+				entry.CreatedAt = r.store.now()
+			}
+			copied := *entry
+			state.audit = append(state.audit, &copied)
+		}
+		return nil
+	})
+}
+
+// find returns copies of the visible posts of the tenant matching match, in less order.
+// Ties are broken by ID so that results are stable.
+func (r *memoryBlogRepository) find(match func(blog *models.Blog) bool, less func(a, b *models.Blog) bool) ([]models.Blog, error) {
+	var matched []*models.Blog
+	err := r.read(func(state *memoryBlogState) error {
+		for _, blog := range state.blogs {
+			if r.visible(blog) && (match == nil || match(blog)) {
+				matched = append(matched, blog)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		if less != nil {
+			return less(matched[i], matched[j])
+		}
+		return matched[i].ID < matched[j].ID
+	})
+	blogs := make([]models.Blog, len(matched))
+	for i, blog := range matched {
+		blogs[i] = *copyBlog(blog)
+	}
+	return blogs, nil
+}
+
+// usedSlugs returns the slugs of the visible posts of the tenant
+func (r *memoryBlogRepository) usedSlugs(state *memoryBlogState) map[string]bool {
+	used := make(map[string]bool)
+	for _, blog := range state.blogs {
+		if r.visible(blog) && blog.Slug != "" {
+			used[blog.Slug] = true
+		}
+	}
+	return used
+}
+
+// visible reports whether a post belongs to the tenant of the repository and is not deleted
+func (r *memoryBlogRepository) visible(blog *models.Blog) bool {
+	return blog.TenantID == r.tenantID && !blog.DeletedAt.Valid
+}
+
+// hiddenBy reports whether a filter with PublishedOnly set leaves out an unpublished post
+func hiddenBy(filter *models.BlogFilter, blog *models.Blog) bool {
+	return filter != nil && filter.PublishedOnly && !blog.IsPublished() && (filter.DraftsOf == "" || blog.AuthorID != filter.DraftsOf)
+}
+
+// assignTenant assigns a post and its tags to the tenant of the repository
+func (r *memoryBlogRepository) assignTenant(blog *models.Blog) {
+	blog.TenantID = r.tenantID
+	for i := range blog.Tags {
+		blog.Tags[i].TenantID = r.tenantID
+		blog.Tags[i].BlogID = blog.ID
+	}
+}
+
+// copyBlog returns a deep copy of a post with its tags in name order, as the database
+// repository loads them
+func copyBlog(blog *models.Blog) *models.Blog {
+	copied := *blog
+	copied.Tags = append([]models.BlogTag{}, blog.Tags...)
+	sort.Slice(copied.Tags, func(i, j int) bool { return copied.Tags[i].Name < copied.Tags[j].Name })
+	if blog.PublishedAt != nil {
+		publishedAt := *blog.PublishedAt
+		copied.PublishedAt = &publishedAt
+	}
+	copied.Tenant = nil
+	return &copied
+}
+
+// newestFirst orders posts by (created_at, id), newest first
+func newestFirst(a, b *models.Blog) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return a.ID > b.ID
+}
+
+// publicationTime returns when a post was published, or created if that is not known
+func publicationTime(blog *models.Blog) time.Time {
+	if blog.PublishedAt != nil {
+		return *blog.PublishedAt
+	}
+	return blog.CreatedAt
+}
+
+// hasTag reports whether a post carries a tag, ignoring case
+func hasTag(blog *models.Blog, name string) bool {
+	for _, tag := range blog.Tags {
+		if strings.EqualFold(tag.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateBlogs keeps the first limit posts
+func truncateBlogs(blogs []models.Blog, limit int) []models.Blog {
+	if limit >= 0 && len(blogs) > limit {
+		return blogs[:limit]
+	}
+	return blogs
+}
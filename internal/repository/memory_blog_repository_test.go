@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"BlogManagment/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEvent(blog *models.Blog) *models.BlogEvent {
+	return &models.BlogEvent{
+		ID:         uuid.New().String(),
+		Type:       models.EventPostCreated,
+		OccurredAt: time.Now().UTC(),
+		Data:       &models.BlogResponse{ID: blog.ID, Title: blog.Title},
+	}
+}
+
+func TestMemoryBlogRepository_AppendsEventsToOutbox(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.OutboxEvent{}))
+	repo := NewMemoryBlogRepositoryWithOutbox(NewBlogRepository(db)).WithTenant("acme")
+
+	blog := newTestBlog("Hello", "hello")
+	require.NoError(t, repo.Transaction(func(tx BlogRepository) error {
+		if err := tx.Create(blog); err != nil {
+			return err
+		}
+		return tx.AppendEvents([]*models.BlogEvent{newTestEvent(blog)})
+	}))
+	require.NoError(t, repo.AppendEvents([]*models.BlogEvent{newTestEvent(blog)}))
+
+	events, err := NewOutboxRepository(db).ListAfter(0, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	for _, event := range events {
+		assert.Equal(t, "acme", event.TenantID)
+		assert.Equal(t, blog.ID, event.AggregateID)
+	}
+
+	// Events of a change that is rolled back never reach the outbox
+	discarded := newTestBlog("Discarded", "discarded")
+	assert.Error(t, repo.Transaction(func(tx BlogRepository) error {
+		if err := tx.AppendEvents([]*models.BlogEvent{newTestEvent(discarded)}); err != nil {
+			return err
+		}
+		return ErrBlogNotFound
+	}))
+	events, err = NewOutboxRepository(db).ListAfter(0, 10)
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+}
+
+func TestMemoryBlogRepository_AppendsAuditEntriesToOutbox(t *testing.T) {
+	db := newAuditTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.OutboxEvent{}))
+	repo := NewMemoryBlogRepositoryWithOutbox(NewBlogRepository(db)).WithTenant("acme")
+
+	blog := newTestBlog("Hello", "hello")
+	require.NoError(t, repo.Transaction(func(tx BlogRepository) error {
+		if err := tx.Create(blog); err != nil {
+			return err
+		}
+		if err := tx.AppendEvents([]*models.BlogEvent{newTestEvent(blog)}); err != nil {
+			return err
+		}
+		return tx.AppendAudit([]*models.AuditEntry{newTestAuditEntry(models.AuditActionPostCreate, blog.ID, "alice", time.Now())})
+	}))
+
+	entries, err := NewAuditRepository(db).List("acme", &models.AuditFilter{}, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, blog.ID, entries[0].TargetID)
+	assert.Equal(t, "alice", entries[0].Subject)
+}
+
+func TestMemoryBlogRepository_KeepsNoChangeWithoutItsEvents(t *testing.T) {
+	// The outbox table is missing, so appending to it fails
+	repo := NewMemoryBlogRepositoryWithOutbox(NewBlogRepository(newTestDB(t)))
+
+	blog := newTestBlog("Hello", "hello")
+	err := repo.Transaction(func(tx BlogRepository) error {
+		if err := tx.Create(blog); err != nil {
+			return err
+		}
+		return tx.AppendEvents([]*models.BlogEvent{newTestEvent(blog)})
+	})
+	assert.ErrorContains(t, err, "outbox")
+
+	_, err = repo.GetByID(blog.ID)
+	assert.ErrorIs(t, err, ErrBlogNotFound)
+}
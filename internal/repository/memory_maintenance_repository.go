@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"BlogManagment/internal/models"
+	"sort"
+	"time"
+)
+
+// memoryMaintenanceRepository implements MaintenanceRepository for the posts of a memory store
+type memoryMaintenanceRepository struct {
+	store *memoryBlogStore
+}
+
+// NewMemoryMaintenanceRepository creates a maintenance repository for the posts of the store of
+// blogRepo, which must be a memory repository
+func NewMemoryMaintenanceRepository(blogRepo BlogRepository) MaintenanceRepository {
+	return &memoryMaintenanceRepository{store: blogRepo.(*memoryBlogRepository).store}
+}
+
+// PostsWithoutSlug retrieves the posts of every tenant that have no slug, oldest first
+func (r *memoryMaintenanceRepository) PostsWithoutSlug() ([]models.Blog, error) {
+	r.store.mu.RLock()
+	var blogs []models.Blog
+	for _, blog := range r.store.state.blogs {
+		if !blog.DeletedAt.Valid && blog.Slug == "" {
+			blogs = append(blogs, *copyBlog(blog))
+		}
+	}
+	r.store.mu.RUnlock()
+
+	sort.Slice(blogs, func(i, j int) bool {
+		if !blogs[i].CreatedAt.Equal(blogs[j].CreatedAt) {
+			return blogs[i].CreatedAt.Before(blogs[j].CreatedAt)
+		}
+		return blogs[i].ID < blogs[j].ID
+	})
+	return blogs, nil
+}
+
+// RebuildIndexes does nothing, since a memory store has no indexes to rebuild
+func (r *memoryMaintenanceRepository) RebuildIndexes() ([]string, error) {
+	return []string{}, nil
+}
+
+// PurgeDeleted permanently removes the posts deleted before the given time from one tenant, or
+// from every tenant if tenantID is empty. It returns the number of posts removed.
+func (r *memoryMaintenanceRepository) PurgeDeleted(tenantID string, before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	staged := r.store.state.clone()
+	var purged int64
+	for id, blog := range staged.blogs {
+		if blog.DeletedAt.Valid && blog.DeletedAt.Time.Before(before) && (tenantID == "" || blog.TenantID == tenantID) {
+			delete(staged.blogs, id)
+			purged++
+		}
+	}
+	r.store.state = staged
+	return purged, nil
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"BlogManagment/internal/models"
+	"errors"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrCursorLocked is returned when another relay is processing events for the same sink
+var ErrCursorLocked = errors.New("outbox cursor is locked by another relay")
+
+// OutboxRepository defines the interface for reading the event outbox and tracking sink cursors
+type OutboxRepository interface {
+	ListAfter(position int64, limit int) ([]models.OutboxEvent, error)
+	LastSequence() (int64, error)
+	Advance(sink string, fn func(position int64) (int64, error)) error
+	DeleteProcessed(sinks []string, before time.Time) (int64, error)
+}
+
+// outboxRepository implements OutboxRepository interface
+type outboxRepository struct {
+	db *gorm.DB
+	// mu serialises relays on databases without row locks
+	mu sync.Mutex
+}
+
+// NewOutboxRepository creates a new outbox repository instance
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// ListAfter retrieves up to limit events following position, in sequence order
+func (r *outboxRepository) ListAfter(position int64, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	result := r.db.Where("sequence > ?", position).Order("sequence ASC").Limit(limit).Find(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return events, nil
+}
+
+// LastSequence returns the sequence number of the newest event, or 0 when the outbox is empty
+func (r *outboxRepository) LastSequence() (int64, error) {
+	var sequence int64
+	result := r.db.Model(&models.OutboxEvent{}).Select("COALESCE(MAX(sequence), 0)").Scan(&sequence)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return sequence, nil
+}
+
+// Advance locks the cursor of a sink, creating it at position 0 on first use, and stores the
+// position returned by fn. The lock is held while fn runs so that only one relay processes a
+// sink at a time; ErrCursorLocked is returned without calling fn when another relay holds it.
+// When fn fails the cursor is left unchanged.
+func (r *outboxRepository) Advance(sink string, fn func(position int64) (int64, error)) error {
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.OutboxCursor{Sink: sink}).Error; err != nil {
+		return err
+	}
+	if r.db.Dialector.Name() != "postgres" {
+		return r.advanceLocally(sink, fn)
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var cursor models.OutboxCursor
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("sink = ?", sink).
+			Limit(1).
+			Find(&cursor)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrCursorLocked
+		}
+
+		position, err := fn(cursor.Position)
+		if err != nil || position == cursor.Position {
+			return err
+		}
+		return tx.Model(&cursor).Update("position", position).Error
+	})
+}
+
+// advanceLocally is Advance for SQLite, which allows a single writer at a time: a transaction
+// holding the cursor would block the writes of sinks until it timed out. Only one process uses
+// an SQLite database, so relays are serialised in memory instead.
+func (r *outboxRepository) advanceLocally(sink string, fn func(position int64) (int64, error)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var cursor models.OutboxCursor
+	if err := r.db.Where("sink = ?", sink).First(&cursor).Error; err != nil {
+		return err
+	}
+	position, err := fn(cursor.Position)
+	if err != nil || position == cursor.Position {
+		return err
+	}
+	return r.db.Model(&cursor).Update("position", position).Error
+}
+
+// DeleteProcessed removes events created before the given time that every listed sink has
+// processed, and returns the number of deleted events
+func (r *outboxRepository) DeleteProcessed(sinks []string, before time.Time) (int64, error) {
+	if len(sinks) == 0 {
+		return 0, nil
+	}
+
+	// A sink without a cursor has not processed anything yet
+	var cursors []models.OutboxCursor
+	if err := r.db.Where("sink IN ?", sinks).Find(&cursors).Error; err != nil {
+		return 0, err
+	}
+	if len(cursors) < len(sinks) {
+		return 0, nil
+	}
+
+	processed := cursors[0].Position
+	for _, cursor := range cursors[1:] {
+		processed = min(processed, cursor.Position)
+	}
+
+	result := r.db.Where("sequence <= ? AND created_at < ?", processed, before).Delete(&models.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
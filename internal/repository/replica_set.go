@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Replica is a read replica of the primary database
+type Replica struct {
+	Name string
+	DB   *gorm.DB
+}
+
+// ReplicaStatus is the outcome of the last health check of a replica
+type ReplicaStatus struct {
+	Name      string        `json:"name"`
+	Healthy   bool          `json:"healthy"`
+	Lag       time.Duration `json:"lag"`
+	Error     string        `json:"error,omitempty"`
+	CheckedAt time.Time     `json:"checked_at"`
+}
+
+// replica is a replica together with the outcome of its last health check
+type replica struct {
+	Replica
+	mu     sync.RWMutex
+	status ReplicaStatus
+}
+
+// healthy reports whether the replica may serve reads
+func (r *replica) healthy() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.status.Healthy
+}
+
+// ReplicaSet spreads reads over the replicas of a primary database. A replica only serves
+// reads once a health check has found it trailing the primary by at most maxLag, and callers
+// that wrote within the last window read from the primary so that they see their own writes.
+type ReplicaSet struct {
+	primary    *gorm.DB
+	replicas   []*replica
+	maxLag     time.Duration
+	window     time.Duration
+	next       atomic.Uint64
+	now        func() time.Time
+	measureLag func(ctx context.Context, db *gorm.DB) (time.Duration, error)
+
+	mu     sync.Mutex
+	writes map[string]time.Time
+}
+
+// NewReplicaSet creates a replica set. Replicas are out of rotation until the first Check.
+func NewReplicaSet(primary *gorm.DB, replicas []Replica, maxLag, window time.Duration) *ReplicaSet {
+	s := &ReplicaSet{
+		primary:    primary,
+		maxLag:     maxLag,
+		window:     window,
+		now:        time.Now,
+		measureLag: measureReplicationLag,
+		writes:     make(map[string]time.Time),
+	}
+	for _, r := range replicas {
+		s.replicas = append(s.replicas, &replica{Replica: r, status: ReplicaStatus{Name: r.Name}})
+	}
+	return s
+}
+
+// Primary returns the primary database
+func (s *ReplicaSet) Primary() *gorm.DB {
+	return s.primary
+}
+
+// Reader returns the database the session should read from: the primary if the session wrote
+// within the window or no replica is healthy, and otherwise the next healthy replica in turn
+func (s *ReplicaSet) Reader(session string) *gorm.DB {
+	if s.wroteRecently(session) {
+		return s.primary
+	}
+
+	start := s.next.Add(1)
+	for i := range s.replicas {
+		r := s.replicas[(start+uint64(i))%uint64(len(s.replicas))]
+		if r.healthy() {
+			return r.DB
+		}
+	}
+	return s.primary
+}
+
+// MarkWrite records that the session has just written to the primary
+func (s *ReplicaSet) MarkWrite(session string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writes[session] = s.now()
+}
+
+// wroteRecently reports whether the session wrote within the window
+func (s *ReplicaSet) wroteRecently(session string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	wroteAt, ok := s.writes[session]
+	return ok && s.now().Sub(wroteAt) < s.window
+}
+
+// Check measures how far every replica trails the primary, taking replicas that lag by more
+// than maxLag or cannot be reached out of rotation and returning those that caught up. It also
+// forgets the sessions whose window has passed.
+func (s *ReplicaSet) Check(ctx context.Context) error {
+	for _, r := range s.replicas {
+		lag, err := s.measureLag(ctx, r.DB)
+		status := ReplicaStatus{Name: r.Name, Healthy: err == nil && lag <= s.maxLag, Lag: lag, CheckedAt: s.now()}
+		if err != nil {
+			status.Error = err.Error()
+		}
+
+		r.mu.Lock()
+		wasHealthy := r.status.Healthy
+		r.status = status
+		r.mu.Unlock()
+
+		switch {
+		case wasHealthy && !status.Healthy && err != nil:
+			log.Printf("Replica %s removed from rotation: %v", r.Name, err)
+		case wasHealthy && !status.Healthy:
+			log.Printf("Replica %s removed from rotation: %s behind the primary", r.Name, lag)
+		case !wasHealthy && status.Healthy:
+			log.Printf("Replica %s in rotation, %s behind the primary", r.Name, lag)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for session, wroteAt := range s.writes {
+		if s.now().Sub(wroteAt) >= s.window {
+			delete(s.writes, session)
+		}
+	}
+	return nil
+}
+
+// Status returns the outcome of the last health check of every replica
+func (s *ReplicaSet) Status() []ReplicaStatus {
+	statuses := make([]ReplicaStatus, len(s.replicas))
+	for i, r := range s.replicas {
+		r.mu.RLock()
+		statuses[i] = r.status
+		r.mu.RUnlock()
+	}
+	return statuses
+}
+
+// measureReplicationLag returns how long ago a PostgreSQL standby replayed the last
+// transaction it received. A standby that has replayed everything it received is not behind,
+// however long ago that was, and a server that is not a standby never is. Other databases
+// only need to answer.
+func measureReplicationLag(ctx context.Context, db *gorm.DB) (time.Duration, error) {
+	db = db.WithContext(ctx)
+	if db.Dialector.Name() != "postgres" {
+		return 0, db.Exec("SELECT 1").Error
+	}
+
+	var seconds float64
+	err := db.Raw(`SELECT CASE
+		WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END`).Scan(&seconds).Error
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
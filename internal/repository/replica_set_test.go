@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newTestReplicaSet returns a replica set over separate databases, so that the database a read
+// went to can be told by its result, with a clock and replica lags the test controls
+func newTestReplicaSet(t *testing.T, replicas int) (*ReplicaSet, []*gorm.DB, *time.Time, map[*gorm.DB]time.Duration) {
+	t.Helper()
+	var dbs []*gorm.DB
+	var members []Replica
+	for i := 0; i < replicas; i++ {
+		db := newTestDB(t)
+		dbs = append(dbs, db)
+		members = append(members, Replica{Name: fmt.Sprintf("replica-%d", i+1), DB: db})
+	}
+
+	now := time.Now()
+	lags := make(map[*gorm.DB]time.Duration)
+	set := NewReplicaSet(newTestDB(t), members, 5*time.Second, 10*time.Second)
+	set.now = func() time.Time { return now }
+	set.measureLag = func(ctx context.Context, db *gorm.DB) (time.Duration, error) {
+		if lag, ok := lags[db]; ok && lag < 0 {
+			return 0, errors.New("connection refused")
+		}
+		return lags[db], nil
+	}
+	return set, dbs, &now, lags
+}
+
+func TestReplicaSet_ReadsGoToHealthyReplicas(t *testing.T) {
+	set, replicas, _, _ := newTestReplicaSet(t, 2)
+	repo := NewReplicatedBlogRepository(set)
+
+	// Replicas are out of rotation until they have been checked
+	assert.Same(t, set.Primary(), set.Reader(""))
+
+	require.NoError(t, set.Check(context.Background()))
+	first, second := set.Reader(""), set.Reader("")
+	assert.NotSame(t, first, second)
+	assert.Contains(t, []*gorm.DB{replicas[0], replicas[1]}, first)
+	assert.Contains(t, []*gorm.DB{replicas[0], replicas[1]}, second)
+
+	// A post only the replicas have is found through them
+	blog := newTestBlog("Replicated", "replicated")
+	require.NoError(t, NewBlogRepository(replicas[0]).Create(blog))
+	require.NoError(t, NewBlogRepository(replicas[1]).Create(newTestBlog("Replicated", "replicated")))
+	blogs, err := repo.GetAll()
+	require.NoError(t, err)
+	assert.Len(t, blogs, 1)
+
+	// Reads of a repository that must see the primary skip the replicas
+	blogs, err = repo.Primary().GetAll()
+	require.NoError(t, err)
+	assert.Empty(t, blogs)
+}
+
+func TestReplicaSet_ReadYourWrites(t *testing.T) {
+	set, _, now, _ := newTestReplicaSet(t, 1)
+	require.NoError(t, set.Check(context.Background()))
+	alice := NewReplicatedBlogRepository(set).WithSession("user:alice")
+	bob := NewReplicatedBlogRepository(set).WithSession("user:bob")
+
+	blog := newTestBlog("Written", "written")
+	require.NoError(t, alice.Create(blog))
+
+	// The writer reads from the primary, other sessions from the replica that has not caught up
+	found, err := alice.GetByID(blog.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Written", found.Title)
+	_, err = bob.GetByID(blog.ID)
+	assert.ErrorIs(t, err, ErrBlogNotFound)
+
+	// Writes through a transaction count, and so do writes of other tenants of the session
+	require.NoError(t, bob.WithTenant("acme").Transaction(func(repo BlogRepository) error {
+		return nil
+	}))
+	_, err = bob.GetByID(blog.ID)
+	assert.NoError(t, err)
+
+	// Once the window has passed, the session reads from the replica again
+	*now = now.Add(10 * time.Second)
+	_, err = alice.GetByID(blog.ID)
+	assert.ErrorIs(t, err, ErrBlogNotFound)
+
+	require.NoError(t, set.Check(context.Background()))
+	assert.Empty(t, set.writes)
+}
+
+func TestReplicaSet_LaggingReplicasLeaveRotation(t *testing.T) {
+	set, replicas, _, lags := newTestReplicaSet(t, 2)
+	lags[replicas[0]] = 6 * time.Second
+	lags[replicas[1]] = time.Second
+	require.NoError(t, set.Check(context.Background()))
+
+	for i := 0; i < 4; i++ {
+		assert.Same(t, replicas[1], set.Reader(""))
+	}
+
+	lags[replicas[1]] = -1
+	require.NoError(t, set.Check(context.Background()))
+	assert.Same(t, set.Primary(), set.Reader(""))
+
+	status := set.Status()
+	require.Len(t, status, 2)
+	assert.False(t, status[0].Healthy)
+	assert.Equal(t, 6*time.Second, status[0].Lag)
+	assert.False(t, status[1].Healthy)
+	assert.Equal(t, "connection refused", status[1].Error)
+
+	// Replicas that catch up return to rotation
+	lags[replicas[0]] = 0
+	require.NoError(t, set.Check(context.Background()))
+	assert.Same(t, replicas[0], set.Reader(""))
+}
+
+func TestMeasureReplicationLag(t *testing.T) {
+	lag, err := measureReplicationLag(context.Background(), newTestDB(t))
+	require.NoError(t, err)
+	assert.Zero(t, lag)
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"BlogManagment/internal/models"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrRoleAssignmentNotFound is returned when a subject does not hold a role
+var ErrRoleAssignmentNotFound = errors.New("role assignment not found")
+
+// RoleRepository defines the interface for role assignments and the log of denied requests.
+// Every method is restricted to a single tenant.
+type RoleRepository interface {
+	Assign(assignment *models.RoleAssignment) error
+	Revoke(tenantID, subject, role string) error
+	GetRoles(tenantID, subject string) ([]string, error)
+	List(tenantID, subject string) ([]models.RoleAssignment, error)
+	RecordDenial(denial *models.AccessDenial) error
+	ListDenials(tenantID, subject string, limit int) ([]models.AccessDenial, error)
+}
+
+// roleRepository implements RoleRepository interface
+type roleRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleRepository creates a new role repository instance
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+// Assign grants a role. Assigning a role the subject already holds is not an error.
+func (r *roleRepository) Assign(assignment *models.RoleAssignment) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(assignment).Error
+}
+
+// Revoke removes a role from a subject
+func (r *roleRepository) Revoke(tenantID, subject, role string) error {
+	result := r.db.Where("tenant_id = ? AND subject = ? AND role = ?", tenantID, subject, role).Delete(&models.RoleAssignment{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRoleAssignmentNotFound
+	}
+	return nil
+}
+
+// GetRoles returns the names of the roles a subject holds
+func (r *roleRepository) GetRoles(tenantID, subject string) ([]string, error) {
+	var roles []string
+	result := r.db.Model(&models.RoleAssignment{}).
+		Where("tenant_id = ? AND subject = ?", tenantID, subject).
+		Order("role ASC").
+		Pluck("role", &roles)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return roles, nil
+}
+
+// List retrieves the role assignments of a tenant, optionally only those of one subject
+func (r *roleRepository) List(tenantID, subject string) ([]models.RoleAssignment, error) {
+	var assignments []models.RoleAssignment
+	query := r.db.Where("tenant_id = ?", tenantID).Order("subject ASC, role ASC")
+	if subject != "" {
+		query = query.Where("subject = ?", subject)
+	}
+	result := query.Find(&assignments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return assignments, nil
+}
+
+// RecordDenial adds a denied request to the log
+func (r *roleRepository) RecordDenial(denial *models.AccessDenial) error {
+	return r.db.Create(denial).Error
+}
+
+// ListDenials retrieves up to limit denied requests of a tenant, newest first, optionally only
+// those of one subject
+func (r *roleRepository) ListDenials(tenantID, subject string, limit int) ([]models.AccessDenial, error) {
+	var denials []models.AccessDenial
+	query := r.db.Where("tenant_id = ?", tenantID).Order("created_at DESC, id DESC").Limit(limit)
+	if subject != "" {
+		query = query.Where("subject = ?", subject)
+	}
+	result := query.Find(&denials)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return denials, nil
+}
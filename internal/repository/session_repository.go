@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"BlogManagment/internal/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Session repository errors
+var (
+	ErrSessionNotFound      = errors.New("session not found")
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	// ErrRefreshTokenRotated is returned when rotating a refresh token that was already rotated
+	ErrRefreshTokenRotated = errors.New("refresh token was already rotated")
+)
+
+// SessionRepository defines the interface for the data operations of sessions and their
+// refresh tokens. Refresh tokens are looked up by hash across tenants; callers check the
+// tenant of the session they belong to.
+type SessionRepository interface {
+	Create(session *models.Session, token *models.RefreshToken) error
+	GetRefreshToken(tokenHash string) (*models.RefreshToken, error)
+	Rotate(tokenID string, replacement *models.RefreshToken, at time.Time) error
+	List(tenantID, userID string, now time.Time) ([]models.Session, error)
+	Revoke(tenantID, userID, id, reason string, at time.Time) error
+	RevokeAll(tenantID, userID, reason string, at time.Time) error
+}
+
+// sessionRepository implements SessionRepository interface
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new session repository instance
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+// Create stores a new session with its first refresh token, in one transaction
+func (r *sessionRepository) Create(session *models.Session, token *models.RefreshToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		token.SessionID = session.ID
+		return tx.Omit("Session").Create(token).Error
+	})
+}
+
+// GetRefreshToken retrieves the refresh token with the given hash, with its session
+func (r *sessionRepository) GetRefreshToken(tokenHash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	result := r.db.Preload("Session").Where("token_hash = ?", tokenHash).First(&token)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRefreshTokenNotFound
+		}
+		return nil, result.Error
+	}
+	if token.Session == nil {
+		return nil, ErrRefreshTokenNotFound
+	}
+	return &token, nil
+}
+
+// Rotate marks a refresh token as used and stores its replacement in the same session, which
+// then lasts until the replacement expires. A token can only be rotated once, so that of two
+// concurrent refreshes with the same token only one succeeds.
+func (r *sessionRepository) Rotate(tokenID string, replacement *models.RefreshToken, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.RefreshToken{}).Where("id = ? AND rotated_at IS NULL", tokenID).Update("rotated_at", at)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRefreshTokenRotated
+		}
+
+		if err := tx.Omit("Session").Create(replacement).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Session{}).Where("id = ?", replacement.SessionID).
+			Updates(map[string]interface{}{"last_used_at": at, "expires_at": replacement.ExpiresAt}).Error
+	})
+}
+
+// List retrieves the sessions of a user that are neither revoked nor expired, most recently used first
+func (r *sessionRepository) List(tenantID, userID string, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	result := r.db.Where("tenant_id = ? AND user_id = ? AND revoked_at IS NULL AND expires_at > ?", tenantID, userID, now).
+		Order("last_used_at DESC, id ASC").Find(&sessions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return sessions, nil
+}
+
+// Revoke ends a session of a user, so that its refresh tokens stop working. Revoking a revoked
+// session is not an error.
+func (r *sessionRepository) Revoke(tenantID, userID, id, reason string, at time.Time) error {
+	result := r.db.Model(&models.Session{}).
+		Where("tenant_id = ? AND user_id = ? AND id = ? AND revoked_at IS NULL", tenantID, userID, id).
+		Updates(map[string]interface{}{"revoked_at": at, "revoked_reason": reason})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := r.db.Model(&models.Session{}).Where("tenant_id = ? AND user_id = ? AND id = ?", tenantID, userID, id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrSessionNotFound
+		}
+	}
+	return nil
+}
+
+// RevokeAll ends every session of a user
+func (r *sessionRepository) RevokeAll(tenantID, userID, reason string, at time.Time) error {
+	return r.db.Model(&models.Session{}).
+		Where("tenant_id = ? AND user_id = ? AND revoked_at IS NULL", tenantID, userID).
+		Updates(map[string]interface{}{"revoked_at": at, "revoked_reason": reason}).Error
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"BlogManagment/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSession(tenantID, userID string, now time.Time) (*models.Session, *models.RefreshToken) {
+	session := &models.Session{
+		ID:         uuid.New().String(),
+		TenantID:   tenantID,
+		UserID:     userID,
+		ExpiresAt:  now.Add(time.Hour),
+		LastUsedAt: now,
+	}
+	token := &models.RefreshToken{ID: uuid.New().String(), TokenHash: uuid.New().String(), ExpiresAt: now.Add(time.Hour)}
+	return session, token
+}
+
+func TestSessionRepository_Rotate(t *testing.T) {
+	repo := NewSessionRepository(newAccountTestDB(t))
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	session, token := newTestSession("acme", "user-1", now)
+	require.NoError(t, repo.Create(session, token))
+
+	stored, err := repo.GetRefreshToken(token.TokenHash)
+	require.NoError(t, err)
+	assert.Equal(t, session.ID, stored.SessionID)
+	require.NotNil(t, stored.Session)
+	assert.Equal(t, "user-1", stored.Session.UserID)
+
+	later := now.Add(30 * time.Minute)
+	replacement := &models.RefreshToken{ID: uuid.New().String(), SessionID: session.ID, TokenHash: uuid.New().String(), ExpiresAt: later.Add(time.Hour)}
+	require.NoError(t, repo.Rotate(token.ID, replacement, later))
+
+	again := &models.RefreshToken{ID: uuid.New().String(), SessionID: session.ID, TokenHash: uuid.New().String(), ExpiresAt: later.Add(time.Hour)}
+	assert.ErrorIs(t, repo.Rotate(token.ID, again, later), ErrRefreshTokenRotated, "tokens are rotated once")
+
+	stored, err = repo.GetRefreshToken(replacement.TokenHash)
+	require.NoError(t, err)
+	assert.True(t, later.Equal(stored.Session.LastUsedAt))
+	assert.True(t, replacement.ExpiresAt.Equal(stored.Session.ExpiresAt), "the session lasts as long as its newest token")
+
+	_, err = repo.GetRefreshToken("unknown")
+	assert.ErrorIs(t, err, ErrRefreshTokenNotFound)
+}
+
+func TestSessionRepository_Revoke(t *testing.T) {
+	repo := NewSessionRepository(newAccountTestDB(t))
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	first, firstToken := newTestSession("acme", "user-1", now)
+	second, secondToken := newTestSession("acme", "user-1", now.Add(time.Minute))
+	other, otherToken := newTestSession("acme", "user-2", now)
+	require.NoError(t, repo.Create(first, firstToken))
+	require.NoError(t, repo.Create(second, secondToken))
+	require.NoError(t, repo.Create(other, otherToken))
+
+	sessions, err := repo.List("acme", "user-1", now)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, second.ID, sessions[0].ID, "most recently used first")
+
+	assert.ErrorIs(t, repo.Revoke("acme", "user-1", other.ID, models.SessionRevokedByUser, now), ErrSessionNotFound, "sessions of other users cannot be revoked")
+	require.NoError(t, repo.Revoke("acme", "user-1", first.ID, models.SessionRevokedByUser, now))
+	require.NoError(t, repo.Revoke("acme", "user-1", first.ID, models.SessionRevokedByUser, now), "revoking twice is not an error")
+
+	sessions, err = repo.List("acme", "user-1", now)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, second.ID, sessions[0].ID)
+
+	require.NoError(t, repo.RevokeAll("acme", "user-1", models.SessionRevokedPasswordReset, now))
+	sessions, err = repo.List("acme", "user-1", now)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	stored, err := repo.GetRefreshToken(firstToken.TokenHash)
+	require.NoError(t, err)
+	assert.Equal(t, models.SessionRevokedByUser, stored.Session.RevokedReason, "revoked sessions keep their first reason")
+
+	sessions, err = repo.List("acme", "user-2", now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, sessions, "expired sessions are not listed")
+}
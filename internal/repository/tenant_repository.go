@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"BlogManagment/internal/models"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrTenantNotFound is returned when a tenant does not exist
+var ErrTenantNotFound = errors.New("tenant not found")
+
+// ErrTenantExists is returned when a tenant with the same ID already exists
+var ErrTenantExists = errors.New("tenant already exists")
+
+// TenantRepository defines the interface for tenant data operations
+type TenantRepository interface {
+	Create(tenant *models.Tenant) error
+	GetByID(id string) (*models.Tenant, error)
+	GetAll() ([]models.Tenant, error)
+}
+
+// tenantRepository implements TenantRepository interface
+type tenantRepository struct {
+	db *gorm.DB
+}
+
+// NewTenantRepository creates a new tenant repository instance
+func NewTenantRepository(db *gorm.DB) TenantRepository {
+	return &tenantRepository{db: db}
+}
+
+// Create adds a new tenant to the database
+func (r *tenantRepository) Create(tenant *models.Tenant) error {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(tenant)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTenantExists
+	}
+	return nil
+}
+
+// GetByID retrieves a tenant by its ID
+func (r *tenantRepository) GetByID(id string) (*models.Tenant, error) {
+	var tenant models.Tenant
+	result := r.db.Where("id = ?", id).First(&tenant)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrTenantNotFound
+		}
+		return nil, result.Error
+	}
+	return &tenant, nil
+}
+
+// GetAll retrieves every tenant in ID order
+func (r *tenantRepository) GetAll() ([]models.Tenant, error) {
+	var tenants []models.Tenant
+	result := r.db.Order("id ASC").Find(&tenants)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return tenants, nil
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"BlogManagment/internal/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// User repository errors
+var (
+	ErrUserNotFound = errors.New("user not found")
+	// ErrUserExists is returned when the tenant already has an account with the same email
+	ErrUserExists = errors.New("user already exists")
+	// ErrPasswordResetInvalid is returned for password reset tokens that are unknown, used or expired
+	ErrPasswordResetInvalid = errors.New("password reset token is invalid or expired")
+	// ErrRecoveryCodeInvalid is returned for recovery codes that are unknown or used
+	ErrRecoveryCodeInvalid = errors.New("recovery code is invalid or was already used")
+)
+
+// UserRepository defines the interface for the data operations of local accounts, their
+// password reset tokens and their second factor
+type UserRepository interface {
+	Create(user *models.User) error
+	GetByID(tenantID, id string) (*models.User, error)
+	GetByEmail(tenantID, email string) (*models.User, error)
+	RecordFailedLogin(tenantID, id string, maxFailures int, lockedUntil time.Time) (locked bool, err error)
+	ResetFailedLogins(tenantID, id string) error
+	UpdatePassword(tenantID, id, passwordHash string) error
+	CreatePasswordReset(token *models.PasswordResetToken) error
+	ConsumePasswordReset(tenantID, tokenHash string, at time.Time) (*models.PasswordResetToken, error)
+	SetTOTPSecret(tenantID, id, secret string) error
+	EnableTOTP(tenantID, id string, counter int64, at time.Time, codes []*models.RecoveryCode) error
+	DisableTOTP(tenantID, id string) error
+	AdvanceTOTPCounter(tenantID, id string, counter int64) (bool, error)
+	UseRecoveryCode(tenantID, userID, codeHash string, at time.Time) error
+}
+
+// userRepository implements UserRepository interface
+type userRepository struct {
+	db *gorm.DB
+}
+
+// NewUserRepository creates a new user repository instance
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+// Create adds a new account
+func (r *userRepository) Create(user *models.User) error {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(user)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserExists
+	}
+	return nil
+}
+
+// GetByID retrieves an account of a tenant by its ID
+func (r *userRepository) GetByID(tenantID, id string) (*models.User, error) {
+	return r.first("tenant_id = ? AND id = ?", tenantID, id)
+}
+
+// GetByEmail retrieves an account of a tenant by its email address
+func (r *userRepository) GetByEmail(tenantID, email string) (*models.User, error) {
+	return r.first("tenant_id = ? AND email = ?", tenantID, email)
+}
+
+// first retrieves the account matching a condition
+func (r *userRepository) first(query string, args ...interface{}) (*models.User, error) {
+	var user models.User
+	result := r.db.Where(query, args...).First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, result.Error
+	}
+	return &user, nil
+}
+
+// RecordFailedLogin counts a failed sign-in. Once maxFailures are counted the account is locked
+// until lockedUntil and counting starts over. It reports whether the account was locked.
+func (r *userRepository) RecordFailedLogin(tenantID, id string, maxFailures int, lockedUntil time.Time) (bool, error) {
+	locked := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Incrementing in SQL keeps concurrent failures from being lost
+		result := tx.Model(&models.User{}).Where("tenant_id = ? AND id = ?", tenantID, id).
+			UpdateColumn("failed_logins", gorm.Expr("failed_logins + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+
+		result = tx.Model(&models.User{}).Where("tenant_id = ? AND id = ? AND failed_logins >= ?", tenantID, id, maxFailures).
+			UpdateColumns(map[string]interface{}{"failed_logins": 0, "locked_until": lockedUntil})
+		locked = result.RowsAffected > 0
+		return result.Error
+	})
+	return locked, err
+}
+
+// ResetFailedLogins clears the failed sign-ins and the lockout of an account after a successful sign-in
+func (r *userRepository) ResetFailedLogins(tenantID, id string) error {
+	return r.db.Model(&models.User{}).Where("tenant_id = ? AND id = ?", tenantID, id).
+		UpdateColumns(map[string]interface{}{"failed_logins": 0, "locked_until": nil}).Error
+}
+
+// UpdatePassword replaces the password hash of an account and lifts its lockout
+func (r *userRepository) UpdatePassword(tenantID, id, passwordHash string) error {
+	result := r.db.Model(&models.User{}).Where("tenant_id = ? AND id = ?", tenantID, id).
+		Updates(map[string]interface{}{"password_hash": passwordHash, "failed_logins": 0, "locked_until": nil})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// CreatePasswordReset stores a new password reset token
+func (r *userRepository) CreatePasswordReset(token *models.PasswordResetToken) error {
+	return r.db.Create(token).Error
+}
+
+// ConsumePasswordReset marks the token of a tenant with the given hash as used and returns it.
+// Tokens can only be used once, before they expire.
+func (r *userRepository) ConsumePasswordReset(tenantID, tokenHash string, at time.Time) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.PasswordResetToken{}).
+			Where("tenant_id = ? AND token_hash = ? AND used_at IS NULL AND expires_at > ?", tenantID, tokenHash, at).
+			Update("used_at", at)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrPasswordResetInvalid
+		}
+		return tx.Where("token_hash = ?", tokenHash).First(&token).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// SetTOTPSecret stores the secret of an authenticator app being enrolled. It only takes effect
+// once confirmed with EnableTOTP.
+func (r *userRepository) SetTOTPSecret(tenantID, id, secret string) error {
+	result := r.db.Model(&models.User{}).Where("tenant_id = ? AND id = ?", tenantID, id).
+		Updates(map[string]interface{}{"totp_secret": secret, "totp_enabled_at": nil, "totp_last_counter": 0})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// EnableTOTP turns on two-factor authentication once the first code, of period counter, is
+// confirmed, and replaces the recovery codes of the account
+func (r *userRepository) EnableTOTP(tenantID, id string, counter int64, at time.Time, codes []*models.RecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).Where("tenant_id = ? AND id = ?", tenantID, id).
+			Updates(map[string]interface{}{"totp_enabled_at": at, "totp_last_counter": counter})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+
+		if err := tx.Where("tenant_id = ? AND user_id = ?", tenantID, id).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(codes).Error
+	})
+}
+
+// DisableTOTP turns off two-factor authentication and deletes the recovery codes of the account
+func (r *userRepository) DisableTOTP(tenantID, id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).Where("tenant_id = ? AND id = ?", tenantID, id).
+			Updates(map[string]interface{}{"totp_secret": "", "totp_enabled_at": nil, "totp_last_counter": 0})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+		return tx.Where("tenant_id = ? AND user_id = ?", tenantID, id).Delete(&models.RecoveryCode{}).Error
+	})
+}
+
+// AdvanceTOTPCounter records that the code of period counter was used. It reports false when a
+// code of that period or a later one was already used, so that two requests cannot both use
+// the same code.
+func (r *userRepository) AdvanceTOTPCounter(tenantID, id string, counter int64) (bool, error) {
+	result := r.db.Model(&models.User{}).Where("tenant_id = ? AND id = ? AND totp_last_counter < ?", tenantID, id, counter).
+		UpdateColumn("totp_last_counter", counter)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UseRecoveryCode marks the recovery code of a user with the given hash as used. Codes can
+// only be used once.
+func (r *userRepository) UseRecoveryCode(tenantID, userID, codeHash string, at time.Time) error {
+	result := r.db.Model(&models.RecoveryCode{}).
+		Where("tenant_id = ? AND user_id = ? AND code_hash = ? AND used_at IS NULL", tenantID, userID, codeHash).
+		Update("used_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecoveryCodeInvalid
+	}
+	return nil
+}
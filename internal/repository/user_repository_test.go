@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"BlogManagment/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newAccountTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := newTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.Session{}, &models.RefreshToken{}, &models.PasswordResetToken{}, &models.RecoveryCode{}))
+	return db
+}
+
+func newTestUser(tenantID, email string) *models.User {
+	return &models.User{ID: uuid.New().String(), TenantID: tenantID, Email: email, PasswordHash: "hash"}
+}
+
+func TestUserRepository_Create_UniquePerTenant(t *testing.T) {
+	repo := NewUserRepository(newAccountTestDB(t))
+
+	alice := newTestUser("acme", "alice@example.com")
+	require.NoError(t, repo.Create(alice))
+	assert.ErrorIs(t, repo.Create(newTestUser("acme", "alice@example.com")), ErrUserExists)
+	require.NoError(t, repo.Create(newTestUser("globex", "alice@example.com")), "emails are unique per tenant")
+
+	user, err := repo.GetByEmail("acme", "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, alice.ID, user.ID)
+
+	_, err = repo.GetByID("globex", alice.ID)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestUserRepository_RecordFailedLogin(t *testing.T) {
+	repo := NewUserRepository(newAccountTestDB(t))
+	user := newTestUser("acme", "alice@example.com")
+	require.NoError(t, repo.Create(user))
+	lockedUntil := time.Date(2024, 1, 1, 12, 15, 0, 0, time.UTC)
+
+	for i := 0; i < 2; i++ {
+		locked, err := repo.RecordFailedLogin("acme", user.ID, 3, lockedUntil)
+		require.NoError(t, err)
+		assert.False(t, locked)
+	}
+	locked, err := repo.RecordFailedLogin("acme", user.ID, 3, lockedUntil)
+	require.NoError(t, err)
+	assert.True(t, locked, "the third failure locks the account")
+
+	stored, err := repo.GetByID("acme", user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stored.FailedLogins, "counting starts over after a lockout")
+	require.NotNil(t, stored.LockedUntil)
+	assert.True(t, lockedUntil.Equal(*stored.LockedUntil))
+
+	require.NoError(t, repo.UpdatePassword("acme", user.ID, "new hash"))
+	stored, err = repo.GetByID("acme", user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "new hash", stored.PasswordHash)
+	assert.Nil(t, stored.LockedUntil, "resetting the password lifts the lockout")
+
+	_, err = repo.RecordFailedLogin("globex", user.ID, 3, lockedUntil)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestUserRepository_ConsumePasswordReset(t *testing.T) {
+	repo := NewUserRepository(newAccountTestDB(t))
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.CreatePasswordReset(&models.PasswordResetToken{
+		ID: uuid.New().String(), TenantID: "acme", UserID: "user-1", TokenHash: "valid", ExpiresAt: now.Add(time.Hour),
+	}))
+	require.NoError(t, repo.CreatePasswordReset(&models.PasswordResetToken{
+		ID: uuid.New().String(), TenantID: "acme", UserID: "user-1", TokenHash: "expired", ExpiresAt: now,
+	}))
+
+	_, err := repo.ConsumePasswordReset("globex", "valid", now)
+	assert.ErrorIs(t, err, ErrPasswordResetInvalid, "tokens only work for their tenant")
+
+	token, err := repo.ConsumePasswordReset("acme", "valid", now)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", token.UserID)
+	require.NotNil(t, token.UsedAt)
+
+	_, err = repo.ConsumePasswordReset("acme", "valid", now)
+	assert.ErrorIs(t, err, ErrPasswordResetInvalid, "tokens work once")
+	_, err = repo.ConsumePasswordReset("acme", "expired", now)
+	assert.ErrorIs(t, err, ErrPasswordResetInvalid)
+	_, err = repo.ConsumePasswordReset("acme", "unknown", now)
+	assert.ErrorIs(t, err, ErrPasswordResetInvalid)
+}
+
+func TestUserRepository_TOTP(t *testing.T) {
+	repo := NewUserRepository(newAccountTestDB(t))
+	user := newTestUser("acme", "alice@example.com")
+	require.NoError(t, repo.Create(user))
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	newCode := func(hash string) *models.RecoveryCode {
+		return &models.RecoveryCode{ID: uuid.New().String(), TenantID: "acme", UserID: user.ID, CodeHash: hash}
+	}
+
+	require.NoError(t, repo.SetTOTPSecret("acme", user.ID, "JBSWY3DPEHPK3PXP"))
+	require.NoError(t, repo.EnableTOTP("acme", user.ID, 100, now, []*models.RecoveryCode{newCode("old")}))
+	require.NoError(t, repo.EnableTOTP("acme", user.ID, 100, now, []*models.RecoveryCode{newCode("first"), newCode("second")}))
+
+	stored, err := repo.GetByID("acme", user.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.TwoFactorEnabled())
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", stored.TOTPSecret)
+
+	advanced, err := repo.AdvanceTOTPCounter("acme", user.ID, 100)
+	require.NoError(t, err)
+	assert.False(t, advanced, "the code confirming the app cannot be used again")
+	advanced, err = repo.AdvanceTOTPCounter("acme", user.ID, 101)
+	require.NoError(t, err)
+	assert.True(t, advanced)
+
+	assert.ErrorIs(t, repo.UseRecoveryCode("acme", user.ID, "old", now), ErrRecoveryCodeInvalid, "enabling again replaces the codes")
+	require.NoError(t, repo.UseRecoveryCode("acme", user.ID, "first", now))
+	assert.ErrorIs(t, repo.UseRecoveryCode("acme", user.ID, "first", now), ErrRecoveryCodeInvalid, "codes work once")
+	assert.ErrorIs(t, repo.UseRecoveryCode("acme", "someone-else", "second", now), ErrRecoveryCodeInvalid)
+
+	require.NoError(t, repo.DisableTOTP("acme", user.ID))
+	stored, err = repo.GetByID("acme", user.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.TwoFactorEnabled())
+	assert.Empty(t, stored.TOTPSecret)
+	assert.ErrorIs(t, repo.UseRecoveryCode("acme", user.ID, "second", now), ErrRecoveryCodeInvalid)
+}
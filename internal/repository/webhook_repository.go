@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"BlogManagment/internal/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrWebhookNotFound is returned when a webhook does not exist
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// ErrDeliveryNotFound is returned when a webhook delivery does not exist
+var ErrDeliveryNotFound = errors.New("webhook delivery not found")
+
+// WebhookRepository defines the interface for webhook subscriptions and their delivery queue.
+// Webhooks and deliveries belong to the tenant of the repository, except that the dispatcher
+// works through the queue of every tenant with ClaimDueDeliveries, GetByIDs and UpdateDelivery.
+type WebhookRepository interface {
+	WithTenant(tenantID string) WebhookRepository
+	Create(webhook *models.Webhook) error
+	GetByID(id string) (*models.Webhook, error)
+	GetByIDs(ids []string) ([]models.Webhook, error)
+	GetAll() ([]models.Webhook, error)
+	GetActive() ([]models.Webhook, error)
+	Update(webhook *models.Webhook) error
+	Delete(id string) error
+	EnqueueDeliveries(deliveries []*models.WebhookDelivery) error
+	GetDelivery(id string) (*models.WebhookDelivery, error)
+	ListDeliveries(webhookID, status string, limit int) ([]models.WebhookDelivery, error)
+	ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error)
+	UpdateDelivery(delivery *models.WebhookDelivery) error
+}
+
+// webhookRepository implements WebhookRepository interface for the webhooks of tenantID
+type webhookRepository struct {
+	db       *gorm.DB
+	tenantID string
+}
+
+// NewWebhookRepository creates a new webhook repository instance for the default tenant
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db, tenantID: models.DefaultTenantID}
+}
+
+// WithTenant returns a repository for the webhooks of another tenant
+func (r *webhookRepository) WithTenant(tenantID string) WebhookRepository {
+	return &webhookRepository{db: r.db, tenantID: tenantID}
+}
+
+// Create adds a new webhook of the tenant to the database
+func (r *webhookRepository) Create(webhook *models.Webhook) error {
+	webhook.TenantID = r.tenantID
+	return r.db.Create(webhook).Error
+}
+
+// GetByID retrieves a webhook of the tenant by its ID
+func (r *webhookRepository) GetByID(id string) (*models.Webhook, error) {
+	var webhook models.Webhook
+	result := r.db.Scopes(r.tenantScope).Where("id = ?", id).First(&webhook)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, result.Error
+	}
+	return &webhook, nil
+}
+
+// GetByIDs retrieves the webhooks with the given IDs, of any tenant; missing IDs are skipped
+func (r *webhookRepository) GetByIDs(ids []string) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	if len(ids) == 0 {
+		return webhooks, nil
+	}
+	result := r.db.Where("id IN ?", ids).Find(&webhooks)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return webhooks, nil
+}
+
+// GetAll retrieves every webhook of the tenant, oldest first
+func (r *webhookRepository) GetAll() ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	result := r.db.Scopes(r.tenantScope).Order("created_at ASC").Find(&webhooks)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return webhooks, nil
+}
+
+// GetActive retrieves every webhook of the tenant that currently receives events
+func (r *webhookRepository) GetActive() ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	result := r.db.Scopes(r.tenantScope).Where("active = ?", true).Find(&webhooks)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return webhooks, nil
+}
+
+// Update modifies an existing webhook of the tenant
+func (r *webhookRepository) Update(webhook *models.Webhook) error {
+	webhook.TenantID = r.tenantID
+	result := r.db.Scopes(r.tenantScope).Select("*").Updates(webhook)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// Delete removes a webhook of the tenant together with its delivery history
+func (r *webhookRepository) Delete(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Scopes(r.tenantScope).Where("id = ?", id).Delete(&models.Webhook{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrWebhookNotFound
+		}
+		return tx.Where("webhook_id = ?", id).Delete(&models.WebhookDelivery{}).Error
+	})
+}
+
+// EnqueueDeliveries adds deliveries of the tenant to the queue. A webhook is only queued once
+// per event, so deliveries of an event that is published again are ignored.
+func (r *webhookRepository) EnqueueDeliveries(deliveries []*models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	for _, delivery := range deliveries {
+		delivery.TenantID = r.tenantID
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "webhook_id"}, {Name: "event_id"}},
+		DoNothing: true,
+	}).CreateInBatches(deliveries, 100).Error
+}
+
+// GetDelivery retrieves a delivery of the tenant by its ID
+func (r *webhookRepository) GetDelivery(id string) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	result := r.db.Scopes(r.tenantScope).Where("id = ?", id).First(&delivery)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, result.Error
+	}
+	return &delivery, nil
+}
+
+// ListDeliveries retrieves the most recent deliveries of the tenant, optionally restricted to one
+// webhook and one status
+func (r *webhookRepository) ListDeliveries(webhookID, status string, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	query := r.db.Scopes(r.tenantScope).Order("created_at DESC, id DESC").Limit(limit)
+	if webhookID != "" {
+		query = query.Where("webhook_id = ?", webhookID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	result := query.Find(&deliveries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return deliveries, nil
+}
+
+// ClaimDueDeliveries locks up to limit pending deliveries of any tenant that are due and pushes their next
+// attempt back by lease, so that concurrent dispatchers skip them. A dispatcher that dies
+// mid-delivery leaves the delivery to be retried once the lease expires.
+func (r *webhookRepository) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.DeliveryStatusPending, now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&deliveries)
+		if result.Error != nil || len(deliveries) == 0 {
+			return result.Error
+		}
+
+		ids := make([]string, len(deliveries))
+		for i := range deliveries {
+			ids[i] = deliveries[i].ID
+		}
+		return tx.Model(&models.WebhookDelivery{}).Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// UpdateDelivery stores the outcome of a delivery attempt; the delivery keeps its tenant
+func (r *webhookRepository) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	result := r.db.Save(delivery)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDeliveryNotFound
+	}
+	return nil
+}
+
+// tenantScope restricts a query to the webhooks or deliveries of the tenant
+func (r *webhookRepository) tenantScope(db *gorm.DB) *gorm.DB {
+	return db.Where("tenant_id = ?", r.tenantID)
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"BlogManagment/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWebhook() *models.Webhook {
+	return &models.Webhook{
+		ID:     uuid.New().String(),
+		URL:    "https://hooks.example.com/blog",
+		Events: []string{"*"},
+		Secret: "secret",
+		Active: true,
+	}
+}
+
+func TestWebhookRepository_TenantIsolation(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Webhook{}, &models.WebhookDelivery{}))
+	acme := NewWebhookRepository(db).WithTenant("acme")
+	globex := NewWebhookRepository(db).WithTenant("globex")
+
+	webhook := newTestWebhook()
+	require.NoError(t, acme.Create(webhook))
+	assert.Equal(t, "acme", webhook.TenantID)
+
+	delivery := &models.WebhookDelivery{
+		ID:            uuid.New().String(),
+		WebhookID:     webhook.ID,
+		EventID:       uuid.New().String(),
+		EventType:     models.EventPostCreated,
+		Payload:       []byte(`{}`),
+		Status:        models.DeliveryStatusPending,
+		NextAttemptAt: time.Now(),
+	}
+	require.NoError(t, acme.EnqueueDeliveries([]*models.WebhookDelivery{delivery}))
+
+	t.Run("reads", func(t *testing.T) {
+		_, err := globex.GetByID(webhook.ID)
+		assert.ErrorIs(t, err, ErrWebhookNotFound)
+
+		all, err := globex.GetAll()
+		require.NoError(t, err)
+		assert.Empty(t, all)
+
+		active, err := globex.GetActive()
+		require.NoError(t, err)
+		assert.Empty(t, active)
+
+		_, err = globex.GetDelivery(delivery.ID)
+		assert.ErrorIs(t, err, ErrDeliveryNotFound)
+
+		deliveries, err := globex.ListDeliveries("", "", 10)
+		require.NoError(t, err)
+		assert.Empty(t, deliveries)
+	})
+
+	t.Run("writes", func(t *testing.T) {
+		stolen := *webhook
+		stolen.URL = "https://attacker.example.com"
+		assert.ErrorIs(t, globex.Update(&stolen), ErrWebhookNotFound)
+		assert.ErrorIs(t, globex.Delete(webhook.ID), ErrWebhookNotFound)
+
+		stored, err := acme.GetByID(webhook.ID)
+		require.NoError(t, err)
+		assert.Equal(t, webhook.URL, stored.URL)
+
+		deliveries, err := acme.ListDeliveries(webhook.ID, "", 10)
+		require.NoError(t, err)
+		assert.Len(t, deliveries, 1, "the deliveries of the webhook are kept")
+	})
+
+	t.Run("dispatcher", func(t *testing.T) {
+		claimed, err := globex.ClaimDueDeliveries(time.Now(), time.Minute, 10)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1, "the dispatcher works through the queue of every tenant")
+		assert.Equal(t, "acme", claimed[0].TenantID)
+	})
+}
@@ -3,25 +3,123 @@ package routes
 import (
 	"BlogManagment/internal/controller"
 	"BlogManagment/internal/middleware"
+	"BlogManagment/internal/rbac"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// SetupRoutes configures all application routes
-func SetupRoutes(app *fiber.App, blogController *controller.BlogController) {
+// Deps holds what the routes are served by. Every field is required unless documented otherwise.
+type Deps struct {
+	Blog     *controller.BlogController
+	Transfer *controller.TransferController
+	Webhook  *controller.WebhookController
+	Event    *controller.EventController
+	GraphQL  *controller.GraphQLController
+	Role     *controller.RoleController
+	APIKey   *controller.APIKeyController
+	Audit    *controller.AuditController
+	// Auth serves the local accounts; the account routes are left out when it is nil
+	Auth *controller.AuthController
+	// Cache serves the cache statistics; the route is left out when it is nil
+	Cache *controller.CacheController
+	// OpenRegistration lets every caller register an account; otherwise registering needs role:manage
+	OpenRegistration bool
+
+	RateLimiter *middleware.RateLimiter
+	Authorizer  *middleware.Authorizer
+	// Idempotency replays the responses of retried POST requests. It only guards the changes of
+	// posts, so that the credentials issued by other routes are never stored.
+	Idempotency fiber.Handler
+	// CacheControl sets the Cache-Control header of post reads
+	CacheControl fiber.Handler
+}
+
+// SetupRoutes configures all application routes. Every route that changes data declares the
+// permission it needs; reading posts and streaming events is open to every caller.
+func SetupRoutes(app *fiber.App, deps Deps) {
 	// Global middleware
 	app.Use(middleware.Logger())
 
 	// API routes group
-	api := app.Group("/api")
-
-	// Blog routes
-	blogRoutes := api.Group("/blog-post")
-	blogRoutes.Post("/", blogController.CreateBlog)      // POST /api/blog-post
-	blogRoutes.Get("/", blogController.GetAllBlogs)      // GET /api/blog-post
-	blogRoutes.Get("/:id", blogController.GetBlogByID)   // GET /api/blog-post/:id
-	blogRoutes.Patch("/:id", blogController.UpdateBlog)  // PATCH /api/blog-post/:id
-	blogRoutes.Delete("/:id", blogController.DeleteBlog) // DELETE /api/blog-post/:id
+	api := app.Group("/api", deps.Authorizer.Load())
+	require := deps.Authorizer.Require
+
+	// Blog routes; whether the caller may change a particular post is checked by the blog service.
+	// A bulk request needs at least one of the permissions of its operations.
+	changePosts := require(rbac.PostCreate, rbac.PostUpdateOwn, rbac.PostUpdateAny, rbac.PostDeleteOwn, rbac.PostDeleteAny)
+	blogRoutes := api.Group("/blog-post", deps.RateLimiter.For("blog"), deps.Idempotency)
+	blogRoutes.Post("/", require(rbac.PostCreate), deps.Blog.CreateBlog)                             // POST /api/blog-post
+	blogRoutes.Post("/bulk", changePosts, deps.Blog.BulkBlogs)                                       // POST /api/blog-post/bulk
+	blogRoutes.Get("/", deps.CacheControl, deps.Blog.GetAllBlogs)                                    // GET /api/blog-post
+	blogRoutes.Get("/:id", deps.CacheControl, deps.Blog.GetBlogByID)                                 // GET /api/blog-post/:id
+	blogRoutes.Patch("/:id", require(rbac.PostUpdateOwn, rbac.PostUpdateAny), deps.Blog.UpdateBlog)  // PATCH /api/blog-post/:id
+	blogRoutes.Delete("/:id", require(rbac.PostDeleteOwn, rbac.PostDeleteAny), deps.Blog.DeleteBlog) // DELETE /api/blog-post/:id
+
+	// Export and import routes
+	api.Get("/export", deps.RateLimiter.For("transfer"), require(rbac.PostExport), deps.Transfer.Export)                    // GET /api/export
+	api.Post("/import", deps.RateLimiter.For("transfer"), require(rbac.PostImport), deps.Idempotency, deps.Transfer.Import) // POST /api/import
+
+	// Webhook routes; the delivery routes come first so that "deliveries" is not taken for an ID
+	webhookRoutes := api.Group("/webhooks", deps.RateLimiter.For("webhooks"), require(rbac.WebhookManage))
+	webhookRoutes.Get("/deliveries", deps.Webhook.ListDeliveries)            // GET /api/webhooks/deliveries
+	webhookRoutes.Post("/deliveries/:id/retry", deps.Webhook.RetryDelivery)  // POST /api/webhooks/deliveries/:id/retry
+	webhookRoutes.Post("/", deps.Webhook.CreateWebhook)                      // POST /api/webhooks
+	webhookRoutes.Get("/", deps.Webhook.GetAllWebhooks)                      // GET /api/webhooks
+	webhookRoutes.Get("/:id", deps.Webhook.GetWebhook)                       // GET /api/webhooks/:id
+	webhookRoutes.Patch("/:id", deps.Webhook.UpdateWebhook)                  // PATCH /api/webhooks/:id
+	webhookRoutes.Delete("/:id", deps.Webhook.DeleteWebhook)                 // DELETE /api/webhooks/:id
+	webhookRoutes.Get("/:id/deliveries", deps.Webhook.ListWebhookDeliveries) // GET /api/webhooks/:id/deliveries
+
+	// Role administration
+	adminRoutes := api.Group("/admin", require(rbac.RoleManage))
+	adminRoutes.Get("/roles", deps.Role.GetRoles)                                // GET /api/admin/roles
+	adminRoutes.Get("/role-assignments", deps.Role.GetRoleAssignments)           // GET /api/admin/role-assignments
+	adminRoutes.Post("/role-assignments", deps.Role.AssignRole)                  // POST /api/admin/role-assignments
+	adminRoutes.Delete("/role-assignments/:subject/:role", deps.Role.RevokeRole) // DELETE /api/admin/role-assignments/:subject/:role
+	adminRoutes.Get("/access-denials", deps.Role.GetAccessDenials)               // GET /api/admin/access-denials
+	if deps.Cache != nil {
+		adminRoutes.Get("/cache", deps.Cache.GetStats) // GET /api/admin/cache
+	}
+
+	// API keys of machine clients
+	apiKeyRoutes := api.Group("/api-keys", require(rbac.APIKeyManage))
+	apiKeyRoutes.Post("/", deps.APIKey.CreateAPIKey)           // POST /api/api-keys
+	apiKeyRoutes.Get("/", deps.APIKey.GetAllAPIKeys)           // GET /api/api-keys
+	apiKeyRoutes.Get("/:id", deps.APIKey.GetAPIKey)            // GET /api/api-keys/:id
+	apiKeyRoutes.Post("/:id/rotate", deps.APIKey.RotateAPIKey) // POST /api/api-keys/:id/rotate
+	apiKeyRoutes.Delete("/:id", deps.APIKey.RevokeAPIKey)      // DELETE /api/api-keys/:id
+
+	// Audit log of changes to posts
+	auditRoutes := api.Group("/audit", require(rbac.AuditRead))
+	auditRoutes.Get("/", deps.Audit.GetAuditEntries)          // GET /api/audit
+	auditRoutes.Get("/export", deps.Audit.ExportAuditEntries) // GET /api/audit/export
+
+	// Local accounts and their sessions; open to every caller, since signing in is how callers get
+	// credentials, except for registration when it is closed
+	if deps.Auth != nil {
+		register := []fiber.Handler{deps.Auth.Register}
+		if !deps.OpenRegistration {
+			register = append([]fiber.Handler{require(rbac.RoleManage)}, register...)
+		}
+		authRoutes := api.Group("/auth", deps.RateLimiter.For("auth"))
+		authRoutes.Post("/register", register...)                           // POST /api/auth/register
+		authRoutes.Post("/login", deps.Auth.Login)                          // POST /api/auth/login
+		authRoutes.Post("/refresh", deps.Auth.Refresh)                      // POST /api/auth/refresh
+		authRoutes.Post("/logout", deps.Auth.Logout)                        // POST /api/auth/logout
+		authRoutes.Get("/sessions", deps.Auth.GetSessions)                  // GET /api/auth/sessions
+		authRoutes.Delete("/sessions/:id", deps.Auth.RevokeSession)         // DELETE /api/auth/sessions/:id
+		authRoutes.Post("/password-reset", deps.Auth.RequestPasswordReset)  // POST /api/auth/password-reset
+		authRoutes.Post("/password-reset/confirm", deps.Auth.ResetPassword) // POST /api/auth/password-reset/confirm
+		authRoutes.Post("/2fa/enroll", deps.Auth.EnrollTOTP)                // POST /api/auth/2fa/enroll
+		authRoutes.Post("/2fa/confirm", deps.Auth.ConfirmTOTP)              // POST /api/auth/2fa/confirm
+		authRoutes.Post("/2fa/disable", deps.Auth.DisableTOTP)              // POST /api/auth/2fa/disable
+	}
+
+	// Live event stream
+	api.Get("/events/stream", deps.RateLimiter.For("events"), deps.Event.Stream) // GET /api/events/stream
+
+	// GraphQL endpoint; mutations are checked against the caller's permissions by the blog service
+	app.Post("/graphql", deps.RateLimiter.For("graphql"), deps.Authorizer.Load(), deps.GraphQL.Query) // POST /graphql
 
 	// Health check endpoint
 	app.Get("/health", func(c *fiber.Ctx) error {
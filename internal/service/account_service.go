@@ -0,0 +1,580 @@
+package service
+
+import (
+	"BlogManagment/internal/auth"
+	"BlogManagment/internal/mail"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Account errors
+var (
+	ErrEmailTaken          = errors.New("an account with this email already exists")
+	ErrInvalidCredentials  = errors.New("invalid email or password")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	// ErrRefreshTokenReused is returned when a rotated refresh token is used again, which means
+	// it was stolen or replayed; the session it belongs to is revoked
+	ErrRefreshTokenReused = errors.New("refresh token was already used, the session has been revoked")
+	ErrInvalidResetToken  = errors.New("invalid or expired password reset token")
+	// ErrTwoFactorRequired is returned when signing in to an account with two-factor
+	// authentication without a code
+	ErrTwoFactorRequired    = errors.New("two-factor authentication code required")
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor authentication code")
+	ErrTwoFactorEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled  = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotEnrolled = errors.New("no authenticator app is being enrolled")
+)
+
+// AccountLockedError is returned when signing in to an account locked after too many failed attempts
+type AccountLockedError struct {
+	Until time.Time
+}
+
+func (e *AccountLockedError) Error() string {
+	return "account is locked after too many failed sign-in attempts, try again after " + e.Until.UTC().Format(time.RFC3339)
+}
+
+// AccountPolicy holds the lifetimes and limits of accounts and sessions
+type AccountPolicy struct {
+	// RefreshTokenTTL is how long a session lasts without being refreshed
+	RefreshTokenTTL time.Duration
+	// MaxFailedLogins is the number of failed sign-ins that locks an account for LockoutDuration
+	MaxFailedLogins  int
+	LockoutDuration  time.Duration
+	PasswordResetTTL time.Duration
+	// PasswordResetURL is the page of the frontend that resets passwords; the token is added as
+	// the token query parameter. Emails contain the bare token when it is empty.
+	PasswordResetURL string
+	// TOTPIssuer names the API in authenticator apps
+	TOTPIssuer string
+}
+
+// AccountService defines the interface for local accounts: registration, signing in and out,
+// sessions, password resets and two-factor authentication
+type AccountService interface {
+	Register(tenantID string, request *models.RegisterRequest) (*models.UserResponse, error)
+	Login(tenantID string, request *models.LoginRequest, userAgent, ip string) (*models.AuthTokens, error)
+	Refresh(tenantID, refreshToken string) (*models.AuthTokens, error)
+	Logout(tenantID, refreshToken string) error
+	ListSessions(tenantID, userID string) ([]models.SessionResponse, error)
+	RevokeSession(tenantID, userID, sessionID string) error
+	RequestPasswordReset(tenantID string, request *models.PasswordResetRequest) error
+	ResetPassword(tenantID string, request *models.PasswordResetConfirmRequest) error
+	EnrollTOTP(tenantID, userID string) (*models.TOTPEnrollment, error)
+	ConfirmTOTP(tenantID, userID string, request *models.TwoFactorCodeRequest) (*models.RecoveryCodesResponse, error)
+	DisableTOTP(tenantID, userID string, request *models.TwoFactorCodeRequest) error
+}
+
+// accountService implements AccountService interface
+type accountService struct {
+	userRepo       repository.UserRepository
+	sessionRepo    repository.SessionRepository
+	issuer         auth.TokenIssuer
+	mailer         mail.Mailer
+	policy         AccountPolicy
+	passwordParams auth.PasswordParams
+	// dummyHash is verified when signing in to an unknown account, so that the response time
+	// does not tell which email addresses have accounts
+	dummyHash string
+	now       func() time.Time
+}
+
+// NewAccountService creates a new account service instance. Access tokens are signed by issuer
+// and password reset emails sent with mailer.
+func NewAccountService(userRepo repository.UserRepository, sessionRepo repository.SessionRepository, issuer auth.TokenIssuer, mailer mail.Mailer, policy AccountPolicy) AccountService {
+	return newAccountService(userRepo, sessionRepo, issuer, mailer, policy, auth.DefaultPasswordParams)
+}
+
+// newAccountService creates an account service hashing passwords with the given parameters
+func newAccountService(userRepo repository.UserRepository, sessionRepo repository.SessionRepository, issuer auth.TokenIssuer, mailer mail.Mailer, policy AccountPolicy, params auth.PasswordParams) *accountService {
+	dummyHash, err := auth.HashPassword(uuid.New().String(), params)
+	if err != nil {
+		log.Printf("Failed to hash the dummy password: %v", err)
+	}
+	return &accountService{
+		userRepo:       userRepo,
+		sessionRepo:    sessionRepo,
+		issuer:         issuer,
+		mailer:         mailer,
+		policy:         policy,
+		passwordParams: params,
+		dummyHash:      dummyHash,
+		now:            time.Now,
+	}
+}
+
+// normalizeEmail makes email addresses that differ only in case or surrounding space the same account
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// Register creates an account
+func (s *accountService) Register(tenantID string, request *models.RegisterRequest) (*models.UserResponse, error) {
+	if request == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	request.Email = normalizeEmail(request.Email)
+	if err := validateStruct(request); err != nil {
+		return nil, err
+	}
+
+	passwordHash, err := auth.HashPassword(request.Password, s.passwordParams)
+	if err != nil {
+		return nil, err
+	}
+	user := &models.User{
+		ID:           uuid.New().String(),
+		TenantID:     tenantID,
+		Email:        request.Email,
+		PasswordHash: passwordHash,
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		if errors.Is(err, repository.ErrUserExists) {
+			return nil, ErrEmailTaken
+		}
+		return nil, err
+	}
+	return userToResponse(user), nil
+}
+
+// Login checks the credentials of an account and starts a session. Accounts with two-factor
+// authentication also need a code of their authenticator app or a recovery code. Accounts are
+// locked for a while after too many failed attempts; attempts on a locked account are not checked.
+func (s *accountService) Login(tenantID string, request *models.LoginRequest, userAgent, ip string) (*models.AuthTokens, error) {
+	if request == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	if err := validateStruct(request); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	user, err := s.userRepo.GetByEmail(tenantID, normalizeEmail(request.Email))
+	if errors.Is(err, repository.ErrUserNotFound) {
+		_, _ = auth.VerifyPassword(request.Password, s.dummyHash)
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.LockedUntil != nil && now.Before(*user.LockedUntil) {
+		return nil, &AccountLockedError{Until: *user.LockedUntil}
+	}
+
+	matches, err := auth.VerifyPassword(request.Password, user.PasswordHash)
+	if err != nil {
+		return nil, err
+	}
+	if !matches {
+		return nil, s.failLogin(user, now, ErrInvalidCredentials)
+	}
+
+	if user.TwoFactorEnabled() {
+		if request.TOTPCode == "" && request.RecoveryCode == "" {
+			return nil, ErrTwoFactorRequired
+		}
+		verified, err := s.verifySecondFactor(user, request.TOTPCode, request.RecoveryCode, now)
+		if err != nil {
+			return nil, err
+		}
+		if !verified {
+			return nil, s.failLogin(user, now, ErrInvalidTwoFactorCode)
+		}
+	}
+
+	if user.FailedLogins > 0 || user.LockedUntil != nil {
+		if err := s.userRepo.ResetFailedLogins(tenantID, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	session := &models.Session{
+		ID:         uuid.New().String(),
+		TenantID:   tenantID,
+		UserID:     user.ID,
+		UserAgent:  truncate(userAgent, 255),
+		IP:         ip,
+		ExpiresAt:  now.Add(s.policy.RefreshTokenTTL),
+		LastUsedAt: now,
+		TwoFactor:  user.TwoFactorEnabled(),
+	}
+	refreshToken, token, err := s.newRefreshToken(session.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sessionRepo.Create(session, token); err != nil {
+		return nil, err
+	}
+	return s.issueTokens(session, refreshToken, token)
+}
+
+// failLogin counts a failed sign-in of an account and returns err, or the lockout it causes
+func (s *accountService) failLogin(user *models.User, now time.Time, err error) error {
+	lockedUntil := now.Add(s.policy.LockoutDuration)
+	locked, recordErr := s.userRepo.RecordFailedLogin(user.TenantID, user.ID, s.policy.MaxFailedLogins, lockedUntil)
+	if recordErr != nil {
+		return recordErr
+	}
+	if locked {
+		return &AccountLockedError{Until: lockedUntil}
+	}
+	return err
+}
+
+// verifySecondFactor checks a code of the authenticator app of an account, or else one of its
+// recovery codes. Either works once.
+func (s *accountService) verifySecondFactor(user *models.User, totpCode, recoveryCode string, now time.Time) (bool, error) {
+	if totpCode != "" {
+		counter, valid, err := auth.VerifyTOTP(user.TOTPSecret, totpCode, now, user.TOTPLastCounter)
+		if err != nil || !valid {
+			return false, err
+		}
+		// Another request may have used the same code since the account was loaded
+		return s.userRepo.AdvanceTOTPCounter(user.TenantID, user.ID, counter)
+	}
+
+	err := s.userRepo.UseRecoveryCode(user.TenantID, user.ID, auth.HashRecoveryCode(recoveryCode), now)
+	if errors.Is(err, repository.ErrRecoveryCodeInvalid) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// EnrollTOTP starts enrolling an authenticator app for two-factor authentication. It is only
+// required once ConfirmTOTP has checked a first code; enrolling again replaces the secret.
+func (s *accountService) EnrollTOTP(tenantID, userID string) (*models.TOTPEnrollment, error) {
+	user, err := s.userRepo.GetByID(tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled() {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	secret, err := auth.NewTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.SetTOTPSecret(tenantID, userID, secret); err != nil {
+		return nil, err
+	}
+	return &models.TOTPEnrollment{Secret: secret, URI: auth.TOTPURI(s.policy.TOTPIssuer, user.Email, secret)}, nil
+}
+
+// ConfirmTOTP turns on two-factor authentication with a first code of the enrolled
+// authenticator app, and returns the recovery codes of the account
+func (s *accountService) ConfirmTOTP(tenantID, userID string, request *models.TwoFactorCodeRequest) (*models.RecoveryCodesResponse, error) {
+	if request == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	if err := validateStruct(request); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled() {
+		return nil, ErrTwoFactorEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+
+	now := s.now()
+	counter, valid, err := auth.VerifyTOTP(user.TOTPSecret, request.Code, now, 0)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes := make([]string, auth.RecoveryCodeCount)
+	records := make([]*models.RecoveryCode, auth.RecoveryCodeCount)
+	for i := range codes {
+		if codes[i], err = auth.NewRecoveryCode(); err != nil {
+			return nil, err
+		}
+		records[i] = &models.RecoveryCode{
+			ID:       uuid.New().String(),
+			TenantID: tenantID,
+			UserID:   userID,
+			CodeHash: auth.HashRecoveryCode(codes[i]),
+		}
+	}
+	if err := s.userRepo.EnableTOTP(tenantID, userID, counter, now, records); err != nil {
+		return nil, err
+	}
+	return &models.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTOTP turns off two-factor authentication, given a code of the authenticator app or a
+// recovery code. Wrong codes count as failed sign-ins, so that they cannot be guessed.
+func (s *accountService) DisableTOTP(tenantID, userID string, request *models.TwoFactorCodeRequest) error {
+	if request == nil {
+		return errors.New("request cannot be nil")
+	}
+	if err := validateStruct(request); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(tenantID, userID)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled() {
+		return ErrTwoFactorNotEnabled
+	}
+
+	now := s.now()
+	if user.LockedUntil != nil && now.Before(*user.LockedUntil) {
+		return &AccountLockedError{Until: *user.LockedUntil}
+	}
+	totpCode, recoveryCode := request.Code, ""
+	if len(strings.ReplaceAll(request.Code, " ", "")) != auth.TOTPDigits {
+		totpCode, recoveryCode = "", request.Code
+	}
+	verified, err := s.verifySecondFactor(user, totpCode, recoveryCode, now)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return s.failLogin(user, now, ErrInvalidTwoFactorCode)
+	}
+	return s.userRepo.DisableTOTP(tenantID, userID)
+}
+
+// Refresh exchanges a refresh token for new tokens. The refresh token is rotated: it stops
+// working, and using it again revokes the whole session.
+func (s *accountService) Refresh(tenantID, refreshToken string) (*models.AuthTokens, error) {
+	token, err := s.sessionRepo.GetRefreshToken(auth.HashToken(refreshToken))
+	if errors.Is(err, repository.ErrRefreshTokenNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	session := token.Session
+	if session.TenantID != tenantID || session.RevokedAt != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	now := s.now()
+	if token.RotatedAt != nil {
+		return nil, s.revokeReusedSession(session)
+	}
+	if !now.Before(token.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	newRefreshToken, replacement, err := s.newRefreshToken(session.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sessionRepo.Rotate(token.ID, replacement, now); err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenRotated) {
+			// Another request rotated the token first
+			return nil, s.revokeReusedSession(session)
+		}
+		return nil, err
+	}
+	return s.issueTokens(session, newRefreshToken, replacement)
+}
+
+// revokeReusedSession ends a session whose rotated refresh token was used again
+func (s *accountService) revokeReusedSession(session *models.Session) error {
+	log.Printf("Refresh token reuse detected for session %s of user %s, revoking the session", session.ID, session.UserID)
+	if err := s.sessionRepo.Revoke(session.TenantID, session.UserID, session.ID, models.SessionRevokedReuse, s.now()); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// Logout ends the session of a refresh token. Access tokens already issued keep working until
+// they expire. Unknown tokens are ignored, so that signing out twice succeeds.
+func (s *accountService) Logout(tenantID, refreshToken string) error {
+	token, err := s.sessionRepo.GetRefreshToken(auth.HashToken(refreshToken))
+	if errors.Is(err, repository.ErrRefreshTokenNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	session := token.Session
+	if session.TenantID != tenantID {
+		return nil
+	}
+	return s.sessionRepo.Revoke(tenantID, session.UserID, session.ID, models.SessionRevokedLogout, s.now())
+}
+
+// ListSessions retrieves the active sessions of a user
+func (s *accountService) ListSessions(tenantID, userID string) ([]models.SessionResponse, error) {
+	sessions, err := s.sessionRepo.List(tenantID, userID, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = models.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+		}
+	}
+	return responses, nil
+}
+
+// RevokeSession ends a session of a user, such as one on a lost device
+func (s *accountService) RevokeSession(tenantID, userID, sessionID string) error {
+	return s.sessionRepo.Revoke(tenantID, userID, sessionID, models.SessionRevokedByUser, s.now())
+}
+
+// RequestPasswordReset emails a password reset token to the account with the given email. It
+// succeeds whether or not the account exists, so that it does not tell which addresses have
+// accounts; failures to send the email are only logged for the same reason.
+func (s *accountService) RequestPasswordReset(tenantID string, request *models.PasswordResetRequest) error {
+	if request == nil {
+		return errors.New("request cannot be nil")
+	}
+	request.Email = normalizeEmail(request.Email)
+	if err := validateStruct(request); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByEmail(tenantID, request.Email)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	resetToken, err := auth.NewToken(auth.PasswordResetTokenPrefix)
+	if err != nil {
+		return err
+	}
+	expiresAt := s.now().Add(s.policy.PasswordResetTTL)
+	err = s.userRepo.CreatePasswordReset(&models.PasswordResetToken{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		UserID:    user.ID,
+		TokenHash: auth.HashToken(resetToken),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.mailer.Send(s.passwordResetMessage(user.Email, resetToken, expiresAt)); err != nil {
+		log.Printf("Failed to send the password reset email of user %s: %v", user.ID, err)
+	}
+	return nil
+}
+
+// passwordResetMessage builds the email carrying a password reset token
+func (s *accountService) passwordResetMessage(email, resetToken string, expiresAt time.Time) *mail.Message {
+	instructions := "Use this token to choose a new password:\n\n" + resetToken
+	if s.policy.PasswordResetURL != "" {
+		link := s.policy.PasswordResetURL
+		separator := "?"
+		if strings.Contains(link, "?") {
+			separator = "&"
+		}
+		instructions = "Open this link to choose a new password:\n\n" + link + separator + "token=" + url.QueryEscape(resetToken)
+	}
+
+	return &mail.Message{
+		To:      email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Someone asked to reset the password of your account.\n\n%s\n\n"+
+			"The link expires at %s. If you did not ask for it, you can ignore this email.\n",
+			instructions, expiresAt.UTC().Format(time.RFC1123)),
+	}
+}
+
+// ResetPassword sets a new password with a password reset token. The token can only be used
+// once; the lockout of the account is lifted and every session is ended.
+func (s *accountService) ResetPassword(tenantID string, request *models.PasswordResetConfirmRequest) error {
+	if request == nil {
+		return errors.New("request cannot be nil")
+	}
+	if err := validateStruct(request); err != nil {
+		return err
+	}
+
+	now := s.now()
+	token, err := s.userRepo.ConsumePasswordReset(tenantID, auth.HashToken(request.Token), now)
+	if errors.Is(err, repository.ErrPasswordResetInvalid) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+
+	passwordHash, err := auth.HashPassword(request.Password, s.passwordParams)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.UpdatePassword(tenantID, token.UserID, passwordHash); err != nil {
+		return err
+	}
+	return s.sessionRepo.RevokeAll(tenantID, token.UserID, models.SessionRevokedPasswordReset, now)
+}
+
+// newRefreshToken creates a refresh token of a session, returning the token handed to the
+// client and the record storing its hash
+func (s *accountService) newRefreshToken(sessionID string, now time.Time) (string, *models.RefreshToken, error) {
+	refreshToken, err := auth.NewToken(auth.RefreshTokenPrefix)
+	if err != nil {
+		return "", nil, err
+	}
+	return refreshToken, &models.RefreshToken{
+		ID:        uuid.New().String(),
+		SessionID: sessionID,
+		TokenHash: auth.HashToken(refreshToken),
+		ExpiresAt: now.Add(s.policy.RefreshTokenTTL),
+	}, nil
+}
+
+// issueTokens signs an access token for the user of a session and returns it with the refresh token
+func (s *accountService) issueTokens(session *models.Session, refreshToken string, token *models.RefreshToken) (*models.AuthTokens, error) {
+	accessToken, expiresAt, err := s.issuer.Issue(session.UserID, session.TenantID, session.TwoFactor)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AuthTokens{
+		AccessToken:           accessToken,
+		TokenType:             "Bearer",
+		ExpiresIn:             int64(expiresAt.Sub(s.now()).Round(time.Second).Seconds()),
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: token.ExpiresAt,
+		SessionID:             session.ID,
+	}, nil
+}
+
+// userToResponse converts an account to its response model, without the password
+func userToResponse(user *models.User) *models.UserResponse {
+	return &models.UserResponse{ID: user.ID, Email: user.Email, TwoFactorEnabled: user.TwoFactorEnabled(), CreatedAt: user.CreatedAt}
+}
+
+// truncate shortens s to at most max bytes
+func truncate(s string, max int) string {
+	if len(s) > max {
+		return s[:max]
+	}
+	return s
+}
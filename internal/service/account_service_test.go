@@ -0,0 +1,401 @@
+package service
+
+import (
+	"BlogManagment/internal/auth"
+	"BlogManagment/internal/mail"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB opens an empty in-memory SQLite database with the given tables
+func newTestDB(t *testing.T, tables ...interface{}) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+uuid.New().String()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(tables...))
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+// MockTokenIssuer is a mock implementation of TokenIssuer
+type MockTokenIssuer struct {
+	mock.Mock
+}
+
+func (m *MockTokenIssuer) Issue(subject, tenantID string, twoFactor bool) (string, time.Time, error) {
+	args := m.Called(subject, tenantID, twoFactor)
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+// MockMailer is a mock implementation of Mailer
+type MockMailer struct {
+	mock.Mock
+}
+
+func (m *MockMailer) Send(message *mail.Message) error {
+	args := m.Called(message)
+	return args.Error(0)
+}
+
+// sent returns the messages the mailer was asked to send
+func (m *MockMailer) sent() []*mail.Message {
+	var messages []*mail.Message
+	for _, call := range m.Calls {
+		if call.Method == "Send" {
+			messages = append(messages, call.Arguments.Get(0).(*mail.Message))
+		}
+	}
+	return messages
+}
+
+// accountTestEnv is an account service on an in-memory database, with a clock advanced by advance
+type accountTestEnv struct {
+	service  *accountService
+	db       *gorm.DB
+	users    repository.UserRepository
+	sessions repository.SessionRepository
+	issuer   *MockTokenIssuer
+	mailer   *MockMailer
+	advance  func(time.Duration)
+}
+
+// newTestAccountService creates an account service whose access tokens are "access-token", or
+// "access-token:mfa" when signed in with a second factor
+func newTestAccountService(t *testing.T) *accountTestEnv {
+	t.Helper()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	db := newTestDB(t, &models.User{}, &models.Session{}, &models.RefreshToken{}, &models.PasswordResetToken{}, &models.RecoveryCode{})
+	env := &accountTestEnv{
+		db:       db,
+		users:    repository.NewUserRepository(db),
+		sessions: repository.NewSessionRepository(db),
+		issuer:   &MockTokenIssuer{},
+		mailer:   &MockMailer{},
+		advance:  func(d time.Duration) { now = now.Add(d) },
+	}
+	env.issuer.On("Issue", mock.Anything, mock.Anything, false).Return("access-token", now.Add(15*time.Minute), nil).Maybe()
+	env.issuer.On("Issue", mock.Anything, mock.Anything, true).Return("access-token:mfa", now.Add(15*time.Minute), nil).Maybe()
+	env.service = newAccountService(env.users, env.sessions, env.issuer, env.mailer, AccountPolicy{
+		RefreshTokenTTL:  24 * time.Hour,
+		MaxFailedLogins:  3,
+		LockoutDuration:  15 * time.Minute,
+		PasswordResetTTL: time.Hour,
+		PasswordResetURL: "https://blog.example.com/reset",
+		TOTPIssuer:       "Blog",
+	}, auth.PasswordParams{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	env.service.now = clock
+	return env
+}
+
+// user retrieves an account of the acme tenant
+func (env *accountTestEnv) user(t *testing.T, id string) *models.User {
+	t.Helper()
+	user, err := env.users.GetByID("acme", id)
+	require.NoError(t, err)
+	return user
+}
+
+// recoveryCodes counts the stored recovery codes whose hash is codeHash, or every one if it is empty
+func (env *accountTestEnv) recoveryCodes(t *testing.T, codeHash string) int64 {
+	t.Helper()
+	query := env.db.Model(&models.RecoveryCode{})
+	if codeHash != "" {
+		query = query.Where("code_hash = ?", codeHash)
+	}
+	var count int64
+	require.NoError(t, query.Count(&count).Error)
+	return count
+}
+
+// register creates an account and signs in to it
+func (env *accountTestEnv) register(t *testing.T, email, password string) (*models.UserResponse, *models.AuthTokens) {
+	t.Helper()
+	user, err := env.service.Register("acme", &models.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+	tokens, err := env.service.Login("acme", &models.LoginRequest{Email: email, Password: password}, "curl/8.0", "203.0.113.7")
+	require.NoError(t, err)
+	return user, tokens
+}
+
+func TestAccountService_Register(t *testing.T) {
+	env := newTestAccountService(t)
+
+	user, err := env.service.Register("acme", &models.RegisterRequest{Email: " Alice@Example.com ", Password: "correct horse battery staple"})
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", user.Email)
+	assert.True(t, strings.HasPrefix(env.user(t, user.ID).PasswordHash, "$argon2id$"), "only a hash is stored")
+
+	_, err = env.service.Register("acme", &models.RegisterRequest{Email: "ALICE@example.com", Password: "correct horse battery staple"})
+	assert.ErrorIs(t, err, ErrEmailTaken)
+
+	_, err = env.service.Register("globex", &models.RegisterRequest{Email: "alice@example.com", Password: "correct horse battery staple"})
+	assert.NoError(t, err, "accounts belong to a tenant")
+
+	_, err = env.service.Register("acme", &models.RegisterRequest{Email: "bob@example.com", Password: "too short"})
+	assert.ErrorContains(t, err, "password")
+	_, err = env.service.Register("acme", &models.RegisterRequest{Email: "bob", Password: "correct horse battery staple"})
+	assert.ErrorContains(t, err, "email address")
+}
+
+func TestAccountService_Login(t *testing.T) {
+	env := newTestAccountService(t)
+	user, tokens := env.register(t, "alice@example.com", "correct horse battery staple")
+
+	assert.Equal(t, "access-token", tokens.AccessToken)
+	env.issuer.AssertCalled(t, "Issue", user.ID, "acme", false)
+	assert.Equal(t, "Bearer", tokens.TokenType)
+	assert.Equal(t, int64(900), tokens.ExpiresIn)
+	assert.True(t, strings.HasPrefix(tokens.RefreshToken, auth.RefreshTokenPrefix))
+	_, err := env.sessions.GetRefreshToken(auth.HashToken(tokens.RefreshToken))
+	assert.NoError(t, err, "only a hash of the refresh token is stored")
+
+	sessions, err := env.service.ListSessions("acme", user.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, tokens.SessionID, sessions[0].ID)
+	assert.Equal(t, "curl/8.0", sessions[0].UserAgent)
+	assert.Equal(t, "203.0.113.7", sessions[0].IP)
+
+	_, err = env.service.Login("acme", &models.LoginRequest{Email: "alice@example.com", Password: "wrong"}, "", "")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = env.service.Login("acme", &models.LoginRequest{Email: "nobody@example.com", Password: "wrong"}, "", "")
+	assert.ErrorIs(t, err, ErrInvalidCredentials, "unknown accounts look like wrong passwords")
+	_, err = env.service.Login("globex", &models.LoginRequest{Email: "alice@example.com", Password: "correct horse battery staple"}, "", "")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestAccountService_Login_Lockout(t *testing.T) {
+	env := newTestAccountService(t)
+	user, _ := env.register(t, "alice@example.com", "correct horse battery staple")
+	wrong := &models.LoginRequest{Email: "alice@example.com", Password: "wrong"}
+	right := &models.LoginRequest{Email: "alice@example.com", Password: "correct horse battery staple"}
+
+	for i := 0; i < 2; i++ {
+		_, err := env.service.Login("acme", wrong, "", "")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	}
+	_, err := env.service.Login("acme", wrong, "", "")
+	var locked *AccountLockedError
+	require.ErrorAs(t, err, &locked)
+	assert.Equal(t, env.service.now().Add(15*time.Minute), locked.Until)
+
+	_, err = env.service.Login("acme", right, "", "")
+	assert.ErrorAs(t, err, &locked, "the right password does not open a locked account")
+
+	env.advance(15 * time.Minute)
+	_, err = env.service.Login("acme", right, "", "")
+	require.NoError(t, err)
+	assert.Nil(t, env.user(t, user.ID).LockedUntil)
+	assert.Zero(t, env.user(t, user.ID).FailedLogins)
+}
+
+func TestAccountService_Refresh(t *testing.T) {
+	env := newTestAccountService(t)
+	user, tokens := env.register(t, "alice@example.com", "correct horse battery staple")
+
+	env.advance(time.Hour)
+	refreshed, err := env.service.Refresh("acme", tokens.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, tokens.SessionID, refreshed.SessionID)
+	assert.NotEqual(t, tokens.RefreshToken, refreshed.RefreshToken, "refresh tokens are rotated")
+	assert.Equal(t, env.service.now().Add(24*time.Hour), refreshed.RefreshTokenExpiresAt)
+
+	_, err = env.service.Refresh("globex", refreshed.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken, "tokens only work for their tenant")
+	_, err = env.service.Refresh("acme", "rt_unknown")
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	_, err = env.service.Refresh("acme", tokens.RefreshToken)
+	assert.ErrorIs(t, err, ErrRefreshTokenReused)
+	stored, err := env.sessions.GetRefreshToken(auth.HashToken(tokens.RefreshToken))
+	require.NoError(t, err)
+	assert.Equal(t, models.SessionRevokedReuse, stored.Session.RevokedReason)
+
+	_, err = env.service.Refresh("acme", refreshed.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken, "reuse revokes the newest token of the session too")
+	sessions, err := env.service.ListSessions("acme", user.ID)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+}
+
+func TestAccountService_Refresh_Expired(t *testing.T) {
+	env := newTestAccountService(t)
+	_, tokens := env.register(t, "alice@example.com", "correct horse battery staple")
+
+	env.advance(24 * time.Hour)
+	_, err := env.service.Refresh("acme", tokens.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+}
+
+func TestAccountService_LogoutAndRevokeSession(t *testing.T) {
+	env := newTestAccountService(t)
+	user, tokens := env.register(t, "alice@example.com", "correct horse battery staple")
+	other, err := env.service.Login("acme", &models.LoginRequest{Email: "alice@example.com", Password: "correct horse battery staple"}, "", "")
+	require.NoError(t, err)
+
+	require.NoError(t, env.service.Logout("acme", tokens.RefreshToken))
+	require.NoError(t, env.service.Logout("acme", tokens.RefreshToken), "signing out twice succeeds")
+	require.NoError(t, env.service.Logout("acme", "rt_unknown"))
+	_, err = env.service.Refresh("acme", tokens.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	assert.ErrorIs(t, env.service.RevokeSession("acme", "someone-else", other.SessionID), repository.ErrSessionNotFound)
+	require.NoError(t, env.service.RevokeSession("acme", user.ID, other.SessionID))
+	_, err = env.service.Refresh("acme", other.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+}
+
+func TestAccountService_PasswordReset(t *testing.T) {
+	env := newTestAccountService(t)
+	_, tokens := env.register(t, "alice@example.com", "correct horse battery staple")
+
+	env.mailer.On("Send", mock.Anything).Return(nil)
+
+	require.NoError(t, env.service.RequestPasswordReset("acme", &models.PasswordResetRequest{Email: "nobody@example.com"}))
+	env.mailer.AssertNotCalled(t, "Send", mock.Anything, "unknown addresses get no email, and no error")
+
+	require.NoError(t, env.service.RequestPasswordReset("acme", &models.PasswordResetRequest{Email: "Alice@example.com"}))
+	require.Len(t, env.mailer.sent(), 1)
+	message := env.mailer.sent()[0]
+	assert.Equal(t, "alice@example.com", message.To)
+	_, after, found := strings.Cut(message.Body, "https://blog.example.com/reset?token=")
+	require.True(t, found, message.Body)
+	resetToken, _, _ := strings.Cut(after, "\n")
+	assert.True(t, strings.HasPrefix(resetToken, auth.PasswordResetTokenPrefix))
+
+	confirm := &models.PasswordResetConfirmRequest{Token: resetToken, Password: "another horse battery staple"}
+	assert.ErrorIs(t, env.service.ResetPassword("globex", confirm), ErrInvalidResetToken)
+
+	require.NoError(t, env.service.ResetPassword("acme", confirm))
+	assert.ErrorIs(t, env.service.ResetPassword("acme", confirm), ErrInvalidResetToken, "reset tokens work once")
+
+	_, err := env.service.Refresh("acme", tokens.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken, "resetting the password ends every session")
+	_, err = env.service.Login("acme", &models.LoginRequest{Email: "alice@example.com", Password: "correct horse battery staple"}, "", "")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = env.service.Login("acme", &models.LoginRequest{Email: "alice@example.com", Password: "another horse battery staple"}, "", "")
+	assert.NoError(t, err)
+}
+
+func TestAccountService_PasswordReset_MailerFailure(t *testing.T) {
+	env := newTestAccountService(t)
+	env.register(t, "alice@example.com", "correct horse battery staple")
+	env.mailer.On("Send", mock.Anything).Return(errors.New("mail server down"))
+
+	assert.NoError(t, env.service.RequestPasswordReset("acme", &models.PasswordResetRequest{Email: "alice@example.com"}),
+		"failures to send are only logged, so that they do not tell which addresses have accounts")
+}
+
+// enableTwoFactor enrolls an authenticator app for a user and returns its secret and recovery codes
+func (env *accountTestEnv) enableTwoFactor(t *testing.T, userID string) (string, []string) {
+	t.Helper()
+	enrollment, err := env.service.EnrollTOTP("acme", userID)
+	require.NoError(t, err)
+	code, err := auth.TOTPCode(enrollment.Secret, env.service.now())
+	require.NoError(t, err)
+	codes, err := env.service.ConfirmTOTP("acme", userID, &models.TwoFactorCodeRequest{Code: code})
+	require.NoError(t, err)
+	return enrollment.Secret, codes.RecoveryCodes
+}
+
+func TestAccountService_EnrollTOTP(t *testing.T) {
+	env := newTestAccountService(t)
+	user, _ := env.register(t, "alice@example.com", "correct horse battery staple")
+
+	enrollment, err := env.service.EnrollTOTP("acme", user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth://totp/Blog:alice@example.com?algorithm=SHA1&digits=6&issuer=Blog&period=30&secret="+enrollment.Secret, enrollment.URI)
+	assert.False(t, env.user(t, user.ID).TwoFactorEnabled(), "enrolling alone does not require the app")
+
+	_, err = env.service.ConfirmTOTP("acme", user.ID, &models.TwoFactorCodeRequest{Code: "000000"})
+	assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+
+	code, err := auth.TOTPCode(enrollment.Secret, env.service.now())
+	require.NoError(t, err)
+	codes, err := env.service.ConfirmTOTP("acme", user.ID, &models.TwoFactorCodeRequest{Code: code})
+	require.NoError(t, err)
+	assert.Len(t, codes.RecoveryCodes, auth.RecoveryCodeCount)
+	assert.True(t, env.user(t, user.ID).TwoFactorEnabled())
+	assert.Equal(t, int64(1), env.recoveryCodes(t, auth.HashRecoveryCode(codes.RecoveryCodes[0])), "only hashes are stored")
+
+	_, err = env.service.EnrollTOTP("acme", user.ID)
+	assert.ErrorIs(t, err, ErrTwoFactorEnabled)
+}
+
+func TestAccountService_Login_TwoFactor(t *testing.T) {
+	env := newTestAccountService(t)
+	user, _ := env.register(t, "alice@example.com", "correct horse battery staple")
+	secret, recoveryCodes := env.enableTwoFactor(t, user.ID)
+	login := func(totpCode, recoveryCode string) (*models.AuthTokens, error) {
+		return env.service.Login("acme", &models.LoginRequest{
+			Email: "alice@example.com", Password: "correct horse battery staple", TOTPCode: totpCode, RecoveryCode: recoveryCode,
+		}, "", "")
+	}
+
+	_, err := login("", "")
+	assert.ErrorIs(t, err, ErrTwoFactorRequired)
+
+	code, err := auth.TOTPCode(secret, env.service.now())
+	require.NoError(t, err)
+	_, err = login(code, "")
+	assert.ErrorIs(t, err, ErrInvalidTwoFactorCode, "the code confirming the app cannot be used again")
+
+	env.advance(auth.TOTPPeriod)
+	code, err = auth.TOTPCode(secret, env.service.now())
+	require.NoError(t, err)
+	tokens, err := login(code, "")
+	require.NoError(t, err)
+	assert.Equal(t, "access-token:mfa", tokens.AccessToken)
+	env.issuer.AssertCalled(t, "Issue", user.ID, "acme", true)
+
+	refreshed, err := env.service.Refresh("acme", tokens.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, tokens.AccessToken, refreshed.AccessToken, "refreshed tokens keep the second factor")
+
+	tokens, err = login("", strings.ToUpper(recoveryCodes[0]))
+	require.NoError(t, err)
+	assert.Equal(t, "access-token:mfa", tokens.AccessToken)
+	_, err = login("", recoveryCodes[0])
+	assert.ErrorIs(t, err, ErrInvalidTwoFactorCode, "recovery codes work once")
+
+	_, err = login("000000", "")
+	assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+	_, err = login("", "wrong")
+	var locked *AccountLockedError
+	assert.ErrorAs(t, err, &locked, "wrong codes count as failed sign-ins")
+}
+
+func TestAccountService_DisableTOTP(t *testing.T) {
+	env := newTestAccountService(t)
+	user, _ := env.register(t, "alice@example.com", "correct horse battery staple")
+
+	assert.ErrorIs(t, env.service.DisableTOTP("acme", user.ID, &models.TwoFactorCodeRequest{Code: "123456"}), ErrTwoFactorNotEnabled)
+
+	_, recoveryCodes := env.enableTwoFactor(t, user.ID)
+	assert.ErrorIs(t, env.service.DisableTOTP("acme", user.ID, &models.TwoFactorCodeRequest{Code: "123456"}), ErrInvalidTwoFactorCode)
+	require.NoError(t, env.service.DisableTOTP("acme", user.ID, &models.TwoFactorCodeRequest{Code: recoveryCodes[0]}))
+
+	assert.False(t, env.user(t, user.ID).TwoFactorEnabled())
+	assert.Zero(t, env.recoveryCodes(t, ""))
+	tokens, err := env.service.Login("acme", &models.LoginRequest{Email: "alice@example.com", Password: "correct horse battery staple"}, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "access-token", tokens.AccessToken)
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"BlogManagment/internal/auth"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/repository"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// lastUsedPrecision is how stale the last-used time of a key may get before it is updated,
+// so that busy keys do not cause a write on every request
+const lastUsedPrecision = time.Minute
+
+// API key authentication errors
+var (
+	ErrInvalidAPIKey = errors.New("invalid API key")
+	ErrAPIKeyExpired = errors.New("API key has expired")
+	ErrAPIKeyRevoked = errors.New("API key has been revoked")
+)
+
+// ErrInvalidGracePeriod is returned when a rotation names a grace period that is not a duration
+var ErrInvalidGracePeriod = errors.New("invalid grace_period, expected a duration such as 24h")
+
+// APIKeyService defines the interface for managing the API keys of machine clients and
+// authenticating the requests made with them
+type APIKeyService interface {
+	CreateAPIKey(tenantID string, creator *rbac.Actor, request *models.APIKeyCreateRequest) (*models.APIKeyResponse, error)
+	GetAPIKey(tenantID, id string) (*models.APIKeyResponse, error)
+	ListAPIKeys(tenantID string) ([]models.APIKeyResponse, error)
+	RotateAPIKey(tenantID, id string, request *models.APIKeyRotateRequest) (*models.APIKeyResponse, error)
+	RevokeAPIKey(tenantID, id string) error
+	Verify(key string) (*auth.Principal, error)
+}
+
+// apiKeyService implements APIKeyService interface
+type apiKeyService struct {
+	apiKeyRepo    repository.APIKeyRepository
+	defaultTTL    time.Duration
+	rotationGrace time.Duration
+	now           func() time.Time
+}
+
+// NewAPIKeyService creates a new API key service instance. Keys expire after defaultTTL unless
+// created with an expiry, and rotated keys keep working for rotationGrace unless the rotation
+// asks for another grace period.
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository, defaultTTL, rotationGrace time.Duration) APIKeyService {
+	return &apiKeyService{apiKeyRepo: apiKeyRepo, defaultTTL: defaultTTL, rotationGrace: rotationGrace, now: time.Now}
+}
+
+// CreateAPIKey creates a key limited to the scopes of the request. The creator must hold every
+// permission it grants. The response is the only place the key is returned.
+func (s *apiKeyService) CreateAPIKey(tenantID string, creator *rbac.Actor, request *models.APIKeyCreateRequest) (*models.APIKeyResponse, error) {
+	if request == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	if err := validateStruct(request); err != nil {
+		return nil, err
+	}
+	permissions, err := rbac.ExpandScopes(request.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	for _, permission := range permissions {
+		if err := creator.Check(permission); err != nil {
+			return nil, err
+		}
+	}
+
+	now := s.now()
+	expiresAt := now.Add(s.defaultTTL)
+	if request.ExpiresAt != nil {
+		if !request.ExpiresAt.After(now) {
+			return nil, errors.New("expires_at must be in the future")
+		}
+		expiresAt = *request.ExpiresAt
+	}
+
+	scopes := make([]string, len(permissions))
+	for i, permission := range permissions {
+		scopes[i] = string(permission)
+	}
+	key := &models.APIKey{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		Name:      request.Name,
+		Scopes:    scopes,
+		CreatedBy: creator.Subject,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}
+	secret, err := s.setSecret(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apiKeyRepo.Create(key); err != nil {
+		return nil, err
+	}
+
+	response := s.apiKeyToResponse(key)
+	response.Key = auth.FormatAPIKey(key.ID, secret)
+	return response, nil
+}
+
+// GetAPIKey retrieves an API key without its secret
+func (s *apiKeyService) GetAPIKey(tenantID, id string) (*models.APIKeyResponse, error) {
+	key, err := s.apiKeyRepo.Get(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.apiKeyToResponse(key), nil
+}
+
+// ListAPIKeys retrieves the API keys of a tenant without their secrets
+func (s *apiKeyService) ListAPIKeys(tenantID string) ([]models.APIKeyResponse, error) {
+	keys, err := s.apiKeyRepo.List(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.APIKeyResponse, len(keys))
+	for i := range keys {
+		responses[i] = *s.apiKeyToResponse(&keys[i])
+	}
+	return responses, nil
+}
+
+// RotateAPIKey replaces a key with a new one of the same name and scopes. The old key keeps
+// working until the grace period ends, or until it expires if that is sooner.
+func (s *apiKeyService) RotateAPIKey(tenantID, id string, request *models.APIKeyRotateRequest) (*models.APIKeyResponse, error) {
+	grace := s.rotationGrace
+	if request != nil && request.GracePeriod != "" {
+		parsed, err := time.ParseDuration(request.GracePeriod)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidGracePeriod, request.GracePeriod)
+		}
+		grace = parsed
+	}
+
+	old, err := s.apiKeyRepo.Get(tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if old.Status(now) != models.APIKeyStatusActive {
+		return nil, repository.ErrAPIKeyNotRotatable
+	}
+
+	oldExpiresAt := now.Add(grace)
+	if old.ExpiresAt.Before(oldExpiresAt) {
+		oldExpiresAt = old.ExpiresAt
+	}
+	// The replacement gets the lifetime the old key was created with
+	key := &models.APIKey{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		Name:      old.Name,
+		Scopes:    old.Scopes,
+		CreatedBy: old.CreatedBy,
+		ExpiresAt: now.Add(old.ExpiresAt.Sub(old.CreatedAt)),
+		CreatedAt: now,
+	}
+	secret, err := s.setSecret(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apiKeyRepo.Rotate(tenantID, id, oldExpiresAt, key); err != nil {
+		return nil, err
+	}
+
+	response := s.apiKeyToResponse(key)
+	response.Key = auth.FormatAPIKey(key.ID, secret)
+	return response, nil
+}
+
+// RevokeAPIKey disables a key immediately
+func (s *apiKeyService) RevokeAPIKey(tenantID, id string) error {
+	return s.apiKeyRepo.Revoke(tenantID, id, s.now())
+}
+
+// Verify authenticates a request made with an API key and returns the principal of the key,
+// restricted to its tenant and scopes
+func (s *apiKeyService) Verify(key string) (*auth.Principal, error) {
+	id, secret, err := auth.ParseAPIKey(key)
+	if err != nil {
+		return nil, ErrInvalidAPIKey
+	}
+	stored, err := s.apiKeyRepo.GetByID(id)
+	if errors.Is(err, repository.ErrAPIKeyNotFound) {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !auth.APIKeySecretMatches(secret, stored.SecretHash) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := s.now()
+	switch stored.Status(now) {
+	case models.APIKeyStatusRevoked:
+		return nil, ErrAPIKeyRevoked
+	case models.APIKeyStatusExpired:
+		return nil, ErrAPIKeyExpired
+	}
+
+	if stored.LastUsedAt == nil || now.Sub(*stored.LastUsedAt) >= lastUsedPrecision {
+		if err := s.apiKeyRepo.UpdateLastUsed(stored.ID, now); err != nil {
+			log.Printf("Failed to record use of API key %s: %v", stored.ID, err)
+		}
+	}
+
+	return &auth.Principal{Subject: stored.ID, Kind: auth.KindAPIKey, TenantID: stored.TenantID, Scopes: stored.Scopes}, nil
+}
+
+// setSecret generates the secret of a key, stores its hash in the key and returns the secret
+func (s *apiKeyService) setSecret(key *models.APIKey) (string, error) {
+	secret, err := auth.NewAPIKeySecret()
+	if err != nil {
+		return "", err
+	}
+	key.SecretHash = auth.HashAPIKeySecret(secret)
+	return secret, nil
+}
+
+// apiKeyToResponse converts an APIKey model to APIKeyResponse without its secret
+func (s *apiKeyService) apiKeyToResponse(key *models.APIKey) *models.APIKeyResponse {
+	return &models.APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Scopes:     key.Scopes,
+		Status:     key.Status(s.now()),
+		CreatedBy:  key.CreatedBy,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		ReplacedBy: key.ReplacedBy,
+		CreatedAt:  key.CreatedAt,
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"BlogManagment/internal/auth"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestAPIKeyService creates a service on an in-memory database whose clock is advanced by the
+// returned function
+func newTestAPIKeyService(t *testing.T) (*apiKeyService, repository.APIKeyRepository, func(time.Duration)) {
+	t.Helper()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := repository.NewAPIKeyRepository(newTestDB(t, &models.APIKey{}))
+	s := NewAPIKeyService(repo, 30*24*time.Hour, time.Hour).(*apiKeyService)
+	s.now = func() time.Time { return now }
+	return s, repo, func(d time.Duration) { now = now.Add(d) }
+}
+
+// storedAPIKey retrieves a key from repo
+func storedAPIKey(t *testing.T, repo repository.APIKeyRepository, id string) *models.APIKey {
+	t.Helper()
+	key, err := repo.GetByID(id)
+	require.NoError(t, err)
+	return key
+}
+
+func TestAPIKeyService_CreateAndVerify(t *testing.T) {
+	s, repo, advance := newTestAPIKeyService(t)
+
+	created, err := s.CreateAPIKey("acme", rbac.Unrestricted("alice"), &models.APIKeyCreateRequest{
+		Name:   "CI",
+		Scopes: []string{"author", "post:publish", "post:create"},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.Key)
+	assert.Equal(t, []string{"post:create", "post:update:own", "post:delete:own", "post:publish"}, created.Scopes)
+	assert.Equal(t, "alice", created.CreatedBy)
+	assert.Equal(t, s.now().Add(30*24*time.Hour), created.ExpiresAt)
+	_, secret, err := auth.ParseAPIKey(created.Key)
+	require.NoError(t, err)
+	assert.Equal(t, auth.HashAPIKeySecret(secret), storedAPIKey(t, repo, created.ID).SecretHash, "only a hash is stored")
+
+	listed, err := s.ListAPIKeys("acme")
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Empty(t, listed[0].Key, "the key is only returned once")
+
+	principal, err := s.Verify(created.Key)
+	require.NoError(t, err)
+	assert.Equal(t, &auth.Principal{Subject: created.ID, Kind: auth.KindAPIKey, TenantID: "acme", Scopes: created.Scopes}, principal)
+	assert.True(t, s.now().Equal(*storedAPIKey(t, repo, created.ID).LastUsedAt))
+
+	advance(30 * time.Second)
+	_, err = s.Verify(created.Key)
+	require.NoError(t, err)
+	assert.True(t, s.now().Add(-30*time.Second).Equal(*storedAPIKey(t, repo, created.ID).LastUsedAt), "recent uses are not recorded again")
+
+	advance(30 * 24 * time.Hour)
+	_, err = s.Verify(created.Key)
+	assert.ErrorIs(t, err, ErrAPIKeyExpired)
+}
+
+func TestAPIKeyService_CreateAPIKey_Scopes(t *testing.T) {
+	s, _, _ := newTestAPIKeyService(t)
+	author := rbac.NewActor("bob", []string{rbac.RoleAuthor}, nil)
+
+	_, err := s.CreateAPIKey("acme", author, &models.APIKeyCreateRequest{Name: "CI", Scopes: []string{"post:publish"}})
+	assert.ErrorIs(t, err, rbac.ErrForbidden, "keys cannot grant more than their creator holds")
+
+	_, err = s.CreateAPIKey("acme", author, &models.APIKeyCreateRequest{Name: "CI", Scopes: []string{"post:everything"}})
+	assert.ErrorContains(t, err, "unknown scope")
+
+	_, err = s.CreateAPIKey("acme", author, &models.APIKeyCreateRequest{Name: "CI"})
+	assert.Error(t, err)
+
+	past := s.now().Add(-time.Hour)
+	_, err = s.CreateAPIKey("acme", author, &models.APIKeyCreateRequest{Name: "CI", Scopes: []string{"post:create"}, ExpiresAt: &past})
+	assert.ErrorContains(t, err, "expires_at")
+}
+
+func TestAPIKeyService_Verify_Invalid(t *testing.T) {
+	s, _, _ := newTestAPIKeyService(t)
+	created, err := s.CreateAPIKey("acme", rbac.Unrestricted(""), &models.APIKeyCreateRequest{Name: "CI", Scopes: []string{"editor"}})
+	require.NoError(t, err)
+	id, _, err := auth.ParseAPIKey(created.Key)
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "not-a-key", auth.FormatAPIKey(id, "wrong"), auth.FormatAPIKey("9b2d6f3e-1c4a-4f6e-8d1a-2b3c4d5e6f70", "secret")} {
+		_, err := s.Verify(key)
+		assert.ErrorIs(t, err, ErrInvalidAPIKey, key)
+	}
+
+	require.NoError(t, s.RevokeAPIKey("acme", created.ID))
+	_, err = s.Verify(created.Key)
+	assert.ErrorIs(t, err, ErrAPIKeyRevoked)
+	assert.ErrorIs(t, s.RevokeAPIKey("globex", created.ID), repository.ErrAPIKeyNotFound)
+}
+
+func TestAPIKeyService_RotateAPIKey(t *testing.T) {
+	s, _, advance := newTestAPIKeyService(t)
+	old, err := s.CreateAPIKey("acme", rbac.Unrestricted(""), &models.APIKeyCreateRequest{Name: "CI", Scopes: []string{"editor"}})
+	require.NoError(t, err)
+
+	_, err = s.RotateAPIKey("acme", old.ID, &models.APIKeyRotateRequest{GracePeriod: "soon"})
+	assert.ErrorIs(t, err, ErrInvalidGracePeriod)
+
+	advance(24 * time.Hour)
+	rotated, err := s.RotateAPIKey("acme", old.ID, &models.APIKeyRotateRequest{GracePeriod: "2h"})
+	require.NoError(t, err)
+	assert.NotEqual(t, old.Key, rotated.Key)
+	assert.Equal(t, old.Scopes, rotated.Scopes)
+	assert.Equal(t, s.now().Add(30*24*time.Hour), rotated.ExpiresAt)
+
+	advance(time.Hour)
+	_, err = s.Verify(old.Key)
+	assert.NoError(t, err, "the old key works during the grace period")
+	_, err = s.Verify(rotated.Key)
+	assert.NoError(t, err)
+
+	_, err = s.RotateAPIKey("acme", old.ID, nil)
+	assert.ErrorIs(t, err, repository.ErrAPIKeyNotRotatable)
+
+	advance(time.Hour)
+	_, err = s.Verify(old.Key)
+	assert.ErrorIs(t, err, ErrAPIKeyExpired)
+	_, err = s.Verify(rotated.Key)
+	assert.NoError(t, err)
+
+	stored, err := s.GetAPIKey("acme", old.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.APIKeyStatusExpired, stored.Status)
+	assert.Equal(t, rotated.ID, stored.ReplacedBy)
+}
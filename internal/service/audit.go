@@ -0,0 +1,94 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// unauditedFields are the fields of a post left out of audit diffs because every change sets them
+var unauditedFields = []string{"id", "created_at", "updated_at"}
+
+// appendAudit records a change to a post in the audit log, within the transaction of repo.
+// before is nil for created posts and after is nil for deleted ones.
+func appendAudit(repo repository.BlogRepository, requester *models.Requester, action, targetID string, before, after *models.BlogResponse) error {
+	changes, err := diffPosts(before, after)
+	if err != nil {
+		return err
+	}
+
+	entry := &models.AuditEntry{
+		Action:    action,
+		TargetID:  targetID,
+		Changes:   changes,
+		CreatedAt: time.Now().UTC(),
+	}
+	if requester != nil {
+		entry.PrincipalKind = requester.Kind
+		entry.Subject = requester.Subject
+		entry.IP = requester.IP
+		entry.RequestID = requester.RequestID
+	}
+	return repo.AppendAudit([]*models.AuditEntry{entry})
+}
+
+// diffPosts returns the fields that differ between two versions of a post, by their JSON names.
+// Fields that are empty in both versions are left out.
+func diffPosts(before, after *models.BlogResponse) (map[string]models.FieldChange, error) {
+	oldFields, err := postFields(before)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := postFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]models.FieldChange)
+	for name, value := range newFields {
+		if old := oldFields[name]; !reflect.DeepEqual(old, value) && !(isEmptyValue(old) && isEmptyValue(value)) {
+			changes[name] = models.FieldChange{Old: old, New: value}
+		}
+	}
+	for name, old := range oldFields {
+		if _, ok := newFields[name]; !ok && !isEmptyValue(old) {
+			changes[name] = models.FieldChange{Old: old}
+		}
+	}
+	return changes, nil
+}
+
+// postFields returns the audited fields of a post as decoded JSON values
+func postFields(post *models.BlogResponse) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	if post == nil {
+		return fields, nil
+	}
+	encoded, err := json.Marshal(post)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	for _, name := range unauditedFields {
+		delete(fields, name)
+	}
+	return fields, nil
+}
+
+// isEmptyValue reports whether a decoded JSON value is null, an empty string or an empty array
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
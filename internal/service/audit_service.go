@@ -0,0 +1,171 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultAuditPageSize is the number of entries returned by ListAuditEntries when no limit is requested
+const defaultAuditPageSize = 50
+
+// maxAuditPageSize bounds the number of entries returned by ListAuditEntries
+const maxAuditPageSize = 500
+
+// auditCSVColumns is the column order of CSV audit exports
+var auditCSVColumns = []string{"id", "created_at", "action", "target_id", "principal_kind", "subject", "ip", "request_id", "changes"}
+
+// AuditService defines the interface for reading and exporting the audit log
+type AuditService interface {
+	ListAuditEntries(tenantID string, filter *models.AuditFilter, limit int) (*models.AuditPage, error)
+	ExportAuditEntries(w io.Writer, tenantID string, filter *models.AuditFilter, format string) (int, error)
+}
+
+// auditService implements AuditService interface
+type auditService struct {
+	auditRepo repository.AuditRepository
+}
+
+// NewAuditService creates a new audit service instance
+func NewAuditService(auditRepo repository.AuditRepository) AuditService {
+	return &auditService{auditRepo: auditRepo}
+}
+
+// ListAuditEntries retrieves a page of the entries of a tenant matching the filter, newest first.
+// The returned NextBefore is passed back as the filter's Before to fetch the following page.
+func (s *auditService) ListAuditEntries(tenantID string, filter *models.AuditFilter, limit int) (*models.AuditPage, error) {
+	if err := ValidateAuditFilter(filter); err != nil {
+		return nil, err
+	}
+	if limit < 0 || limit > maxAuditPageSize {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxAuditPageSize)
+	}
+	if limit == 0 {
+		limit = defaultAuditPageSize
+	}
+
+	// Fetch one extra entry to find out whether another page follows
+	entries, err := s.auditRepo.List(tenantID, filter, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.AuditPage{Entries: entries}
+	if len(entries) > limit {
+		page.Entries = entries[:limit]
+		page.NextBefore = page.Entries[limit-1].ID
+	}
+	return page, nil
+}
+
+// ExportAuditEntries writes every entry of a tenant matching the filter to w as JSON Lines or
+// CSV, oldest first, paging through the repository. The filter's Before is ignored.
+// It returns the number of exported entries.
+func (s *auditService) ExportAuditEntries(w io.Writer, tenantID string, filter *models.AuditFilter, format string) (int, error) {
+	if err := ValidateExportFormat(format); err != nil {
+		return 0, err
+	}
+	if err := ValidateAuditFilter(filter); err != nil {
+		return 0, err
+	}
+
+	var csvWriter *csv.Writer
+	var jsonEncoder *json.Encoder
+	if format == models.TransferFormatCSV {
+		csvWriter = csv.NewWriter(w)
+		if err := csvWriter.Write(auditCSVColumns); err != nil {
+			return 0, err
+		}
+	} else {
+		jsonEncoder = json.NewEncoder(w)
+	}
+
+	count := 0
+	var after int64
+	for {
+		entries, err := s.auditRepo.ListAfter(tenantID, filter, after, exportPageSize)
+		if err != nil {
+			return count, err
+		}
+
+		for i := range entries {
+			if csvWriter != nil {
+				var row []string
+				if row, err = auditEntryToCSV(&entries[i]); err == nil {
+					err = csvWriter.Write(row)
+				}
+			} else {
+				err = jsonEncoder.Encode(&entries[i])
+			}
+			if err != nil {
+				return count, err
+			}
+			count++
+		}
+
+		if csvWriter != nil {
+			csvWriter.Flush()
+			if err := csvWriter.Error(); err != nil {
+				return count, err
+			}
+		}
+
+		if len(entries) < exportPageSize {
+			return count, nil
+		}
+		after = entries[len(entries)-1].ID
+	}
+}
+
+// ValidateAuditFilter checks that a filter names an audited action and a valid time range
+func ValidateAuditFilter(filter *models.AuditFilter) error {
+	if filter == nil {
+		return errors.New("filter cannot be nil")
+	}
+	if filter.Action != "" && !isAuditAction(filter.Action) {
+		return fmt.Errorf("action must be one of: %s", strings.Join(models.AuditActions, " "))
+	}
+	if filter.Since != nil && filter.Until != nil && !filter.Since.Before(*filter.Until) {
+		return errors.New("since must be before until")
+	}
+	if filter.Before < 0 {
+		return errors.New("before must be the id of an entry")
+	}
+	return nil
+}
+
+// isAuditAction reports whether action is an audited action
+func isAuditAction(action string) bool {
+	for _, audited := range models.AuditActions {
+		if action == audited {
+			return true
+		}
+	}
+	return false
+}
+
+// auditEntryToCSV converts an audit entry to a CSV row; changes are written as a JSON object
+func auditEntryToCSV(entry *models.AuditEntry) ([]string, error) {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return nil, err
+	}
+	return []string{
+		strconv.FormatInt(entry.ID, 10),
+		entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+		entry.Action,
+		entry.TargetID,
+		entry.PrincipalKind,
+		entry.Subject,
+		entry.IP,
+		entry.RequestID,
+		string(changes),
+	}, nil
+}
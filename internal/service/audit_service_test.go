@@ -0,0 +1,157 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAuditRepository is a mock implementation of AuditRepository
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) List(tenantID string, filter *models.AuditFilter, limit int) ([]models.AuditEntry, error) {
+	args := m.Called(tenantID, filter, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AuditEntry), args.Error(1)
+}
+
+func (m *MockAuditRepository) ListAfter(tenantID string, filter *models.AuditFilter, after int64, limit int) ([]models.AuditEntry, error) {
+	args := m.Called(tenantID, filter, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AuditEntry), args.Error(1)
+}
+
+// testAuditEntries returns count entries on post-1, oldest first, alternating between
+// creates and updates
+func testAuditEntries(count int) []*models.AuditEntry {
+	entries := make([]*models.AuditEntry, count)
+	for i := 1; i <= count; i++ {
+		action := models.AuditActionPostUpdate
+		if i%2 == 1 {
+			action = models.AuditActionPostCreate
+		}
+		entries[i-1] = &models.AuditEntry{
+			Action:    action,
+			TargetID:  "post-1",
+			Subject:   "alice",
+			Changes:   map[string]models.FieldChange{"title": {Old: "Old", New: "New"}},
+			CreatedAt: time.Date(2024, 1, 1, 0, 0, i, 0, time.UTC),
+		}
+	}
+	return entries
+}
+
+// newTestAuditRepository creates an audit repository on an in-memory database whose acme
+// tenant holds count entries
+func newTestAuditRepository(t *testing.T, count int) repository.AuditRepository {
+	t.Helper()
+	db := newTestDB(t, &models.AuditEntry{})
+	require.NoError(t, repository.NewBlogRepository(db).WithTenant("acme").AppendAudit(testAuditEntries(count)))
+	return repository.NewAuditRepository(db)
+}
+
+func TestAuditService_ListAuditEntries_Pages(t *testing.T) {
+	var entries []models.AuditEntry
+	for i, entry := range testAuditEntries(5) {
+		entry.ID = int64(i + 1)
+		entries = append([]models.AuditEntry{*entry}, entries...)
+	}
+	repo := &MockAuditRepository{}
+	repo.On("List", "acme", &models.AuditFilter{}, 3).Return(entries[:3], nil).Once()
+	repo.On("List", "acme", &models.AuditFilter{Before: 4}, 4).Return(entries[2:], nil).Once()
+	repo.On("List", "acme", &models.AuditFilter{}, defaultAuditPageSize+1).Return(entries, nil).Once()
+	service := NewAuditService(repo)
+
+	page, err := service.ListAuditEntries("acme", &models.AuditFilter{}, 2)
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 2)
+	assert.Equal(t, int64(5), page.Entries[0].ID)
+	assert.Equal(t, int64(4), page.NextBefore)
+
+	page, err = service.ListAuditEntries("acme", &models.AuditFilter{Before: page.NextBefore}, 3)
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 3)
+	assert.Equal(t, int64(3), page.Entries[0].ID)
+	assert.Zero(t, page.NextBefore, "the last page has no cursor")
+
+	_, err = service.ListAuditEntries("acme", &models.AuditFilter{}, 0)
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestAuditService_ListAuditEntries_ValidatesFilter(t *testing.T) {
+	service := NewAuditService(newTestAuditRepository(t, 1))
+	since := time.Now()
+	until := since.Add(-time.Hour)
+
+	for name, test := range map[string]struct {
+		filter *models.AuditFilter
+		limit  int
+		err    string
+	}{
+		"unknown action": {&models.AuditFilter{Action: "post.read"}, 0, "action must be one of: post.create post.update post.delete"},
+		"empty range":    {&models.AuditFilter{Since: &since, Until: &until}, 0, "since must be before until"},
+		"limit too high": {&models.AuditFilter{}, maxAuditPageSize + 1, "limit must be between 1 and 500"},
+		"negative limit": {&models.AuditFilter{}, -1, "limit must be between 1 and 500"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.ListAuditEntries("acme", test.filter, test.limit)
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestAuditService_ExportAuditEntries_JSONL(t *testing.T) {
+	service := NewAuditService(newTestAuditRepository(t, exportPageSize+3))
+	var buffer bytes.Buffer
+
+	count, err := service.ExportAuditEntries(&buffer, "acme", &models.AuditFilter{}, models.TransferFormatJSONL)
+
+	require.NoError(t, err)
+	assert.Equal(t, exportPageSize+3, count, "every page is exported")
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	require.Len(t, lines, count)
+	var last models.AuditEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &last))
+	assert.Equal(t, int64(exportPageSize+3), last.ID, "oldest first")
+	assert.Equal(t, "New", last.Changes["title"].New)
+}
+
+func TestAuditService_ExportAuditEntries_CSV(t *testing.T) {
+	service := NewAuditService(newTestAuditRepository(t, 4))
+	var buffer bytes.Buffer
+
+	count, err := service.ExportAuditEntries(&buffer, "acme", &models.AuditFilter{Action: models.AuditActionPostCreate}, models.TransferFormatCSV)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	rows, err := csv.NewReader(&buffer).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, auditCSVColumns, rows[0])
+	assert.Equal(t, []string{"1", "2024-01-01T00:00:01Z", "post.create", "post-1", "", "alice", "", "",
+		`{"title":{"old":"Old","new":"New"}}`}, rows[1])
+}
+
+func TestAuditService_ExportAuditEntries_RejectsUnknownFormat(t *testing.T) {
+	service := NewAuditService(newTestAuditRepository(t, 1))
+
+	_, err := service.ExportAuditEntries(&bytes.Buffer{}, "acme", &models.AuditFilter{}, "xml")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// appendedAuditEntries returns every entry added to the audit log, in order
+func appendedAuditEntries(m *MockBlogRepository) []*models.AuditEntry {
+	var entries []*models.AuditEntry
+	for _, call := range m.Calls {
+		if call.Method == "AppendAudit" {
+			entries = append(entries, call.Arguments.Get(0).([]*models.AuditEntry)...)
+		}
+	}
+	return entries
+}
+
+func TestBlogService_AuditsChanges(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	requester := &models.Requester{Kind: "user", Subject: "alice", IP: "203.0.113.7", RequestID: "req-1"}
+	service := NewBlogService(mockRepo).WithRequester(requester)
+
+	existing := &models.Blog{ID: "post-1", Slug: "original", Title: "Original", Body: "Body", Status: models.BlogStatusDraft}
+	mockRepo.On("Create", mock.AnythingOfType("*models.Blog")).Return(nil)
+	mockRepo.On("GetByID", "post-1").Return(existing, nil)
+	mockRepo.On("Update", mock.AnythingOfType("*models.Blog")).Return(nil)
+	mockRepo.On("Delete", "post-1").Return(nil)
+
+	created, err := service.CreateBlog(&models.BlogCreateRequest{Title: "New", Body: "Body"})
+	require.NoError(t, err)
+	title := "Renamed"
+	_, err = service.UpdateBlog("post-1", &models.BlogUpdateRequest{Title: &title})
+	require.NoError(t, err)
+	require.NoError(t, service.DeleteBlog("post-1"))
+
+	entries := appendedAuditEntries(mockRepo)
+	require.Len(t, entries, 3)
+	for _, entry := range entries {
+		assert.Equal(t, "user", entry.PrincipalKind)
+		assert.Equal(t, "alice", entry.Subject)
+		assert.Equal(t, "203.0.113.7", entry.IP)
+		assert.Equal(t, "req-1", entry.RequestID)
+		assert.NotContains(t, entry.Changes, "id")
+		assert.NotContains(t, entry.Changes, "updated_at")
+	}
+
+	assert.Equal(t, models.AuditActionPostCreate, entries[0].Action)
+	assert.Equal(t, created.ID, entries[0].TargetID)
+	assert.Equal(t, models.FieldChange{New: "New"}, entries[0].Changes["title"])
+	assert.NotContains(t, entries[0].Changes, "description", "fields that stay empty are left out")
+
+	assert.Equal(t, models.AuditActionPostUpdate, entries[1].Action)
+	assert.Equal(t, map[string]models.FieldChange{"title": {Old: "Original", New: "Renamed"}}, entries[1].Changes)
+
+	assert.Equal(t, models.AuditActionPostDelete, entries[2].Action)
+	assert.Equal(t, "post-1", entries[2].TargetID)
+	assert.Equal(t, models.FieldChange{Old: "Renamed"}, entries[2].Changes["title"])
+}
+
+func TestBlogService_AuditFailureFailsChange(t *testing.T) {
+	mockRepo := &MockBlogRepository{}
+	service := NewBlogService(mockRepo)
+
+	mockRepo.On("Transaction")
+	mockRepo.On("Create", mock.AnythingOfType("*models.Blog")).Return(nil)
+	mockRepo.On("AppendAudit", mock.Anything).Return(errors.New("audit log unavailable"))
+
+	response, err := service.CreateBlog(&models.BlogCreateRequest{Title: "Title", Body: "Body"})
+
+	assert.EqualError(t, err, "audit log unavailable")
+	assert.Nil(t, response)
+	mockRepo.AssertNotCalled(t, "AppendEvents", mock.Anything)
+}
+
+func TestBlogService_BulkBlogs_AuditsEachOperation(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogService(mockRepo).WithRequester(&models.Requester{Kind: "api_key", Subject: "key-1"})
+
+	request := parseBulkRequest(t, `{"mode": "atomic", "operations": [
+		{"action": "create", "data": {"title": "New", "body": "Body"}},
+		{"action": "delete", "id": "obsolete"}
+	]}`)
+
+	mockRepo.On("GetByIDs", []string{"obsolete"}).Return([]models.Blog{{ID: "obsolete", Title: "Obsolete", Body: "Body"}}, nil)
+	mockRepo.On("Delete", "obsolete").Return(nil)
+	mockRepo.On("CreateBatch", mock.AnythingOfType("[]*models.Blog")).Return(nil)
+
+	response, err := service.BulkBlogs(request)
+	require.NoError(t, err)
+	require.True(t, response.Committed)
+
+	entries := appendedAuditEntries(mockRepo)
+	require.Len(t, entries, 2)
+	assert.Equal(t, models.AuditActionPostCreate, entries[0].Action)
+	assert.Equal(t, response.Results[0].ID, entries[0].TargetID)
+	assert.Equal(t, "key-1", entries[0].Subject)
+	assert.Equal(t, models.AuditActionPostDelete, entries[1].Action)
+	assert.Equal(t, models.FieldChange{Old: "Obsolete"}, entries[1].Changes["title"])
+}
+
+func TestDiffPosts(t *testing.T) {
+	publishedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	before := &models.BlogResponse{ID: "post-1", Title: "Title", Tags: []string{"go"}, Status: models.BlogStatusDraft}
+	after := &models.BlogResponse{ID: "post-1", Title: "Title", Tags: []string{"go", "api"}, Status: models.BlogStatusPublished,
+		PublishedAt: &publishedAt, UpdatedAt: time.Now()}
+
+	changes, err := diffPosts(before, after)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]models.FieldChange{
+		"tags":         {Old: []interface{}{"go"}, New: []interface{}{"go", "api"}},
+		"status":       {Old: models.BlogStatusDraft, New: models.BlogStatusPublished},
+		"published_at": {New: "2024-01-02T03:04:05Z"},
+	}, changes)
+}
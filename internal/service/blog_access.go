@@ -0,0 +1,215 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/repository"
+)
+
+// accessControlledBlogService checks the permissions of an actor before changing blog posts.
+// Drafts are only read by their author and by actors that may change every post.
+type accessControlledBlogService struct {
+	BlogService
+	actor *rbac.Actor
+}
+
+// WithAccessControl returns a blog service that only lets the actor make the changes its
+// permissions allow. Posts are created on behalf of the actor, and actors that may not publish
+// create drafts unless they ask for a published post, which is refused.
+func WithAccessControl(blogService BlogService, actor *rbac.Actor) BlogService {
+	return &accessControlledBlogService{BlogService: blogService, actor: actor}
+}
+
+// WithTenant returns a service for the posts of another tenant, checked against the same actor
+func (s *accessControlledBlogService) WithTenant(tenantID string) BlogService {
+	return WithAccessControl(s.BlogService.WithTenant(tenantID), s.actor)
+}
+
+// WithRequester returns a service that attributes its changes to requester, checked against the same actor
+func (s *accessControlledBlogService) WithRequester(requester *models.Requester) BlogService {
+	return WithAccessControl(s.BlogService.WithRequester(requester), s.actor)
+}
+
+// GetBlogByID retrieves a blog post the actor may read
+func (s *accessControlledBlogService) GetBlogByID(id string) (*models.BlogResponse, error) {
+	return s.readable(s.BlogService.GetBlogByID(id))
+}
+
+// GetBlogBySlug retrieves a blog post the actor may read
+func (s *accessControlledBlogService) GetBlogBySlug(slug string) (*models.BlogResponse, error) {
+	return s.readable(s.BlogService.GetBlogBySlug(slug))
+}
+
+// GetAllBlogs retrieves every blog post the actor may read
+func (s *accessControlledBlogService) GetAllBlogs() ([]models.BlogResponse, error) {
+	posts, err := s.BlogService.GetAllBlogs()
+	if err != nil || s.actor.Can(rbac.PostUpdateAny) {
+		return posts, err
+	}
+
+	readable := make([]models.BlogResponse, 0, len(posts))
+	for _, post := range posts {
+		if s.canRead(&post) {
+			readable = append(readable, post)
+		}
+	}
+	return readable, nil
+}
+
+// ListBlogs retrieves a page of the blog posts the actor may read
+func (s *accessControlledBlogService) ListBlogs(query *models.BlogListQuery) (*models.BlogPage, error) {
+	if query != nil && !s.actor.Can(rbac.PostUpdateAny) {
+		restricted := *query
+		restricted.PublishedOnly = true
+		restricted.DraftsOf = s.actor.Subject
+		query = &restricted
+	}
+	return s.BlogService.ListBlogs(query)
+}
+
+// CountBlogsByTags counts the blog posts the actor may read carrying each of the given tags
+func (s *accessControlledBlogService) CountBlogsByTags(query *models.TagCountQuery) (map[string]int, error) {
+	if query != nil && !s.actor.Can(rbac.PostUpdateAny) {
+		restricted := *query
+		restricted.PublishedOnly = true
+		restricted.DraftsOf = s.actor.Subject
+		query = &restricted
+	}
+	return s.BlogService.CountBlogsByTags(query)
+}
+
+// readable hides a post the actor may not read as if it did not exist
+func (s *accessControlledBlogService) readable(post *models.BlogResponse, err error) (*models.BlogResponse, error) {
+	if err != nil {
+		return nil, err
+	}
+	if !s.canRead(post) {
+		return nil, repository.ErrBlogNotFound
+	}
+	return post, nil
+}
+
+// canRead reports whether the actor may read a post
+func (s *accessControlledBlogService) canRead(post *models.BlogResponse) bool {
+	return CanRead(s.actor, post)
+}
+
+// CanRead reports whether an actor may read a post: published posts are public, drafts are
+// read by their author and by actors that may change every post
+func CanRead(actor *rbac.Actor, post *models.BlogResponse) bool {
+	if post.Status == models.BlogStatusPublished || actor.Can(rbac.PostUpdateAny) {
+		return true
+	}
+	return actor.Subject != "" && post.AuthorID == actor.Subject
+}
+
+// CreateBlog creates a blog post written by the actor
+func (s *accessControlledBlogService) CreateBlog(request *models.BlogCreateRequest) (*models.BlogResponse, error) {
+	if request != nil {
+		if err := s.checkCreate(request); err != nil {
+			return nil, err
+		}
+	}
+	return s.BlogService.CreateBlog(request)
+}
+
+// UpdateBlog updates a blog post the actor may change
+func (s *accessControlledBlogService) UpdateBlog(id string, request *models.BlogUpdateRequest) (*models.BlogResponse, error) {
+	if err := s.checkUpdate(id, request); err != nil {
+		return nil, err
+	}
+	return s.BlogService.UpdateBlog(id, request)
+}
+
+// DeleteBlog deletes a blog post the actor may delete
+func (s *accessControlledBlogService) DeleteBlog(id string) error {
+	if err := s.checkChange(id, rbac.PostDeleteOwn, rbac.PostDeleteAny); err != nil {
+		return err
+	}
+	return s.BlogService.DeleteBlog(id)
+}
+
+// BulkBlogs applies a batch of operations. The whole batch is refused if the actor may not
+// perform any one of them.
+func (s *accessControlledBlogService) BulkBlogs(request *models.BlogBulkRequest) (*models.BlogBulkResponse, error) {
+	if request != nil {
+		for i := range request.Operations {
+			if err := s.checkBulkOperation(&request.Operations[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return s.BlogService.BulkBlogs(request)
+}
+
+// checkBulkOperation checks a single operation of a bulk request. Operations that are invalid
+// are left to the validation of the bulk request.
+func (s *accessControlledBlogService) checkBulkOperation(operation *models.BlogBulkOperation) error {
+	switch operation.Action {
+	case models.BulkActionCreate:
+		if operation.Create == nil {
+			return s.actor.Check(rbac.PostCreate)
+		}
+		return s.checkCreate(operation.Create)
+	case models.BulkActionUpdate:
+		if operation.ID == "" {
+			return s.actor.Check(rbac.PostUpdateOwn, rbac.PostUpdateAny)
+		}
+		return s.checkUpdate(operation.ID, operation.Update)
+	case models.BulkActionDelete:
+		if operation.ID == "" {
+			return s.actor.Check(rbac.PostDeleteOwn, rbac.PostDeleteAny)
+		}
+		return s.checkChange(operation.ID, rbac.PostDeleteOwn, rbac.PostDeleteAny)
+	default:
+		return nil
+	}
+}
+
+// checkCreate checks that the actor may create the post and assigns it to the actor
+func (s *accessControlledBlogService) checkCreate(request *models.BlogCreateRequest) error {
+	if err := s.actor.Check(rbac.PostCreate); err != nil {
+		return err
+	}
+	switch {
+	case request.Status == "" && !s.actor.Can(rbac.PostPublish):
+		request.Status = models.BlogStatusDraft
+	case request.Status == models.BlogStatusPublished:
+		if err := s.actor.Check(rbac.PostPublish); err != nil {
+			return err
+		}
+	}
+	request.AuthorID = s.actor.Subject
+	return nil
+}
+
+// checkUpdate checks that the actor may change the post and, if the update publishes it, publish it
+func (s *accessControlledBlogService) checkUpdate(id string, request *models.BlogUpdateRequest) error {
+	if err := s.checkChange(id, rbac.PostUpdateOwn, rbac.PostUpdateAny); err != nil {
+		return err
+	}
+	if request != nil && request.Status != nil && *request.Status == models.BlogStatusPublished {
+		return s.actor.Check(rbac.PostPublish)
+	}
+	return nil
+}
+
+// checkChange checks that the actor holds the permission to change any post, or the permission
+// to change its own posts and wrote the post
+func (s *accessControlledBlogService) checkChange(id string, ownPosts, anyPost rbac.Permission) error {
+	if err := s.actor.Check(ownPosts, anyPost); err != nil {
+		return err
+	}
+	if s.actor.Can(anyPost) {
+		return nil
+	}
+
+	post, err := s.BlogService.GetBlogByID(id)
+	if err != nil {
+		return err
+	}
+	if s.actor.Subject == "" || post.AuthorID != s.actor.Subject {
+		return s.actor.Check(anyPost)
+	}
+	return nil
+}
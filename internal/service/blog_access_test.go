@@ -0,0 +1,219 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/repository"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMemoryBlogService creates a blog service over a memory repository holding posts
+func newMemoryBlogService(t *testing.T, posts ...*models.Blog) BlogService {
+	t.Helper()
+	repo := repository.NewMemoryBlogRepository()
+	for _, post := range posts {
+		require.NoError(t, repo.Create(post))
+	}
+	return NewBlogService(repo)
+}
+
+// memoryPost returns a post of author for a memory repository
+func memoryPost(id, authorID, status string, tags ...string) *models.Blog {
+	post := &models.Blog{ID: id, Slug: id, Title: "Post " + id, Body: "Body", AuthorID: authorID, Status: status}
+	for _, tag := range tags {
+		post.Tags = append(post.Tags, models.BlogTag{Name: tag})
+	}
+	return post
+}
+
+// postIDs returns the IDs of posts in order
+func postIDs(posts []models.BlogResponse) []string {
+	ids := make([]string, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+	return ids
+}
+
+func TestWithAccessControl_CreateBlog(t *testing.T) {
+	t.Run("authors create drafts", func(t *testing.T) {
+		blogService := newMemoryBlogService(t)
+		author := rbac.NewActor("bob", []string{rbac.RoleAuthor}, nil)
+
+		post, err := WithAccessControl(blogService, author).CreateBlog(&models.BlogCreateRequest{Title: "Hello", Body: "World"})
+
+		require.NoError(t, err)
+		assert.Equal(t, models.BlogStatusDraft, post.Status)
+		assert.Equal(t, "bob", post.AuthorID)
+	})
+
+	t.Run("authors cannot publish", func(t *testing.T) {
+		blogService := newMemoryBlogService(t)
+		author := rbac.NewActor("bob", []string{rbac.RoleAuthor}, nil)
+
+		_, err := WithAccessControl(blogService, author).CreateBlog(&models.BlogCreateRequest{Title: "Hello", Body: "World", Status: models.BlogStatusPublished})
+
+		assert.ErrorIs(t, err, rbac.ErrForbidden)
+		posts, err := blogService.GetAllBlogs()
+		require.NoError(t, err)
+		assert.Empty(t, posts)
+	})
+
+	t.Run("editors publish by default", func(t *testing.T) {
+		blogService := newMemoryBlogService(t)
+		editor := rbac.NewActor("carol", []string{rbac.RoleEditor}, nil)
+
+		post, err := WithAccessControl(blogService, editor).CreateBlog(&models.BlogCreateRequest{Title: "Hello", Body: "World"})
+
+		require.NoError(t, err)
+		assert.Equal(t, models.BlogStatusPublished, post.Status, "the blog service applies its own default")
+		assert.Equal(t, "carol", post.AuthorID)
+	})
+
+	t.Run("reviewers cannot create", func(t *testing.T) {
+		blogService := newMemoryBlogService(t)
+		reviewer := rbac.NewActor("dave", []string{rbac.RoleReviewer}, nil)
+
+		_, err := WithAccessControl(blogService, reviewer).CreateBlog(&models.BlogCreateRequest{Title: "Hello", Body: "World"})
+
+		assert.ErrorIs(t, err, rbac.ErrForbidden)
+	})
+}
+
+func TestWithAccessControl_OwnPosts(t *testing.T) {
+	blogService := newMemoryBlogService(t,
+		memoryPost("own", "bob", models.BlogStatusDraft),
+		memoryPost("other", "alice", models.BlogStatusPublished),
+	)
+	author := WithAccessControl(blogService, rbac.NewActor("bob", []string{rbac.RoleAuthor}, nil))
+
+	renamed := "Renamed"
+	_, err := author.UpdateBlog("own", &models.BlogUpdateRequest{Title: &renamed})
+	assert.NoError(t, err)
+
+	_, err = author.UpdateBlog("other", &models.BlogUpdateRequest{Title: &renamed})
+	assert.ErrorIs(t, err, rbac.ErrForbidden)
+	assert.ErrorIs(t, author.DeleteBlog("other"), rbac.ErrForbidden)
+
+	published := models.BlogStatusPublished
+	_, err = author.UpdateBlog("own", &models.BlogUpdateRequest{Status: &published})
+	assert.ErrorIs(t, err, rbac.ErrForbidden, "publishing requires post:publish")
+
+	_, err = author.UpdateBlog("missing", &models.BlogUpdateRequest{Title: &renamed})
+	assert.ErrorIs(t, err, repository.ErrBlogNotFound)
+
+	assert.NoError(t, author.DeleteBlog("own"))
+
+	other, err := blogService.GetBlogByID("other")
+	require.NoError(t, err)
+	assert.Equal(t, "Post other", other.Title, "the post of another author is left alone")
+	_, err = blogService.GetBlogByID("own")
+	assert.ErrorIs(t, err, repository.ErrBlogNotFound)
+}
+
+func TestWithAccessControl_AnyPost(t *testing.T) {
+	blogService := newMemoryBlogService(t, memoryPost("other", "alice", models.BlogStatusDraft))
+	reviewer := WithAccessControl(blogService, rbac.NewActor("dave", []string{rbac.RoleReviewer}, nil))
+
+	published := models.BlogStatusPublished
+	post, err := reviewer.UpdateBlog("other", &models.BlogUpdateRequest{Status: &published})
+	require.NoError(t, err)
+	assert.Equal(t, models.BlogStatusPublished, post.Status)
+	assert.ErrorIs(t, reviewer.DeleteBlog("other"), rbac.ErrForbidden)
+}
+
+func TestWithAccessControl_BulkBlogs(t *testing.T) {
+	blogService := newMemoryBlogService(t, memoryPost("other", "alice", models.BlogStatusPublished))
+	author := WithAccessControl(blogService, rbac.NewActor("bob", []string{rbac.RoleAuthor}, nil))
+
+	_, err := author.BulkBlogs(&models.BlogBulkRequest{Operations: []models.BlogBulkOperation{
+		{Action: models.BulkActionCreate, Create: &models.BlogCreateRequest{Title: "Hello", Body: "World"}},
+		{Action: models.BulkActionDelete, ID: "other"},
+	}})
+	assert.ErrorIs(t, err, rbac.ErrForbidden)
+	posts, err := blogService.GetAllBlogs()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other"}, postIDs(posts), "the whole batch is refused")
+
+	request := &models.BlogBulkRequest{Operations: []models.BlogBulkOperation{
+		{Action: models.BulkActionCreate, Create: &models.BlogCreateRequest{Title: "Hello", Body: "World"}},
+	}}
+	response, err := author.BulkBlogs(request)
+	require.NoError(t, err)
+	require.Equal(t, 1, response.Succeeded)
+	assert.Equal(t, "bob", response.Results[0].Data.AuthorID)
+	assert.Equal(t, models.BlogStatusDraft, response.Results[0].Data.Status)
+}
+
+func TestWithAccessControl_WithTenant(t *testing.T) {
+	blogService := newMemoryBlogService(t)
+	scoped := WithAccessControl(blogService, &rbac.Actor{}).WithTenant("acme")
+
+	_, err := scoped.CreateBlog(&models.BlogCreateRequest{Title: "Hello", Body: "World"})
+	assert.ErrorIs(t, err, rbac.ErrForbidden)
+}
+
+func TestWithAccessControl_Drafts(t *testing.T) {
+	blogService := newMemoryBlogService(t,
+		memoryPost("1", "alice", models.BlogStatusPublished, "go"),
+		memoryPost("2", "alice", models.BlogStatusDraft, "go"),
+		memoryPost("3", "bob", models.BlogStatusDraft, "go"),
+	)
+
+	tests := []struct {
+		name    string
+		actor   *rbac.Actor
+		wantIDs []string
+	}{
+		{
+			name:    "anonymous callers read published posts",
+			actor:   &rbac.Actor{},
+			wantIDs: []string{"1"},
+		},
+		{
+			name:    "authors read their own drafts",
+			actor:   rbac.NewActor("bob", []string{rbac.RoleAuthor}, nil),
+			wantIDs: []string{"1", "3"},
+		},
+		{
+			name:    "reviewers read every draft",
+			actor:   rbac.NewActor("dave", []string{rbac.RoleReviewer}, nil),
+			wantIDs: []string{"1", "2", "3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blogs := WithAccessControl(blogService, tt.actor)
+
+			posts, err := blogs.GetAllBlogs()
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.wantIDs, postIDs(posts))
+
+			for _, id := range []string{"1", "2", "3"} {
+				_, errByID := blogs.GetBlogByID(id)
+				_, errBySlug := blogs.GetBlogBySlug(id)
+				if slices.Contains(tt.wantIDs, id) {
+					assert.NoError(t, errByID)
+					assert.NoError(t, errBySlug)
+				} else {
+					assert.ErrorIs(t, errByID, repository.ErrBlogNotFound, "drafts of others look missing")
+					assert.ErrorIs(t, errBySlug, repository.ErrBlogNotFound, "drafts of others look missing")
+				}
+			}
+
+			query := &models.BlogListQuery{Tag: "go"}
+			page, err := blogs.ListBlogs(query)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.wantIDs, postIDs(page.Posts))
+			assert.Equal(t, &models.BlogListQuery{Tag: "go"}, query, "the query of the caller is left alone")
+
+			counts, err := blogs.CountBlogsByTags(&models.TagCountQuery{Names: []string{"go"}})
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.wantIDs), counts["go"], "tags are counted over the posts the actor may read")
+		})
+	}
+}
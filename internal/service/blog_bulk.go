@@ -0,0 +1,294 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// maxBulkOperations limits the size of a single bulk request
+const maxBulkOperations = 1000
+
+// BulkBlogs applies a batch of create, update and delete operations in request order.
+// Every operation is validated before the database is touched. In atomic mode the batch
+// runs in a single transaction and nothing is applied if any operation fails; in partial
+// mode each valid operation is applied on its own and reported individually.
+func (s *blogService) BulkBlogs(request *models.BlogBulkRequest) (*models.BlogBulkResponse, error) {
+	if request == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	if len(request.Operations) == 0 {
+		return nil, errors.New("at least one operation is required")
+	}
+
+	if len(request.Operations) > maxBulkOperations {
+		return nil, fmt.Errorf("a bulk request may contain at most %d operations", maxBulkOperations)
+	}
+
+	mode := request.Mode
+	if mode == "" {
+		mode = models.BulkModeAtomic
+	}
+	if mode != models.BulkModeAtomic && mode != models.BulkModePartial {
+		return nil, errors.New("mode must be one of: atomic partial")
+	}
+
+	response := &models.BlogBulkResponse{
+		Mode:    mode,
+		Results: make([]models.BlogBulkItemResult, len(request.Operations)),
+	}
+
+	// Validate every operation before touching the database
+	allValid := true
+	for i := range request.Operations {
+		operation := &request.Operations[i]
+		response.Results[i] = models.BlogBulkItemResult{Index: i, Action: operation.Action, ID: operation.ID}
+		if err := s.validateBulkOperation(operation); err != nil {
+			response.Results[i].Status = http.StatusBadRequest
+			response.Results[i].Error = err.Error()
+			allValid = false
+		}
+	}
+
+	if mode == models.BulkModeAtomic {
+		if allValid {
+			err := s.blogRepo.Transaction(func(repo repository.BlogRepository) error {
+				return s.executeBulk(repo, request.Operations, response.Results, true)
+			})
+			response.Committed = err == nil
+		}
+
+		if !response.Committed {
+			markNotApplied(response.Results)
+		}
+	} else {
+		s.executeBulk(s.blogRepo.Primary(), request.Operations, response.Results, false)
+		response.Committed = true
+	}
+
+	for _, result := range response.Results {
+		if result.Error == "" {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+
+	return response, nil
+}
+
+// executeBulk applies the operations that passed validation and records their results.
+// With atomic set, repo is bound to the batch transaction: the lifecycle events of the whole
+// batch are added to the outbox at the end and the first error is returned so that the
+// caller can roll back. Otherwise every operation runs in a transaction of its own together
+// with its events.
+func (s *blogService) executeBulk(repo repository.BlogRepository, operations []models.BlogBulkOperation, results []models.BlogBulkItemResult, atomic bool) error {
+	// Load every update and delete target with a single query; deleted posts are recorded in the audit log
+	var targetIDs []string
+	for i, operation := range operations {
+		if results[i].Error == "" && operation.Action != models.BulkActionCreate {
+			targetIDs = append(targetIDs, operation.ID)
+		}
+	}
+	targets := make(map[string]*models.Blog, len(targetIDs))
+	if len(targetIDs) > 0 {
+		existing, err := repo.GetByIDs(targetIDs)
+		if err != nil {
+			for i, operation := range operations {
+				if results[i].Error == "" && operation.Action != models.BulkActionCreate {
+					results[i].Status = http.StatusInternalServerError
+					results[i].Error = err.Error()
+				}
+			}
+			if atomic {
+				return err
+			}
+		}
+		for i := range existing {
+			targets[existing[i].ID] = &existing[i]
+		}
+	}
+
+	// In atomic mode runs of consecutive creates are buffered and inserted together before the
+	// next update or delete, so that the operations still take effect in request order
+	var pendingCreates []*models.Blog
+	var pendingIndexes []int
+	var events []*models.BlogEvent
+	flushCreates := func() error {
+		if len(pendingCreates) == 0 {
+			return nil
+		}
+		if err := repo.CreateBatch(pendingCreates); err != nil {
+			for _, i := range pendingIndexes {
+				results[i].Status = bulkErrorStatus(err)
+				results[i].Error = err.Error()
+			}
+			return err
+		}
+		for n, i := range pendingIndexes {
+			events = append(events, s.recordBulkCreate(pendingCreates[n], &results[i])...)
+			if err := appendAudit(repo, s.requester, models.AuditActionPostCreate, results[i].ID, nil, results[i].Data); err != nil {
+				return err
+			}
+		}
+		pendingCreates, pendingIndexes = nil, nil
+		return nil
+	}
+
+	for i, operation := range operations {
+		if results[i].Error != "" {
+			continue
+		}
+
+		var err error
+		switch {
+		case atomic && operation.Action == models.BulkActionCreate:
+			pendingCreates = append(pendingCreates, s.newBlog(operation.Create))
+			pendingIndexes = append(pendingIndexes, i)
+			continue
+		case atomic:
+			if err := flushCreates(); err != nil {
+				return err
+			}
+			var operationEvents []*models.BlogEvent
+			operationEvents, err = s.executeBulkOperation(repo, operation, targets, &results[i])
+			events = append(events, operationEvents...)
+		default:
+			err = saveWithEvents(repo, func(repo repository.BlogRepository) ([]*models.BlogEvent, error) {
+				return s.executeBulkOperation(repo, operation, targets, &results[i])
+			})
+		}
+
+		if err != nil {
+			results[i].Status = bulkErrorStatus(err)
+			results[i].Error = err.Error()
+			results[i].ID, results[i].Data = operation.ID, nil
+			if atomic {
+				return err
+			}
+		}
+	}
+
+	if !atomic {
+		return nil
+	}
+	if err := flushCreates(); err != nil {
+		return err
+	}
+	return repo.AppendEvents(events)
+}
+
+// executeBulkOperation applies a single create, update or delete and returns its lifecycle events
+func (s *blogService) executeBulkOperation(repo repository.BlogRepository, operation models.BlogBulkOperation, targets map[string]*models.Blog, result *models.BlogBulkItemResult) ([]*models.BlogEvent, error) {
+	switch operation.Action {
+	case models.BulkActionCreate:
+		blog := s.newBlog(operation.Create)
+		if err := repo.Create(blog); err != nil {
+			return nil, err
+		}
+		events := s.recordBulkCreate(blog, result)
+		return events, appendAudit(repo, s.requester, models.AuditActionPostCreate, blog.ID, nil, result.Data)
+	case models.BulkActionUpdate:
+		return s.executeBulkUpdate(repo, targets[operation.ID], operation.Update, result)
+	default:
+		if err := repo.Delete(operation.ID); err != nil {
+			return nil, err
+		}
+		var before *models.BlogResponse
+		if target := targets[operation.ID]; target != nil {
+			before = s.blogToResponse(target)
+		}
+		if err := appendAudit(repo, s.requester, models.AuditActionPostDelete, operation.ID, before, nil); err != nil {
+			return nil, err
+		}
+		delete(targets, operation.ID)
+		result.Status = http.StatusOK
+		return []*models.BlogEvent{newEvent(models.EventPostDeleted, &models.BlogResponse{ID: operation.ID})}, nil
+	}
+}
+
+// recordBulkCreate records a successfully created blog post in its bulk result and returns its events
+func (s *blogService) recordBulkCreate(blog *models.Blog, result *models.BlogBulkItemResult) []*models.BlogEvent {
+	result.ID = blog.ID
+	result.Status = http.StatusCreated
+	result.Data = s.blogToResponse(blog)
+	return lifecycleEvents(models.EventPostCreated, false, result.Data)
+}
+
+// executeBulkUpdate updates a single blog post of a bulk request and returns its events
+func (s *blogService) executeBulkUpdate(repo repository.BlogRepository, blog *models.Blog, request *models.BlogUpdateRequest, result *models.BlogBulkItemResult) ([]*models.BlogEvent, error) {
+	if blog == nil {
+		return nil, repository.ErrBlogNotFound
+	}
+
+	wasPublished := blog.IsPublished()
+	before := s.blogToResponse(blog)
+	s.applyUpdateRequest(blog, request)
+	if err := repo.Update(blog); err != nil {
+		return nil, err
+	}
+
+	result.Status = http.StatusOK
+	result.Data = s.blogToResponse(blog)
+	if err := appendAudit(repo, s.requester, models.AuditActionPostUpdate, blog.ID, before, result.Data); err != nil {
+		return nil, err
+	}
+	return lifecycleEvents(models.EventPostUpdated, wasPublished, result.Data), nil
+}
+
+// validateBulkOperation validates a single operation without touching the database
+func (s *blogService) validateBulkOperation(operation *models.BlogBulkOperation) error {
+	switch operation.Action {
+	case models.BulkActionCreate:
+		if operation.Create == nil {
+			return errors.New("data is required for create")
+		}
+		return s.validateCreateRequest(operation.Create)
+	case models.BulkActionUpdate:
+		if operation.ID == "" {
+			return errors.New("blog ID is required")
+		}
+		if operation.Update == nil {
+			return errors.New("data is required for update")
+		}
+		return s.validateUpdateRequest(operation.Update)
+	case models.BulkActionDelete:
+		if operation.ID == "" {
+			return errors.New("blog ID is required")
+		}
+		return nil
+	case "":
+		return errors.New("action is required")
+	default:
+		return fmt.Errorf("action must be one of: %s %s %s", models.BulkActionCreate, models.BulkActionUpdate, models.BulkActionDelete)
+	}
+}
+
+// markNotApplied flags every operation of a rolled back batch that did not fail itself
+func markNotApplied(results []models.BlogBulkItemResult) {
+	for i := range results {
+		if results[i].Error != "" {
+			continue
+		}
+		results[i].Status = http.StatusFailedDependency
+		results[i].Error = "not applied: another operation in the batch failed"
+		results[i].Data = nil
+		if results[i].Action == models.BulkActionCreate {
+			results[i].ID = ""
+		}
+	}
+}
+
+// bulkErrorStatus maps an operation error to an HTTP status code
+func bulkErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrBlogNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrSlugConflict):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
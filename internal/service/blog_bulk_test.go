@@ -0,0 +1,231 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// parseBulkRequest decodes a bulk request the way the controller does
+func parseBulkRequest(t *testing.T, body string) *models.BlogBulkRequest {
+	var request models.BlogBulkRequest
+	assert.NoError(t, json.Unmarshal([]byte(body), &request))
+	return &request
+}
+
+func TestBlogService_BulkBlogs_AtomicSuccess(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogService(mockRepo)
+
+	request := parseBulkRequest(t, `{"operations": [
+		{"action": "create", "data": {"title": "New", "body": "Body"}},
+		{"action": "update", "id": "existing", "data": {"title": "Renamed"}},
+		{"action": "delete", "id": "obsolete"}
+	]}`)
+
+	mockRepo.On("Transaction").Return()
+	mockRepo.On("GetByIDs", []string{"existing", "obsolete"}).Return([]models.Blog{{ID: "existing", Title: "Old", Body: "Body"}, {ID: "obsolete", Title: "Obsolete", Body: "Body"}}, nil)
+	mockRepo.On("Update", mock.AnythingOfType("*models.Blog")).Return(nil)
+	mockRepo.On("Delete", "obsolete").Return(nil)
+	mockRepo.On("CreateBatch", mock.AnythingOfType("[]*models.Blog")).Return(nil)
+
+	response, err := service.BulkBlogs(request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.BulkModeAtomic, response.Mode)
+	assert.True(t, response.Committed)
+	assert.Equal(t, 3, response.Succeeded)
+	assert.Equal(t, 0, response.Failed)
+	assert.Equal(t, http.StatusCreated, response.Results[0].Status)
+	assert.NotEmpty(t, response.Results[0].ID)
+	assert.Equal(t, "New", response.Results[0].Data.Title)
+	assert.Equal(t, http.StatusOK, response.Results[1].Status)
+	assert.Equal(t, "Renamed", response.Results[1].Data.Title)
+	assert.Equal(t, http.StatusOK, response.Results[2].Status)
+	// Operations are applied in request order, and all events are recorded in the batch transaction
+	assert.Equal(t, []string{
+		models.EventPostCreated,
+		models.EventPostPublished,
+		models.EventPostUpdated,
+		models.EventPostDeleted,
+	}, appendedEventTypes(mockRepo))
+	mockRepo.AssertNumberOfCalls(t, "Transaction", 1)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBlogService_BulkBlogs_AtomicAppliesOperationsInRequestOrder(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogService(mockRepo)
+
+	request := parseBulkRequest(t, `{"operations": [
+		{"action": "update", "id": "existing", "data": {"slug": "renamed"}},
+		{"action": "create", "data": {"title": "First", "body": "Body"}},
+		{"action": "create", "data": {"title": "Second", "body": "Body"}},
+		{"action": "delete", "id": "existing"},
+		{"action": "create", "data": {"title": "Third", "body": "Body"}}
+	]}`)
+
+	mockRepo.On("Transaction").Return()
+	mockRepo.On("GetByIDs", []string{"existing", "existing"}).Return([]models.Blog{{ID: "existing", Title: "Old", Body: "Body"}}, nil)
+	mockRepo.On("Update", mock.AnythingOfType("*models.Blog")).Return(nil)
+	mockRepo.On("CreateBatch", mock.AnythingOfType("[]*models.Blog")).Return(nil)
+	mockRepo.On("Delete", "existing").Return(nil)
+
+	response, err := service.BulkBlogs(request)
+
+	assert.NoError(t, err)
+	assert.True(t, response.Committed)
+	assert.Equal(t, 5, response.Succeeded)
+
+	// Consecutive creates are still inserted together
+	var applied []string
+	for _, call := range mockRepo.Calls {
+		switch call.Method {
+		case "Update", "Delete":
+			applied = append(applied, call.Method)
+		case "CreateBatch":
+			applied = append(applied, fmt.Sprintf("CreateBatch(%d)", len(call.Arguments.Get(0).([]*models.Blog))))
+		}
+	}
+	assert.Equal(t, []string{"Update", "CreateBatch(2)", "Delete", "CreateBatch(1)"}, applied)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBlogService_BulkBlogs_SlugConflictIsAConflict(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogService(mockRepo)
+
+	request := parseBulkRequest(t, `{"mode": "partial", "operations": [
+		{"action": "update", "id": "existing", "data": {"slug": "taken"}}
+	]}`)
+
+	mockRepo.On("GetByIDs", []string{"existing"}).Return([]models.Blog{{ID: "existing", Title: "Old", Body: "Body"}}, nil)
+	mockRepo.On("Update", mock.AnythingOfType("*models.Blog")).Return(repository.ErrSlugConflict)
+
+	response, err := service.BulkBlogs(request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, response.Failed)
+	assert.Equal(t, http.StatusConflict, response.Results[0].Status)
+	assert.Equal(t, repository.ErrSlugConflict.Error(), response.Results[0].Error)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBlogService_BulkBlogs_AtomicRollsBackOnFailure(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogService(mockRepo)
+
+	request := parseBulkRequest(t, `{"mode": "atomic", "operations": [
+		{"action": "create", "data": {"title": "New", "body": "Body"}},
+		{"action": "delete", "id": "missing"},
+		{"action": "delete", "id": "other"}
+	]}`)
+
+	mockRepo.On("Transaction").Return()
+	mockRepo.On("GetByIDs", []string{"missing", "other"}).Return([]models.Blog{{ID: "other", Title: "Other", Body: "Body"}}, nil)
+	mockRepo.On("CreateBatch", mock.AnythingOfType("[]*models.Blog")).Return(nil)
+	mockRepo.On("Delete", "missing").Return(repository.ErrBlogNotFound)
+
+	response, err := service.BulkBlogs(request)
+
+	assert.NoError(t, err)
+	assert.False(t, response.Committed)
+	assert.Equal(t, 0, response.Succeeded)
+	assert.Equal(t, 3, response.Failed)
+	assert.Equal(t, http.StatusFailedDependency, response.Results[0].Status)
+	assert.Empty(t, response.Results[0].ID)
+	assert.Equal(t, http.StatusNotFound, response.Results[1].Status)
+	assert.Equal(t, "blog post not found", response.Results[1].Error)
+	assert.Equal(t, http.StatusFailedDependency, response.Results[2].Status)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Delete", "other")
+	mockRepo.AssertNotCalled(t, "AppendEvents", mock.Anything)
+}
+
+func TestBlogService_BulkBlogs_ValidatesBeforeTouchingDatabase(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogService(mockRepo)
+
+	request := parseBulkRequest(t, `{"operations": [
+		{"action": "create", "data": {"title": "New", "body": "Body"}},
+		{"action": "create", "data": {"title": "", "body": "Body"}},
+		{"action": "update", "data": {"title": "No ID"}},
+		{"action": "publish", "id": "x"}
+	]}`)
+
+	response, err := service.BulkBlogs(request)
+
+	assert.NoError(t, err)
+	assert.False(t, response.Committed)
+	assert.Equal(t, http.StatusFailedDependency, response.Results[0].Status)
+	assert.Equal(t, http.StatusBadRequest, response.Results[1].Status)
+	assert.Equal(t, "title is required", response.Results[1].Error)
+	assert.Equal(t, "blog ID is required", response.Results[2].Error)
+	assert.Equal(t, "action must be one of: create update delete", response.Results[3].Error)
+
+	mockRepo.AssertNotCalled(t, "Transaction")
+}
+
+func TestBlogService_BulkBlogs_PartialReportsEachItem(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogService(mockRepo)
+
+	longTitle := make([]byte, 256)
+	for i := range longTitle {
+		longTitle[i] = 'a'
+	}
+	request := parseBulkRequest(t, `{"mode": "partial", "operations": [
+		{"action": "create", "data": {"title": "New", "body": "Body"}},
+		{"action": "update", "id": "missing", "data": {"body": "Body"}},
+		{"action": "create", "data": {"title": "`+string(longTitle)+`", "body": "Body"}},
+		{"action": "delete", "id": "broken"}
+	]}`)
+
+	mockRepo.On("GetByIDs", []string{"missing", "broken"}).Return([]models.Blog{{ID: "broken", Title: "Broken", Body: "Body"}}, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*models.Blog")).Return(nil)
+	mockRepo.On("Delete", "broken").Return(errors.New("database error"))
+
+	response, err := service.BulkBlogs(request)
+
+	assert.NoError(t, err)
+	assert.True(t, response.Committed)
+	assert.Equal(t, 1, response.Succeeded)
+	assert.Equal(t, 3, response.Failed)
+	assert.Equal(t, http.StatusCreated, response.Results[0].Status)
+	assert.Equal(t, http.StatusNotFound, response.Results[1].Status)
+	assert.Equal(t, http.StatusBadRequest, response.Results[2].Status)
+	assert.Equal(t, "title must be at most 255 characters", response.Results[2].Error)
+	assert.Equal(t, http.StatusInternalServerError, response.Results[3].Status)
+
+	mockRepo.AssertExpectations(t)
+	// Each applied operation runs in a transaction of its own
+	mockRepo.AssertNumberOfCalls(t, "Transaction", 3)
+}
+
+func TestBlogService_BulkBlogs_InvalidRequest(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogService(mockRepo)
+
+	response, err := service.BulkBlogs(nil)
+	assert.Nil(t, response)
+	assert.Equal(t, "request cannot be nil", err.Error())
+
+	response, err = service.BulkBlogs(&models.BlogBulkRequest{})
+	assert.Nil(t, response)
+	assert.Equal(t, "at least one operation is required", err.Error())
+
+	response, err = service.BulkBlogs(parseBulkRequest(t, `{"mode": "best-effort", "operations": [{"action": "delete", "id": "x"}]}`))
+	assert.Nil(t, response)
+	assert.Equal(t, "mode must be one of: atomic partial", err.Error())
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"BlogManagment/internal/cache"
+	"BlogManagment/internal/models"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// BlogCache keeps the posts read through the blog services it wraps. A change made through a
+// wrapped service drops the entries it affects as soon as it succeeds; changes made anywhere
+// else, such as imports or other replicas, are passed to Invalidate.
+type BlogCache struct {
+	cache cache.Cache
+	ttl   time.Duration
+	group singleflight.Group
+	loads atomic.Uint64
+
+	// generation is advanced by every invalidation, so that a read that started before a
+	// change does not store what it read after the change
+	mu         sync.Mutex
+	generation uint64
+}
+
+// BlogCacheStats holds the counters of a blog cache. Loads counts the reads that reached the
+// database; concurrent misses for the same entry share one load.
+type BlogCacheStats struct {
+	cache.Stats
+	Loads uint64 `json:"loads"`
+}
+
+// NewBlogCache creates a blog cache that keeps entries in c for ttl
+func NewBlogCache(c cache.Cache, ttl time.Duration) *BlogCache {
+	return &BlogCache{cache: c, ttl: ttl}
+}
+
+// TTL returns how long entries are kept
+func (c *BlogCache) TTL() time.Duration {
+	return c.ttl
+}
+
+// Stats returns the counters of the cache
+func (c *BlogCache) Stats() BlogCacheStats {
+	return BlogCacheStats{Stats: c.cache.Stats(), Loads: c.loads.Load()}
+}
+
+// Invalidate drops the cached posts with the given IDs and the post listing of a tenant
+func (c *BlogCache) Invalidate(tenantID string, ids ...string) {
+	keys := []string{blogListKey(tenantID)}
+	for _, id := range ids {
+		keys = append(keys, blogPostKey(tenantID, id))
+	}
+
+	c.mu.Lock()
+	c.generation++
+	c.cache.Delete(keys...)
+	c.mu.Unlock()
+
+	// Later misses must not join a load that started before the change
+	for _, key := range keys {
+		c.group.Forget(key)
+	}
+}
+
+// Clear drops every entry, for when changes may have been missed
+func (c *BlogCache) Clear() {
+	c.mu.Lock()
+	c.generation++
+	c.cache.Clear()
+	c.mu.Unlock()
+}
+
+// load returns the value cached under key, calling fetch on a miss. Errors are not cached.
+func (c *BlogCache) load(key string, fetch func() (interface{}, error)) (interface{}, error) {
+	if value, ok := c.cache.Get(key); ok {
+		return value, nil
+	}
+
+	value, err, _ := c.group.Do(key, func() (interface{}, error) {
+		c.mu.Lock()
+		generation := c.generation
+		c.mu.Unlock()
+
+		c.loads.Add(1)
+		value, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		if c.generation == generation {
+			c.cache.Set(key, value, c.ttl)
+		}
+		c.mu.Unlock()
+		return value, nil
+	})
+	return value, err
+}
+
+// blogPostKey returns the cache key of a single post
+func blogPostKey(tenantID, id string) string {
+	return "blog:" + tenantID + ":post:" + id
+}
+
+// blogListKey returns the cache key of the listing of every post of a tenant
+func blogListKey(tenantID string) string {
+	return "blog:" + tenantID + ":all"
+}
+
+// cachedBlogService serves GetBlogByID and GetAllBlogs from a BlogCache
+type cachedBlogService struct {
+	BlogService
+	cache    *BlogCache
+	tenantID string
+}
+
+// WithCache returns a blog service that reads single posts and the full post listing through
+// blogCache and invalidates them when it changes posts
+func WithCache(blogService BlogService, blogCache *BlogCache) BlogService {
+	return &cachedBlogService{BlogService: blogService, cache: blogCache, tenantID: models.DefaultTenantID}
+}
+
+// WithTenant returns a service for the posts of another tenant, cached separately
+func (s *cachedBlogService) WithTenant(tenantID string) BlogService {
+	return &cachedBlogService{BlogService: s.BlogService.WithTenant(tenantID), cache: s.cache, tenantID: tenantID}
+}
+
+// WithRequester returns a service that attributes its changes to requester, sharing the cache
+func (s *cachedBlogService) WithRequester(requester *models.Requester) BlogService {
+	return &cachedBlogService{BlogService: s.BlogService.WithRequester(requester), cache: s.cache, tenantID: s.tenantID}
+}
+
+// GetBlogByID retrieves a blog post by ID from the cache, reading it on a miss
+func (s *cachedBlogService) GetBlogByID(id string) (*models.BlogResponse, error) {
+	if id == "" {
+		return s.BlogService.GetBlogByID(id)
+	}
+
+	value, err := s.cache.load(blogPostKey(s.tenantID, id), func() (interface{}, error) {
+		return s.BlogService.GetBlogByID(id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cloneBlogResponse(value.(*models.BlogResponse)), nil
+}
+
+// GetAllBlogs retrieves all blog posts from the cache, reading them on a miss
+func (s *cachedBlogService) GetAllBlogs() ([]models.BlogResponse, error) {
+	value, err := s.cache.load(blogListKey(s.tenantID), func() (interface{}, error) {
+		return s.BlogService.GetAllBlogs()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	blogs := value.([]models.BlogResponse)
+	responses := make([]models.BlogResponse, len(blogs))
+	for i := range blogs {
+		responses[i] = *cloneBlogResponse(&blogs[i])
+	}
+	return responses, nil
+}
+
+// CreateBlog creates a blog post and drops the cached listing
+func (s *cachedBlogService) CreateBlog(request *models.BlogCreateRequest) (*models.BlogResponse, error) {
+	response, err := s.BlogService.CreateBlog(request)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Invalidate(s.tenantID)
+	return response, nil
+}
+
+// UpdateBlog updates a blog post and drops its cached copy and the cached listing
+func (s *cachedBlogService) UpdateBlog(id string, request *models.BlogUpdateRequest) (*models.BlogResponse, error) {
+	response, err := s.BlogService.UpdateBlog(id, request)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Invalidate(s.tenantID, id)
+	return response, nil
+}
+
+// DeleteBlog deletes a blog post and drops its cached copy and the cached listing
+func (s *cachedBlogService) DeleteBlog(id string) error {
+	if err := s.BlogService.DeleteBlog(id); err != nil {
+		return err
+	}
+	s.cache.Invalidate(s.tenantID, id)
+	return nil
+}
+
+// BulkBlogs applies a batch of operations and drops the cached copies of the posts it changed
+func (s *cachedBlogService) BulkBlogs(request *models.BlogBulkRequest) (*models.BlogBulkResponse, error) {
+	response, err := s.BlogService.BulkBlogs(request)
+	if err != nil || !response.Committed || response.Succeeded == 0 {
+		return response, err
+	}
+
+	var ids []string
+	for _, result := range response.Results {
+		if result.Error == "" && result.ID != "" {
+			ids = append(ids, result.ID)
+		}
+	}
+	s.cache.Invalidate(s.tenantID, ids...)
+	return response, nil
+}
+
+// cloneBlogResponse copies a post so that callers cannot change the cached one
+func cloneBlogResponse(blog *models.BlogResponse) *models.BlogResponse {
+	clone := *blog
+	if blog.Tags != nil {
+		clone.Tags = append(make([]string, 0, len(blog.Tags)), blog.Tags...)
+	}
+	if blog.PublishedAt != nil {
+		publishedAt := *blog.PublishedAt
+		clone.PublishedAt = &publishedAt
+	}
+	return &clone
+}
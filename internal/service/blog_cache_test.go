@@ -0,0 +1,266 @@
+package service
+
+import (
+	"BlogManagment/internal/cache"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingBlogService counts the reads that reach the wrapped service. While gate is set,
+// single post reads wait for it to be closed before returning what they read.
+type countingBlogService struct {
+	BlogService
+	reads *atomic.Int32
+	gate  chan struct{}
+}
+
+func (s *countingBlogService) WithTenant(tenantID string) BlogService {
+	return &countingBlogService{BlogService: s.BlogService.WithTenant(tenantID), reads: s.reads, gate: s.gate}
+}
+
+func (s *countingBlogService) WithRequester(requester *models.Requester) BlogService {
+	return &countingBlogService{BlogService: s.BlogService.WithRequester(requester), reads: s.reads, gate: s.gate}
+}
+
+func (s *countingBlogService) GetBlogByID(id string) (*models.BlogResponse, error) {
+	s.reads.Add(1)
+	post, err := s.BlogService.GetBlogByID(id)
+	if s.gate != nil {
+		<-s.gate
+	}
+	return post, err
+}
+
+func (s *countingBlogService) GetAllBlogs() ([]models.BlogResponse, error) {
+	s.reads.Add(1)
+	return s.BlogService.GetAllBlogs()
+}
+
+// newCachedBlogService returns a cached blog service backed by an in-memory repository, the
+// wrapped service that counts its reads and the cache
+func newCachedBlogService(t *testing.T) (BlogService, *countingBlogService, *BlogCache) {
+	t.Helper()
+	counting := &countingBlogService{BlogService: NewBlogService(repository.NewMemoryBlogRepository()), reads: &atomic.Int32{}}
+	blogCache := NewBlogCache(cache.NewLRU(100), time.Minute)
+	return WithCache(counting, blogCache), counting, blogCache
+}
+
+func TestWithCache_ReadsAreCached(t *testing.T) {
+	blogService, counting, blogCache := newCachedBlogService(t)
+	post, err := blogService.CreateBlog(&models.BlogCreateRequest{Title: "Hello", Body: "World", Tags: []string{"go"}})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		found, err := blogService.GetBlogByID(post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Hello", found.Title)
+
+		all, err := blogService.GetAllBlogs()
+		require.NoError(t, err)
+		assert.Len(t, all, 1)
+	}
+
+	assert.Equal(t, int32(2), counting.reads.Load())
+	stats := blogCache.Stats()
+	assert.Equal(t, uint64(4), stats.Hits)
+	assert.Equal(t, uint64(2), stats.Misses)
+	assert.Equal(t, uint64(2), stats.Loads)
+	assert.Equal(t, 2, stats.Entries)
+}
+
+func TestWithCache_ErrorsAreNotCached(t *testing.T) {
+	blogService, counting, _ := newCachedBlogService(t)
+
+	_, err := blogService.GetBlogByID("missing")
+	assert.ErrorIs(t, err, repository.ErrBlogNotFound)
+	_, err = blogService.GetBlogByID("missing")
+	assert.ErrorIs(t, err, repository.ErrBlogNotFound)
+
+	assert.Equal(t, int32(2), counting.reads.Load())
+}
+
+func TestWithCache_ChangesInvalidate(t *testing.T) {
+	blogService, counting, _ := newCachedBlogService(t)
+	post, err := blogService.CreateBlog(&models.BlogCreateRequest{Title: "Hello", Body: "World"})
+	require.NoError(t, err)
+	other, err := blogService.CreateBlog(&models.BlogCreateRequest{Title: "Other", Body: "World"})
+	require.NoError(t, err)
+
+	warm := func() {
+		_, _ = blogService.GetBlogByID(post.ID)
+		_, _ = blogService.GetBlogByID(other.ID)
+		_, _ = blogService.GetAllBlogs()
+	}
+
+	t.Run("update drops the post and the listing", func(t *testing.T) {
+		warm()
+		title := "Changed"
+		_, err := blogService.UpdateBlog(post.ID, &models.BlogUpdateRequest{Title: &title})
+		require.NoError(t, err)
+
+		reads := counting.reads.Load()
+		found, err := blogService.GetBlogByID(post.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Changed", found.Title)
+		all, err := blogService.GetAllBlogs()
+		require.NoError(t, err)
+		assert.Contains(t, []string{all[0].Title, all[1].Title}, "Changed")
+		_, err = blogService.GetBlogByID(other.ID)
+		require.NoError(t, err)
+
+		// The other post is still cached
+		assert.Equal(t, reads+2, counting.reads.Load())
+	})
+
+	t.Run("create drops the listing", func(t *testing.T) {
+		warm()
+		_, err := blogService.CreateBlog(&models.BlogCreateRequest{Title: "Third", Body: "World"})
+		require.NoError(t, err)
+
+		reads := counting.reads.Load()
+		all, err := blogService.GetAllBlogs()
+		require.NoError(t, err)
+		assert.Len(t, all, 3)
+		_, _ = blogService.GetBlogByID(post.ID)
+		assert.Equal(t, reads+1, counting.reads.Load())
+	})
+
+	t.Run("delete drops the post and the listing", func(t *testing.T) {
+		warm()
+		require.NoError(t, blogService.DeleteBlog(other.ID))
+
+		_, err := blogService.GetBlogByID(other.ID)
+		assert.ErrorIs(t, err, repository.ErrBlogNotFound)
+		all, err := blogService.GetAllBlogs()
+		require.NoError(t, err)
+		assert.Len(t, all, 2)
+	})
+
+	t.Run("bulk drops the posts it changed", func(t *testing.T) {
+		warm()
+		response, err := blogService.BulkBlogs(&models.BlogBulkRequest{Operations: []models.BlogBulkOperation{
+			{Action: models.BulkActionDelete, ID: post.ID},
+		}})
+		require.NoError(t, err)
+		require.True(t, response.Committed)
+
+		_, err = blogService.GetBlogByID(post.ID)
+		assert.ErrorIs(t, err, repository.ErrBlogNotFound)
+	})
+
+	t.Run("invalidate drops changes made elsewhere", func(t *testing.T) {
+		warm()
+		reads := counting.reads.Load()
+		blogService.(*cachedBlogService).cache.Invalidate(models.DefaultTenantID)
+
+		_, _ = blogService.GetAllBlogs()
+		assert.Equal(t, reads+1, counting.reads.Load())
+	})
+}
+
+func TestWithCache_TenantsAreSeparate(t *testing.T) {
+	blogService, _, _ := newCachedBlogService(t)
+	_, err := blogService.CreateBlog(&models.BlogCreateRequest{Title: "Default", Body: "World"})
+	require.NoError(t, err)
+
+	all, err := blogService.GetAllBlogs()
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+
+	acme := blogService.WithTenant("acme").WithRequester(&models.Requester{Subject: "alice"})
+	all, err = acme.GetAllBlogs()
+	require.NoError(t, err)
+	assert.Empty(t, all)
+
+	_, err = acme.CreateBlog(&models.BlogCreateRequest{Title: "Acme", Body: "World"})
+	require.NoError(t, err)
+	all, err = acme.GetAllBlogs()
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+}
+
+func TestWithCache_ReturnsCopies(t *testing.T) {
+	blogService, _, _ := newCachedBlogService(t)
+	post, err := blogService.CreateBlog(&models.BlogCreateRequest{Title: "Hello", Body: "World", Tags: []string{"go"}})
+	require.NoError(t, err)
+
+	found, err := blogService.GetBlogByID(post.ID)
+	require.NoError(t, err)
+	found.Title = "Changed"
+	found.Tags[0] = "changed"
+
+	found, err = blogService.GetBlogByID(post.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", found.Title)
+	assert.Equal(t, []string{"go"}, found.Tags)
+}
+
+func TestWithCache_ConcurrentMissesShareALoad(t *testing.T) {
+	blogService, counting, blogCache := newCachedBlogService(t)
+	post, err := blogService.CreateBlog(&models.BlogCreateRequest{Title: "Hello", Body: "World"})
+	require.NoError(t, err)
+
+	gate := make(chan struct{})
+	cached := WithCache(&countingBlogService{BlogService: counting.BlogService, reads: counting.reads, gate: gate}, blogCache)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			found, err := cached.GetBlogByID(post.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, "Hello", found.Title)
+		}()
+	}
+
+	// Let the misses pile up behind the first load before releasing it
+	require.Eventually(t, func() bool { return blogCache.Stats().Misses == 10 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(gate)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), counting.reads.Load())
+	assert.Equal(t, uint64(1), blogCache.Stats().Loads)
+}
+
+func TestWithCache_LoadsOverlappingAChangeAreNotStored(t *testing.T) {
+	blogService, counting, blogCache := newCachedBlogService(t)
+	post, err := blogService.CreateBlog(&models.BlogCreateRequest{Title: "Hello", Body: "World"})
+	require.NoError(t, err)
+
+	gate := make(chan struct{})
+	slow := WithCache(&countingBlogService{BlogService: counting.BlogService, reads: counting.reads, gate: gate}, blogCache)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		found, err := slow.GetBlogByID(post.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello", found.Title)
+	}()
+	require.Eventually(t, func() bool { return counting.reads.Load() == 1 }, time.Second, time.Millisecond)
+
+	// The slow read has already seen the old title; it must not be cached after the change
+	title := "Changed"
+	_, err = blogService.UpdateBlog(post.ID, &models.BlogUpdateRequest{Title: &title})
+	require.NoError(t, err)
+
+	found, err := blogService.GetBlogByID(post.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Changed", found.Title)
+
+	close(gate)
+	<-done
+
+	found, err = blogService.GetBlogByID(post.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Changed", found.Title)
+}
@@ -3,7 +3,10 @@ package service
 import (
 	"BlogManagment/internal/models"
 	"BlogManagment/internal/repository"
+	"BlogManagment/internal/slug"
+	"encoding/base64"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,20 +17,41 @@ type BlogService interface {
 	CreateBlog(request *models.BlogCreateRequest) (*models.BlogResponse, error)
 	GetBlogByID(id string) (*models.BlogResponse, error)
 	GetAllBlogs() ([]models.BlogResponse, error)
+	GetPublishedBlogs() ([]models.BlogResponse, error)
+	ListBlogs(query *models.BlogListQuery) (*models.BlogPage, error)
+	GetBlogBySlug(slug string) (*models.BlogResponse, error)
+	CountBlogsByTags(query *models.TagCountQuery) (map[string]int, error)
 	UpdateBlog(id string, request *models.BlogUpdateRequest) (*models.BlogResponse, error)
 	DeleteBlog(id string) error
+	BulkBlogs(request *models.BlogBulkRequest) (*models.BlogBulkResponse, error)
+	WithTenant(tenantID string) BlogService
+	WithRequester(requester *models.Requester) BlogService
 }
 
 // blogService implements BlogService interface
 type blogService struct {
-	blogRepo repository.BlogRepository
+	blogRepo  repository.BlogRepository
+	requester *models.Requester
 }
 
-// NewBlogService creates a new blog service instance
+// NewBlogService creates a new blog service instance.
+// Every change records its lifecycle events in the outbox and an entry in the audit log in the
+// same transaction.
 func NewBlogService(blogRepo repository.BlogRepository) BlogService {
 	return &blogService{blogRepo: blogRepo}
 }
 
+// WithTenant returns a service for the posts of another tenant
+func (s *blogService) WithTenant(tenantID string) BlogService {
+	return &blogService{blogRepo: s.blogRepo.WithTenant(tenantID), requester: s.requester}
+}
+
+// WithRequester returns a service that attributes its changes to requester in the audit log.
+// Reads made after a change of the requester see the change, even with read replicas.
+func (s *blogService) WithRequester(requester *models.Requester) BlogService {
+	return &blogService{blogRepo: s.blogRepo.WithSession(requester.Session()), requester: requester}
+}
+
 // CreateBlog creates a new blog post
 func (s *blogService) CreateBlog(request *models.BlogCreateRequest) (*models.BlogResponse, error) {
 	// Validate request
@@ -36,22 +60,26 @@ func (s *blogService) CreateBlog(request *models.BlogCreateRequest) (*models.Blo
 	}
 
 	// Create blog model
-	blog := &models.Blog{
-		ID:          uuid.New().String(),
-		Title:       request.Title,
-		Description: request.Description,
-		Body:        request.Body,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
-	}
+	blog := s.newBlog(request)
 
 	// Save to database
-	if err := s.blogRepo.Create(blog); err != nil {
+	var response *models.BlogResponse
+	err := saveWithEvents(s.blogRepo, func(repo repository.BlogRepository) ([]*models.BlogEvent, error) {
+		if err := repo.Create(blog); err != nil {
+			return nil, err
+		}
+		response = s.blogToResponse(blog)
+		if err := appendAudit(repo, s.requester, models.AuditActionPostCreate, blog.ID, nil, response); err != nil {
+			return nil, err
+		}
+		return lifecycleEvents(models.EventPostCreated, false, response), nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	// Return response
-	return s.blogToResponse(blog), nil
+	return response, nil
 }
 
 // GetBlogByID retrieves a blog post by ID
@@ -83,45 +111,177 @@ func (s *blogService) GetAllBlogs() ([]models.BlogResponse, error) {
 	return responses, nil
 }
 
-// UpdateBlog updates an existing blog post
-func (s *blogService) UpdateBlog(id string, request *models.BlogUpdateRequest) (*models.BlogResponse, error) {
-	if id == "" {
-		return nil, errors.New("blog ID is required")
+// GetPublishedBlogs retrieves every published blog post, most recently published first
+func (s *blogService) GetPublishedBlogs() ([]models.BlogResponse, error) {
+	blogs, err := s.blogRepo.GetPublished()
+	if err != nil {
+		return nil, err
 	}
 
-	// Get existing blog
-	existingBlog, err := s.blogRepo.GetByID(id)
-	if err != nil {
+	responses := make([]models.BlogResponse, len(blogs))
+	for i, blog := range blogs {
+		responses[i] = *s.blogToResponse(&blog)
+	}
+
+	return responses, nil
+}
+
+// defaultPageSize is the number of posts returned by ListBlogs when no page size is requested
+const defaultPageSize = 20
+
+// ListBlogs retrieves a page of blog posts matching the query, newest first.
+// The returned EndCursor is passed back as After to fetch the following page.
+func (s *blogService) ListBlogs(query *models.BlogListQuery) (*models.BlogPage, error) {
+	if query == nil {
+		return nil, errors.New("query cannot be nil")
+	}
+
+	if err := validateStruct(query); err != nil {
 		return nil, err
 	}
 
-	// Update fields if provided
-	if request.Title != nil {
-		if *request.Title == "" {
-			return nil, errors.New("title cannot be empty")
+	var after *models.BlogCursor
+	if query.After != "" {
+		cursor, err := decodeBlogCursor(query.After)
+		if err != nil {
+			return nil, err
 		}
-		existingBlog.Title = *request.Title
+		after = cursor
 	}
 
-	if request.Description != nil {
-		existingBlog.Description = *request.Description
+	limit := query.First
+	if limit == 0 {
+		limit = defaultPageSize
 	}
 
-	if request.Body != nil {
-		if *request.Body == "" {
-			return nil, errors.New("body cannot be empty")
-		}
-		existingBlog.Body = *request.Body
+	// Fetch one extra post to find out whether another page follows
+	filter := &models.BlogFilter{
+		Status:        query.Status,
+		Tag:           strings.TrimSpace(query.Tag),
+		PublishedOnly: query.PublishedOnly,
+		DraftsOf:      query.DraftsOf,
 	}
+	blogs, err := s.blogRepo.List(filter, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.BlogPage{HasNextPage: len(blogs) > limit}
+	if page.HasNextPage {
+		blogs = blogs[:limit]
+	}
+
+	page.Posts = make([]models.BlogResponse, len(blogs))
+	for i, blog := range blogs {
+		page.Posts[i] = *s.blogToResponse(&blog)
+	}
+	if len(blogs) > 0 {
+		last := blogs[len(blogs)-1]
+		page.EndCursor = EncodeBlogCursor(&models.BlogCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	return page, nil
+}
+
+// GetBlogBySlug retrieves a blog post by its slug
+func (s *blogService) GetBlogBySlug(slug string) (*models.BlogResponse, error) {
+	if slug == "" {
+		return nil, errors.New("blog slug is required")
+	}
+
+	blog, err := s.blogRepo.GetBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.blogToResponse(blog), nil
+}
+
+// CountBlogsByTags returns the number of blog posts carrying each of the tags of the query.
+// Every requested tag is present in the result, with zero for tags without posts.
+func (s *blogService) CountBlogsByTags(query *models.TagCountQuery) (map[string]int, error) {
+	if query == nil {
+		return nil, errors.New("query cannot be nil")
+	}
+
+	filter := &models.BlogFilter{PublishedOnly: query.PublishedOnly, DraftsOf: query.DraftsOf}
+	counts, err := s.blogRepo.CountByTags(query.Names, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int, len(query.Names))
+	for _, name := range query.Names {
+		result[name] = counts[name]
+	}
+	return result, nil
+}
 
-	existingBlog.UpdatedAt = time.Now()
+// EncodeBlogCursor returns the opaque cursor string for a position in the post listing
+func EncodeBlogCursor(cursor *models.BlogCursor) string {
+	raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + cursor.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeBlogCursor parses a cursor produced by EncodeBlogCursor
+func decodeBlogCursor(value string) (*models.BlogCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	createdAt, id, found := strings.Cut(string(raw), "|")
+	if !found || id == "" {
+		return nil, errors.New("invalid cursor")
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	return &models.BlogCursor{CreatedAt: timestamp, ID: id}, nil
+}
+
+// UpdateBlog updates an existing blog post
+func (s *blogService) UpdateBlog(id string, request *models.BlogUpdateRequest) (*models.BlogResponse, error) {
+	if id == "" {
+		return nil, errors.New("blog ID is required")
+	}
+
+	// Get existing blog; it is written back whole, so it must not come from a lagging replica
+	existingBlog, err := s.blogRepo.Primary().GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate request
+	if err := s.validateUpdateRequest(request); err != nil {
+		return nil, err
+	}
+
+	// Update fields if provided
+	wasPublished := existingBlog.IsPublished()
+	before := s.blogToResponse(existingBlog)
+	s.applyUpdateRequest(existingBlog, request)
 
 	// Save to database
-	if err := s.blogRepo.Update(existingBlog); err != nil {
+	var response *models.BlogResponse
+	err = saveWithEvents(s.blogRepo, func(repo repository.BlogRepository) ([]*models.BlogEvent, error) {
+		if err := repo.Update(existingBlog); err != nil {
+			return nil, err
+		}
+		response = s.blogToResponse(existingBlog)
+		if err := appendAudit(repo, s.requester, models.AuditActionPostUpdate, id, before, response); err != nil {
+			return nil, err
+		}
+		return lifecycleEvents(models.EventPostUpdated, wasPublished, response), nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return s.blogToResponse(existingBlog), nil
+	return response, nil
 }
 
 // DeleteBlog deletes a blog post
@@ -130,7 +290,20 @@ func (s *blogService) DeleteBlog(id string) error {
 		return errors.New("blog ID is required")
 	}
 
-	return s.blogRepo.Delete(id)
+	return saveWithEvents(s.blogRepo, func(repo repository.BlogRepository) ([]*models.BlogEvent, error) {
+		// The deleted post is read first so that the audit log records what was removed
+		existingBlog, err := repo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		if err := repo.Delete(id); err != nil {
+			return nil, err
+		}
+		if err := appendAudit(repo, s.requester, models.AuditActionPostDelete, id, s.blogToResponse(existingBlog), nil); err != nil {
+			return nil, err
+		}
+		return []*models.BlogEvent{newEvent(models.EventPostDeleted, &models.BlogResponse{ID: id})}, nil
+	})
 }
 
 // validateCreateRequest validates the create request
@@ -147,17 +320,120 @@ func (s *blogService) validateCreateRequest(request *models.BlogCreateRequest) e
 		return errors.New("body is required")
 	}
 
-	return nil
+	if request.Slug != "" && slug.Make(request.Slug) == "" {
+		return errors.New("slug must contain letters or digits")
+	}
+
+	return validateStruct(request)
+}
+
+// validateUpdateRequest validates the update request
+func (s *blogService) validateUpdateRequest(request *models.BlogUpdateRequest) error {
+	if request == nil {
+		return errors.New("request cannot be nil")
+	}
+
+	if request.Title != nil && *request.Title == "" {
+		return errors.New("title cannot be empty")
+	}
+
+	if request.Body != nil && *request.Body == "" {
+		return errors.New("body cannot be empty")
+	}
+
+	if request.Slug != nil && slug.Make(*request.Slug) == "" {
+		return errors.New("slug must contain letters or digits")
+	}
+
+	return validateStruct(request)
+}
+
+// applyUpdateRequest copies the provided fields of an update request onto a blog post
+func (s *blogService) applyUpdateRequest(blog *models.Blog, request *models.BlogUpdateRequest) {
+	if request.Slug != nil {
+		blog.Slug = slug.Make(*request.Slug)
+	}
+
+	if request.Title != nil {
+		blog.Title = *request.Title
+	}
+
+	if request.Description != nil {
+		blog.Description = *request.Description
+	}
+
+	if request.Body != nil {
+		blog.Body = *request.Body
+	}
+
+	if request.Tags != nil {
+		blog.Tags = newBlogTags(blog.ID, normalizeTags(*request.Tags))
+	}
+
+	if request.Status != nil {
+		setStatus(blog, *request.Status)
+	}
+
+	blog.UpdatedAt = time.Now()
+}
+
+// newBlog builds a new blog post from a validated create request
+func (s *blogService) newBlog(request *models.BlogCreateRequest) *models.Blog {
+	postSlug := slug.Make(request.Slug)
+	if postSlug == "" {
+		postSlug = slug.Make(request.Title)
+	}
+
+	id := uuid.New().String()
+	blog := &models.Blog{
+		ID:          id,
+		Slug:        postSlug,
+		Title:       request.Title,
+		Description: request.Description,
+		Body:        request.Body,
+		Tags:        newBlogTags(id, normalizeTags(request.Tags)),
+		AuthorID:    request.AuthorID,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	status := request.Status
+	if status == "" {
+		status = models.BlogStatusPublished
+	}
+	setStatus(blog, status)
+
+	return blog
+}
+
+// setStatus changes the publication state of a blog post.
+// The first time a post is published its publication time is recorded.
+func setStatus(blog *models.Blog, status string) {
+	blog.Status = status
+	if status == models.BlogStatusPublished && blog.PublishedAt == nil {
+		now := time.Now()
+		blog.PublishedAt = &now
+	}
 }
 
 // blogToResponse converts a Blog model to BlogResponse
 func (s *blogService) blogToResponse(blog *models.Blog) *models.BlogResponse {
+	return newBlogResponse(blog)
+}
+
+// newBlogResponse converts a Blog model to BlogResponse
+func newBlogResponse(blog *models.Blog) *models.BlogResponse {
 	return &models.BlogResponse{
 		ID:          blog.ID,
+		Slug:        blog.Slug,
 		Title:       blog.Title,
 		Description: blog.Description,
 		Body:        blog.Body,
+		Tags:        blog.TagNames(),
+		Status:      blog.Status,
+		AuthorID:    blog.AuthorID,
+		PublishedAt: blog.PublishedAt,
 		CreatedAt:   blog.CreatedAt,
 		UpdatedAt:   blog.UpdatedAt,
 	}
-} 
\ No newline at end of file
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
 	"errors"
 	"testing"
 	"time"
@@ -37,6 +38,14 @@ func (m *MockBlogRepository) GetAll() ([]models.Blog, error) {
 	return args.Get(0).([]models.Blog), args.Error(1)
 }
 
+func (m *MockBlogRepository) GetPublished() ([]models.Blog, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Blog), args.Error(1)
+}
+
 func (m *MockBlogRepository) Update(blog *models.Blog) error {
 	args := m.Called(blog)
 	return args.Error(0)
@@ -47,8 +56,107 @@ func (m *MockBlogRepository) Delete(id string) error {
 	return args.Error(0)
 }
 
+func (m *MockBlogRepository) GetBySlug(slug string) (*models.Blog, error) {
+	args := m.Called(slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Blog), args.Error(1)
+}
+
+func (m *MockBlogRepository) ListAfter(cursor *models.BlogCursor, limit int) ([]models.Blog, error) {
+	args := m.Called(cursor, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Blog), args.Error(1)
+}
+
+func (m *MockBlogRepository) List(filter *models.BlogFilter, before *models.BlogCursor, limit int) ([]models.Blog, error) {
+	args := m.Called(filter, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Blog), args.Error(1)
+}
+
+func (m *MockBlogRepository) CountByTags(names []string, filter *models.BlogFilter) (map[string]int, error) {
+	args := m.Called(names, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+func (m *MockBlogRepository) CreateBatch(blogs []*models.Blog) error {
+	args := m.Called(blogs)
+	return args.Error(0)
+}
+
+func (m *MockBlogRepository) GetByIDs(ids []string) ([]models.Blog, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Blog), args.Error(1)
+}
+
+// Transaction runs fn against the mock itself, so expectations cover calls made inside the transaction
+func (m *MockBlogRepository) Transaction(fn func(repo repository.BlogRepository) error) error {
+	m.Called()
+	return fn(m)
+}
+
+func (m *MockBlogRepository) AppendEvents(events []*models.BlogEvent) error {
+	args := m.Called(events)
+	return args.Error(0)
+}
+
+func (m *MockBlogRepository) AppendAudit(entries []*models.AuditEntry) error {
+	args := m.Called(entries)
+	return args.Error(0)
+}
+
+func (m *MockBlogRepository) WithTenant(tenantID string) repository.BlogRepository {
+	args := m.Called(tenantID)
+	return args.Get(0).(repository.BlogRepository)
+}
+
+// WithSession returns the mock itself, which has no replicas to route reads to
+func (m *MockBlogRepository) WithSession(session string) repository.BlogRepository {
+	return m
+}
+
+// Primary returns the mock itself, which has no replicas to route reads to
+func (m *MockBlogRepository) Primary() repository.BlogRepository {
+	return m
+}
+
+// newMockBlogRepository creates a mock that accepts transactions and outbox writes, so that
+// tests only need to set up the calls they are interested in
+func newMockBlogRepository() *MockBlogRepository {
+	m := &MockBlogRepository{}
+	m.On("Transaction").Maybe()
+	m.On("AppendEvents", mock.Anything).Return(nil).Maybe()
+	m.On("AppendAudit", mock.Anything).Return(nil).Maybe()
+	return m
+}
+
+// appendedEventTypes returns the types of every event added to the outbox, in order
+func appendedEventTypes(m *MockBlogRepository) []string {
+	var types []string
+	for _, call := range m.Calls {
+		if call.Method == "AppendEvents" {
+			for _, event := range call.Arguments.Get(0).([]*models.BlogEvent) {
+				types = append(types, event.Type)
+			}
+		}
+	}
+	return types
+}
+
 func TestNewBlogService(t *testing.T) {
-	mockRepo := &MockBlogRepository{}
+	mockRepo := newMockBlogRepository()
 	service := NewBlogService(mockRepo)
 
 	assert.NotNil(t, service)
@@ -56,7 +164,7 @@ func TestNewBlogService(t *testing.T) {
 }
 
 func TestBlogService_CreateBlog_Success(t *testing.T) {
-	mockRepo := &MockBlogRepository{}
+	mockRepo := newMockBlogRepository()
 	service := NewBlogService(mockRepo)
 
 	request := &models.BlogCreateRequest{
@@ -81,8 +189,30 @@ func TestBlogService_CreateBlog_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestBlogService_CreateBlog_NormalizesTags(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogService(mockRepo)
+
+	request := &models.BlogCreateRequest{
+		Title: "Tagged",
+		Body:  "Body",
+		Tags:  []string{" Go ", "go", "", "web   development"},
+	}
+
+	mockRepo.On("Create", mock.MatchedBy(func(blog *models.Blog) bool {
+		return len(blog.Tags) == 2 && blog.Tags[0].BlogID == blog.ID
+	})).Return(nil)
+
+	response, err := service.CreateBlog(request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Go", "web development"}, response.Tags)
+
+	mockRepo.AssertExpectations(t)
+}
+
 func TestBlogService_CreateBlog_ValidationError(t *testing.T) {
-	mockRepo := &MockBlogRepository{}
+	mockRepo := newMockBlogRepository()
 	service := NewBlogService(mockRepo)
 
 	// Test with nil request
@@ -113,7 +243,7 @@ func TestBlogService_CreateBlog_ValidationError(t *testing.T) {
 }
 
 func TestBlogService_CreateBlog_RepositoryError(t *testing.T) {
-	mockRepo := &MockBlogRepository{}
+	mockRepo := newMockBlogRepository()
 	service := NewBlogService(mockRepo)
 
 	request := &models.BlogCreateRequest{
@@ -133,7 +263,7 @@ func TestBlogService_CreateBlog_RepositoryError(t *testing.T) {
 }
 
 func TestBlogService_GetBlogByID_Success(t *testing.T) {
-	mockRepo := &MockBlogRepository{}
+	mockRepo := newMockBlogRepository()
 	service := NewBlogService(mockRepo)
 
 	blogID := uuid.New().String()
@@ -160,8 +290,21 @@ func TestBlogService_GetBlogByID_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestBlogService_WithTenant(t *testing.T) {
+	defaultRepo := newMockBlogRepository()
+	tenantRepo := newMockBlogRepository()
+	defaultRepo.On("WithTenant", "acme").Return(tenantRepo)
+	tenantRepo.On("GetByID", "1").Return(&models.Blog{ID: "1", Title: "Acme post"}, nil)
+
+	response, err := NewBlogService(defaultRepo).WithTenant("acme").GetBlogByID("1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Acme post", response.Title)
+	defaultRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+	tenantRepo.AssertExpectations(t)
+}
 func TestBlogService_GetBlogByID_EmptyID(t *testing.T) {
-	mockRepo := &MockBlogRepository{}
+	mockRepo := newMockBlogRepository()
 	service := NewBlogService(mockRepo)
 
 	response, err := service.GetBlogByID("")
@@ -172,7 +315,7 @@ func TestBlogService_GetBlogByID_EmptyID(t *testing.T) {
 }
 
 func TestBlogService_GetBlogByID_NotFound(t *testing.T) {
-	mockRepo := &MockBlogRepository{}
+	mockRepo := newMockBlogRepository()
 	service := NewBlogService(mockRepo)
 
 	blogID := uuid.New().String()
@@ -188,7 +331,7 @@ func TestBlogService_GetBlogByID_NotFound(t *testing.T) {
 }
 
 func TestBlogService_GetAllBlogs_Success(t *testing.T) {
-	mockRepo := &MockBlogRepository{}
+	mockRepo := newMockBlogRepository()
 	service := NewBlogService(mockRepo)
 
 	expectedBlogs := []models.Blog{
@@ -224,7 +367,7 @@ func TestBlogService_GetAllBlogs_Success(t *testing.T) {
 }
 
 func TestBlogService_GetAllBlogs_Error(t *testing.T) {
-	mockRepo := &MockBlogRepository{}
+	mockRepo := newMockBlogRepository()
 	service := NewBlogService(mockRepo)
 
 	mockRepo.On("GetAll").Return(nil, errors.New("database error"))
@@ -238,8 +381,118 @@ func TestBlogService_GetAllBlogs_Error(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestBlogService_ListBlogs_Pagination(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogService(mockRepo)
+
+	now := time.Now().UTC()
+	blogs := []models.Blog{
+		{ID: "c", Title: "Third", CreatedAt: now},
+		{ID: "b", Title: "Second", CreatedAt: now.Add(-time.Minute)},
+		{ID: "a", Title: "First", CreatedAt: now.Add(-2 * time.Minute)},
+	}
+	filter := &models.BlogFilter{Status: models.BlogStatusPublished, Tag: "go"}
+
+	mockRepo.On("List", filter, (*models.BlogCursor)(nil), 3).Return(blogs, nil).Once()
+
+	page, err := service.ListBlogs(&models.BlogListQuery{Status: models.BlogStatusPublished, Tag: " go ", First: 2})
+
+	assert.NoError(t, err)
+	assert.True(t, page.HasNextPage)
+	assert.Len(t, page.Posts, 2)
+	assert.Equal(t, "b", page.Posts[1].ID)
+
+	cursor := &models.BlogCursor{CreatedAt: blogs[1].CreatedAt, ID: "b"}
+	assert.Equal(t, EncodeBlogCursor(cursor), page.EndCursor)
+
+	mockRepo.On("List", filter, cursor, 3).Return(blogs[2:], nil).Once()
+
+	page, err = service.ListBlogs(&models.BlogListQuery{Status: models.BlogStatusPublished, Tag: "go", First: 2, After: page.EndCursor})
+
+	assert.NoError(t, err)
+	assert.False(t, page.HasNextPage)
+	assert.Len(t, page.Posts, 1)
+	assert.Equal(t, "a", page.Posts[0].ID)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBlogService_ListBlogs_DefaultPageSize(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogService(mockRepo)
+
+	mockRepo.On("List", &models.BlogFilter{}, (*models.BlogCursor)(nil), defaultPageSize+1).Return([]models.Blog{}, nil)
+
+	page, err := service.ListBlogs(&models.BlogListQuery{})
+
+	assert.NoError(t, err)
+	assert.Empty(t, page.Posts)
+	assert.Empty(t, page.EndCursor)
+	assert.False(t, page.HasNextPage)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBlogService_ListBlogs_ValidationError(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogService(mockRepo)
+
+	tests := []struct {
+		name  string
+		query *models.BlogListQuery
+	}{
+		{"nil query", nil},
+		{"page too large", &models.BlogListQuery{First: 101}},
+		{"unknown status", &models.BlogListQuery{Status: "archived"}},
+		{"malformed cursor", &models.BlogListQuery{After: "not a cursor"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := service.ListBlogs(tt.query)
+			assert.Error(t, err)
+			assert.Nil(t, page)
+		})
+	}
+
+	mockRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBlogService_GetBlogBySlug(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogService(mockRepo)
+
+	mockRepo.On("GetBySlug", "hello-world").Return(&models.Blog{ID: "1", Slug: "hello-world"}, nil)
+	mockRepo.On("GetBySlug", "missing").Return(nil, repository.ErrBlogNotFound)
+
+	response, err := service.GetBlogBySlug("hello-world")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", response.ID)
+
+	_, err = service.GetBlogBySlug("missing")
+	assert.ErrorIs(t, err, repository.ErrBlogNotFound)
+
+	_, err = service.GetBlogBySlug("")
+	assert.Error(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBlogService_CountBlogsByTags_FillsMissingTags(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogService(mockRepo)
+
+	mockRepo.On("CountByTags", []string{"go", "rust"}, &models.BlogFilter{}).Return(map[string]int{"go": 3}, nil)
+
+	counts, err := service.CountBlogsByTags(&models.TagCountQuery{Names: []string{"go", "rust"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"go": 3, "rust": 0}, counts)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestBlogService_UpdateBlog_Success(t *testing.T) {
-	mockRepo := &MockBlogRepository{}
+	mockRepo := newMockBlogRepository()
 	service := NewBlogService(mockRepo)
 
 	blogID := uuid.New().String()
@@ -271,10 +524,83 @@ func TestBlogService_UpdateBlog_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
-func TestBlogService_UpdateBlog_EmptyID(t *testing.T) {
+func TestBlogService_PublishDraft(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogService(mockRepo)
+
+	mockRepo.On("Create", mock.AnythingOfType("*models.Blog")).Return(nil)
+
+	draft, err := service.CreateBlog(&models.BlogCreateRequest{Title: "Draft", Body: "Body", Status: models.BlogStatusDraft})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.BlogStatusDraft, draft.Status)
+	assert.Nil(t, draft.PublishedAt)
+
+	existingBlog := &models.Blog{ID: draft.ID, Title: "Draft", Body: "Body", Status: models.BlogStatusDraft}
+	published := models.BlogStatusPublished
+	mockRepo.On("GetByID", draft.ID).Return(existingBlog, nil)
+	mockRepo.On("Update", mock.AnythingOfType("*models.Blog")).Return(nil)
+
+	response, err := service.UpdateBlog(draft.ID, &models.BlogUpdateRequest{Status: &published})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.BlogStatusPublished, response.Status)
+	assert.NotNil(t, response.PublishedAt)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBlogService_RecordsLifecycleEventsInOutbox(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogService(mockRepo)
+
+	mockRepo.On("Create", mock.AnythingOfType("*models.Blog")).Return(nil)
+
+	draft, err := service.CreateBlog(&models.BlogCreateRequest{Title: "Draft", Body: "Body", Status: models.BlogStatusDraft})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{models.EventPostCreated}, appendedEventTypes(mockRepo))
+
+	existingBlog := &models.Blog{ID: draft.ID, Title: "Draft", Body: "Body", Status: models.BlogStatusDraft}
+	published := models.BlogStatusPublished
+	mockRepo.On("GetByID", draft.ID).Return(existingBlog, nil)
+	mockRepo.On("Update", mock.AnythingOfType("*models.Blog")).Return(nil)
+	mockRepo.On("Delete", draft.ID).Return(nil)
+
+	_, err = service.UpdateBlog(draft.ID, &models.BlogUpdateRequest{Status: &published})
+	assert.NoError(t, err)
+	assert.NoError(t, service.DeleteBlog(draft.ID))
+
+	assert.Equal(t, []string{
+		models.EventPostCreated,
+		models.EventPostUpdated,
+		models.EventPostPublished,
+		models.EventPostDeleted,
+	}, appendedEventTypes(mockRepo))
+	// Every write and its events share a transaction
+	mockRepo.AssertNumberOfCalls(t, "Transaction", 3)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBlogService_CreateBlog_OutboxError(t *testing.T) {
 	mockRepo := &MockBlogRepository{}
 	service := NewBlogService(mockRepo)
 
+	mockRepo.On("Transaction")
+	mockRepo.On("Create", mock.AnythingOfType("*models.Blog")).Return(nil)
+	mockRepo.On("AppendAudit", mock.Anything).Return(nil)
+	mockRepo.On("AppendEvents", mock.Anything).Return(errors.New("outbox unavailable"))
+
+	response, err := service.CreateBlog(&models.BlogCreateRequest{Title: "Title", Body: "Body"})
+
+	assert.EqualError(t, err, "outbox unavailable")
+	assert.Nil(t, response)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBlogService_UpdateBlog_EmptyID(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogService(mockRepo)
+
 	request := &models.BlogUpdateRequest{}
 
 	response, err := service.UpdateBlog("", request)
@@ -285,7 +611,7 @@ func TestBlogService_UpdateBlog_EmptyID(t *testing.T) {
 }
 
 func TestBlogService_UpdateBlog_NotFound(t *testing.T) {
-	mockRepo := &MockBlogRepository{}
+	mockRepo := newMockBlogRepository()
 	service := NewBlogService(mockRepo)
 
 	blogID := uuid.New().String()
@@ -303,7 +629,7 @@ func TestBlogService_UpdateBlog_NotFound(t *testing.T) {
 }
 
 func TestBlogService_UpdateBlog_ValidationError(t *testing.T) {
-	mockRepo := &MockBlogRepository{}
+	mockRepo := newMockBlogRepository()
 	service := NewBlogService(mockRepo)
 
 	blogID := uuid.New().String()
@@ -333,10 +659,11 @@ func TestBlogService_UpdateBlog_ValidationError(t *testing.T) {
 }
 
 func TestBlogService_DeleteBlog_Success(t *testing.T) {
-	mockRepo := &MockBlogRepository{}
+	mockRepo := newMockBlogRepository()
 	service := NewBlogService(mockRepo)
 
 	blogID := uuid.New().String()
+	mockRepo.On("GetByID", blogID).Return(&models.Blog{ID: blogID, Title: "Title", Body: "Body"}, nil)
 	mockRepo.On("Delete", blogID).Return(nil)
 
 	err := service.DeleteBlog(blogID)
@@ -346,7 +673,7 @@ func TestBlogService_DeleteBlog_Success(t *testing.T) {
 }
 
 func TestBlogService_DeleteBlog_EmptyID(t *testing.T) {
-	mockRepo := &MockBlogRepository{}
+	mockRepo := newMockBlogRepository()
 	service := NewBlogService(mockRepo)
 
 	err := service.DeleteBlog("")
@@ -356,11 +683,11 @@ func TestBlogService_DeleteBlog_EmptyID(t *testing.T) {
 }
 
 func TestBlogService_DeleteBlog_NotFound(t *testing.T) {
-	mockRepo := &MockBlogRepository{}
+	mockRepo := newMockBlogRepository()
 	service := NewBlogService(mockRepo)
 
 	blogID := uuid.New().String()
-	mockRepo.On("Delete", blogID).Return(errors.New("blog post not found"))
+	mockRepo.On("GetByID", blogID).Return(nil, errors.New("blog post not found"))
 
 	err := service.DeleteBlog(blogID)
 
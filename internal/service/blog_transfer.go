@@ -0,0 +1,559 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"BlogManagment/internal/slug"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// exportPageSize is the number of posts read from the repository per page
+const exportPageSize = 500
+
+// maxImportLineSize bounds a single JSON Lines record
+const maxImportLineSize = 16 * 1024 * 1024
+
+// maxDescriptionLength matches the validation limit of descriptions
+const maxDescriptionLength = 1000
+
+// csvColumns is the column order of CSV exports
+var csvColumns = []string{"id", "slug", "title", "description", "body", "tags", "status", "published_at", "created_at", "updated_at"}
+
+// csvTagSeparator separates tag names within the tags column of CSV files
+const csvTagSeparator = "|"
+
+// BlogTransferService defines the interface for exporting and importing blog posts
+type BlogTransferService interface {
+	Export(w io.Writer, format string) (int, error)
+	Import(r io.Reader, format string, dryRun bool) (*models.ImportReport, error)
+	ImportMarkdownFS(fsys fs.FS, dryRun bool) (*models.ImportReport, error)
+	WithTenant(tenantID string) BlogTransferService
+	WithRequester(requester *models.Requester) BlogTransferService
+}
+
+// blogTransferService implements BlogTransferService interface
+type blogTransferService struct {
+	blogRepo  repository.BlogRepository
+	requester *models.Requester
+}
+
+// NewBlogTransferService creates a new blog transfer service instance.
+// Imported posts record the same lifecycle events and audit entries as posts written through BlogService.
+func NewBlogTransferService(blogRepo repository.BlogRepository) BlogTransferService {
+	return &blogTransferService{blogRepo: blogRepo}
+}
+
+// WithTenant returns a service for the posts of another tenant
+func (s *blogTransferService) WithTenant(tenantID string) BlogTransferService {
+	return &blogTransferService{blogRepo: s.blogRepo.WithTenant(tenantID), requester: s.requester}
+}
+
+// WithRequester returns a service that attributes imported changes to requester in the audit log
+func (s *blogTransferService) WithRequester(requester *models.Requester) BlogTransferService {
+	return &blogTransferService{blogRepo: s.blogRepo.WithSession(requester.Session()), requester: requester}
+}
+
+// ValidateExportFormat checks that a format is supported by export
+func ValidateExportFormat(format string) error {
+	if format != models.TransferFormatJSONL && format != models.TransferFormatCSV {
+		return fmt.Errorf("format must be one of: %s %s", models.TransferFormatJSONL, models.TransferFormatCSV)
+	}
+	return nil
+}
+
+// ValidateImportFormat checks that a format is supported by import
+func ValidateImportFormat(format string) error {
+	switch format {
+	case models.TransferFormatJSONL, models.TransferFormatCSV, models.TransferFormatWXR, models.TransferFormatMarkdown:
+		return nil
+	default:
+		return fmt.Errorf("format must be one of: %s %s %s %s", models.TransferFormatJSONL, models.TransferFormatCSV,
+			models.TransferFormatWXR, models.TransferFormatMarkdown)
+	}
+}
+
+// Export writes every blog post to w, oldest first, paging through the repository.
+// It returns the number of exported posts.
+func (s *blogTransferService) Export(w io.Writer, format string) (int, error) {
+	if err := ValidateExportFormat(format); err != nil {
+		return 0, err
+	}
+
+	var csvWriter *csv.Writer
+	var jsonEncoder *json.Encoder
+	if format == models.TransferFormatCSV {
+		csvWriter = csv.NewWriter(w)
+		if err := csvWriter.Write(csvColumns); err != nil {
+			return 0, err
+		}
+	} else {
+		jsonEncoder = json.NewEncoder(w)
+	}
+
+	count := 0
+	var cursor *models.BlogCursor
+	for {
+		blogs, err := s.blogRepo.ListAfter(cursor, exportPageSize)
+		if err != nil {
+			return count, err
+		}
+
+		for i := range blogs {
+			record := blogToImportRecord(&blogs[i])
+			if csvWriter != nil {
+				err = csvWriter.Write(recordToCSV(record))
+			} else {
+				err = jsonEncoder.Encode(record)
+			}
+			if err != nil {
+				return count, err
+			}
+			count++
+		}
+
+		if csvWriter != nil {
+			csvWriter.Flush()
+			if err := csvWriter.Error(); err != nil {
+				return count, err
+			}
+		}
+
+		if len(blogs) < exportPageSize {
+			return count, nil
+		}
+		last := blogs[len(blogs)-1]
+		cursor = &models.BlogCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+// Import reads blog posts from r and upserts them by ID or slug.
+// Every record is validated; invalid records are reported and skipped.
+// With dryRun set the report describes what would happen without writing anything.
+// The markdown format expects a tar archive, optionally gzip compressed.
+func (s *blogTransferService) Import(r io.Reader, format string, dryRun bool) (*models.ImportReport, error) {
+	if err := ValidateImportFormat(format); err != nil {
+		return nil, err
+	}
+
+	var rows importRowReader
+	var err error
+	switch format {
+	case models.TransferFormatCSV:
+		rows, err = newCSVRowReader(r)
+	case models.TransferFormatWXR:
+		rows, err = newWXRRowReader(r)
+	case models.TransferFormatMarkdown:
+		rows, err = newMarkdownTarRowReader(r)
+	default:
+		rows = newJSONLRowReader(r)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.importRows(rows, format, dryRun)
+}
+
+// ImportMarkdownFS imports every Markdown file below the root of fsys, typically a content directory
+func (s *blogTransferService) ImportMarkdownFS(fsys fs.FS, dryRun bool) (*models.ImportReport, error) {
+	rows, err := newMarkdownFSRowReader(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return s.importRows(rows, models.TransferFormatMarkdown, dryRun)
+}
+
+// importRows imports every row of an import file and reports the outcome of each
+func (s *blogTransferService) importRows(rows importRowReader, format string, dryRun bool) (*models.ImportReport, error) {
+	report := &models.ImportReport{Format: format, DryRun: dryRun, Items: []models.ImportItemResult{}}
+	for {
+		row, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		item := models.ImportItemResult{Line: row.line, Source: row.source}
+		rowErr := row.err
+		switch {
+		case rowErr != nil:
+		case row.skip != "":
+			item.Action, item.Reason = models.ImportActionSkip, row.skip
+			if row.record != nil {
+				item.Slug = row.record.Slug
+			}
+		default:
+			item.Action, rowErr = s.importRecord(row.record, dryRun)
+			item.ID, item.Slug = row.record.ID, row.record.Slug
+		}
+
+		if rowErr != nil {
+			item.Action = models.ImportActionError
+			item.Error = rowErr.Error()
+		}
+		s.tallyImport(report, item)
+	}
+
+	return report, nil
+}
+
+// importRecord validates a record and creates or updates the matching blog post
+func (s *blogTransferService) importRecord(record *models.BlogImportRecord, dryRun bool) (string, error) {
+	if err := validateStruct(record); err != nil {
+		return "", err
+	}
+
+	postSlug := slug.Make(record.Slug)
+	if record.Slug != "" && postSlug == "" {
+		return "", errors.New("slug must contain letters or digits")
+	}
+
+	// Only an explicit slug identifies an existing post; titles are not unique
+	existing, err := s.findImportTarget(record.ID, postSlug)
+	if err != nil {
+		return "", err
+	}
+
+	if existing != nil {
+		wasPublished := existing.IsPublished()
+		before := newBlogResponse(existing)
+		existing.Title = record.Title
+		existing.Description = record.Description
+		existing.Body = record.Body
+		if postSlug != "" {
+			existing.Slug = postSlug
+		}
+		if record.Tags != nil {
+			existing.Tags = newBlogTags(existing.ID, normalizeTags(record.Tags))
+		}
+		if record.Status != "" {
+			if record.PublishedAt != nil {
+				existing.PublishedAt = record.PublishedAt
+			}
+			setStatus(existing, record.Status)
+		}
+		existing.UpdatedAt = time.Now()
+
+		record.ID, record.Slug = existing.ID, existing.Slug
+		if dryRun {
+			return models.ImportActionUpdate, nil
+		}
+		err := saveWithEvents(s.blogRepo, func(repo repository.BlogRepository) ([]*models.BlogEvent, error) {
+			if err := repo.Update(existing); err != nil {
+				return nil, err
+			}
+			after := newBlogResponse(existing)
+			if err := appendAudit(repo, s.requester, models.AuditActionPostUpdate, existing.ID, before, after); err != nil {
+				return nil, err
+			}
+			return lifecycleEvents(models.EventPostUpdated, wasPublished, after), nil
+		})
+		if err != nil {
+			return "", err
+		}
+		return models.ImportActionUpdate, nil
+	}
+
+	if postSlug == "" {
+		postSlug = slug.Make(record.Title)
+	}
+
+	blog := &models.Blog{
+		ID:          record.ID,
+		Slug:        postSlug,
+		Title:       record.Title,
+		Description: record.Description,
+		Body:        record.Body,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if blog.ID == "" {
+		blog.ID = uuid.New().String()
+	}
+	blog.Tags = newBlogTags(blog.ID, normalizeTags(record.Tags))
+	if record.CreatedAt != nil {
+		blog.CreatedAt = *record.CreatedAt
+	}
+	if record.UpdatedAt != nil {
+		blog.UpdatedAt = *record.UpdatedAt
+	}
+
+	// Posts imported as published keep their original publication time
+	status := record.Status
+	if status == "" {
+		status = models.BlogStatusPublished
+	}
+	blog.PublishedAt = record.PublishedAt
+	if blog.PublishedAt == nil && status == models.BlogStatusPublished && record.CreatedAt != nil {
+		publishedAt := *record.CreatedAt
+		blog.PublishedAt = &publishedAt
+	}
+	setStatus(blog, status)
+
+	if !dryRun {
+		err := saveWithEvents(s.blogRepo, func(repo repository.BlogRepository) ([]*models.BlogEvent, error) {
+			if err := repo.Create(blog); err != nil {
+				return nil, err
+			}
+			after := newBlogResponse(blog)
+			if err := appendAudit(repo, s.requester, models.AuditActionPostCreate, blog.ID, nil, after); err != nil {
+				return nil, err
+			}
+			return lifecycleEvents(models.EventPostCreated, false, after), nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	record.ID, record.Slug = blog.ID, blog.Slug
+	return models.ImportActionCreate, nil
+}
+
+// findImportTarget looks up the post an import record refers to, by ID first and then by slug.
+// A lagging replica could miss a recent post and have it imported twice, so it reads from the primary.
+func (s *blogTransferService) findImportTarget(id, slug string) (*models.Blog, error) {
+	primary := s.blogRepo.Primary()
+	if id != "" {
+		blog, err := primary.GetByID(id)
+		if err == nil {
+			return blog, nil
+		}
+		if !errors.Is(err, repository.ErrBlogNotFound) {
+			return nil, err
+		}
+	}
+
+	if slug != "" {
+		blog, err := primary.GetBySlug(slug)
+		if err == nil {
+			// A record with its own ID must not silently take over another post's slug
+			if id != "" && blog.ID != id {
+				return nil, repository.ErrSlugConflict
+			}
+			return blog, nil
+		}
+		if !errors.Is(err, repository.ErrBlogNotFound) {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// tallyImport adds an item to the report and updates its counters
+func (s *blogTransferService) tallyImport(report *models.ImportReport, item models.ImportItemResult) {
+	report.Total++
+	switch item.Action {
+	case models.ImportActionCreate:
+		report.Created++
+	case models.ImportActionUpdate:
+		report.Updated++
+	case models.ImportActionSkip:
+		report.Skipped++
+	default:
+		report.Failed++
+	}
+	report.Items = append(report.Items, item)
+}
+
+// truncateDescription shortens an imported excerpt to the maximum description length,
+// cutting at a word boundary where possible
+func truncateDescription(description string) string {
+	runes := []rune(description)
+	if len(runes) <= maxDescriptionLength {
+		return description
+	}
+
+	cut := string(runes[:maxDescriptionLength-1])
+	if space := strings.LastIndexAny(cut, " \n\t"); space > maxDescriptionLength/2 {
+		cut = cut[:space]
+	}
+	return strings.TrimSpace(cut) + "…"
+}
+
+// blogToImportRecord converts a Blog model to the record written by exports
+func blogToImportRecord(blog *models.Blog) *models.BlogImportRecord {
+	createdAt, updatedAt := blog.CreatedAt, blog.UpdatedAt
+	return &models.BlogImportRecord{
+		ID:          blog.ID,
+		Slug:        blog.Slug,
+		Title:       blog.Title,
+		Description: blog.Description,
+		Body:        blog.Body,
+		Tags:        blog.TagNames(),
+		Status:      blog.Status,
+		PublishedAt: blog.PublishedAt,
+		CreatedAt:   &createdAt,
+		UpdatedAt:   &updatedAt,
+	}
+}
+
+// formatOptionalTime formats a timestamp for CSV, leaving missing timestamps empty
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// recordToCSV converts a record to a CSV row in csvColumns order
+func recordToCSV(record *models.BlogImportRecord) []string {
+	return []string{
+		record.ID,
+		record.Slug,
+		record.Title,
+		record.Description,
+		record.Body,
+		strings.Join(record.Tags, csvTagSeparator),
+		record.Status,
+		formatOptionalTime(record.PublishedAt),
+		record.CreatedAt.UTC().Format(time.RFC3339Nano),
+		record.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// importRow is a single record read from an import file, or the reason it could not be read.
+// Rows with a skip reason are reported but not imported.
+type importRow struct {
+	line   int
+	source string
+	record *models.BlogImportRecord
+	skip   string
+	err    error
+}
+
+// importRowReader yields the rows of an import file.
+// Next returns io.EOF at the end of the file and other errors when reading cannot continue.
+type importRowReader interface {
+	Next() (*importRow, error)
+}
+
+// jsonlRowReader reads one JSON object per line
+type jsonlRowReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newJSONLRowReader(r io.Reader) *jsonlRowReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxImportLineSize)
+	return &jsonlRowReader{scanner: scanner}
+}
+
+// Next returns the next non-blank line of the file
+func (r *jsonlRowReader) Next() (*importRow, error) {
+	for r.scanner.Scan() {
+		r.line++
+		text := strings.TrimSpace(r.scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var record models.BlogImportRecord
+		if err := json.Unmarshal([]byte(text), &record); err != nil {
+			return &importRow{line: r.line, err: fmt.Errorf("invalid JSON: %w", err)}, nil
+		}
+		return &importRow{line: r.line, record: &record}, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// csvRowReader reads rows of a CSV file with a header line
+type csvRowReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVRowReader(r io.Reader) (*csvRowReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("CSV file is empty")
+		}
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"title", "body"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header is missing the %q column", required)
+		}
+	}
+
+	return &csvRowReader{reader: reader, columns: columns}, nil
+}
+
+// Next returns the next row of the file
+func (r *csvRowReader) Next() (*importRow, error) {
+	row, err := r.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return &importRow{line: parseErr.StartLine, err: fmt.Errorf("invalid CSV: %w", parseErr.Err)}, nil
+		}
+		return nil, err
+	}
+	line, _ := r.reader.FieldPos(0)
+
+	field := func(name string) string {
+		if i, ok := r.columns[name]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+
+	record := &models.BlogImportRecord{
+		ID:          strings.TrimSpace(field("id")),
+		Slug:        strings.TrimSpace(field("slug")),
+		Title:       field("title"),
+		Description: field("description"),
+		Body:        field("body"),
+		Status:      strings.TrimSpace(field("status")),
+	}
+	if _, ok := r.columns["tags"]; ok {
+		record.Tags = []string{}
+		if tags := strings.TrimSpace(field("tags")); tags != "" {
+			record.Tags = strings.Split(tags, csvTagSeparator)
+		}
+	}
+
+	for name, target := range map[string]**time.Time{
+		"published_at": &record.PublishedAt,
+		"created_at":   &record.CreatedAt,
+		"updated_at":   &record.UpdatedAt,
+	} {
+		value := strings.TrimSpace(field(name))
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return &importRow{line: line, err: fmt.Errorf("invalid %s: expected an RFC 3339 timestamp", name)}, nil
+		}
+		*target = &parsed
+	}
+
+	return &importRow{line: line, record: record}, nil
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBlogTransferService_Export_JSONL(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogTransferService(mockRepo)
+
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	blogs := []models.Blog{
+		{ID: "1", Slug: "first", Title: "First", Body: "Body 1", CreatedAt: createdAt, UpdatedAt: createdAt},
+		{ID: "2", Slug: "second", Title: "Second", Body: "Body 2", CreatedAt: createdAt, UpdatedAt: createdAt},
+	}
+	mockRepo.On("ListAfter", (*models.BlogCursor)(nil), exportPageSize).Return(blogs, nil)
+
+	var out bytes.Buffer
+	count, err := service.Export(&out, models.TransferFormatJSONL)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 2)
+
+	var record models.BlogImportRecord
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Equal(t, "2", record.ID)
+	assert.Equal(t, "second", record.Slug)
+	assert.True(t, createdAt.Equal(*record.CreatedAt))
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBlogTransferService_Export_PagesThroughRepository(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogTransferService(mockRepo)
+
+	createdAt := time.Now()
+	firstPage := make([]models.Blog, exportPageSize)
+	for i := range firstPage {
+		firstPage[i] = models.Blog{ID: string(rune('a' + i%26)), Title: "T", Body: "B", CreatedAt: createdAt}
+	}
+	last := firstPage[len(firstPage)-1]
+
+	mockRepo.On("ListAfter", (*models.BlogCursor)(nil), exportPageSize).Return(firstPage, nil)
+	mockRepo.On("ListAfter", &models.BlogCursor{CreatedAt: last.CreatedAt, ID: last.ID}, exportPageSize).
+		Return([]models.Blog{{ID: "z", Title: "T", Body: "B", CreatedAt: createdAt}}, nil)
+
+	var out bytes.Buffer
+	count, err := service.Export(&out, models.TransferFormatCSV)
+
+	assert.NoError(t, err)
+	assert.Equal(t, exportPageSize+1, count)
+	assert.True(t, strings.HasPrefix(out.String(), "id,slug,title,description,body,tags,status,published_at,created_at,updated_at\n"))
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBlogTransferService_Export_InvalidFormat(t *testing.T) {
+	service := NewBlogTransferService(newMockBlogRepository())
+
+	_, err := service.Export(&bytes.Buffer{}, "xml")
+
+	assert.EqualError(t, err, "format must be one of: jsonl csv")
+}
+
+func TestBlogTransferService_Import_JSONL(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogTransferService(mockRepo)
+
+	input := strings.Join([]string{
+		`{"id": "existing", "title": "Updated", "body": "Body"}`,
+		`{"slug": "Known Slug", "title": "By slug", "body": "Body"}`,
+		`{"title": "Brand New", "body": "Body", "created_at": "2020-05-01T10:00:00Z"}`,
+		``,
+		`{"title": "", "body": "Body"}`,
+		`not json`,
+	}, "\n")
+
+	mockRepo.On("GetByID", "existing").Return(&models.Blog{ID: "existing", Slug: "old", Title: "Old", Body: "Old"}, nil)
+	mockRepo.On("GetBySlug", "known-slug").Return(&models.Blog{ID: "by-slug", Slug: "known-slug", Title: "Old", Body: "Old"}, nil)
+	mockRepo.On("Update", mock.AnythingOfType("*models.Blog")).Return(nil).Twice()
+	mockRepo.On("Create", mock.MatchedBy(func(blog *models.Blog) bool {
+		return blog.Slug == "brand-new" && blog.CreatedAt.Equal(time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC))
+	})).Return(nil)
+
+	report, err := service.Import(strings.NewReader(input), models.TransferFormatJSONL, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, report.Total)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 2, report.Updated)
+	assert.Equal(t, 2, report.Failed)
+
+	assert.Equal(t, models.ImportActionUpdate, report.Items[0].Action)
+	assert.Equal(t, "existing", report.Items[0].ID)
+	assert.Equal(t, "by-slug", report.Items[1].ID)
+	assert.Equal(t, models.ImportActionCreate, report.Items[2].Action)
+	assert.NotEmpty(t, report.Items[2].ID)
+	assert.Equal(t, 5, report.Items[3].Line)
+	assert.Equal(t, "title is required", report.Items[3].Error)
+	assert.Equal(t, 6, report.Items[4].Line)
+	assert.Contains(t, report.Items[4].Error, "invalid JSON")
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBlogTransferService_Import_DryRunDoesNotWrite(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogTransferService(mockRepo)
+
+	input := "id,title,body\nexisting,Updated,Body\nnew-id,New,Body\n"
+
+	mockRepo.On("GetByID", "existing").Return(&models.Blog{ID: "existing", Title: "Old", Body: "Old"}, nil)
+	mockRepo.On("GetByID", "new-id").Return(nil, repository.ErrBlogNotFound)
+
+	report, err := service.Import(strings.NewReader(input), models.TransferFormatCSV, true)
+
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 2, report.Items[0].Line)
+	assert.Equal(t, "new-id", report.Items[1].ID)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestBlogTransferService_Import_SlugOwnedByAnotherPost(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogTransferService(mockRepo)
+
+	mockRepo.On("GetByID", "mine").Return(nil, repository.ErrBlogNotFound)
+	mockRepo.On("GetBySlug", "taken").Return(&models.Blog{ID: "theirs", Slug: "taken"}, nil)
+
+	report, err := service.Import(strings.NewReader(`{"id": "mine", "slug": "taken", "title": "T", "body": "B"}`), models.TransferFormatJSONL, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, "slug is already in use", report.Items[0].Error)
+}
+
+func TestBlogTransferService_Import_CSVMissingColumns(t *testing.T) {
+	service := NewBlogTransferService(newMockBlogRepository())
+
+	_, err := service.Import(strings.NewReader("id,title\n1,Title\n"), models.TransferFormatCSV, false)
+
+	assert.EqualError(t, err, `CSV header is missing the "body" column`)
+}
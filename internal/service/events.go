@@ -0,0 +1,46 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventPublisher receives blog post lifecycle events relayed from the outbox
+type EventPublisher interface {
+	Publish(event *models.BlogEvent) error
+}
+
+// newEvent creates an event describing a change to a post
+func newEvent(eventType string, data *models.BlogResponse) *models.BlogEvent {
+	return &models.BlogEvent{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+// lifecycleEvents returns the events for a saved post: the change itself, followed by
+// post.published when the change made a draft, or a new post, publicly visible
+func lifecycleEvents(eventType string, wasPublished bool, post *models.BlogResponse) []*models.BlogEvent {
+	events := []*models.BlogEvent{newEvent(eventType, post)}
+	if !wasPublished && post.Status == models.BlogStatusPublished {
+		events = append(events, newEvent(models.EventPostPublished, post))
+	}
+	return events
+}
+
+// saveWithEvents runs write in a transaction and records the events it returns in the
+// outbox within the same transaction, so that an event is stored if and only if the change is
+func saveWithEvents(repo repository.BlogRepository, write func(repo repository.BlogRepository) ([]*models.BlogEvent, error)) error {
+	return repo.Transaction(func(tx repository.BlogRepository) error {
+		events, err := write(tx)
+		if err != nil {
+			return err
+		}
+		return tx.AppendEvents(events)
+	})
+}
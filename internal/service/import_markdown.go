@@ -0,0 +1,330 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxMarkdownFileSize bounds a single Markdown file
+const maxMarkdownFileSize = maxImportLineSize
+
+// datedFilename matches Jekyll style file names such as 2023-01-01-my-post.md
+var datedFilename = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})-(.+)$`)
+
+// frontMatterTimeLayouts are the date formats accepted in front matter; dates without an offset are UTC
+var frontMatterTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 -07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// markdownFrontMatter holds the front matter keys understood by the importer.
+// Hugo, Jekyll and Eleventy spellings are accepted for descriptions and dates.
+type markdownFrontMatter struct {
+	Title       string          `yaml:"title"`
+	Slug        string          `yaml:"slug"`
+	Description string          `yaml:"description"`
+	Summary     string          `yaml:"summary"`
+	Excerpt     string          `yaml:"excerpt"`
+	Date        frontMatterTime `yaml:"date"`
+	LastMod     frontMatterTime `yaml:"lastmod"`
+	Updated     frontMatterTime `yaml:"updated"`
+	Tags        frontMatterList `yaml:"tags"`
+	Draft       bool            `yaml:"draft"`
+	Published   *bool           `yaml:"published"`
+}
+
+// frontMatterTime is a front matter date in any of frontMatterTimeLayouts
+type frontMatterTime struct {
+	time *time.Time
+}
+
+// UnmarshalYAML parses the raw scalar so that dates YAML itself does not recognise are accepted
+func (t *frontMatterTime) UnmarshalYAML(node *yaml.Node) error {
+	value := strings.TrimSpace(node.Value)
+	if value == "" {
+		return nil
+	}
+	parsed, err := parseFrontMatterTime(value)
+	if err != nil {
+		return err
+	}
+	t.time = &parsed
+	return nil
+}
+
+// frontMatterList is a list of strings written either as a YAML sequence or as a comma separated string
+type frontMatterList []string
+
+// UnmarshalYAML accepts both list spellings
+func (l *frontMatterList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.SequenceNode {
+		var values []string
+		if err := node.Decode(&values); err != nil {
+			return err
+		}
+		*l = values
+		return nil
+	}
+
+	var value string
+	if err := node.Decode(&value); err != nil {
+		return err
+	}
+	*l = strings.Split(value, ",")
+	return nil
+}
+
+// parseFrontMatterTime parses a date in the first matching layout
+func parseFrontMatterTime(value string) (time.Time, error) {
+	for _, layout := range frontMatterTimeLayouts {
+		if parsed, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return parsed.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised date %q", value)
+}
+
+// markdownToRow maps a Markdown file with YAML front matter onto an import record.
+// The slug and date fall back to the file name, and drafts are reported as skipped.
+func markdownToRow(name string, content []byte) *importRow {
+	row := &importRow{source: name}
+
+	frontMatter, body, err := splitFrontMatter(content)
+	if err != nil {
+		row.err = err
+		return row
+	}
+
+	var meta markdownFrontMatter
+	if err := yaml.Unmarshal(frontMatter, &meta); err != nil {
+		row.err = fmt.Errorf("invalid front matter: %w", err)
+		return row
+	}
+
+	fileSlug, fileDate := slugAndDateFromPath(name)
+	record := &models.BlogImportRecord{
+		Slug:  strings.TrimSpace(meta.Slug),
+		Title: strings.TrimSpace(meta.Title),
+		Body:  strings.TrimSpace(string(body)),
+		Tags:  []string(meta.Tags),
+	}
+	if record.Slug == "" {
+		record.Slug = fileSlug
+	}
+	if record.Tags == nil {
+		record.Tags = []string{}
+	}
+	for _, description := range []string{meta.Description, meta.Summary, meta.Excerpt} {
+		if description = strings.TrimSpace(description); description != "" {
+			record.Description = truncateDescription(description)
+			break
+		}
+	}
+
+	record.CreatedAt = meta.Date.time
+	if record.CreatedAt == nil {
+		record.CreatedAt = fileDate
+	}
+	record.UpdatedAt = meta.LastMod.time
+	if record.UpdatedAt == nil {
+		record.UpdatedAt = meta.Updated.time
+	}
+	if record.UpdatedAt == nil {
+		record.UpdatedAt = record.CreatedAt
+	}
+	row.record = record
+
+	if meta.Draft || (meta.Published != nil && !*meta.Published) {
+		row.skip = "drafts are not imported"
+	}
+	return row
+}
+
+// splitFrontMatter separates the YAML front matter delimited by "---" lines from the body
+func splitFrontMatter(content []byte) ([]byte, []byte, error) {
+	content = bytes.TrimPrefix(content, []byte("\ufeff"))
+	content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+
+	if bytes.HasPrefix(content, []byte("+++\n")) {
+		return nil, nil, errors.New("TOML front matter is not supported, use YAML")
+	}
+	if !bytes.HasPrefix(content, []byte("---\n")) {
+		return nil, nil, errors.New("missing YAML front matter")
+	}
+
+	rest := content[len("---\n"):]
+	for offset := 0; offset < len(rest); {
+		end := bytes.IndexByte(rest[offset:], '\n')
+		lineEnd := len(rest)
+		if end >= 0 {
+			lineEnd = offset + end
+		}
+		if line := string(bytes.TrimRight(rest[offset:lineEnd], " \t")); line == "---" || line == "..." {
+			body := rest[min(lineEnd+1, len(rest)):]
+			return rest[:offset], body, nil
+		}
+		offset = lineEnd + 1
+	}
+	return nil, nil, errors.New("front matter is not terminated by a --- line")
+}
+
+// slugAndDateFromPath derives a slug and, for Jekyll style names, a date from a file path.
+// Page bundles (a directory with an index.md) take the directory name.
+func slugAndDateFromPath(name string) (string, *time.Time) {
+	base := strings.TrimSuffix(path.Base(name), path.Ext(name))
+	if base == "index" || base == "_index" {
+		if dir := path.Base(path.Dir(name)); dir != "." && dir != "/" {
+			base = dir
+		}
+	}
+
+	if match := datedFilename.FindStringSubmatch(base); match != nil {
+		if date, err := time.ParseInLocation("2006-01-02", match[1], time.UTC); err == nil {
+			return match[2], &date
+		}
+	}
+	return base, nil
+}
+
+// isMarkdownFile reports whether a path names a Markdown file outside hidden directories
+func isMarkdownFile(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") && part != "." && part != ".." {
+			return false
+		}
+	}
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".md" || ext == ".markdown"
+}
+
+// markdownFSRowReader reads the Markdown files of a file system in lexical path order
+type markdownFSRowReader struct {
+	fsys  fs.FS
+	paths []string
+}
+
+// newMarkdownFSRowReader lists every Markdown file below the root of fsys
+func newMarkdownFSRowReader(fsys fs.FS) (*markdownFSRowReader, error) {
+	var paths []string
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if name != "." && strings.HasPrefix(entry.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if entry.Type().IsRegular() && isMarkdownFile(name) {
+			paths = append(paths, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Markdown files: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, errors.New("no Markdown files found")
+	}
+	return &markdownFSRowReader{fsys: fsys, paths: paths}, nil
+}
+
+// Next returns the next Markdown file
+func (r *markdownFSRowReader) Next() (*importRow, error) {
+	if len(r.paths) == 0 {
+		return nil, io.EOF
+	}
+	name := r.paths[0]
+	r.paths = r.paths[1:]
+
+	file, err := r.fsys.Open(name)
+	if err != nil {
+		return &importRow{source: name, err: err}, nil
+	}
+	defer file.Close()
+
+	content, err := readMarkdownFile(file)
+	if err != nil {
+		return &importRow{source: name, err: err}, nil
+	}
+	return markdownToRow(name, content), nil
+}
+
+// markdownTarRowReader reads the Markdown files of a tar archive, optionally gzip compressed
+type markdownTarRowReader struct {
+	reader *tar.Reader
+}
+
+// newMarkdownTarRowReader detects gzip compression and opens the archive
+func newMarkdownTarRowReader(r io.Reader) (*markdownTarRowReader, error) {
+	buffered := bufio.NewReader(r)
+	magic, err := buffered.Peek(2)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	var archive io.Reader = buffered
+	if bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip archive: %w", err)
+		}
+		archive = gz
+	}
+	return &markdownTarRowReader{reader: tar.NewReader(archive)}, nil
+}
+
+// Next returns the next Markdown file of the archive, skipping every other entry
+func (r *markdownTarRowReader) Next() (*importRow, error) {
+	for {
+		header, err := r.reader.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("invalid tar archive: %w", err)
+		}
+
+		name := strings.TrimPrefix(path.Clean("/"+header.Name), "/")
+		if header.Typeflag != tar.TypeReg || !isMarkdownFile(name) {
+			continue
+		}
+
+		content, err := readMarkdownFile(r.reader)
+		if err != nil {
+			return &importRow{source: name, err: err}, nil
+		}
+		return markdownToRow(name, content), nil
+	}
+}
+
+// readMarkdownFile reads a file of at most maxMarkdownFileSize bytes
+func readMarkdownFile(r io.Reader) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(r, maxMarkdownFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxMarkdownFileSize {
+		return nil, fmt.Errorf("file is larger than %d bytes", maxMarkdownFileSize)
+	}
+	return content, nil
+}
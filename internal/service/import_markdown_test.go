@@ -0,0 +1,122 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMarkdownToRow(t *testing.T) {
+	content := "---\r\ntitle: Hello World\r\nsummary: The summary\r\ndate: 2021-06-01 08:30:00 +0200\r\ntags: [go, Go, fiber]\r\n---\r\n\r\n# Heading\r\n\r\nBody text\r\n"
+
+	row := markdownToRow("posts/hello/index.md", []byte(content))
+
+	assert.NoError(t, row.err)
+	assert.Empty(t, row.skip)
+	assert.Equal(t, "hello", row.record.Slug)
+	assert.Equal(t, "Hello World", row.record.Title)
+	assert.Equal(t, "The summary", row.record.Description)
+	assert.Equal(t, "# Heading\n\nBody text", row.record.Body)
+	assert.Equal(t, []string{"go", "Go", "fiber"}, row.record.Tags)
+	assert.True(t, row.record.CreatedAt.Equal(time.Date(2021, 6, 1, 6, 30, 0, 0, time.UTC)))
+	assert.Equal(t, row.record.CreatedAt, row.record.UpdatedAt)
+}
+
+func TestMarkdownToRow_JekyllFileName(t *testing.T) {
+	row := markdownToRow("_posts/2020-02-03-my-post.markdown", []byte("---\ntitle: Mine\ntags: a, b\n---\nBody"))
+
+	assert.NoError(t, row.err)
+	assert.Equal(t, "my-post", row.record.Slug)
+	assert.Equal(t, []string{"a", " b"}, row.record.Tags)
+	assert.True(t, row.record.CreatedAt.Equal(time.Date(2020, 2, 3, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestMarkdownToRow_Errors(t *testing.T) {
+	assert.EqualError(t, markdownToRow("a.md", []byte("# No front matter")).err, "missing YAML front matter")
+	assert.EqualError(t, markdownToRow("a.md", []byte("+++\ntitle = 'x'\n+++\n")).err, "TOML front matter is not supported, use YAML")
+	assert.EqualError(t, markdownToRow("a.md", []byte("---\ntitle: x\n")).err, "front matter is not terminated by a --- line")
+	assert.Contains(t, markdownToRow("a.md", []byte("---\ndate: yesterday\n---\n")).err.Error(), `unrecognised date "yesterday"`)
+	assert.Equal(t, "drafts are not imported", markdownToRow("a.md", []byte("---\ntitle: x\ndraft: true\n---\nBody")).skip)
+}
+
+func TestBlogTransferService_ImportMarkdownFS(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogTransferService(mockRepo)
+
+	fsys := fstest.MapFS{
+		"first.md":             {Data: []byte("---\ntitle: First\n---\nBody")},
+		"nested/second.md":     {Data: []byte("---\ntitle: Second\nslug: custom\n---\nBody")},
+		"nested/draft.md":      {Data: []byte("---\ntitle: Draft\ndraft: true\n---\nBody")},
+		"broken.md":            {Data: []byte("---\ntitle: Broken\n---\n")},
+		"README.txt":           {Data: []byte("not markdown")},
+		".git/ignored/hook.md": {Data: []byte("---\ntitle: Hidden\n---\nBody")},
+	}
+
+	mockRepo.On("GetBySlug", mock.Anything).Return(nil, repository.ErrBlogNotFound)
+	mockRepo.On("Create", mock.AnythingOfType("*models.Blog")).Return(nil).Twice()
+
+	report, err := service.ImportMarkdownFS(fsys, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.TransferFormatMarkdown, report.Format)
+	assert.Equal(t, 4, report.Total)
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, 1, report.Failed)
+
+	assert.Equal(t, "broken.md", report.Items[0].Source)
+	assert.Equal(t, "body is required", report.Items[0].Error)
+	assert.Equal(t, "nested/draft.md", report.Items[2].Source)
+	assert.Equal(t, "custom", report.Items[3].Slug)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBlogTransferService_Import_MarkdownTarball(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogTransferService(mockRepo)
+
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	for name, content := range map[string]string{
+		"content/post.md": "---\ntitle: From tar\n---\nBody",
+		"content/img.png": "binary",
+	} {
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gz.Close())
+
+	mockRepo.On("GetBySlug", "post").Return(nil, repository.ErrBlogNotFound)
+
+	report, err := service.Import(&archive, models.TransferFormatMarkdown, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Total)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, "content/post.md", report.Items[0].Source)
+	assert.Equal(t, "post", report.Items[0].Slug)
+
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestTruncateDescription(t *testing.T) {
+	assert.Equal(t, "short", truncateDescription("short"))
+
+	long := strings.Repeat("word ", 300)
+	truncated := truncateDescription(long)
+	assert.LessOrEqual(t, len([]rune(truncated)), maxDescriptionLength)
+	assert.True(t, strings.HasSuffix(truncated, "word…"))
+}
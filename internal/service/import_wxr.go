@@ -0,0 +1,181 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// wxrDateLayout is the layout of the wp:post_date family of elements
+const wxrDateLayout = "2006-01-02 15:04:05"
+
+// wxrItem is an <item> of a WordPress WXR export. Elements are matched by local
+// name so that every WXR version (1.0 to 1.2) is accepted.
+type wxrItem struct {
+	Title       string        `xml:"title"`
+	PubDate     string        `xml:"pubDate"`
+	Encoded     []wxrEncoded  `xml:"encoded"`
+	PostID      string        `xml:"post_id"`
+	PostDate    string        `xml:"post_date"`
+	PostDateGMT string        `xml:"post_date_gmt"`
+	Modified    string        `xml:"post_modified"`
+	ModifiedGMT string        `xml:"post_modified_gmt"`
+	PostName    string        `xml:"post_name"`
+	Status      string        `xml:"status"`
+	PostType    string        `xml:"post_type"`
+	Categories  []wxrCategory `xml:"category"`
+}
+
+// wxrEncoded is a content:encoded or excerpt:encoded element, told apart by namespace
+type wxrEncoded struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+// wxrCategory is a category or tag assigned to an item
+type wxrCategory struct {
+	Domain string `xml:"domain,attr"`
+	Name   string `xml:",chardata"`
+}
+
+// wxrRowReader reads the items of a WordPress WXR export one at a time
+type wxrRowReader struct {
+	decoder *xml.Decoder
+}
+
+// newWXRRowReader checks that r holds an RSS document and prepares to read its items
+func newWXRRowReader(r io.Reader) (*wxrRowReader, error) {
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("WXR file is empty")
+			}
+			return nil, fmt.Errorf("invalid WXR file: %w", err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			if start.Name.Local != "rss" {
+				return nil, fmt.Errorf("not a WordPress WXR export: root element is <%s>", start.Name.Local)
+			}
+			return &wxrRowReader{decoder: decoder}, nil
+		}
+	}
+}
+
+// Next returns the next item of the export
+func (r *wxrRowReader) Next() (*importRow, error) {
+	for {
+		token, err := r.decoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("invalid WXR file: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "item" {
+			continue
+		}
+
+		line, _ := r.decoder.InputPos()
+		var item wxrItem
+		if err := r.decoder.DecodeElement(&item, &start); err != nil {
+			return nil, fmt.Errorf("invalid WXR item at line %d: %w", line, err)
+		}
+		return item.toRow(line), nil
+	}
+}
+
+// toRow maps a WXR item onto an import record. Only published posts are imported;
+// pages, attachments, menu items and unpublished posts are reported as skipped.
+func (item *wxrItem) toRow(line int) *importRow {
+	row := &importRow{line: line, source: "post " + strings.TrimSpace(item.PostID)}
+
+	slug := strings.TrimSpace(item.PostName)
+	// WordPress stores non-ASCII slugs percent-encoded
+	if unescaped, err := url.PathUnescape(slug); err == nil {
+		slug = unescaped
+	}
+
+	record := &models.BlogImportRecord{
+		Slug:  slug,
+		Title: strings.TrimSpace(item.Title),
+		Tags:  []string{},
+	}
+	row.record = record
+
+	if postType := strings.TrimSpace(item.PostType); postType != "" && postType != "post" {
+		row.skip = fmt.Sprintf("post type %q is not imported", postType)
+		return row
+	}
+	if status := strings.TrimSpace(item.Status); status != "" && status != "publish" {
+		row.skip = fmt.Sprintf("status %q is not imported", status)
+		return row
+	}
+
+	for _, encoded := range item.Encoded {
+		if strings.Contains(encoded.XMLName.Space, "/excerpt/") {
+			record.Description = truncateDescription(strings.TrimSpace(encoded.Value))
+		} else {
+			record.Body = encoded.Value
+		}
+	}
+
+	for _, category := range item.Categories {
+		if category.Domain == "post_tag" {
+			record.Tags = append(record.Tags, category.Name)
+		}
+	}
+
+	createdAt, err := wxrTime(item.PostDateGMT, item.PostDate, item.PubDate)
+	if err != nil {
+		row.err = fmt.Errorf("invalid post date: %w", err)
+		return row
+	}
+	updatedAt, err := wxrTime(item.ModifiedGMT, item.Modified, "")
+	if err != nil {
+		row.err = fmt.Errorf("invalid modified date: %w", err)
+		return row
+	}
+	if updatedAt == nil {
+		updatedAt = createdAt
+	}
+	record.CreatedAt, record.UpdatedAt = createdAt, updatedAt
+
+	return row
+}
+
+// wxrTime parses the first usable date of an item: the GMT date, then the site's local
+// date (read as UTC since WXR does not record the offset), then the RFC 1123 pubDate.
+// WordPress writes "0000-00-00 00:00:00" for dates that are not set.
+func wxrTime(gmt, local, pubDate string) (*time.Time, error) {
+	for _, value := range []string{gmt, local} {
+		value = strings.TrimSpace(value)
+		if value == "" || strings.HasPrefix(value, "0000-00-00") {
+			continue
+		}
+		parsed, err := time.ParseInLocation(wxrDateLayout, value, time.UTC)
+		if err != nil {
+			return nil, err
+		}
+		return &parsed, nil
+	}
+
+	if pubDate = strings.TrimSpace(pubDate); pubDate != "" {
+		parsed, err := time.Parse(time.RFC1123Z, pubDate)
+		if err != nil {
+			return nil, err
+		}
+		parsed = parsed.UTC()
+		return &parsed, nil
+	}
+
+	return nil, nil
+}
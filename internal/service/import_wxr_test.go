@@ -0,0 +1,106 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testWXR = `<?xml version="1.0" encoding="UTF-8" ?>
+<rss version="2.0"
+	xmlns:excerpt="http://wordpress.org/export/1.2/excerpt/"
+	xmlns:content="http://purl.org/rss/1.0/modules/content/"
+	xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+	<title>My Blog</title>
+	<wp:wxr_version>1.2</wp:wxr_version>
+	<item>
+		<title>Hello &amp; Welcome</title>
+		<pubDate>Thu, 14 Mar 2019 12:00:00 +0000</pubDate>
+		<content:encoded><![CDATA[<p>First post</p>]]></content:encoded>
+		<excerpt:encoded><![CDATA[A short excerpt]]></excerpt:encoded>
+		<wp:post_id>42</wp:post_id>
+		<wp:post_date><![CDATA[2019-03-14 13:00:00]]></wp:post_date>
+		<wp:post_date_gmt><![CDATA[2019-03-14 12:00:00]]></wp:post_date_gmt>
+		<wp:post_modified_gmt><![CDATA[2020-01-02 03:04:05]]></wp:post_modified_gmt>
+		<wp:post_name><![CDATA[caf%c3%a9-hello]]></wp:post_name>
+		<wp:status><![CDATA[publish]]></wp:status>
+		<wp:post_type><![CDATA[post]]></wp:post_type>
+		<category domain="category" nicename="news"><![CDATA[News]]></category>
+		<category domain="post_tag" nicename="go"><![CDATA[Go]]></category>
+		<category domain="post_tag" nicename="fiber"><![CDATA[Fiber]]></category>
+	</item>
+	<item>
+		<title>Unfinished</title>
+		<content:encoded><![CDATA[Draft body]]></content:encoded>
+		<wp:post_id>43</wp:post_id>
+		<wp:post_date_gmt><![CDATA[0000-00-00 00:00:00]]></wp:post_date_gmt>
+		<wp:status><![CDATA[draft]]></wp:status>
+		<wp:post_type><![CDATA[post]]></wp:post_type>
+	</item>
+	<item>
+		<title>logo.png</title>
+		<wp:post_id>44</wp:post_id>
+		<wp:status><![CDATA[inherit]]></wp:status>
+		<wp:post_type><![CDATA[attachment]]></wp:post_type>
+	</item>
+</channel>
+</rss>`
+
+func TestBlogTransferService_Import_WXR(t *testing.T) {
+	mockRepo := newMockBlogRepository()
+	service := NewBlogTransferService(mockRepo)
+
+	mockRepo.On("GetBySlug", "cafe-hello").Return(nil, repository.ErrBlogNotFound)
+	mockRepo.On("Create", mock.MatchedBy(func(blog *models.Blog) bool {
+		return blog.Title == "Hello & Welcome" &&
+			blog.Description == "A short excerpt" &&
+			blog.Body == "<p>First post</p>" &&
+			assert.ObjectsAreEqual([]string{"Go", "Fiber"}, blog.TagNames()) &&
+			blog.CreatedAt.Equal(time.Date(2019, 3, 14, 12, 0, 0, 0, time.UTC)) &&
+			blog.UpdatedAt.Equal(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+	})).Return(nil)
+
+	report, err := service.Import(strings.NewReader(testWXR), models.TransferFormatWXR, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 2, report.Skipped)
+	assert.Equal(t, 0, report.Failed)
+
+	assert.Equal(t, "post 42", report.Items[0].Source)
+	assert.Equal(t, "cafe-hello", report.Items[0].Slug)
+	assert.Equal(t, models.ImportActionSkip, report.Items[1].Action)
+	assert.Equal(t, `status "draft" is not imported`, report.Items[1].Reason)
+	assert.Equal(t, `post type "attachment" is not imported`, report.Items[2].Reason)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBlogTransferService_Import_WXRRejectsOtherXML(t *testing.T) {
+	service := NewBlogTransferService(newMockBlogRepository())
+
+	_, err := service.Import(strings.NewReader(`<feed></feed>`), models.TransferFormatWXR, false)
+
+	assert.EqualError(t, err, "not a WordPress WXR export: root element is <feed>")
+}
+
+func TestWXRTime(t *testing.T) {
+	parsed, err := wxrTime("0000-00-00 00:00:00", "2019-03-14 13:00:00", "")
+	assert.NoError(t, err)
+	assert.True(t, parsed.Equal(time.Date(2019, 3, 14, 13, 0, 0, 0, time.UTC)))
+
+	parsed, err = wxrTime("", "", "Thu, 14 Mar 2019 12:00:00 +0100")
+	assert.NoError(t, err)
+	assert.True(t, parsed.Equal(time.Date(2019, 3, 14, 11, 0, 0, 0, time.UTC)))
+
+	parsed, err = wxrTime("", "", "")
+	assert.NoError(t, err)
+	assert.Nil(t, parsed)
+}
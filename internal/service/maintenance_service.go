@@ -0,0 +1,94 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"BlogManagment/internal/slug"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxSlugSuffix bounds the numeric suffixes tried when the slug of a title is taken
+const maxSlugSuffix = 1000
+
+// MaintenanceService defines the interface for the maintenance tasks of operators
+type MaintenanceService interface {
+	Reindex(requester *models.Requester) (*models.ReindexReport, error)
+	PurgeTrash(tenantID string, olderThan time.Duration) (*models.PurgeReport, error)
+}
+
+// maintenanceService implements MaintenanceService interface
+type maintenanceService struct {
+	maintenanceRepo repository.MaintenanceRepository
+	blogService     BlogService
+	now             func() time.Time
+}
+
+// NewMaintenanceService creates a new maintenance service instance. Posts are changed through
+// blogService, so that the changes are audited, published as events and dropped from caches.
+func NewMaintenanceService(maintenanceRepo repository.MaintenanceRepository, blogService BlogService) MaintenanceService {
+	return &maintenanceService{maintenanceRepo: maintenanceRepo, blogService: blogService, now: time.Now}
+}
+
+// Reindex gives every post without a slug the slug of its title, attributed to requester, and
+// rebuilds the indexes of the post tables
+func (s *maintenanceService) Reindex(requester *models.Requester) (*models.ReindexReport, error) {
+	blogs, err := s.maintenanceRepo.PostsWithoutSlug()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ReindexReport{}
+	for _, blog := range blogs {
+		assigned, err := s.assignSlug(s.blogService.WithTenant(blog.TenantID).WithRequester(requester), &blog)
+		if err != nil {
+			return nil, fmt.Errorf("failed to assign a slug to post %s: %w", blog.ID, err)
+		}
+		if assigned {
+			report.SlugsAssigned++
+		} else {
+			report.SkippedPosts = append(report.SkippedPosts, blog.ID)
+		}
+	}
+
+	if report.Tables, err = s.maintenanceRepo.RebuildIndexes(); err != nil {
+		return nil, fmt.Errorf("failed to rebuild indexes: %w", err)
+	}
+	return report, nil
+}
+
+// assignSlug gives a post the slug of its title, with a numeric suffix if the slug is taken.
+// It reports false if the title yields no slug.
+func (s *maintenanceService) assignSlug(blogService BlogService, blog *models.Blog) (bool, error) {
+	base := slug.Make(blog.Title)
+	if base == "" {
+		return false, nil
+	}
+
+	postSlug := base
+	for n := 2; n <= maxSlugSuffix; n++ {
+		_, err := blogService.UpdateBlog(blog.ID, &models.BlogUpdateRequest{Slug: &postSlug})
+		if !errors.Is(err, repository.ErrSlugConflict) {
+			return err == nil, err
+		}
+		postSlug = fmt.Sprintf("%s-%d", base, n)
+	}
+	return false, nil
+}
+
+// PurgeTrash permanently removes the posts deleted more than olderThan ago from one tenant, or
+// from every tenant if tenantID is empty
+func (s *maintenanceService) PurgeTrash(tenantID string, olderThan time.Duration) (*models.PurgeReport, error) {
+	if olderThan < 0 {
+		return nil, errors.New("the age of purged posts must not be negative")
+	}
+
+	report := &models.PurgeReport{DeletedBefore: s.now().Add(-olderThan)}
+	purged, err := s.maintenanceRepo.PurgeDeleted(tenantID, report.DeletedBefore)
+	if err != nil {
+		return nil, err
+	}
+	report.Purged = purged
+	return report, nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockMaintenanceRepository is a mock implementation of MaintenanceRepository
+type MockMaintenanceRepository struct {
+	mock.Mock
+}
+
+func (m *MockMaintenanceRepository) PostsWithoutSlug() ([]models.Blog, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Blog), args.Error(1)
+}
+
+func (m *MockMaintenanceRepository) RebuildIndexes() ([]string, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockMaintenanceRepository) PurgeDeleted(tenantID string, before time.Time) (int64, error) {
+	args := m.Called(tenantID, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestMaintenanceService_Reindex(t *testing.T) {
+	blogRepo := repository.NewMemoryBlogRepository()
+	blogService := NewBlogService(blogRepo)
+
+	// createPost stores a post of a tenant with the given slug, as posts created before slugs were
+	createPost := func(tenantID, title, postSlug string) models.Blog {
+		blog := models.Blog{ID: uuid.New().String(), Title: title, Slug: postSlug, Body: "Body", Status: models.BlogStatusPublished}
+		require.NoError(t, blogRepo.WithTenant(tenantID).Create(&blog))
+		return blog
+	}
+	createPost("acme", "Hello World", "hello-world")
+	taken := createPost("acme", "Hello World", "")
+	free := createPost("globex", "Hello World", "")
+	untitled := createPost("acme", "!!!", "")
+
+	mockRepo := &MockMaintenanceRepository{}
+	mockRepo.On("PostsWithoutSlug").Return([]models.Blog{taken, free, untitled}, nil)
+	mockRepo.On("RebuildIndexes").Return([]string{"blogs", "blog_tags"}, nil)
+
+	report, err := NewMaintenanceService(mockRepo, blogService).Reindex(&models.Requester{Kind: models.RequesterKindCLI, Subject: "ops"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.SlugsAssigned)
+	assert.Equal(t, []string{untitled.ID}, report.SkippedPosts)
+	assert.Equal(t, []string{"blogs", "blog_tags"}, report.Tables)
+
+	post, err := blogService.WithTenant("acme").GetBlogByID(taken.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "hello-world-2", post.Slug, "the slug of the title is taken in acme")
+	post, err = blogService.WithTenant("globex").GetBlogByID(free.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "hello-world", post.Slug)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMaintenanceService_PurgeTrash(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	mockRepo := &MockMaintenanceRepository{}
+	maintenanceService := NewMaintenanceService(mockRepo, nil).(*maintenanceService)
+	maintenanceService.now = func() time.Time { return now }
+
+	mockRepo.On("PurgeDeleted", "acme", now.Add(-30*24*time.Hour)).Return(int64(3), nil).Once()
+	report, err := maintenanceService.PurgeTrash("acme", 30*24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, &models.PurgeReport{Purged: 3, DeletedBefore: now.Add(-30 * 24 * time.Hour)}, report)
+
+	mockRepo.On("PurgeDeleted", "", now).Return(int64(0), errors.New("database is locked")).Once()
+	_, err = maintenanceService.PurgeTrash("", 0)
+	assert.EqualError(t, err, "database is locked")
+
+	_, err = maintenanceService.PurgeTrash("", -time.Hour)
+	assert.Error(t, err)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"errors"
+	"strings"
+)
+
+// defaultDenialListLimit and maxDenialListLimit bound listings of denied requests
+const (
+	defaultDenialListLimit = 50
+	maxDenialListLimit     = 500
+)
+
+// RoleService defines the interface for managing the roles of a tenant's users
+type RoleService interface {
+	AssignRole(tenantID string, request *models.RoleAssignmentRequest) (*models.RoleAssignment, error)
+	RevokeRole(tenantID, subject, role string) error
+	ListRoleAssignments(tenantID, subject string) ([]models.RoleAssignment, error)
+	ListAccessDenials(tenantID, subject string, limit int) ([]models.AccessDenial, error)
+}
+
+// roleService implements RoleService interface
+type roleService struct {
+	roleRepo repository.RoleRepository
+}
+
+// NewRoleService creates a new role service instance
+func NewRoleService(roleRepo repository.RoleRepository) RoleService {
+	return &roleService{roleRepo: roleRepo}
+}
+
+// AssignRole grants a role to a subject in a tenant
+func (s *roleService) AssignRole(tenantID string, request *models.RoleAssignmentRequest) (*models.RoleAssignment, error) {
+	if request == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	request.Subject = strings.TrimSpace(request.Subject)
+	if err := validateStruct(request); err != nil {
+		return nil, err
+	}
+
+	assignment := &models.RoleAssignment{TenantID: tenantID, Subject: request.Subject, Role: request.Role}
+	if err := s.roleRepo.Assign(assignment); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// RevokeRole removes a role from a subject in a tenant
+func (s *roleService) RevokeRole(tenantID, subject, role string) error {
+	if subject == "" || role == "" {
+		return errors.New("subject and role are required")
+	}
+	return s.roleRepo.Revoke(tenantID, subject, role)
+}
+
+// ListRoleAssignments returns the role assignments of a tenant, optionally only those of one subject
+func (s *roleService) ListRoleAssignments(tenantID, subject string) ([]models.RoleAssignment, error) {
+	return s.roleRepo.List(tenantID, subject)
+}
+
+// ListAccessDenials returns the denied requests of a tenant, newest first
+func (s *roleService) ListAccessDenials(tenantID, subject string, limit int) ([]models.AccessDenial, error) {
+	if limit <= 0 {
+		limit = defaultDenialListLimit
+	}
+	if limit > maxDenialListLimit {
+		limit = maxDenialListLimit
+	}
+	return s.roleRepo.ListDenials(tenantID, subject, limit)
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRoleRepository is a mock implementation of RoleRepository
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) Assign(assignment *models.RoleAssignment) error {
+	args := m.Called(assignment)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) Revoke(tenantID, subject, role string) error {
+	args := m.Called(tenantID, subject, role)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) GetRoles(tenantID, subject string) ([]string, error) {
+	args := m.Called(tenantID, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockRoleRepository) List(tenantID, subject string) ([]models.RoleAssignment, error) {
+	args := m.Called(tenantID, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RoleAssignment), args.Error(1)
+}
+
+func (m *MockRoleRepository) RecordDenial(denial *models.AccessDenial) error {
+	args := m.Called(denial)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) ListDenials(tenantID, subject string, limit int) ([]models.AccessDenial, error) {
+	args := m.Called(tenantID, subject, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AccessDenial), args.Error(1)
+}
+
+func TestRoleService_AssignRole(t *testing.T) {
+	mockRepo := &MockRoleRepository{}
+	roleService := NewRoleService(mockRepo)
+
+	mockRepo.On("Assign", mock.MatchedBy(func(assignment *models.RoleAssignment) bool {
+		return assignment.TenantID == "acme" && assignment.Subject == "alice" && assignment.Role == "editor"
+	})).Return(nil)
+
+	assignment, err := roleService.AssignRole("acme", &models.RoleAssignmentRequest{Subject: " alice ", Role: "editor"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", assignment.Subject)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRoleService_AssignRole_UnknownRole(t *testing.T) {
+	mockRepo := &MockRoleRepository{}
+	roleService := NewRoleService(mockRepo)
+
+	_, err := roleService.AssignRole("acme", &models.RoleAssignmentRequest{Subject: "alice", Role: "owner"})
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "Assign", mock.Anything)
+}
+
+func TestRoleService_ListAccessDenials_BoundsLimit(t *testing.T) {
+	mockRepo := &MockRoleRepository{}
+	roleService := NewRoleService(mockRepo)
+
+	mockRepo.On("ListDenials", "acme", "", defaultDenialListLimit).Return([]models.AccessDenial{}, nil)
+	mockRepo.On("ListDenials", "acme", "alice", maxDenialListLimit).Return([]models.AccessDenial{}, nil)
+
+	_, err := roleService.ListAccessDenials("acme", "", 0)
+	assert.NoError(t, err)
+	_, err = roleService.ListAccessDenials("acme", "alice", 10000)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
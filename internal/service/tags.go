@@ -0,0 +1,32 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"strings"
+)
+
+// normalizeTags trims tag names, collapses inner whitespace and drops empty and
+// duplicate tags. Duplicates are detected case-insensitively; the first spelling wins.
+func normalizeTags(names []string) []string {
+	tags := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.Join(strings.Fields(name), " ")
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		tags = append(tags, name)
+	}
+	return tags
+}
+
+// newBlogTags builds the tag rows of a blog post from normalized tag names
+func newBlogTags(blogID string, names []string) []models.BlogTag {
+	tags := make([]models.BlogTag, len(names))
+	for i, name := range names {
+		tags[i] = models.BlogTag{BlogID: blogID, Name: name}
+	}
+	return tags
+}
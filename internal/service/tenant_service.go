@@ -0,0 +1,54 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"BlogManagment/internal/tenant"
+	"errors"
+	"strings"
+)
+
+// TenantService defines the interface for tenant business logic
+type TenantService interface {
+	CreateTenant(request *models.TenantCreateRequest) (*models.Tenant, error)
+	GetAllTenants() ([]models.Tenant, error)
+}
+
+// tenantService implements TenantService interface
+type tenantService struct {
+	tenantRepo repository.TenantRepository
+}
+
+// NewTenantService creates a new tenant service instance
+func NewTenantService(tenantRepo repository.TenantRepository) TenantService {
+	return &tenantService{tenantRepo: tenantRepo}
+}
+
+// CreateTenant creates a new tenant workspace
+func (s *tenantService) CreateTenant(request *models.TenantCreateRequest) (*models.Tenant, error) {
+	if request == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	request.ID = strings.ToLower(strings.TrimSpace(request.ID))
+	request.Name = strings.TrimSpace(request.Name)
+	if err := validateStruct(request); err != nil {
+		return nil, err
+	}
+	if !tenant.ValidID(request.ID) {
+		return nil, errors.New("id must consist of lowercase letters, digits and hyphens and cannot start or end with a hyphen")
+	}
+
+	created := &models.Tenant{ID: request.ID, Name: request.Name}
+	if err := s.tenantRepo.Create(created); err != nil {
+		if errors.Is(err, repository.ErrTenantExists) {
+			return nil, errors.New("tenant already exists")
+		}
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetAllTenants retrieves every tenant
+func (s *tenantService) GetAllTenants() ([]models.Tenant, error) {
+	return s.tenantRepo.GetAll()
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTenantRepository is a mock implementation of TenantRepository
+type MockTenantRepository struct {
+	mock.Mock
+}
+
+func (m *MockTenantRepository) Create(tenant *models.Tenant) error {
+	args := m.Called(tenant)
+	return args.Error(0)
+}
+
+func (m *MockTenantRepository) GetByID(id string) (*models.Tenant, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Tenant), args.Error(1)
+}
+
+func (m *MockTenantRepository) GetAll() ([]models.Tenant, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Tenant), args.Error(1)
+}
+
+func TestTenantService_CreateTenant(t *testing.T) {
+	mockRepo := &MockTenantRepository{}
+	tenantService := NewTenantService(mockRepo)
+
+	mockRepo.On("Create", &models.Tenant{ID: "acme", Name: "Acme"}).Return(nil)
+
+	tenant, err := tenantService.CreateTenant(&models.TenantCreateRequest{ID: " Acme ", Name: "Acme"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", tenant.ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTenantService_CreateTenant_InvalidID(t *testing.T) {
+	mockRepo := &MockTenantRepository{}
+	tenantService := NewTenantService(mockRepo)
+
+	for _, id := range []string{"", "-acme", "acme.example", "acme_blog"} {
+		_, err := tenantService.CreateTenant(&models.TenantCreateRequest{ID: id, Name: "Acme"})
+		assert.Error(t, err, id)
+	}
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestTenantService_CreateTenant_Exists(t *testing.T) {
+	mockRepo := &MockTenantRepository{}
+	tenantService := NewTenantService(mockRepo)
+
+	mockRepo.On("Create", mock.Anything).Return(repository.ErrTenantExists)
+
+	_, err := tenantService.CreateTenant(&models.TenantCreateRequest{ID: "acme", Name: "Acme"})
+
+	assert.EqualError(t, err, "tenant already exists")
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// validate checks the `validate` struct tags of request models
+var validate = newValidator()
+
+// newValidator creates a validator that reports fields by their JSON names
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	return v
+}
+
+// validateStruct validates a request model against its `validate` tags
+func validateStruct(request interface{}) error {
+	err := validate.Struct(request)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	messages := make([]string, len(validationErrors))
+	for i, fieldError := range validationErrors {
+		messages[i] = validationMessage(fieldError)
+	}
+	return errors.New(strings.Join(messages, "; "))
+}
+
+// validationMessage describes a single failed validation rule
+func validationMessage(fieldError validator.FieldError) string {
+	field := fieldError.Field()
+	switch fieldError.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", field)
+	case "min":
+		if fieldError.Kind() == reflect.Slice {
+			return fmt.Sprintf("%s must contain at least %s items", field, fieldError.Param())
+		}
+		return fmt.Sprintf("%s must be at least %s characters", field, fieldError.Param())
+	case "max":
+		if fieldError.Kind() == reflect.Slice {
+			return fmt.Sprintf("%s must contain at most %s items", field, fieldError.Param())
+		}
+		return fmt.Sprintf("%s must be at most %s characters", field, fieldError.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, fieldError.Param())
+	case "email":
+		return fmt.Sprintf("%s must be an email address", field)
+	default:
+		return fmt.Sprintf("%s is invalid", field)
+	}
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// defaultDeliveryListLimit and maxDeliveryListLimit bound delivery log listings
+const (
+	defaultDeliveryListLimit = 50
+	maxDeliveryListLimit     = 500
+)
+
+// WebhookService defines the interface for managing webhooks and queueing their deliveries
+type WebhookService interface {
+	EventPublisher
+	WithTenant(tenantID string) WebhookService
+	CreateWebhook(request *models.WebhookCreateRequest) (*models.WebhookResponse, error)
+	GetWebhook(id string) (*models.WebhookResponse, error)
+	GetAllWebhooks() ([]models.WebhookResponse, error)
+	UpdateWebhook(id string, request *models.WebhookUpdateRequest) (*models.WebhookResponse, error)
+	DeleteWebhook(id string) error
+	ListDeliveries(webhookID, status string, limit int) ([]models.WebhookDelivery, error)
+	RetryDelivery(id string) (*models.WebhookDelivery, error)
+}
+
+// webhookService implements WebhookService interface
+type webhookService struct {
+	webhookRepo repository.WebhookRepository
+}
+
+// NewWebhookService creates a new webhook service instance
+func NewWebhookService(webhookRepo repository.WebhookRepository) WebhookService {
+	return &webhookService{webhookRepo: webhookRepo}
+}
+
+// WithTenant returns a service for the webhooks of another tenant
+func (s *webhookService) WithTenant(tenantID string) WebhookService {
+	return &webhookService{webhookRepo: s.webhookRepo.WithTenant(tenantID)}
+}
+
+// CreateWebhook registers a webhook. A signing secret is generated unless one is given;
+// the response is the only place the secret is returned.
+func (s *webhookService) CreateWebhook(request *models.WebhookCreateRequest) (*models.WebhookResponse, error) {
+	if request == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	if err := validateStruct(request); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookURL(request.URL); err != nil {
+		return nil, err
+	}
+
+	secret := request.Secret
+	if secret == "" {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+
+	webhook := &models.Webhook{
+		ID:          uuid.New().String(),
+		URL:         request.URL,
+		Description: request.Description,
+		Events:      uniqueStrings(request.Events),
+		Secret:      secret,
+		Active:      request.Active == nil || *request.Active,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if err := s.webhookRepo.Create(webhook); err != nil {
+		return nil, err
+	}
+
+	response := webhookToResponse(webhook)
+	response.Secret = webhook.Secret
+	return response, nil
+}
+
+// GetWebhook retrieves a webhook by ID
+func (s *webhookService) GetWebhook(id string) (*models.WebhookResponse, error) {
+	if id == "" {
+		return nil, errors.New("webhook ID is required")
+	}
+
+	webhook, err := s.webhookRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return webhookToResponse(webhook), nil
+}
+
+// GetAllWebhooks retrieves every webhook
+func (s *webhookService) GetAllWebhooks() ([]models.WebhookResponse, error) {
+	webhooks, err := s.webhookRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.WebhookResponse, len(webhooks))
+	for i := range webhooks {
+		responses[i] = *webhookToResponse(&webhooks[i])
+	}
+	return responses, nil
+}
+
+// UpdateWebhook updates an existing webhook. A new secret is returned in the response
+// so that it can be rotated.
+func (s *webhookService) UpdateWebhook(id string, request *models.WebhookUpdateRequest) (*models.WebhookResponse, error) {
+	if id == "" {
+		return nil, errors.New("webhook ID is required")
+	}
+	if request == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+	if err := validateStruct(request); err != nil {
+		return nil, err
+	}
+	if request.URL != nil {
+		if err := validateWebhookURL(*request.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	webhook, err := s.webhookRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.URL != nil {
+		webhook.URL = *request.URL
+	}
+	if request.Description != nil {
+		webhook.Description = *request.Description
+	}
+	if request.Events != nil {
+		webhook.Events = uniqueStrings(*request.Events)
+	}
+	if request.Secret != nil {
+		webhook.Secret = *request.Secret
+	}
+	if request.Active != nil {
+		webhook.Active = *request.Active
+	}
+	webhook.UpdatedAt = time.Now()
+
+	if err := s.webhookRepo.Update(webhook); err != nil {
+		return nil, err
+	}
+
+	response := webhookToResponse(webhook)
+	if request.Secret != nil {
+		response.Secret = webhook.Secret
+	}
+	return response, nil
+}
+
+// DeleteWebhook deletes a webhook and its delivery history
+func (s *webhookService) DeleteWebhook(id string) error {
+	if id == "" {
+		return errors.New("webhook ID is required")
+	}
+	return s.webhookRepo.Delete(id)
+}
+
+// ListDeliveries returns the delivery log, newest first. An empty webhookID lists the
+// deliveries of every webhook; status narrows the list, e.g. to dead letters.
+func (s *webhookService) ListDeliveries(webhookID, status string, limit int) ([]models.WebhookDelivery, error) {
+	switch status {
+	case "", models.DeliveryStatusPending, models.DeliveryStatusSucceeded, models.DeliveryStatusDead:
+	default:
+		return nil, errors.New("status must be one of: pending succeeded dead")
+	}
+
+	if limit <= 0 {
+		limit = defaultDeliveryListLimit
+	}
+	if limit > maxDeliveryListLimit {
+		limit = maxDeliveryListLimit
+	}
+
+	if webhookID != "" {
+		if _, err := s.webhookRepo.GetByID(webhookID); err != nil {
+			return nil, err
+		}
+	}
+	return s.webhookRepo.ListDeliveries(webhookID, status, limit)
+}
+
+// RetryDelivery puts a dead letter back on the queue for immediate delivery
+func (s *webhookService) RetryDelivery(id string) (*models.WebhookDelivery, error) {
+	if id == "" {
+		return nil, errors.New("delivery ID is required")
+	}
+
+	delivery, err := s.webhookRepo.GetDelivery(id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status != models.DeliveryStatusDead {
+		return nil, errors.New("only dead deliveries can be retried")
+	}
+
+	delivery.Status = models.DeliveryStatusPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+	if err := s.webhookRepo.UpdateDelivery(delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// Publish queues a delivery of the event for every active webhook of the event's tenant
+// that is subscribed to its type
+func (s *webhookService) Publish(event *models.BlogEvent) error {
+	webhookRepo := s.webhookRepo.WithTenant(event.Tenant())
+	webhooks, err := webhookRepo.GetActive()
+	if err != nil {
+		return err
+	}
+
+	var deliveries []*models.WebhookDelivery
+	var payload []byte
+	for i := range webhooks {
+		if !webhooks[i].Subscribes(event.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(event); err != nil {
+				return err
+			}
+		}
+		deliveries = append(deliveries, &models.WebhookDelivery{
+			ID:            uuid.New().String(),
+			WebhookID:     webhooks[i].ID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       payload,
+			Status:        models.DeliveryStatusPending,
+			NextAttemptAt: time.Now(),
+		})
+	}
+
+	return webhookRepo.EnqueueDeliveries(deliveries)
+}
+
+// validateWebhookURL only accepts absolute http and https URLs
+func validateWebhookURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// generateWebhookSecret creates a random signing secret
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// uniqueStrings removes duplicates while keeping the original order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// webhookToResponse converts a Webhook model to WebhookResponse without its secret
+func webhookToResponse(webhook *models.Webhook) *models.WebhookResponse {
+	return &models.WebhookResponse{
+		ID:          webhook.ID,
+		URL:         webhook.URL,
+		Description: webhook.Description,
+		Events:      webhook.Events,
+		Active:      webhook.Active,
+		CreatedAt:   webhook.CreatedAt,
+		UpdatedAt:   webhook.UpdatedAt,
+	}
+}
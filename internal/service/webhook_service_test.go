@@ -0,0 +1,268 @@
+package service
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWebhookRepository is a mock implementation of WebhookRepository
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) WithTenant(tenantID string) repository.WebhookRepository {
+	args := m.Called(tenantID)
+	return args.Get(0).(repository.WebhookRepository)
+}
+
+func (m *MockWebhookRepository) Create(webhook *models.Webhook) error {
+	args := m.Called(webhook)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetByID(id string) (*models.Webhook, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetByIDs(ids []string) ([]models.Webhook, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetAll() ([]models.Webhook, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetActive() ([]models.Webhook, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Update(webhook *models.Webhook) error {
+	args := m.Called(webhook)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) Delete(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) EnqueueDeliveries(deliveries []*models.WebhookDelivery) error {
+	args := m.Called(deliveries)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetDelivery(id string) (*models.WebhookDelivery, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListDeliveries(webhookID, status string, limit int) ([]models.WebhookDelivery, error) {
+	args := m.Called(webhookID, status, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	args := m.Called(now, lease, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	args := m.Called(delivery)
+	return args.Error(0)
+}
+
+func TestWebhookService_CreateWebhook_GeneratesSecret(t *testing.T) {
+	mockRepo := &MockWebhookRepository{}
+	service := NewWebhookService(mockRepo)
+
+	mockRepo.On("Create", mock.AnythingOfType("*models.Webhook")).Return(nil)
+
+	response, err := service.CreateWebhook(&models.WebhookCreateRequest{
+		URL:    "https://example.com/hook",
+		Events: []string{models.EventPostCreated, models.EventPostCreated, models.EventPostDeleted},
+	})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, response.ID)
+	assert.True(t, strings.HasPrefix(response.Secret, "whsec_"))
+	assert.True(t, response.Active)
+	assert.Equal(t, []string{models.EventPostCreated, models.EventPostDeleted}, response.Events)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWebhookService_CreateWebhook_ValidationError(t *testing.T) {
+	mockRepo := &MockWebhookRepository{}
+	service := NewWebhookService(mockRepo)
+
+	tests := []struct {
+		name    string
+		request *models.WebhookCreateRequest
+	}{
+		{"nil request", nil},
+		{"missing events", &models.WebhookCreateRequest{URL: "https://example.com/hook"}},
+		{"unknown event", &models.WebhookCreateRequest{URL: "https://example.com/hook", Events: []string{"post.read"}}},
+		{"non-http url", &models.WebhookCreateRequest{URL: "ftp://example.com/hook", Events: []string{"*"}}},
+		{"short secret", &models.WebhookCreateRequest{URL: "https://example.com/hook", Events: []string{"*"}, Secret: "short"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := service.CreateWebhook(tt.request)
+
+			assert.Error(t, err)
+			assert.Nil(t, response)
+		})
+	}
+
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestWebhookService_GetWebhook_HidesSecret(t *testing.T) {
+	mockRepo := &MockWebhookRepository{}
+	service := NewWebhookService(mockRepo)
+
+	mockRepo.On("GetByID", "hook-1").Return(&models.Webhook{ID: "hook-1", Secret: "whsec_secret"}, nil)
+
+	response, err := service.GetWebhook("hook-1")
+
+	assert.NoError(t, err)
+	assert.Empty(t, response.Secret)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWebhookService_UpdateWebhook_NotFound(t *testing.T) {
+	mockRepo := &MockWebhookRepository{}
+	service := NewWebhookService(mockRepo)
+
+	mockRepo.On("GetByID", "missing").Return(nil, repository.ErrWebhookNotFound)
+
+	active := false
+	response, err := service.UpdateWebhook("missing", &models.WebhookUpdateRequest{Active: &active})
+
+	assert.ErrorIs(t, err, repository.ErrWebhookNotFound)
+	assert.Nil(t, response)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWebhookService_Publish_QueuesSubscribedWebhooks(t *testing.T) {
+	mockRepo := &MockWebhookRepository{}
+	service := NewWebhookService(mockRepo)
+
+	mockRepo.On("WithTenant", models.DefaultTenantID).Return(mockRepo)
+	mockRepo.On("GetActive").Return([]models.Webhook{
+		{ID: "all", Events: []string{"*"}, Active: true},
+		{ID: "created", Events: []string{models.EventPostCreated}, Active: true},
+		{ID: "deleted", Events: []string{models.EventPostDeleted}, Active: true},
+	}, nil)
+
+	var queued []*models.WebhookDelivery
+	mockRepo.On("EnqueueDeliveries", mock.Anything).Run(func(args mock.Arguments) {
+		queued = args.Get(0).([]*models.WebhookDelivery)
+	}).Return(nil)
+
+	event := newEvent(models.EventPostCreated, &models.BlogResponse{ID: "post-1", Title: "Hello"})
+	assert.NoError(t, service.Publish(event))
+
+	assert.Len(t, queued, 2)
+	assert.Equal(t, "all", queued[0].WebhookID)
+	assert.Equal(t, "created", queued[1].WebhookID)
+	for _, delivery := range queued {
+		assert.Equal(t, event.ID, delivery.EventID)
+		assert.Equal(t, models.DeliveryStatusPending, delivery.Status)
+
+		var payload models.BlogEvent
+		assert.NoError(t, json.Unmarshal(delivery.Payload, &payload))
+		assert.Equal(t, "post-1", payload.Data.ID)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWebhookService_Publish_OnlyQueuesWebhooksOfTheEventsTenant(t *testing.T) {
+	defaultRepo := &MockWebhookRepository{}
+	tenantRepo := &MockWebhookRepository{}
+	service := NewWebhookService(defaultRepo)
+
+	defaultRepo.On("WithTenant", "acme").Return(tenantRepo)
+	tenantRepo.On("GetActive").Return([]models.Webhook{{ID: "acme", Events: []string{"*"}, Active: true}}, nil)
+	tenantRepo.On("EnqueueDeliveries", mock.MatchedBy(func(deliveries []*models.WebhookDelivery) bool {
+		return len(deliveries) == 1 && deliveries[0].WebhookID == "acme"
+	})).Return(nil)
+
+	event := newEvent(models.EventPostCreated, &models.BlogResponse{ID: "post-1", Title: "Hello"})
+	event.TenantID = "acme"
+	assert.NoError(t, service.Publish(event))
+
+	defaultRepo.AssertExpectations(t)
+	defaultRepo.AssertNotCalled(t, "GetActive")
+	tenantRepo.AssertExpectations(t)
+}
+
+func TestWebhookService_ListDeliveries(t *testing.T) {
+	mockRepo := &MockWebhookRepository{}
+	service := NewWebhookService(mockRepo)
+
+	mockRepo.On("ListDeliveries", "", models.DeliveryStatusDead, maxDeliveryListLimit).Return([]models.WebhookDelivery{}, nil)
+
+	_, err := service.ListDeliveries("", models.DeliveryStatusDead, 10000)
+	assert.NoError(t, err)
+
+	_, err = service.ListDeliveries("", "unknown", 0)
+	assert.Error(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWebhookService_RetryDelivery(t *testing.T) {
+	mockRepo := &MockWebhookRepository{}
+	service := NewWebhookService(mockRepo)
+
+	dead := &models.WebhookDelivery{ID: "dead", Status: models.DeliveryStatusDead, Attempts: 8}
+	succeeded := &models.WebhookDelivery{ID: "done", Status: models.DeliveryStatusSucceeded, Attempts: 1}
+	mockRepo.On("GetDelivery", "dead").Return(dead, nil)
+	mockRepo.On("GetDelivery", "done").Return(succeeded, nil)
+	mockRepo.On("UpdateDelivery", dead).Return(nil)
+
+	delivery, err := service.RetryDelivery("dead")
+	assert.NoError(t, err)
+	assert.Equal(t, models.DeliveryStatusPending, delivery.Status)
+	assert.Zero(t, delivery.Attempts)
+
+	_, err = service.RetryDelivery("done")
+	assert.EqualError(t, err, "only dead deliveries can be retried")
+
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,40 @@
+// Package slug builds URL safe identifiers from titles and tag names
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength leaves room for the numeric suffix added to duplicate slugs
+const MaxLength = 200
+
+// Make converts a title, tag name or user supplied slug into a lowercase, URL safe slug
+func Make(value string) string {
+	var builder strings.Builder
+	pendingDash := false
+
+	for _, r := range norm.NFKD.String(value) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Drop combining marks so that "é" becomes "e"
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if pendingDash && builder.Len() > 0 {
+				builder.WriteByte('-')
+			}
+			pendingDash = false
+			builder.WriteRune(unicode.ToLower(r))
+		default:
+			pendingDash = true
+		}
+
+		if builder.Len() >= MaxLength {
+			break
+		}
+	}
+
+	return strings.Trim(builder.String(), "-")
+}
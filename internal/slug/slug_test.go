@@ -0,0 +1,17 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMake(t *testing.T) {
+	assert.Equal(t, "my-first-blog-post", Make("My First Blog Post"))
+	assert.Equal(t, "hello-world", Make("  Hello,   World!  "))
+	assert.Equal(t, "cafe-creme", Make("Café Crème"))
+	assert.Equal(t, "go-1-23-released", Make("Go 1.23 released"))
+	assert.Equal(t, "", Make("!!!"))
+	assert.Len(t, Make(strings.Repeat("a", 300)), MaxLength)
+}
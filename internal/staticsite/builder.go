@@ -0,0 +1,472 @@
+// Package staticsite renders published blog posts to a directory of static HTML pages,
+// feeds and a sitemap using user supplied html/template files.
+package staticsite
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/service"
+	"BlogManagment/internal/slug"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Template names looked up in the template directory
+const (
+	PostTemplate = "post.html"
+	ListTemplate = "list.html"
+	TagTemplate  = "tag.html"
+)
+
+// DefaultPageSize is the number of posts per index, archive and tag page
+const DefaultPageSize = 10
+
+// feedSize is the number of posts in each feed
+const feedSize = 20
+
+// Options configure a static site build
+type Options struct {
+	TemplateDir string
+	OutputDir   string
+	BaseURL     string
+	Title       string
+	PageSize    int
+	// Force re-renders every post even if it has not changed since the last build
+	Force bool
+}
+
+// Result summarizes a build
+type Result struct {
+	Posts     int `json:"posts"`
+	Rendered  int `json:"rendered"`
+	Unchanged int `json:"unchanged"`
+	Removed   int `json:"removed"`
+	Pages     int `json:"pages"`
+}
+
+// Builder renders the published posts of a BlogService to a directory
+type Builder struct {
+	blogService service.BlogService
+	options     Options
+	baseURL     *url.URL
+	templates   *template.Template
+	fingerprint string
+}
+
+// NewBuilder validates the options and parses every *.html file of the template directory.
+// post.html and list.html are required; tag.html is used for tag pages when present
+// and list.html otherwise.
+func NewBuilder(blogService service.BlogService, options Options) (*Builder, error) {
+	if options.TemplateDir == "" {
+		return nil, errors.New("template directory is required")
+	}
+	if options.OutputDir == "" {
+		return nil, errors.New("output directory is required")
+	}
+	if options.PageSize <= 0 {
+		options.PageSize = DefaultPageSize
+	}
+
+	baseURL, err := url.Parse(options.BaseURL)
+	if err != nil || baseURL.Scheme == "" || baseURL.Host == "" {
+		return nil, fmt.Errorf("base URL must be an absolute URL, got %q", options.BaseURL)
+	}
+	baseURL.Path = strings.TrimSuffix(baseURL.Path, "/")
+
+	templates, templateHash, err := parseTemplates(options.TemplateDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range []string{PostTemplate, ListTemplate} {
+		if templates.Lookup(name) == nil {
+			return nil, fmt.Errorf("template directory is missing %s", name)
+		}
+	}
+
+	// Post pages only depend on the post, the templates and these options
+	fingerprint := sha256.Sum256([]byte(strings.Join([]string{templateHash, baseURL.String(), options.Title}, "\n")))
+
+	return &Builder{
+		blogService: blogService,
+		options:     options,
+		baseURL:     baseURL,
+		templates:   templates,
+		fingerprint: hex.EncodeToString(fingerprint[:]),
+	}, nil
+}
+
+// parseTemplates parses the *.html files of dir and hashes their contents
+func parseTemplates(dir string) (*template.Template, string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(files) == 0 {
+		return nil, "", fmt.Errorf("no *.html templates found in %s", dir)
+	}
+	sort.Strings(files)
+
+	hash := sha256.New()
+	templates := template.New("").Funcs(templateFuncs)
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := templates.New(filepath.Base(file)).Parse(string(content)); err != nil {
+			return nil, "", fmt.Errorf("invalid template %s: %w", filepath.Base(file), err)
+		}
+		fmt.Fprintf(hash, "%s\x00%s\x00", filepath.Base(file), content)
+	}
+
+	return templates, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Build renders the site. Post pages are only rendered when the post changed since the
+// previous build; index, archive and tag pages, feeds and the sitemap are regenerated but
+// only written when their content differs. Pages of posts that are no longer published
+// are removed.
+func (b *Builder) Build() (*Result, error) {
+	responses, err := b.blogService.GetPublishedBlogs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load published posts: %w", err)
+	}
+
+	if err := os.MkdirAll(b.options.OutputDir, 0o755); err != nil {
+		return nil, err
+	}
+	previous, err := loadManifest(b.options.OutputDir)
+	if err != nil {
+		return nil, err
+	}
+	rebuildAll := b.options.Force || previous.Fingerprint != b.fingerprint
+
+	site := &Site{Title: b.options.Title, BaseURL: b.baseURL.String()}
+	posts := make([]*Post, len(responses))
+	for i := range responses {
+		posts[i] = b.newPost(&responses[i])
+	}
+
+	result := &Result{Posts: len(posts)}
+	current := &manifest{Fingerprint: b.fingerprint, Posts: make(map[string]manifestPost, len(posts))}
+	claimed := make(map[string]bool, len(posts))
+	for _, post := range posts {
+		file := b.outputPath(post.URL)
+		current.Posts[post.ID] = manifestPost{File: file, UpdatedAt: post.UpdatedAt}
+		claimed[file] = true
+	}
+
+	// Remove the pages of posts that were unpublished or moved to a new slug
+	for id, old := range previous.Posts {
+		if _, ok := current.Posts[id]; !ok {
+			result.Removed++
+		}
+		if !claimed[old.File] {
+			b.remove(old.File)
+		}
+	}
+
+	for _, post := range posts {
+		file := current.Posts[post.ID].File
+		old, known := previous.Posts[post.ID]
+		if !rebuildAll && known && old.File == file && old.UpdatedAt.Equal(post.UpdatedAt) && b.exists(file) {
+			result.Unchanged++
+			continue
+		}
+
+		if err := b.render(file, PostTemplate, &PostPage{Site: site, Post: post}); err != nil {
+			return nil, err
+		}
+		result.Rendered++
+	}
+
+	pages, err := b.buildIndexes(site, posts)
+	if err != nil {
+		return nil, err
+	}
+	current.Pages = pages
+	result.Pages = len(pages)
+
+	written := make(map[string]bool, len(pages))
+	for _, page := range pages {
+		written[page] = true
+	}
+	for _, page := range previous.Pages {
+		if !written[page] {
+			b.remove(page)
+		}
+	}
+
+	if err := saveManifest(b.options.OutputDir, current); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// buildIndexes writes the paginated index, the tag pages, the feeds and the sitemap and
+// returns the paths of every written file relative to the output directory
+func (b *Builder) buildIndexes(site *Site, posts []*Post) ([]string, error) {
+	tags := collectTags(posts)
+	for _, tag := range tags {
+		tag.URL = b.path("/tags/" + tag.Slug + "/")
+	}
+
+	var pages []string
+	listPages, err := b.renderList(site, "", b.path("/"), ListTemplate, nil, posts, tags)
+	if err != nil {
+		return nil, err
+	}
+	pages = append(pages, listPages...)
+
+	tagTemplate := ListTemplate
+	if b.templates.Lookup(TagTemplate) != nil {
+		tagTemplate = TagTemplate
+	}
+	for _, tag := range tags {
+		listPages, err := b.renderList(site, "Posts tagged "+tag.Name, tag.URL, tagTemplate, tag, tag.posts, tags)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, listPages...)
+
+		feedFile := b.outputPath(tag.URL + "feed.xml")
+		if err := b.writeFeed(feedFile, site.Title+": "+tag.Name, tag.URL, tag.posts); err != nil {
+			return nil, err
+		}
+		pages = append(pages, feedFile)
+	}
+
+	if err := b.writeFeed("feed.xml", site.Title, b.path("/"), posts); err != nil {
+		return nil, err
+	}
+	if err := b.writeSitemap("sitemap.xml", posts, tags); err != nil {
+		return nil, err
+	}
+
+	return append(pages, "feed.xml", "sitemap.xml"), nil
+}
+
+// renderList writes a paginated list of posts: the first page at baseURL and the
+// following pages at baseURL/page/N/
+func (b *Builder) renderList(site *Site, title, baseURL, templateName string, tag *Tag, posts []*Post, tags []*Tag) ([]string, error) {
+	pageSize := b.options.PageSize
+	totalPages := (len(posts) + pageSize - 1) / pageSize
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	pageURL := func(page int) string {
+		if page == 1 {
+			return baseURL
+		}
+		return baseURL + "page/" + strconv.Itoa(page) + "/"
+	}
+
+	files := make([]string, 0, totalPages)
+	for page := 1; page <= totalPages; page++ {
+		start := (page - 1) * pageSize
+		end := min(start+pageSize, len(posts))
+
+		data := &ListPage{
+			Site:  site,
+			Title: title,
+			Tag:   tag,
+			Tags:  tags,
+			Posts: posts[start:end],
+			Pagination: Pagination{
+				Page:       page,
+				TotalPages: totalPages,
+			},
+		}
+		if page > 1 {
+			data.Pagination.PrevURL = pageURL(page - 1)
+		}
+		if page < totalPages {
+			data.Pagination.NextURL = pageURL(page + 1)
+		}
+
+		file := b.outputPath(pageURL(page))
+		if err := b.render(file, templateName, data); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// newPost prepares a post for the templates
+func (b *Builder) newPost(response *models.BlogResponse) *Post {
+	postSlug := response.Slug
+	if postSlug == "" {
+		postSlug = response.ID
+	}
+
+	post := &Post{
+		BlogResponse: *response,
+		URL:          b.path("/posts/" + url.PathEscape(postSlug) + "/"),
+		Date:         response.CreatedAt,
+	}
+	if response.PublishedAt != nil {
+		post.Date = *response.PublishedAt
+	}
+	post.Permalink = b.absolute(post.URL)
+	for _, name := range response.Tags {
+		tagSlug := tagSlug(name)
+		post.TagLinks = append(post.TagLinks, &Tag{Name: name, Slug: tagSlug, URL: b.path("/tags/" + tagSlug + "/")})
+	}
+	return post
+}
+
+// render executes a template and writes the result to a file below the output directory
+func (b *Builder) render(file, templateName string, data interface{}) error {
+	var buf bytes.Buffer
+	if err := b.templates.ExecuteTemplate(&buf, templateName, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", file, err)
+	}
+	return b.write(file, buf.Bytes())
+}
+
+// write replaces a file below the output directory unless it already has the given content.
+// Files are written to a temporary file first so that readers never see partial pages.
+func (b *Builder) write(file string, content []byte) error {
+	target := filepath.Join(b.options.OutputDir, filepath.FromSlash(file))
+	if existing, err := os.ReadFile(target); err == nil && bytes.Equal(existing, content) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+// remove deletes a generated file and any directories it leaves empty
+func (b *Builder) remove(file string) {
+	root := filepath.Clean(b.options.OutputDir)
+	target := filepath.Join(root, filepath.FromSlash(file))
+	if err := os.Remove(target); err != nil {
+		return
+	}
+	for dir := filepath.Dir(target); dir != root && strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			return
+		}
+	}
+}
+
+// exists reports whether a generated file is present
+func (b *Builder) exists(file string) bool {
+	_, err := os.Stat(filepath.Join(b.options.OutputDir, filepath.FromSlash(file)))
+	return err == nil
+}
+
+// path prefixes a site path with the path of the base URL
+func (b *Builder) path(sitePath string) string {
+	return b.baseURL.Path + sitePath
+}
+
+// absolute turns a path returned by b.path into an absolute URL
+func (b *Builder) absolute(urlPath string) string {
+	return b.baseURL.Scheme + "://" + b.baseURL.Host + urlPath
+}
+
+// outputPath maps a page URL to its file below the output directory
+func (b *Builder) outputPath(urlPath string) string {
+	file := strings.TrimPrefix(urlPath, b.baseURL.Path)
+	if unescaped, err := url.PathUnescape(file); err == nil {
+		file = unescaped
+	}
+	file = strings.TrimPrefix(path.Clean("/"+file), "/")
+	if strings.HasSuffix(urlPath, "/") {
+		file = path.Join(file, "index.html")
+	}
+	return file
+}
+
+// collectTags groups posts by tag slug, most used tags first
+func collectTags(posts []*Post) []*Tag {
+	bySlug := make(map[string]*Tag)
+	var tags []*Tag
+	for _, post := range posts {
+		for _, link := range post.TagLinks {
+			tag, ok := bySlug[link.Slug]
+			if !ok {
+				tag = &Tag{Name: link.Name, Slug: link.Slug}
+				bySlug[link.Slug] = tag
+				tags = append(tags, tag)
+			}
+			tag.posts = append(tag.posts, post)
+			tag.Count++
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Slug < tags[j].Slug
+	})
+	return tags
+}
+
+// tagSlug builds the URL segment of a tag. Tags without ASCII letters or digits keep
+// their lowercased name so that they still get a page of their own.
+func tagSlug(name string) string {
+	if tagSlug := slug.Make(name); tagSlug != "" {
+		return tagSlug
+	}
+	return url.PathEscape(strings.ToLower(name))
+}
+
+// templateFuncs are available to every template
+var templateFuncs = template.FuncMap{
+	// safeHTML marks trusted post bodies as HTML so that they are not escaped
+	"safeHTML": func(value string) template.HTML {
+		return template.HTML(value)
+	},
+	"formatDate": func(t time.Time, layout string) string {
+		return t.Format(layout)
+	},
+	// dict builds a map from key value pairs so that partials can receive several values
+	"dict": func(pairs ...interface{}) (map[string]interface{}, error) {
+		if len(pairs)%2 != 0 {
+			return nil, errors.New("dict expects key value pairs")
+		}
+		values := make(map[string]interface{}, len(pairs)/2)
+		for i := 0; i < len(pairs); i += 2 {
+			key, ok := pairs[i].(string)
+			if !ok {
+				return nil, fmt.Errorf("dict key %v is not a string", pairs[i])
+			}
+			values[key] = pairs[i+1]
+		}
+		return values, nil
+	},
+}
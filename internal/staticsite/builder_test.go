@@ -0,0 +1,210 @@
+package staticsite
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/service"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockBlogService is a mock implementation of BlogService
+type MockBlogService struct {
+	mock.Mock
+}
+
+func (m *MockBlogService) CreateBlog(request *models.BlogCreateRequest) (*models.BlogResponse, error) {
+	args := m.Called(request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogResponse), args.Error(1)
+}
+
+func (m *MockBlogService) GetBlogByID(id string) (*models.BlogResponse, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogResponse), args.Error(1)
+}
+
+func (m *MockBlogService) GetAllBlogs() ([]models.BlogResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BlogResponse), args.Error(1)
+}
+
+func (m *MockBlogService) GetPublishedBlogs() ([]models.BlogResponse, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BlogResponse), args.Error(1)
+}
+
+func (m *MockBlogService) ListBlogs(query *models.BlogListQuery) (*models.BlogPage, error) {
+	args := m.Called(query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogPage), args.Error(1)
+}
+
+func (m *MockBlogService) GetBlogBySlug(slug string) (*models.BlogResponse, error) {
+	args := m.Called(slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogResponse), args.Error(1)
+}
+
+func (m *MockBlogService) CountBlogsByTags(query *models.TagCountQuery) (map[string]int, error) {
+	args := m.Called(query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+func (m *MockBlogService) UpdateBlog(id string, request *models.BlogUpdateRequest) (*models.BlogResponse, error) {
+	args := m.Called(id, request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogResponse), args.Error(1)
+}
+
+func (m *MockBlogService) DeleteBlog(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockBlogService) BulkBlogs(request *models.BlogBulkRequest) (*models.BlogBulkResponse, error) {
+	args := m.Called(request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BlogBulkResponse), args.Error(1)
+}
+
+// WithTenant returns the mock itself, so expectations cover every tenant
+func (m *MockBlogService) WithTenant(tenantID string) service.BlogService {
+	return m
+}
+
+// WithRequester returns the mock itself
+func (m *MockBlogService) WithRequester(requester *models.Requester) service.BlogService {
+	return m
+}
+
+func writeTemplates(t *testing.T, dir, postTemplate string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "post.html"), []byte(postTemplate), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "list.html"), []byte(
+		`{{define "list.html"}}{{.Title}}{{range .Posts}}<a href="{{.URL}}">{{.Title}}</a>{{end}}`+
+			`{{with .Pagination.NextURL}}<a rel="next" href="{{.}}">older</a>{{end}}{{end}}`), 0o644))
+}
+
+func newTestBuilder(t *testing.T, blogService service.BlogService, templateDir, outputDir string) *Builder {
+	t.Helper()
+	builder, err := NewBuilder(blogService, Options{
+		TemplateDir: templateDir,
+		OutputDir:   outputDir,
+		BaseURL:     "https://example.com/blog/",
+		Title:       "Example",
+		PageSize:    2,
+	})
+	require.NoError(t, err)
+	return builder
+}
+
+func readOutput(t *testing.T, outputDir, file string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(outputDir, filepath.FromSlash(file)))
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestBuilder_Build(t *testing.T) {
+	templateDir, outputDir := t.TempDir(), t.TempDir()
+	writeTemplates(t, templateDir, `<h1>{{.Post.Title}}</h1>{{safeHTML .Post.Body}}{{range .Post.TagLinks}}<a href="{{.URL}}">{{.Name}}</a>{{end}}`)
+
+	updated := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	blogService := &MockBlogService{}
+	blogService.On("GetPublishedBlogs").Return([]models.BlogResponse{
+		{ID: "3", Slug: "third", Title: "Third", Body: "<p>3</p>", Tags: []string{"Go"}, UpdatedAt: updated},
+		{ID: "2", Slug: "second", Title: "Second", Body: "<p>2</p>", Tags: []string{"Go", "Web Dev"}, UpdatedAt: updated},
+		{ID: "1", Slug: "first", Title: "First", Body: "<p>1</p>", UpdatedAt: updated},
+	}, nil)
+
+	result, err := newTestBuilder(t, blogService, templateDir, outputDir).Build()
+
+	require.NoError(t, err)
+	assert.Equal(t, &Result{Posts: 3, Rendered: 3, Pages: 8}, result)
+	assert.Equal(t, `<h1>Second</h1><p>2</p><a href="/blog/tags/go/">Go</a><a href="/blog/tags/web-dev/">Web Dev</a>`,
+		readOutput(t, outputDir, "posts/second/index.html"))
+	assert.Contains(t, readOutput(t, outputDir, "index.html"), `<a rel="next" href="/blog/page/2/">older</a>`)
+	assert.Contains(t, readOutput(t, outputDir, "page/2/index.html"), `<a href="/blog/posts/first/">First</a>`)
+	assert.Contains(t, readOutput(t, outputDir, "tags/go/index.html"), "Posts tagged Go")
+	assert.Contains(t, readOutput(t, outputDir, "tags/go/feed.xml"), "<id>https://example.com/blog/posts/third/</id>")
+	assert.Contains(t, readOutput(t, outputDir, "feed.xml"), `<link href="https://example.com/blog/feed.xml" rel="self"></link>`)
+	assert.Contains(t, readOutput(t, outputDir, "sitemap.xml"), "<loc>https://example.com/blog/tags/web-dev/</loc>")
+}
+
+func TestBuilder_Build_Incremental(t *testing.T) {
+	templateDir, outputDir := t.TempDir(), t.TempDir()
+	writeTemplates(t, templateDir, `{{.Post.Title}}`)
+
+	updated := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	blogService := &MockBlogService{}
+	blogService.On("GetPublishedBlogs").Return([]models.BlogResponse{
+		{ID: "2", Slug: "second", Title: "Second", Body: "B", UpdatedAt: updated},
+		{ID: "1", Slug: "first", Title: "First", Body: "B", UpdatedAt: updated},
+	}, nil).Twice()
+
+	_, err := newTestBuilder(t, blogService, templateDir, outputDir).Build()
+	require.NoError(t, err)
+
+	// Nothing changed
+	result, err := newTestBuilder(t, blogService, templateDir, outputDir).Build()
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Rendered)
+	assert.Equal(t, 2, result.Unchanged)
+
+	// One post was edited and the other unpublished
+	blogService.On("GetPublishedBlogs").Return([]models.BlogResponse{
+		{ID: "2", Slug: "second", Title: "Second, edited", Body: "B", UpdatedAt: updated.Add(time.Hour)},
+	}, nil)
+	result, err = newTestBuilder(t, blogService, templateDir, outputDir).Build()
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Rendered)
+	assert.Equal(t, 1, result.Removed)
+	assert.Equal(t, "Second, edited", readOutput(t, outputDir, "posts/second/index.html"))
+	assert.NoDirExists(t, filepath.Join(outputDir, "posts", "first"))
+
+	// Changed templates re-render every post
+	writeTemplates(t, templateDir, `<b>{{.Post.Title}}</b>`)
+	result, err = newTestBuilder(t, blogService, templateDir, outputDir).Build()
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Rendered)
+	assert.Equal(t, "<b>Second, edited</b>", readOutput(t, outputDir, "posts/second/index.html"))
+}
+
+func TestNewBuilder_Validation(t *testing.T) {
+	templateDir := t.TempDir()
+
+	_, err := NewBuilder(&MockBlogService{}, Options{TemplateDir: templateDir, OutputDir: t.TempDir(), BaseURL: "/relative"})
+	assert.EqualError(t, err, `base URL must be an absolute URL, got "/relative"`)
+
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "list.html"), []byte("list"), 0o644))
+	_, err = NewBuilder(&MockBlogService{}, Options{TemplateDir: templateDir, OutputDir: t.TempDir(), BaseURL: "https://example.com"})
+	assert.EqualError(t, err, "template directory is missing post.html")
+}
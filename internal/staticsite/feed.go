@@ -0,0 +1,135 @@
+package staticsite
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// atomFeed is an Atom 1.0 feed
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Link       atomLink       `xml:"link"`
+	Summary    string         `xml:"summary,omitempty"`
+	Content    atomContent    `xml:"content"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// sitemapURLSet is a sitemaps.org sitemap
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// writeFeed writes an Atom feed of the newest posts. The feed's updated time is the
+// newest post update so that an unchanged site produces an identical feed.
+func (b *Builder) writeFeed(file, title, pageURL string, posts []*Post) error {
+	if len(posts) > feedSize {
+		posts = posts[:feedSize]
+	}
+
+	feed := atomFeed{
+		Title: title,
+		ID:    b.absolute(pageURL),
+		Links: []atomLink{
+			{Href: b.absolute(pageURL)},
+			{Href: b.absolute(b.path("/" + file)), Rel: "self"},
+		},
+	}
+
+	var updated time.Time
+	for _, post := range posts {
+		if post.UpdatedAt.After(updated) {
+			updated = post.UpdatedAt
+		}
+
+		entry := atomEntry{
+			Title:     post.Title,
+			ID:        post.Permalink,
+			Published: formatW3C(post.Date),
+			Updated:   formatW3C(post.UpdatedAt),
+			Link:      atomLink{Href: post.Permalink},
+			Summary:   post.Description,
+			Content:   atomContent{Type: "html", Body: post.Body},
+		}
+		for _, tag := range post.TagLinks {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag.Name})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	feed.Updated = formatW3C(updated)
+
+	return b.writeXML(file, feed)
+}
+
+// writeSitemap lists the home page, every post and the first page of every tag
+func (b *Builder) writeSitemap(file string, posts []*Post, tags []*Tag) error {
+	sitemap := sitemapURLSet{URLs: []sitemapURL{{Loc: b.absolute(b.path("/"))}}}
+	if len(posts) > 0 {
+		sitemap.URLs[0].LastMod = formatW3C(newestUpdate(posts))
+	}
+
+	for _, post := range posts {
+		sitemap.URLs = append(sitemap.URLs, sitemapURL{Loc: post.Permalink, LastMod: formatW3C(post.UpdatedAt)})
+	}
+	for _, tag := range tags {
+		sitemap.URLs = append(sitemap.URLs, sitemapURL{Loc: b.absolute(tag.URL), LastMod: formatW3C(newestUpdate(tag.posts))})
+	}
+
+	return b.writeXML(file, sitemap)
+}
+
+// writeXML writes an indented XML document with a declaration
+func (b *Builder) writeXML(file string, document interface{}) error {
+	content, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return err
+	}
+	return b.write(file, append([]byte(xml.Header), append(content, '\n')...))
+}
+
+// newestUpdate returns the latest update time of a list of posts
+func newestUpdate(posts []*Post) time.Time {
+	var newest time.Time
+	for _, post := range posts {
+		if post.UpdatedAt.After(newest) {
+			newest = post.UpdatedAt
+		}
+	}
+	return newest
+}
+
+// formatW3C formats a time as required by Atom and sitemaps
+func formatW3C(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,56 @@
+package staticsite
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// manifestFile records what the previous build wrote, relative to the output directory
+const manifestFile = ".build-manifest.json"
+
+// manifest is the state kept between builds for incremental rendering
+type manifest struct {
+	// Fingerprint identifies the templates and options the post pages were rendered with
+	Fingerprint string                  `json:"fingerprint"`
+	Posts       map[string]manifestPost `json:"posts"`
+	// Pages are the index, archive, tag, feed and sitemap files
+	Pages []string `json:"pages"`
+}
+
+// manifestPost is the rendered page of a single post
+type manifestPost struct {
+	File      string    `json:"file"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// loadManifest reads the manifest of the previous build, or returns an empty one
+func loadManifest(outputDir string) (*manifest, error) {
+	m := &manifest{Posts: map[string]manifestPost{}}
+	content, err := os.ReadFile(filepath.Join(outputDir, manifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, m); err != nil {
+		return nil, fmt.Errorf("invalid build manifest, remove %s to rebuild: %w", manifestFile, err)
+	}
+	if m.Posts == nil {
+		m.Posts = map[string]manifestPost{}
+	}
+	return m, nil
+}
+
+// saveManifest records the state of the current build
+func saveManifest(outputDir string, m *manifest) error {
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outputDir, manifestFile), content, 0o644)
+}
@@ -0,0 +1,60 @@
+package staticsite
+
+import (
+	"BlogManagment/internal/models"
+	"time"
+)
+
+// Site describes the site as a whole
+type Site struct {
+	Title   string
+	BaseURL string
+}
+
+// Post is a published post as seen by the templates. The fields of BlogResponse are
+// promoted, so templates can use {{.Title}}, {{.Body}} and so on.
+type Post struct {
+	models.BlogResponse
+	// URL is the path of the post page, Permalink its absolute URL
+	URL       string
+	Permalink string
+	// Date is the publication time, or the creation time for posts published before it was recorded
+	Date     time.Time
+	TagLinks []*Tag
+}
+
+// Tag is a tag with the URL of its page
+type Tag struct {
+	Name  string
+	Slug  string
+	URL   string
+	Count int
+
+	posts []*Post
+}
+
+// Pagination links the pages of a paginated list. PrevURL and NextURL are empty on the
+// first and last page respectively.
+type Pagination struct {
+	Page       int
+	TotalPages int
+	PrevURL    string
+	NextURL    string
+}
+
+// PostPage is the data passed to post.html
+type PostPage struct {
+	Site *Site
+	Post *Post
+}
+
+// ListPage is the data passed to list.html and tag.html. Tag is nil on the index
+// and archive pages; Tags lists every tag, most used first.
+type ListPage struct {
+	Site       *Site
+	Title      string
+	Tag        *Tag
+	Tags       []*Tag
+	Posts      []*Post
+	Pagination Pagination
+}
@@ -0,0 +1,204 @@
+// Package stream fans out the events recorded in the outbox to live subscribers, such as
+// Server-Sent Events clients.
+package stream
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/repository"
+	"BlogManagment/internal/service"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// pageSize is the number of events read from the outbox at a time
+const pageSize = 200
+
+// subscriberBuffer is the number of messages a subscriber may fall behind before it is dropped
+const subscriberBuffer = 64
+
+// Message is an outbox event ready to be sent to subscribers
+type Message struct {
+	Sequence int64
+	Event    *models.BlogEvent
+	Payload  []byte
+}
+
+// Filter selects the messages a subscriber receives. Empty fields match everything.
+type Filter struct {
+	TenantID string
+	Types    []string
+	Tag      string
+	// Actor leaves out the events of posts it may not read, such as the drafts of others.
+	// post.deleted events carry nothing but the ID, so they are not filtered by it.
+	Actor *rbac.Actor
+}
+
+// NewFilter creates a filter for the given event types and tag, rejecting unknown event types
+func NewFilter(types []string, tag string) (Filter, error) {
+	filter := Filter{Tag: strings.TrimSpace(tag)}
+	for _, eventType := range types {
+		if !slices.Contains(models.EventTypes, eventType) {
+			return filter, fmt.Errorf("unknown event type %q, expected one of: %s", eventType, strings.Join(models.EventTypes, " "))
+		}
+		filter.Types = append(filter.Types, eventType)
+	}
+	return filter, nil
+}
+
+// Matches reports whether a message passes the filter. Deleted posts carry no tags, so
+// post.deleted events are not filtered by tag.
+func (f Filter) Matches(message *Message) bool {
+	event := message.Event
+	if f.TenantID != "" && event.Tenant() != f.TenantID {
+		return false
+	}
+	if len(f.Types) > 0 && !slices.Contains(f.Types, event.Type) {
+		return false
+	}
+	if f.Actor != nil && event.Type != models.EventPostDeleted && event.Data != nil && !service.CanRead(f.Actor, event.Data) {
+		return false
+	}
+	if f.Tag == "" || event.Type == models.EventPostDeleted {
+		return true
+	}
+	if event.Data == nil {
+		return false
+	}
+	return slices.ContainsFunc(event.Data.Tags, func(tag string) bool {
+		return strings.EqualFold(tag, f.Tag)
+	})
+}
+
+// Subscription receives the live messages matching its filter. C is closed when the
+// subscriber falls too far behind; it should reconnect and replay from its last message.
+type Subscription struct {
+	C      <-chan *Message
+	ch     chan *Message
+	filter Filter
+}
+
+// Broker reads new events from the outbox whenever it is notified and sends them to every
+// matching subscription
+type Broker struct {
+	repo repository.OutboxRepository
+
+	mu          sync.Mutex
+	position    int64
+	started     bool
+	subscribers map[*Subscription]struct{}
+}
+
+// NewBroker creates a new broker
+func NewBroker(repo repository.OutboxRepository) *Broker {
+	return &Broker{repo: repo, subscribers: make(map[*Subscription]struct{})}
+}
+
+// Subscribe registers a subscription for live messages
+func (b *Broker) Subscribe(filter Filter) *Subscription {
+	ch := make(chan *Message, subscriberBuffer)
+	subscription := &Subscription{C: ch, ch: ch, filter: filter}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers[subscription] = struct{}{}
+	return subscription
+}
+
+// Unsubscribe removes a subscription and closes its channel
+func (b *Broker) Unsubscribe(subscription *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subscribers[subscription]; ok {
+		delete(b.subscribers, subscription)
+		close(subscription.ch)
+	}
+}
+
+// Poll reads the events added to the outbox since the previous call and sends them to the
+// subscribers. The first call only records the current position, so that events from before
+// the broker started are not sent to live subscribers.
+func (b *Broker) Poll() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.started {
+		position, err := b.repo.LastSequence()
+		if err != nil {
+			return err
+		}
+		b.position, b.started = position, true
+		return nil
+	}
+
+	for {
+		events, err := b.repo.ListAfter(b.position, pageSize)
+		if err != nil {
+			return err
+		}
+		for i := range events {
+			b.position = events[i].Sequence
+			if message := newMessage(&events[i]); message != nil {
+				b.broadcast(message)
+			}
+		}
+		if len(events) < pageSize {
+			return nil
+		}
+	}
+}
+
+// broadcast sends a message to every matching subscriber, dropping those that are full.
+// The caller must hold the lock.
+func (b *Broker) broadcast(message *Message) {
+	for subscription := range b.subscribers {
+		if !subscription.filter.Matches(message) {
+			continue
+		}
+		select {
+		case subscription.ch <- message:
+		default:
+			delete(b.subscribers, subscription)
+			close(subscription.ch)
+		}
+	}
+}
+
+// Replay calls fn for every event after position that matches the filter, in order, and
+// returns the sequence number of the last event read. Events removed from the outbox by its
+// retention period can no longer be replayed.
+func (b *Broker) Replay(position int64, filter Filter, fn func(*Message) error) (int64, error) {
+	for {
+		events, err := b.repo.ListAfter(position, pageSize)
+		if err != nil {
+			return position, err
+		}
+		for i := range events {
+			position = events[i].Sequence
+			message := newMessage(&events[i])
+			if message == nil || !filter.Matches(message) {
+				continue
+			}
+			if err := fn(message); err != nil {
+				return position, err
+			}
+		}
+		if len(events) < pageSize {
+			return position, nil
+		}
+	}
+}
+
+// newMessage decodes an outbox event; undecodable events are logged and skipped
+func newMessage(event *models.OutboxEvent) *Message {
+	var decoded models.BlogEvent
+	if err := json.Unmarshal(event.Payload, &decoded); err != nil {
+		log.Printf("Skipping undecodable outbox event #%d: %v", event.Sequence, err)
+		return nil
+	}
+	return &Message{Sequence: event.Sequence, Event: &decoded, Payload: event.Payload}
+}
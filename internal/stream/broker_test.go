@@ -0,0 +1,154 @@
+package stream
+
+import (
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/rbac"
+	"BlogManagment/internal/repository"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestOutbox opens an empty in-memory SQLite outbox and returns a repository over it
+func newTestOutbox(t *testing.T) (*gorm.DB, repository.OutboxRepository) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+uuid.New().String()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.OutboxEvent{}))
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db, repository.NewOutboxRepository(db)
+}
+
+// appendEvent commits an event about a post with tags to the outbox
+func appendEvent(t *testing.T, db *gorm.DB, eventType string, tags ...string) {
+	t.Helper()
+	event := &models.BlogEvent{ID: uuid.New().String(), Type: eventType, OccurredAt: time.Now(), Data: &models.BlogResponse{ID: "post", Tags: tags}}
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+	require.NoError(t, db.Create(&models.OutboxEvent{
+		EventID:     event.ID,
+		Type:        eventType,
+		AggregateID: "post",
+		Payload:     payload,
+		CreatedAt:   event.OccurredAt,
+	}).Error)
+}
+
+// received drains the messages currently buffered for a subscription
+func received(subscription *Subscription) []int64 {
+	var sequences []int64
+	for {
+		select {
+		case message, ok := <-subscription.C:
+			if !ok {
+				return sequences
+			}
+			sequences = append(sequences, message.Sequence)
+		default:
+			return sequences
+		}
+	}
+}
+
+func TestBroker_Poll_BroadcastsNewEventsToMatchingSubscribers(t *testing.T) {
+	db, repo := newTestOutbox(t)
+	appendEvent(t, db, models.EventPostCreated)
+
+	broker := NewBroker(repo)
+	require.NoError(t, broker.Poll())
+
+	all := broker.Subscribe(Filter{})
+	deletes := broker.Subscribe(Filter{Types: []string{models.EventPostDeleted}})
+	tagged := broker.Subscribe(Filter{Tag: "Go"})
+
+	appendEvent(t, db, models.EventPostCreated, "go")
+	appendEvent(t, db, models.EventPostUpdated, "rust")
+	appendEvent(t, db, models.EventPostDeleted)
+	require.NoError(t, broker.Poll())
+
+	// Events from before the first poll are not broadcast
+	assert.Equal(t, []int64{2, 3, 4}, received(all))
+	assert.Equal(t, []int64{4}, received(deletes))
+	assert.Equal(t, []int64{2, 4}, received(tagged))
+}
+
+func TestBroker_Poll_DropsSlowSubscribers(t *testing.T) {
+	db, repo := newTestOutbox(t)
+	broker := NewBroker(repo)
+	require.NoError(t, broker.Poll())
+
+	subscription := broker.Subscribe(Filter{})
+	for range subscriberBuffer + 1 {
+		appendEvent(t, db, models.EventPostUpdated)
+	}
+	require.NoError(t, broker.Poll())
+
+	assert.Len(t, received(subscription), subscriberBuffer)
+	_, open := <-subscription.C
+	assert.False(t, open)
+
+	// Unsubscribing a dropped subscription is harmless
+	broker.Unsubscribe(subscription)
+}
+
+func TestBroker_Replay(t *testing.T) {
+	db, repo := newTestOutbox(t)
+	for i := range pageSize + 5 {
+		if i%2 == 0 {
+			appendEvent(t, db, models.EventPostCreated)
+		} else {
+			appendEvent(t, db, models.EventPostUpdated)
+		}
+	}
+
+	broker := NewBroker(repo)
+	var replayed []int64
+	position, err := broker.Replay(10, Filter{Types: []string{models.EventPostUpdated}}, func(message *Message) error {
+		replayed = append(replayed, message.Sequence)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(pageSize+5), position)
+	assert.Equal(t, int64(12), replayed[0])
+	assert.Len(t, replayed, (pageSize+5-10)/2)
+}
+
+func TestFilter_Matches_Tenant(t *testing.T) {
+	legacy := &Message{Event: &models.BlogEvent{Type: models.EventPostCreated}}
+	acme := &Message{Event: &models.BlogEvent{Type: models.EventPostCreated, TenantID: "acme"}}
+
+	assert.True(t, Filter{TenantID: models.DefaultTenantID}.Matches(legacy))
+	assert.False(t, Filter{TenantID: models.DefaultTenantID}.Matches(acme))
+	assert.True(t, Filter{TenantID: "acme"}.Matches(acme))
+	assert.False(t, Filter{TenantID: "globex"}.Matches(acme))
+	assert.True(t, Filter{}.Matches(acme))
+}
+
+func TestFilter_Matches_Drafts(t *testing.T) {
+	draft := &Message{Event: &models.BlogEvent{Type: models.EventPostUpdated, Data: &models.BlogResponse{ID: "1", Status: models.BlogStatusDraft, AuthorID: "alice"}}}
+	published := &Message{Event: &models.BlogEvent{Type: models.EventPostUpdated, Data: &models.BlogResponse{ID: "2", Status: models.BlogStatusPublished}}}
+	deleted := &Message{Event: &models.BlogEvent{Type: models.EventPostDeleted, Data: &models.BlogResponse{ID: "1"}}}
+
+	anonymous := Filter{Actor: rbac.NewActor("", nil, nil)}
+	assert.False(t, anonymous.Matches(draft))
+	assert.True(t, anonymous.Matches(published))
+	assert.True(t, anonymous.Matches(deleted), "deleted posts carry nothing but their ID")
+
+	assert.True(t, Filter{Actor: rbac.NewActor("alice", []string{rbac.RoleAuthor}, nil)}.Matches(draft), "authors see their drafts")
+	assert.False(t, Filter{Actor: rbac.NewActor("bob", []string{rbac.RoleAuthor}, nil)}.Matches(draft))
+	assert.True(t, Filter{Actor: rbac.NewActor("bob", []string{rbac.RoleReviewer}, nil)}.Matches(draft), "reviewers see every draft")
+	assert.True(t, Filter{}.Matches(draft), "filters without an actor see everything")
+}
@@ -0,0 +1,72 @@
+package stream
+
+import (
+	"BlogManagment/internal/repository"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Listener bounds
+const (
+	// pollFallback is how often the outbox is read even without a notification
+	pollFallback = 5 * time.Second
+	// maxReconnectDelay caps the delay between reconnection attempts
+	maxReconnectDelay = 30 * time.Second
+)
+
+// Listen keeps a dedicated connection that LISTENs for outbox notifications and polls the
+// broker whenever one arrives, so that changes committed through any replica reach every
+// subscriber. The outbox is also read every few seconds in case a notification is missed.
+// Listen blocks until the context is cancelled, reconnecting after connection failures.
+func (b *Broker) Listen(ctx context.Context, dsn string) {
+	delay := time.Second
+	for ctx.Err() == nil {
+		connected, err := b.listen(ctx, dsn)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			delay = time.Second
+		}
+		log.Printf("Event listener disconnected: %v; reconnecting in %s", err, delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+// listen runs a single listening connection until it fails. It reports whether the
+// connection was established.
+func (b *Broker) listen(ctx context.Context, dsn string) (bool, error) {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{repository.OutboxChannel}.Sanitize()); err != nil {
+		return true, err
+	}
+
+	for {
+		// Catch up on anything committed while no notification could be received
+		if err := b.Poll(); err != nil {
+			log.Printf("Event stream poll failed: %v", err)
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, pollFallback)
+		_, err := conn.WaitForNotification(waitCtx)
+		cancel()
+		if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			return true, err
+		}
+	}
+}
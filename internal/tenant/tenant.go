@@ -0,0 +1,113 @@
+// Package tenant resolves the workspace a request belongs to and carries it through the
+// request context.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"net"
+	"regexp"
+	"strings"
+
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrTenantMismatch is returned when the credentials of a request belong to another tenant
+// than the one it addresses
+var ErrTenantMismatch = errors.New("credentials are not valid for this tenant")
+
+// idPattern matches valid tenant IDs, which must be usable as a DNS label
+var idPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// ValidID reports whether id is a valid tenant ID: up to 63 lowercase letters, digits and
+// hyphens, not starting or ending with a hyphen
+func ValidID(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+type contextKey struct{}
+
+// NewContext returns a context carrying the tenant ID
+func NewContext(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, tenantID)
+}
+
+// FromContext returns the tenant ID carried by ctx, or the default tenant when there is none
+func FromContext(ctx context.Context) string {
+	if tenantID, ok := ctx.Value(contextKey{}).(string); ok && tenantID != "" {
+		return tenantID
+	}
+	return models.DefaultTenantID
+}
+
+// Set stores the tenant of a request in its user context
+func Set(c *fiber.Ctx, tenantID string) {
+	c.SetUserContext(NewContext(c.UserContext(), tenantID))
+}
+
+// FromCtx returns the tenant of a request, or the default tenant when none was resolved
+func FromCtx(c *fiber.Ctx) string {
+	return FromContext(c.UserContext())
+}
+
+// Resolver determines the tenant of a request and checks that it exists
+type Resolver struct {
+	tenants    repository.TenantRepository
+	baseDomain string
+}
+
+// NewResolver creates a resolver for tenants named by subdomains of baseDomain.
+// Subdomains are ignored when baseDomain is empty.
+func NewResolver(tenants repository.TenantRepository, baseDomain string) *Resolver {
+	return &Resolver{tenants: tenants, baseDomain: strings.ToLower(strings.Trim(baseDomain, "."))}
+}
+
+// Resolve returns the tenant of a request. The tenant is taken from the claim of the caller's
+// credentials, the request header or the subdomain of host, in that order; requests naming
+// none of them belong to the default tenant. A header or subdomain naming another tenant than
+// the claim fails with ErrTenantMismatch, and unknown tenants with repository.ErrTenantNotFound.
+func (r *Resolver) Resolve(claim, header, host string) (string, error) {
+	requested := strings.ToLower(strings.TrimSpace(header))
+	if requested == "" {
+		requested = r.subdomain(host)
+	}
+
+	switch {
+	case claim != "" && requested != "" && requested != claim:
+		return "", ErrTenantMismatch
+	case claim != "":
+		requested = claim
+	case requested == "":
+		return models.DefaultTenantID, nil
+	}
+
+	if requested == models.DefaultTenantID {
+		return requested, nil
+	}
+	if !ValidID(requested) {
+		return "", repository.ErrTenantNotFound
+	}
+	if _, err := r.tenants.GetByID(requested); err != nil {
+		return "", err
+	}
+	return requested, nil
+}
+
+// subdomain returns the label in front of the base domain, or "" when host is not a direct
+// subdomain of it
+func (r *Resolver) subdomain(host string) string {
+	if r.baseDomain == "" {
+		return ""
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	label, found := strings.CutSuffix(strings.ToLower(strings.TrimSuffix(host, ".")), "."+r.baseDomain)
+	if !found || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
@@ -0,0 +1,110 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTenantRepository is a mock implementation of TenantRepository
+type MockTenantRepository struct {
+	mock.Mock
+}
+
+func (m *MockTenantRepository) Create(tenant *models.Tenant) error {
+	args := m.Called(tenant)
+	return args.Error(0)
+}
+
+func (m *MockTenantRepository) GetByID(id string) (*models.Tenant, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Tenant), args.Error(1)
+}
+
+func (m *MockTenantRepository) GetAll() ([]models.Tenant, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Tenant), args.Error(1)
+}
+
+// newMockTenantRepository creates a mock that knows the tenants with the given IDs
+func newMockTenantRepository(ids ...string) *MockTenantRepository {
+	m := &MockTenantRepository{}
+	for _, id := range ids {
+		m.On("GetByID", id).Return(&models.Tenant{ID: id}, nil).Maybe()
+	}
+	m.On("GetByID", mock.Anything).Return(nil, repository.ErrTenantNotFound).Maybe()
+	return m
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	resolver := NewResolver(newMockTenantRepository("acme", "globex"), "blog.example.com")
+
+	tests := []struct {
+		name    string
+		claim   string
+		header  string
+		host    string
+		want    string
+		wantErr error
+	}{
+		{name: "nothing requested", host: "blog.example.com", want: models.DefaultTenantID},
+		{name: "header", header: " ACME ", want: "acme"},
+		{name: "subdomain", host: "acme.blog.example.com:8080", want: "acme"},
+		{name: "header wins over subdomain", header: "globex", host: "acme.blog.example.com", want: "globex"},
+		{name: "nested subdomain is ignored", host: "www.acme.blog.example.com", want: models.DefaultTenantID},
+		{name: "other domain is ignored", host: "acme.example.org", want: models.DefaultTenantID},
+		{name: "claim", claim: "acme", want: "acme"},
+		{name: "claim matching header", claim: "acme", header: "acme", want: "acme"},
+		{name: "claim mismatching header", claim: "acme", header: "globex", wantErr: ErrTenantMismatch},
+		{name: "claim mismatching subdomain", claim: "acme", host: "globex.blog.example.com", wantErr: ErrTenantMismatch},
+		{name: "default tenant header", header: models.DefaultTenantID, want: models.DefaultTenantID},
+		{name: "unknown tenant", header: "initech", wantErr: repository.ErrTenantNotFound},
+		{name: "unknown subdomain", host: "initech.blog.example.com", wantErr: repository.ErrTenantNotFound},
+		{name: "invalid tenant", header: "acme/../globex", wantErr: repository.ErrTenantNotFound},
+		{name: "unknown claim", claim: "initech", wantErr: repository.ErrTenantNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.Resolve(tt.claim, tt.header, tt.host)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResolver_Resolve_WithoutBaseDomain(t *testing.T) {
+	resolver := NewResolver(newMockTenantRepository("acme"), "")
+
+	got, err := resolver.Resolve("", "", "acme.blog.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, models.DefaultTenantID, got)
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, models.DefaultTenantID, FromContext(context.Background()))
+	assert.Equal(t, "acme", FromContext(NewContext(context.Background(), "acme")))
+}
+
+func TestValidID(t *testing.T) {
+	for _, id := range []string{"acme", "a", "acme-2", "0"} {
+		assert.True(t, ValidID(id), id)
+	}
+	for _, id := range []string{"", "-acme", "acme-", "Acme", "acme.blog", "acme_blog"} {
+		assert.False(t, ValidID(id), id)
+	}
+}
@@ -0,0 +1,169 @@
+// Package webhook delivers queued blog post events to webhook subscribers.
+package webhook
+
+import (
+	"BlogManagment/internal/config"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// batchSize is the number of deliveries claimed per run
+const batchSize = 50
+
+// userAgent identifies delivery requests
+const userAgent = "BlogManagment-Webhooks/1.0"
+
+// Dispatcher sends due deliveries from the queue. Failed deliveries are retried with
+// exponential backoff until they run out of attempts and become dead letters.
+type Dispatcher struct {
+	repo   repository.WebhookRepository
+	client *http.Client
+	cfg    *config.WebhookConfig
+	now    func() time.Time
+}
+
+// NewDispatcher creates a new dispatcher
+func NewDispatcher(repo repository.WebhookRepository, cfg *config.WebhookConfig) *Dispatcher {
+	return &Dispatcher{
+		repo:   repo,
+		client: &http.Client{Timeout: cfg.Timeout},
+		cfg:    cfg,
+		now:    time.Now,
+	}
+}
+
+// RunOnce claims the deliveries that are due and sends them. It returns an error only when
+// the queue itself cannot be read or updated; delivery failures are recorded per delivery.
+func (d *Dispatcher) RunOnce(ctx context.Context) error {
+	// The lease must outlast a full batch so that no other dispatcher picks up a delivery in flight
+	lease := d.cfg.Timeout*time.Duration((batchSize+d.cfg.Concurrency-1)/d.cfg.Concurrency) + time.Minute
+	deliveries, err := d.repo.ClaimDueDeliveries(d.now(), lease, batchSize)
+	if err != nil || len(deliveries) == 0 {
+		return err
+	}
+
+	webhooks, err := d.loadWebhooks(deliveries)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	slots := make(chan struct{}, d.cfg.Concurrency)
+	for i := range deliveries {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(delivery *models.WebhookDelivery) {
+			defer func() { <-slots; wg.Done() }()
+			d.attempt(ctx, delivery, webhooks[delivery.WebhookID])
+			if err := d.repo.UpdateDelivery(delivery); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(&deliveries[i])
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// loadWebhooks loads the webhooks of a batch of deliveries with a single query
+func (d *Dispatcher) loadWebhooks(deliveries []models.WebhookDelivery) (map[string]*models.Webhook, error) {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, delivery := range deliveries {
+		if !seen[delivery.WebhookID] {
+			seen[delivery.WebhookID] = true
+			ids = append(ids, delivery.WebhookID)
+		}
+	}
+
+	found, err := d.repo.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	webhooks := make(map[string]*models.Webhook, len(found))
+	for i := range found {
+		webhooks[found[i].ID] = &found[i]
+	}
+	return webhooks, nil
+}
+
+// attempt sends a delivery once and records the outcome on it
+func (d *Dispatcher) attempt(ctx context.Context, delivery *models.WebhookDelivery, webhook *models.Webhook) {
+	now := d.now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = 0
+
+	var err error
+	switch {
+	case webhook == nil:
+		err = fmt.Errorf("webhook no longer exists")
+	case !webhook.Active:
+		err = fmt.Errorf("webhook is disabled")
+	default:
+		delivery.ResponseStatus, err = d.send(ctx, delivery, webhook, now)
+	}
+
+	if err == nil {
+		delivery.Status = models.DeliveryStatusSucceeded
+		delivery.LastError = ""
+		return
+	}
+
+	delivery.LastError = err.Error()
+	// Deliveries for a missing or disabled webhook go straight to the dead-letter list
+	if webhook == nil || !webhook.Active || delivery.Attempts >= d.cfg.MaxAttempts {
+		delivery.Status = models.DeliveryStatusDead
+		return
+	}
+	delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+}
+
+// send posts the signed payload and treats any 2xx response as success
+func (d *Dispatcher) send(ctx context.Context, delivery *models.WebhookDelivery, webhook *models.Webhook, now time.Time) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", userAgent)
+	request.Header.Set(HeaderEvent, delivery.EventType)
+	request.Header.Set(HeaderDelivery, delivery.ID)
+	request.Header.Set(HeaderSignature, Sign(webhook.Secret, now, delivery.Payload))
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	// Drain a bounded amount so that the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
+
+// backoff returns the delay after the given number of failed attempts: BackoffBase doubled
+// for every attempt after the first, capped at BackoffMax
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.cfg.BackoffBase
+	for i := 1; i < attempts && delay < d.cfg.BackoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, d.cfg.BackoffMax)
+}
@@ -0,0 +1,197 @@
+package webhook
+
+import (
+	"BlogManagment/internal/config"
+	"BlogManagment/internal/models"
+	"BlogManagment/internal/repository"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestRepository returns a webhook repository over an empty in-memory SQLite database
+func newTestRepository(t *testing.T) repository.WebhookRepository {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+uuid.New().String()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Webhook{}, &models.WebhookDelivery{}))
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	return repository.NewWebhookRepository(db)
+}
+
+// delivery retrieves a delivery from the repository
+func delivery(t *testing.T, repo repository.WebhookRepository, id string) *models.WebhookDelivery {
+	t.Helper()
+	delivery, err := repo.GetDelivery(id)
+	require.NoError(t, err)
+	return delivery
+}
+
+func testConfig() *config.WebhookConfig {
+	return &config.WebhookConfig{
+		PollInterval: time.Second,
+		Timeout:      time.Second,
+		MaxAttempts:  3,
+		BackoffBase:  time.Minute,
+		BackoffMax:   3 * time.Minute,
+		Concurrency:  2,
+	}
+}
+
+// queue stores webhook and enqueues a due delivery to it for each of deliveryIDs
+func queue(t *testing.T, repo repository.WebhookRepository, webhook models.Webhook, deliveryIDs ...string) {
+	t.Helper()
+	active := webhook.Active
+	require.NoError(t, repo.Create(&webhook))
+	if !active {
+		// Create leaves a false Active to the column default
+		webhook.Active = false
+		require.NoError(t, repo.Update(&webhook))
+	}
+	enqueue(t, repo, webhook.ID, deliveryIDs...)
+}
+
+// enqueue enqueues a due delivery to the webhook with the given ID for each of deliveryIDs
+func enqueue(t *testing.T, repo repository.WebhookRepository, webhookID string, deliveryIDs ...string) {
+	t.Helper()
+	var deliveries []*models.WebhookDelivery
+	for _, id := range deliveryIDs {
+		deliveries = append(deliveries, &models.WebhookDelivery{
+			ID:            id,
+			WebhookID:     webhookID,
+			EventID:       "event-" + id,
+			EventType:     models.EventPostCreated,
+			Payload:       []byte(`{"type":"post.created"}`),
+			Status:        models.DeliveryStatusPending,
+			NextAttemptAt: time.Unix(0, 0),
+		})
+	}
+	require.NoError(t, repo.EnqueueDeliveries(deliveries))
+}
+
+func TestDispatcher_RunOnce_DeliversSignedPayload(t *testing.T) {
+	var mu sync.Mutex
+	var headers http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		headers = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo := newTestRepository(t)
+	queue(t, repo, models.Webhook{ID: "hook-1", URL: server.URL, Secret: "whsec_test", Active: true}, "delivery-1")
+
+	dispatcher := NewDispatcher(repo, testConfig())
+	require.NoError(t, dispatcher.RunOnce(context.Background()))
+
+	delivered := delivery(t, repo, "delivery-1")
+	assert.Equal(t, models.DeliveryStatusSucceeded, delivered.Status)
+	assert.Equal(t, 1, delivered.Attempts)
+	assert.Equal(t, http.StatusNoContent, delivered.ResponseStatus)
+	assert.Empty(t, delivered.LastError)
+
+	assert.Equal(t, `{"type":"post.created"}`, string(body))
+	assert.Equal(t, models.EventPostCreated, headers.Get(HeaderEvent))
+	assert.Equal(t, "delivery-1", headers.Get(HeaderDelivery))
+	assert.NoError(t, Verify("whsec_test", headers.Get(HeaderSignature), body, time.Minute, time.Now()))
+}
+
+func TestDispatcher_RunOnce_BacksOffAndDeadLetters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	repo := newTestRepository(t)
+	queue(t, repo, models.Webhook{ID: "hook-1", URL: server.URL, Secret: "s", Active: true}, "delivery-1")
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	dispatcher := NewDispatcher(repo, testConfig())
+	dispatcher.now = func() time.Time { return now }
+
+	require.NoError(t, dispatcher.RunOnce(context.Background()))
+	failed := delivery(t, repo, "delivery-1")
+	assert.Equal(t, models.DeliveryStatusPending, failed.Status)
+	assert.Equal(t, 1, failed.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, failed.ResponseStatus)
+	assert.Equal(t, "unexpected status 500", failed.LastError)
+	assert.True(t, now.Add(time.Minute).Equal(failed.NextAttemptAt))
+
+	// Not yet due
+	require.NoError(t, dispatcher.RunOnce(context.Background()))
+	assert.Equal(t, 1, delivery(t, repo, "delivery-1").Attempts)
+
+	now = now.Add(time.Minute)
+	require.NoError(t, dispatcher.RunOnce(context.Background()))
+	failed = delivery(t, repo, "delivery-1")
+	assert.Equal(t, 2, failed.Attempts)
+	assert.True(t, now.Add(2*time.Minute).Equal(failed.NextAttemptAt))
+
+	now = now.Add(2 * time.Minute)
+	require.NoError(t, dispatcher.RunOnce(context.Background()))
+	failed = delivery(t, repo, "delivery-1")
+	assert.Equal(t, 3, failed.Attempts)
+	assert.Equal(t, models.DeliveryStatusDead, failed.Status)
+}
+
+func TestDispatcher_RunOnce_DeadLettersDisabledAndDeletedWebhooks(t *testing.T) {
+	repo := newTestRepository(t)
+	queue(t, repo, models.Webhook{ID: "hook-1", URL: "http://127.0.0.1:1", Secret: "s", Active: false}, "delivery-1")
+	enqueue(t, repo, "hook-2", "delivery-2")
+
+	dispatcher := NewDispatcher(repo, testConfig())
+	require.NoError(t, dispatcher.RunOnce(context.Background()))
+
+	disabled := delivery(t, repo, "delivery-1")
+	assert.Equal(t, models.DeliveryStatusDead, disabled.Status)
+	assert.Equal(t, "webhook is disabled", disabled.LastError)
+
+	deleted := delivery(t, repo, "delivery-2")
+	assert.Equal(t, models.DeliveryStatusDead, deleted.Status)
+	assert.Equal(t, "webhook no longer exists", deleted.LastError)
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	cfg := testConfig()
+	cfg.BackoffBase = 30 * time.Second
+	cfg.BackoffMax = 5 * time.Minute
+	dispatcher := NewDispatcher(newTestRepository(t), cfg)
+
+	assert.Equal(t, 30*time.Second, dispatcher.backoff(1))
+	assert.Equal(t, time.Minute, dispatcher.backoff(2))
+	assert.Equal(t, 4*time.Minute, dispatcher.backoff(4))
+	assert.Equal(t, 5*time.Minute, dispatcher.backoff(5))
+	assert.Equal(t, 5*time.Minute, dispatcher.backoff(50))
+}
+
+func TestVerify(t *testing.T) {
+	payload := []byte(`{"id":"1"}`)
+	signedAt := time.Unix(1700000000, 0)
+	header := Sign("secret", signedAt, payload)
+
+	assert.NoError(t, Verify("secret", header, payload, 5*time.Minute, signedAt.Add(time.Minute)))
+	assert.ErrorIs(t, Verify("other", header, payload, 0, signedAt), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("secret", header, []byte(`{"id":"2"}`), 0, signedAt), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("secret", header, payload, 5*time.Minute, signedAt.Add(time.Hour)), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("secret", "garbage", payload, 0, signedAt), ErrInvalidSignature)
+}
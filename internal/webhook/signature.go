@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+)
+
+// ErrInvalidSignature is returned by Verify when a signature does not match the payload
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Sign returns the X-Webhook-Signature header for a payload: "t=<unix time>,v1=<hex>", where
+// the hex value is the HMAC-SHA256 of "<unix time>.<payload>" keyed with the webhook secret.
+// Including the time lets receivers reject replayed deliveries.
+func Sign(secret string, timestamp time.Time, payload []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + unix + ",v1=" + computeSignature(secret, unix, payload)
+}
+
+// Verify checks a signature header produced by Sign. Signatures older than tolerance are
+// rejected; a zero tolerance disables the age check.
+func Verify(secret, header string, payload []byte, tolerance time.Duration, now time.Time) error {
+	var unix string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			unix = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	seconds, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+	if tolerance > 0 && now.Sub(time.Unix(seconds, 0)).Abs() > tolerance {
+		return ErrInvalidSignature
+	}
+
+	expected := computeSignature(secret, unix, payload)
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// computeSignature computes the hex HMAC-SHA256 of "<unix>.<payload>"
+func computeSignature(secret, unix string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
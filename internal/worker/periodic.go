@@ -0,0 +1,27 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RunPeriodically calls fn every interval in a background goroutine until the context is cancelled.
+// Errors are logged with the given task name and do not stop the loop.
+func RunPeriodically(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := fn(ctx); err != nil {
+					log.Printf("%s failed: %v", name, err)
+				}
+			}
+		}
+	}()
+}
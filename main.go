@@ -1,22 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
 	"log"
+	"net"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 
+	"BlogManagment/internal/app"
+	"BlogManagment/internal/cli"
 	"BlogManagment/internal/config"
-	"BlogManagment/internal/controller"
-	"BlogManagment/internal/middleware"
-	"BlogManagment/internal/repository"
-	"BlogManagment/internal/routes"
-	"BlogManagment/internal/service"
-
-	_ "BlogManagment/docs"
-
-	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/recover"
-	"github.com/gofiber/swagger"
+
 	"github.com/joho/godotenv"
 )
 
@@ -38,59 +38,156 @@ import (
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
-// @description Type "Bearer" followed by a space and JWT token.
+// @description Type "Bearer" followed by a space and JWT token, or "ApiKey" followed by a space and an API key.
+
+// commands are the subcommands of the binary with a summary of each, in the order of the usage text
+var commands = [][2]string{
+	{"serve", "serve the HTTP and gRPC APIs (default)"},
+	{"config", "config print: show the effective configuration, secrets redacted"},
+	{"migrate", "bring the database schema up to date, e.g. with DB_AUTO_MIGRATE=false"},
+	{"seed", "create fake posts for demos and load tests"},
+	{"export", "write every post to a file or stdout"},
+	{"import", "read posts from a file, a Markdown directory or stdin"},
+	{"reindex", "give posts without a slug one and rebuild the post indexes"},
+	{"purge-trash", "permanently remove posts deleted long enough ago"},
+	{"user", "user create: create an account, reading its password from stdin"},
+	{"build-static", "render the published posts as a static site"},
+	{"tenants", "create and list tenants"},
+	{"roles", "grant, revoke and list roles"},
+	{"api-keys", "create, list, rotate and revoke API keys"},
+}
+
+// usage prints the commands after the flags shared by every command
+func usage() {
+	fmt.Fprintf(os.Stderr, "\nUsage: %s [flags] [command] [command flags]\n\nCommands:\n", filepath.Base(os.Args[0]))
+	for _, command := range commands {
+		fmt.Fprintf(os.Stderr, "  %-13s %s\n", command[0], command[1])
+	}
+}
 
 func main() {
-	// Load environment variables
-	if err := godotenv.Load("config.env"); err != nil {
-		log.Println("Warning: config.env file not found, using default values")
+	// Variables in config.env count as environment variables
+	if err := godotenv.Load("config.env"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Fatalf("Failed to read config.env: %v", err)
 	}
 
-	// Initialize database
-	dbConfig := config.NewDatabaseConfig()
-	db, err := dbConfig.Connect()
+	// Every command shares the configuration, loaded from its defaults, a file, the environment
+	// and the flags before the command
+	cfg, args, err := config.Load(filepath.Base(os.Args[0]), os.Args[1:], os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		usage()
+		os.Exit(0)
+	}
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		log.Fatal(err)
 	}
 
-	// Initialize repository layer
-	blogRepo := repository.NewBlogRepository(db)
-
-	// Initialize service layer
-	blogService := service.NewBlogService(blogRepo)
+	// Run the server unless another command is given
+	command := "serve"
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+	if !slices.ContainsFunc(commands, func(c [2]string) bool { return c[0] == command }) {
+		usage()
+		log.Fatalf("Unknown command %q", command)
+	}
 
-	// Initialize controller layer
-	blogController := controller.NewBlogController(blogService)
+	switch command {
+	case "config":
+		// Commands that only need the configuration run without a database
+		if err := cli.RunConfig(args, cfg, os.Stdout); err != nil {
+			log.Fatalf("Config command failed: %v", err)
+		}
+		return
+	case "migrate":
+		// Connecting migrates, even if migrations on startup are disabled
+		cfg.Database.AutoMigrate = true
+		if _, err := cfg.Database.Connect(); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
 
-	// Create Fiber app
-	app := fiber.New(fiber.Config{
-		ErrorHandler: middleware.ErrorHandler(),
-		AppName:      "Blog Management API",
-	})
+	// Initialize database
+	db, err := cfg.Database.Connect()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
 
-	// Add global middleware
-	app.Use(recover.New())
-	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
-		AllowMethods: "GET, POST, PUT, PATCH, DELETE",
-	}))
+	// Initialize the repository and service layers
+	application, err := app.New(db, cfg)
+	if err != nil {
+		log.Fatalf("Failed to set up the application: %v", err)
+	}
 
-	// Swagger documentation
-	app.Get("/swagger/*", swagger.HandlerDefault)
+	switch command {
+	case "serve":
+		serve(application, cfg.Server)
+	case "seed":
+		if err := cli.RunSeed(args, application.BlogService, os.Stdout); err != nil {
+			log.Fatalf("Seeding failed: %v", err)
+		}
+	case "export":
+		if err := cli.RunExport(args, application.TransferService, os.Stdout); err != nil {
+			log.Fatalf("Export failed: %v", err)
+		}
+	case "import":
+		if err := cli.RunImport(args, application.TransferService, os.Stdin, os.Stdout); err != nil {
+			log.Fatalf("Import failed: %v", err)
+		}
+	case "reindex":
+		if err := cli.RunReindex(args, application.MaintenanceService, os.Stdout); err != nil {
+			log.Fatalf("Reindex failed: %v", err)
+		}
+	case "purge-trash":
+		if err := cli.RunPurgeTrash(args, application.MaintenanceService, os.Stdout); err != nil {
+			log.Fatalf("Purging the trash failed: %v", err)
+		}
+	case "user":
+		if err := cli.RunUsers(args, application.AccountService, application.RoleService, os.Stdin, os.Stdout); err != nil {
+			log.Fatalf("User command failed: %v", err)
+		}
+	case "build-static":
+		if err := cli.RunBuildStatic(args, application.BlogService, os.Stdout); err != nil {
+			log.Fatalf("Static site build failed: %v", err)
+		}
+	case "tenants":
+		if err := cli.RunTenants(args, application.TenantService, os.Stdout); err != nil {
+			log.Fatalf("Tenant command failed: %v", err)
+		}
+	case "roles":
+		if err := cli.RunRoles(args, application.RoleService, os.Stdout); err != nil {
+			log.Fatalf("Role command failed: %v", err)
+		}
+	case "api-keys":
+		if err := cli.RunAPIKeys(args, application.APIKeyService, os.Stdout); err != nil {
+			log.Fatalf("API key command failed: %v", err)
+		}
+	}
+}
 
-	// Setup routes
-	routes.SetupRoutes(app, blogController)
+// serve starts the HTTP API and, on its own port, the gRPC API for internal consumers
+func serve(application *app.App, serverConfig *config.ServerConfig) {
+	server, err := application.NewServer(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to set up the server: %v", err)
+	}
 
-	// Get port from environment or use default
-	port := os.Getenv("SERVER_PORT")
-	if port == "" {
-		port = "8080"
+	grpcAddress := net.JoinHostPort(serverConfig.GRPCHost, strconv.Itoa(serverConfig.GRPCPort))
+	grpcListener, err := net.Listen("tcp", grpcAddress)
+	if err != nil {
+		log.Fatalf("Failed to listen for gRPC on %s: %v", grpcAddress, err)
 	}
+	go func() {
+		log.Printf("gRPC server starting on %s", grpcAddress)
+		if err := server.GRPC.Serve(grpcListener); err != nil {
+			log.Fatalf("Failed to start gRPC server: %v", err)
+		}
+	}()
 
 	// Start server
-	log.Printf("Server starting on port %s", port)
-	if err := app.Listen(":" + port); err != nil {
+	log.Printf("Server starting on port %d", serverConfig.Port)
+	if err := server.HTTP.Listen(fmt.Sprintf(":%d", serverConfig.Port)); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }